@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v84/github"
+	"github.com/spf13/viper"
+
+	"github.com/plexusone/versionconductor/internal/auth"
+)
+
+// newGitHubClient returns a client for the GitHub instance selected with
+// --github-url, authenticated as configured (see newAuthProvider).
+func newGitHubClient() (*github.Client, error) {
+	provider, err := newAuthProvider()
+	if err != nil {
+		return nil, err
+	}
+	return auth.NewEnterpriseClient(provider, viper.GetString("github-url"))
+}
+
+// newAuthProvider returns the GitHub authentication configured with the
+// --app-* flags, or the --token flag if no GitHub App is configured.
+// Credentials in the config file take precedence for the organizations
+// and hosts they name.
+//
+// Without any GitHub credential, requests fail when made. This is only
+// allowed if other providers are configured, which may not need GitHub.
+func newAuthProvider() (auth.Provider, error) {
+	fallback, err := defaultAuthProvider()
+	if err != nil {
+		return nil, err
+	}
+	if fallback == nil && !viper.IsSet("providers") && !viper.IsSet("credentials") {
+		return nil, fmt.Errorf("GitHub token required. Set GITHUB_TOKEN or use --token flag")
+	}
+	return newCredentialRouter(fallback)
+}
+
+// newCredentialRouter returns a provider using the credentials in the
+// config file, and fallback (if not nil) for all other requests.
+func newCredentialRouter(fallback auth.Provider) (auth.Provider, error) {
+	var creds []auth.Credential
+	if err := viper.UnmarshalKey("credentials", &creds); err != nil {
+		return nil, fmt.Errorf("invalid credentials config: %w", err)
+	}
+	if len(creds) == 0 && fallback != nil {
+		return fallback, nil
+	}
+
+	router, err := auth.NewRouter(creds, fallback)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials config: %w", err)
+	}
+	return router, nil
+}
+
+// defaultAuthProvider returns the GitHub App or token provider, or nil if
+// neither is configured.
+func defaultAuthProvider() (auth.Provider, error) {
+	if appID := viper.GetInt64("app.id"); appID != 0 {
+		keyFile := viper.GetString("app.private-key")
+		if keyFile == "" {
+			return nil, fmt.Errorf("GitHub App private key required (--app-private-key)")
+		}
+
+		provider, err := auth.NewAppProviderFromFile(auth.AppConfig{
+			AppID:           appID,
+			InstallationIDs: appInstallationIDs(),
+			BaseURL:         viper.GetString("github-url"),
+		}, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure GitHub App: %w", err)
+		}
+		return provider, nil
+	}
+
+	token := viper.GetString("token")
+	if token == "" {
+		return nil, nil
+	}
+
+	return auth.NewTokenProvider(token), nil
+}
+
+// appInstallationIDs returns the installation IDs from the flag or config.
+func appInstallationIDs() []int64 {
+	var ids []int64
+	for _, v := range viper.GetIntSlice("app.installation-ids") {
+		ids = append(ids, int64(v))
+	}
+	return ids
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -11,7 +12,11 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/plexusone/versionconductor/internal/auth"
+	"github.com/plexusone/versionconductor/internal/bitbucket"
+	"github.com/plexusone/versionconductor/internal/gitea"
 	"github.com/plexusone/versionconductor/internal/graph"
+	"github.com/plexusone/versionconductor/internal/provider"
 	"github.com/plexusone/versionconductor/internal/report"
 )
 
@@ -164,15 +169,14 @@ func init() {
 func runGraphBuild(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	token := viper.GetString("token")
-	if token == "" {
-		return fmt.Errorf("GitHub token required. Set GITHUB_TOKEN or use --token flag")
-	}
-
 	orgs := viper.GetStringSlice("orgs")
 	if len(orgs) == 0 {
 		return fmt.Errorf("at least one organization required (--orgs)")
 	}
+	orgs, err := expandOrgs(orgs)
+	if err != nil {
+		return err
+	}
 
 	verbose := viper.GetBool("verbose")
 	languages := viper.GetStringSlice("graph.languages")
@@ -180,7 +184,7 @@ func runGraphBuild(cmd *cobra.Command, args []string) error {
 	// Build portfolio
 	portfolio := graph.Portfolio{
 		Name:      "cli-portfolio",
-		Orgs:      expandOrgs(orgs),
+		Orgs:      orgs,
 		Languages: languages,
 	}
 
@@ -190,7 +194,10 @@ func runGraphBuild(cmd *cobra.Command, args []string) error {
 	}
 
 	// Build graph
-	builder := graph.NewBuilder(token)
+	builder, err := newGraphBuilder(nil)
+	if err != nil {
+		return err
+	}
 	g, err := builder.Build(ctx, portfolio)
 	if err != nil {
 		return fmt.Errorf("failed to build graph: %w", err)
@@ -433,19 +440,18 @@ func runGraphStats(cmd *cobra.Command, args []string) error {
 
 // loadOrBuildGraph loads a cached graph or builds a new one.
 func loadOrBuildGraph(ctx context.Context) (graph.Graph, error) {
-	token := viper.GetString("token")
-	if token == "" {
-		return nil, fmt.Errorf("GitHub token required. Set GITHUB_TOKEN or use --token flag")
-	}
-
 	orgs := viper.GetStringSlice("orgs")
 	if len(orgs) == 0 {
 		return nil, fmt.Errorf("at least one organization required (--orgs)")
 	}
+	orgs, err := expandOrgs(orgs)
+	if err != nil {
+		return nil, err
+	}
 
 	portfolio := graph.Portfolio{
 		Name:      "cli-portfolio",
-		Orgs:      expandOrgs(orgs),
+		Orgs:      orgs,
 		Languages: []string{"go"},
 	}
 
@@ -465,25 +471,90 @@ func loadOrBuildGraph(ctx context.Context) (graph.Graph, error) {
 	}
 
 	// Build with configuration
-	builder := graph.NewBuilderWithConfig(graph.BuilderConfig{
-		Token: token,
-		Cache: cache,
-	})
+	builder, err := newGraphBuilder(cache)
+	if err != nil {
+		return nil, err
+	}
 
 	return builder.Build(ctx, portfolio)
 }
 
-// expandOrgs expands org names to full github.com paths.
-func expandOrgs(orgs []string) []string {
+// newGraphBuilder creates a graph builder for GitHub (or the server
+// selected with --github-url) and the Gitea and Bitbucket providers, using
+// cache if not nil.
+func newGraphBuilder(cache *graph.Cache) (*graph.Builder, error) {
+	authProvider, err := newAuthProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := providerConfigs()
+	if err != nil {
+		return nil, err
+	}
+	sources := make(map[string]graph.Source)
+	for _, cfg := range configs {
+		if cfg.Type != provider.Gitea && cfg.Type != provider.Bitbucket {
+			continue
+		}
+		p, err := providerAuth(cfg)
+		if err != nil {
+			return nil, err
+		}
+		httpClient := &http.Client{Transport: p.Transport(nil)}
+
+		if cfg.Type == provider.Bitbucket {
+			client, err := bitbucket.NewClient(httpClient, cfg.URL)
+			if err != nil {
+				return nil, err
+			}
+			sources[providerHost(cfg)] = graph.NewBitbucketSource(client)
+			continue
+		}
+
+		client, err := gitea.NewClient(httpClient, cfg.URL)
+		if err != nil {
+			return nil, err
+		}
+		sources[providerHost(cfg)] = graph.NewGiteaSource(client)
+	}
+
+	return graph.NewBuilderWithConfig(graph.BuilderConfig{
+		Auth:    authProvider,
+		BaseURL: viper.GetString("github-url"),
+		Cache:   cache,
+		Sources: sources,
+	})
+}
+
+// expandOrgs expands org names to full paths on their host, e.g.
+// github.com/grokify, ghe.example.com/team with --github-url, or
+// git.example.com/tools for an org listed in a provider.
+func expandOrgs(orgs []string) ([]string, error) {
+	configs, err := providerConfigs()
+	if err != nil {
+		return nil, err
+	}
+	hosts := make(map[string]string)
+	for _, cfg := range configs {
+		for _, org := range cfg.Orgs {
+			hosts[strings.ToLower(org)] = providerHost(cfg)
+		}
+	}
+
+	defaultHost := auth.Host(viper.GetString("github-url"))
 	result := make([]string, len(orgs))
 	for i, org := range orgs {
-		if !strings.Contains(org, "/") {
-			result[i] = "github.com/" + org
-		} else {
+		switch {
+		case strings.Contains(org, "/"):
 			result[i] = org
+		case hosts[strings.ToLower(org)] != "":
+			result[i] = hosts[strings.ToLower(org)] + "/" + org
+		default:
+			result[i] = defaultHost + "/" + org
 		}
 	}
-	return result
+	return result, nil
 }
 
 // formatModulesTable formats modules as a table.
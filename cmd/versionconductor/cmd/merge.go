@@ -4,12 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	"github.com/plexusone/versionconductor/internal/collector"
 	"github.com/plexusone/versionconductor/internal/merger"
 	"github.com/plexusone/versionconductor/internal/policy"
 	"github.com/plexusone/versionconductor/internal/report"
@@ -41,7 +42,7 @@ Examples:
 func init() {
 	rootCmd.AddCommand(mergeCmd)
 
-	mergeCmd.Flags().String("profile", "balanced", "Merge profile: aggressive, balanced, conservative")
+	mergeCmd.Flags().String("profile", "balanced", "Merge profile: aggressive, balanced, conservative, or a YAML profile file")
 	mergeCmd.Flags().String("strategy", "squash", "Merge strategy: merge, squash, rebase")
 	mergeCmd.Flags().Bool("execute", false, "Actually merge PRs (default is dry-run)")
 	mergeCmd.Flags().Bool("delete-branch", true, "Delete branch after merge")
@@ -49,7 +50,7 @@ func init() {
 	mergeCmd.Flags().Bool("wait-for-checks", false, "Wait for pending checks to complete")
 	mergeCmd.Flags().Int("checks-timeout", 300, "Timeout in seconds for waiting on checks")
 	mergeCmd.Flags().StringSlice("update-type", nil, "Filter by update type: major, minor, patch")
-	mergeCmd.Flags().String("bot", "", "Filter by dependency bot: renovate, dependabot")
+	mergeCmd.Flags().String("bot", "", "Filter by dependency bot: renovate, dependabot, or the name of a configured bot")
 
 	_ = viper.BindPFlag("merge.profile", mergeCmd.Flags().Lookup("profile"))
 	_ = viper.BindPFlag("merge.strategy", mergeCmd.Flags().Lookup("strategy"))
@@ -65,9 +66,9 @@ func init() {
 func runMerge(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	token := viper.GetString("token")
-	if token == "" {
-		return fmt.Errorf("GitHub token required. Set GITHUB_TOKEN or use --token flag")
+	plat, err := newPlatforms()
+	if err != nil {
+		return err
 	}
 
 	orgs := viper.GetStringSlice("orgs")
@@ -83,9 +84,9 @@ func runMerge(cmd *cobra.Command, args []string) error {
 
 	// Get merge profile
 	profileName := viper.GetString("merge.profile")
-	profile := policy.GetProfile(profileName)
-	if profile == nil {
-		return fmt.Errorf("unknown profile: %s", profileName)
+	profile, err := policy.ResolveProfile(profileName)
+	if err != nil {
+		return err
 	}
 
 	// Override profile settings with flags
@@ -97,9 +98,19 @@ func runMerge(cmd *cobra.Command, args []string) error {
 		profile.MaxPRsPerRun = maxPRs
 	}
 
+	engine, err := newPolicyEngine(profile)
+	if err != nil {
+		return err
+	}
+
+	vulns, err := loadVulnDB()
+	if err != nil {
+		return err
+	}
+
 	// Create collector and merger
-	coll := collector.NewGitHub(token)
-	merg := merger.NewGitHub(token)
+	coll := plat.Collector
+	merg := plat.Merger
 
 	// Build filters
 	repoFilter := model.RepoFilter{
@@ -152,96 +163,167 @@ func runMerge(cmd *cobra.Command, args []string) error {
 
 	mergeCount := 0
 
-	for _, repo := range allRepos {
+	opts := scanOptions{
+		Base: engine.Profile(),
+		Filter: func(rp *model.RepoProfile) model.PRFilter {
+			// Young security fixes are kept for the policy to let through.
+			repoPRFilter := prFilter
+			repoPRFilter.MinAgeHours = rp.Profile.MinAgeHours
+			if rp.Profile.SecurityFixesSkipAge {
+				repoPRFilter.MinAgeHours = 0
+			}
+			return repoPRFilter
+		},
+		Details: true,
+		Proxy:   plat.Proxy,
+		Vulns:   vulns,
+	}
+	scans := scanRepos(ctx, coll, allRepos, opts)
+
+	// Merges are made one at a time, so a repo's PRs are never merged
+	// concurrently. Security fixes go first across all repositories, most
+	// severe first, so a run limited by --max-prs doesn't spend it on one
+	// repo's routine updates while another's fixes wait.
+	type mergeCandidate struct {
+		rs *repoScan
+		ps prScan
+	}
+	var candidates []mergeCandidate
+	for _, scan := range scans {
+		// Repos whose config can't be read are skipped rather than
+		// merged under the base profile.
+		rs := scan.Value
+		if scan.Err != nil {
+			result.Errors = append(result.Errors, model.ScanError{
+				Repo:    scan.Repo.FullName,
+				Message: scan.Err.Error(),
+			})
+			continue
+		}
+		if rs.Profile.ConfigPath != "" {
+			result.Profiles = append(result.Profiles, *rs.Profile)
+		}
+		if rs.Profile.Disabled {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Skipping %s: disabled by %s\n", scan.Repo.FullName, rs.Profile.ConfigPath)
+			}
+			continue
+		}
+
+		for _, ps := range rs.PRs {
+			candidates = append(candidates, mergeCandidate{rs: rs, ps: ps})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b mergeCandidate) int {
+		return compareSeverity(&a.ps.PR, &b.ps.PR)
+	})
+
+	for _, c := range candidates {
 		if profile.MaxPRsPerRun > 0 && mergeCount >= profile.MaxPRsPerRun {
 			break
 		}
 
-		ref := model.RepoRef{Owner: repo.Owner, Name: repo.Name}
+		rs, ps := c.rs, c.ps
+		repoEngine := engine.WithProfile(&rs.Profile.Profile)
 
-		prs, err := coll.ListDependencyPRs(ctx, ref)
-		if err != nil {
-			if verbose {
-				fmt.Fprintf(os.Stderr, "Error listing PRs for %s: %v\n", repo.FullName, err)
-			}
+		if rs.ProtectionErr != nil {
+			result.Skipped = append(result.Skipped, model.SkippedPR{
+				PR:     ps.PR,
+				Reason: rs.ProtectionErr.Error(),
+				Codes:  []model.ReasonCode{model.ReasonProtectionUnavailable},
+			})
 			continue
 		}
 
-		for _, pr := range prs {
-			if profile.MaxPRsPerRun > 0 && mergeCount >= profile.MaxPRsPerRun {
-				break
-			}
-
-			if !matchesPRFilter(pr, prFilter) {
+		// The PR may have changed since the scan, not least through
+		// the repo's other PRs being merged, so it's read again.
+		if !dryRun {
+			ps = rescanPR(ctx, coll, rs.Ref, ps.PR, &rs.Profile.Profile, opts)
+			if ps.InfoErr == nil && ps.PR.State != "open" {
+				result.Skipped = append(result.Skipped, model.SkippedPR{
+					PR:     ps.PR,
+					Reason: "no longer open",
+					Codes:  []model.ReasonCode{model.ReasonNotOpen},
+				})
 				continue
 			}
-
-			// Get checks
-			checks, err := coll.GetPRChecks(ctx, ref, pr.Number)
-			if err != nil {
+			if ps.InfoErr == nil && !matchesPRFilter(ps.PR, opts.Filter(rs.Profile)) {
 				if verbose {
-					fmt.Fprintf(os.Stderr, "Error getting checks for %s#%d: %v\n", repo.FullName, pr.Number, err)
+					fmt.Fprintf(os.Stderr, "Skipping %s#%d: no longer matches the filter\n", rs.Repo.FullName, ps.PR.Number)
 				}
 				continue
 			}
+		}
 
-			pr.TestsPassed = collector.TestsPassed(checks)
+		pr := ps.PR
+		if code, err := ps.readErr(); err != nil {
+			result.Skipped = append(result.Skipped, model.SkippedPR{
+				PR:     pr,
+				Reason: err.Error(),
+				Codes:  []model.ReasonCode{code},
+			})
+			continue
+		}
 
-			// Get mergeable status
-			prDetails, err := coll.GetPRDetails(ctx, ref, pr.Number)
-			if err == nil {
-				pr.Mergeable = prDetails.Mergeable
-				pr.MergeableStr = prDetails.MergeableStr
-			}
+		// Evaluate against policies
+		decision, err := repoEngine.CanMerge(ctx, &pr, ps.Checks)
+		if err != nil {
+			result.Skipped = append(result.Skipped, model.SkippedPR{
+				PR:     pr,
+				Reason: fmt.Sprintf("failed to evaluate policy: %v", err),
+				Codes:  []model.ReasonCode{model.ReasonPolicyError},
+			})
+			continue
+		}
 
-			// Evaluate against profile
-			shouldMerge, reason := policy.EvaluateProfile(profile, &pr, checks)
+		if !decision.Allowed {
+			result.Skipped = append(result.Skipped, model.SkippedPR{
+				PR:         pr,
+				Reason:     strings.Join(decision.Reasons, "; "),
+				Codes:      decision.Codes(),
+				Details:    decision.Details,
+				NextWindow: decision.NextWindow,
+			})
+			continue
+		}
 
-			if !shouldMerge {
-				result.Skipped = append(result.Skipped, model.SkippedPR{
-					PR:     pr,
-					Reason: reason,
-				})
-				continue
+		// Merge the PR
+		if dryRun {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Would merge %s#%d: %s\n", rs.Repo.FullName, pr.Number, pr.Title)
+			}
+			result.Merged = append(result.Merged, model.MergedPR{
+				PR:       pr,
+				MergedBy: "dry-run",
+			})
+			mergeCount++
+		} else {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Merging %s#%d: %s\n", rs.Repo.FullName, pr.Number, pr.Title)
 			}
 
-			// Merge the PR
-			if dryRun {
-				if verbose {
-					fmt.Fprintf(os.Stderr, "Would merge %s#%d: %s\n", repo.FullName, pr.Number, pr.Title)
-				}
-				result.Merged = append(result.Merged, model.MergedPR{
-					PR:       pr,
-					MergedBy: "dry-run",
-				})
-				mergeCount++
-			} else {
-				if verbose {
-					fmt.Fprintf(os.Stderr, "Merging %s#%d: %s\n", repo.FullName, pr.Number, pr.Title)
-				}
-
-				info, err := merg.MergePR(ctx, ref, pr.Number, merger.MergeStrategy(profile.MergeStrategy), "")
-				if err != nil {
-					result.Failed = append(result.Failed, model.FailedPR{
-						PR:    pr,
-						Error: err.Error(),
-					})
-					continue
-				}
-
-				result.Merged = append(result.Merged, model.MergedPR{
-					PR:       pr,
-					MergedBy: "versionconductor",
-					SHA:      info.SHA,
+			info, err := merg.MergePR(ctx, rs.Ref, pr.Number, merger.MergeStrategy(rs.Profile.Profile.MergeStrategy), "")
+			if err != nil {
+				result.Failed = append(result.Failed, model.FailedPR{
+					PR:    pr,
+					Error: err.Error(),
 				})
-				mergeCount++
+				continue
 			}
+
+			result.Merged = append(result.Merged, model.MergedPR{
+				PR:       pr,
+				MergedBy: "versionconductor",
+				SHA:      info.SHA,
+			})
+			mergeCount++
 		}
 	}
 
 	result.MergedCount = len(result.Merged)
 	result.SkippedCount = len(result.Skipped)
 	result.FailedCount = len(result.Failed)
+	result.CountSkippedReasons()
 
 	// Generate output
 	format := viper.GetString("format")
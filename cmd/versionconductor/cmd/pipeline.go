@@ -0,0 +1,324 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/google/go-github/v84/github"
+	"github.com/spf13/viper"
+
+	"github.com/plexusone/versionconductor/internal/collector"
+	"github.com/plexusone/versionconductor/internal/goproxy"
+	"github.com/plexusone/versionconductor/internal/graph"
+	"github.com/plexusone/versionconductor/internal/osv"
+	"github.com/plexusone/versionconductor/internal/pipeline"
+	"github.com/plexusone/versionconductor/internal/policy"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// repoScan holds everything read about a repository before any policy is
+// evaluated or change made. ProtectionErr is set if the branch protection
+// couldn't be read; the PRs are still listed, for each to be skipped with
+// the reason rather than acted on under a profile missing the
+// protection's requirements.
+type repoScan struct {
+	Repo          model.Repo
+	Ref           model.RepoRef
+	Profile       *model.RepoProfile
+	ProtectionErr error
+	PRs           []prScan
+}
+
+// prScan is a dependency PR with its checks. ChecksErr is set if the
+// checks couldn't be read, InfoErr if the PR's changed files, commit
+// authors or approvals couldn't be.
+type prScan struct {
+	PR        model.PullRequest
+	Checks    []model.CheckRun
+	ChecksErr error
+	InfoErr   error
+}
+
+// readErr returns the error reading the PR's checks or other information
+// and the reason code the PR is skipped with, or nil if it was read.
+func (ps *prScan) readErr() (model.ReasonCode, error) {
+	if ps.ChecksErr != nil {
+		return model.ReasonChecksUnavailable, fmt.Errorf("failed to read checks: %w", ps.ChecksErr)
+	}
+	if ps.InfoErr != nil {
+		return model.ReasonPRInfoUnavailable, ps.InfoErr
+	}
+	return "", nil
+}
+
+// rateLimitErr returns the error reading the PR if GitHub rate limited
+// the request, or nil otherwise.
+func (ps *prScan) rateLimitErr() error {
+	for _, err := range []error{ps.ChecksErr, ps.InfoErr} {
+		if _, limited := pipeline.RetryAfter(err); limited {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanOptions configures scanRepos.
+type scanOptions struct {
+	// Base is the merge profile the repository config is applied to.
+	Base *model.MergeProfile
+
+	// Filter returns the PR filter for a repository's effective profile.
+	Filter func(rp *model.RepoProfile) model.PRFilter
+
+	// Details fetches each PR's mergeable state.
+	Details bool
+
+	// Proxy returns the client looking up when the new versions of Go
+	// modules were published.
+	Proxy func() (*goproxy.Client, error)
+
+	// Vulns is the OSV database security fixes are found in, if any.
+	Vulns *osv.Database
+
+	// Risk scores the risk of each PR, if set.
+	Risk policy.RiskScorer
+}
+
+// newCollector creates a collector for the API selected with --api.
+func newCollector(client *github.Client) (collector.Collector, error) {
+	return collector.New(viper.GetString("api"), client)
+}
+
+// pipelineOptions returns the pipeline options from the --concurrency flag.
+func pipelineOptions() pipeline.Options {
+	return pipeline.Options{Concurrency: viper.GetInt("concurrency")}
+}
+
+// scanRepos reads the config, branch protection, dependency PRs and PR
+// checks of each repository concurrently. Results are in the same order
+// as repos. Repositories disabled by their config have no PRs; the PRs of
+// the others are ordered by the severity of the vulnerabilities they fix,
+// so security fixes are handled first. A rate limit on any request for a
+// repository, including those for its PRs, fails the repository, so the
+// pipeline reads it again once the limit resets.
+func scanRepos(ctx context.Context, coll collector.Collector, repos []model.Repo, opts scanOptions) []pipeline.Result[*repoScan] {
+	verbose := viper.GetBool("verbose")
+
+	// Collectors that can read many repositories per request load them
+	// up front; if that fails, each repository is read on its own.
+	if p, ok := coll.(collector.Prefetcher); ok {
+		refs := make([]model.RepoRef, 0, len(repos))
+		for _, repo := range repos {
+			refs = append(refs, model.RepoRef{Owner: repo.Owner, Name: repo.Name})
+		}
+		if err := p.Prefetch(ctx, refs); err != nil && verbose {
+			fmt.Fprintf(os.Stderr, "Error prefetching pull requests: %v\n", err)
+		}
+	}
+
+	return pipeline.Run(ctx, repos, pipelineOptions(), func(ctx context.Context, repo model.Repo) (*repoScan, error) {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Scanning %s...\n", repo.FullName)
+		}
+
+		ref := model.RepoRef{Owner: repo.Owner, Name: repo.Name}
+		rs := &repoScan{Repo: repo, Ref: ref}
+
+		repoProfile, err := loadRepoProfile(ctx, coll, ref, opts.Base)
+		if err != nil {
+			return rs, err
+		}
+		rs.Profile = repoProfile
+		if repoProfile.Disabled {
+			return rs, nil
+		}
+
+		rs.ProtectionErr = loadRepoProtection(ctx, coll, ref, repo.DefaultBranch, repoProfile)
+		if _, limited := pipeline.RetryAfter(rs.ProtectionErr); limited {
+			return rs, rs.ProtectionErr
+		}
+
+		prs, err := coll.ListDependencyPRs(ctx, ref)
+		if err != nil {
+			return rs, fmt.Errorf("failed to list PRs: %w", err)
+		}
+
+		filter := opts.Filter(repoProfile)
+		for _, pr := range prs {
+			if !matchesPRFilter(pr, filter) {
+				continue
+			}
+
+			ps := scanPR(ctx, coll, ref, pr, &repoProfile.Profile, opts)
+			if err := ps.rateLimitErr(); err != nil {
+				return rs, fmt.Errorf("failed to read PR #%d: %w", pr.Number, err)
+			}
+			rs.PRs = append(rs.PRs, ps)
+		}
+		slices.SortStableFunc(rs.PRs, func(a, b prScan) int {
+			return compareSeverity(&a.PR, &b.PR)
+		})
+
+		return rs, nil
+	})
+}
+
+// scanPR reads the checks of a PR and everything else its policy is
+// evaluated on.
+func scanPR(ctx context.Context, coll collector.Collector, ref model.RepoRef, pr model.PullRequest, profile *model.MergeProfile, opts scanOptions) prScan {
+	ps := prScan{PR: pr}
+	ps.Checks, ps.ChecksErr = coll.GetPRChecks(ctx, ref, pr.Number)
+	if ps.ChecksErr == nil {
+		ps.PR.TestsPassed = collector.TestsPassed(ps.Checks)
+	}
+
+	ps.InfoErr = loadPRInfo(ctx, coll, opts.Proxy, opts.Vulns, ref, &ps.PR, profile)
+
+	if opts.Risk != nil {
+		risk := opts.Risk.Score(&ps.PR, ps.Checks)
+		ps.PR.Risk = &risk
+	}
+
+	if opts.Details {
+		if details, err := coll.GetPRDetails(ctx, ref, pr.Number); err == nil {
+			ps.PR.Mergeable = details.Mergeable
+			ps.PR.MergeableStr = details.MergeableStr
+		}
+	}
+
+	return ps
+}
+
+// rescanPR reads a PR again right before it is merged, so the merge
+// isn't decided on what scanRepos read, which may be long out of date by
+// the time a large run gets to it. The PR may have been closed since.
+func rescanPR(ctx context.Context, coll collector.Collector, ref model.RepoRef, pr model.PullRequest, profile *model.MergeProfile, opts scanOptions) prScan {
+	details, err := coll.GetPRDetails(ctx, ref, pr.Number)
+	if err != nil {
+		return prScan{PR: pr, InfoErr: fmt.Errorf("failed to read PR: %w", err)}
+	}
+
+	opts.Details = false
+	return scanPR(ctx, coll, ref, *details, profile, opts)
+}
+
+// newGoProxy creates the Go module proxy client from the --goproxy flag,
+// falling back to the GOPROXY environment variable. As with the go
+// command, modules matching GONOPROXY, or GOPRIVATE if that is unset,
+// are never sent to a proxy.
+func newGoProxy() (*goproxy.Client, error) {
+	list := viper.GetString("goproxy")
+	if list == "" {
+		list = os.Getenv("GOPROXY")
+	}
+	client, err := goproxy.NewClient(nil, list)
+	if err != nil {
+		return nil, err
+	}
+
+	private := os.Getenv("GONOPROXY")
+	if private == "" {
+		private = os.Getenv("GOPRIVATE")
+	}
+	client.SetPrivate(private)
+	return client, nil
+}
+
+// loadVulnDB loads the OSV database given with --osv-db, or returns nil if
+// none is.
+func loadVulnDB() (*osv.Database, error) {
+	path := viper.GetString("osv-db")
+	if path == "" {
+		return nil, nil
+	}
+
+	db, err := osv.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if viper.GetBool("verbose") {
+		fmt.Fprintf(os.Stderr, "Loaded %d advisories from %s\n", db.Len(), path)
+	}
+	return db, nil
+}
+
+// newRiskScorer creates the risk scorer, counting managed dependents in
+// the dependency graph snapshot given with --dependency-graph, if any.
+func newRiskScorer() (policy.RiskScorer, error) {
+	path := viper.GetString("dependency-graph")
+	if path == "" {
+		return policy.NewRiskScorer(nil), nil
+	}
+
+	g, err := graph.LoadSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	return policy.NewRiskScorer(g.ManagedDependents), nil
+}
+
+// compareRisk orders PRs by risk score, riskiest first. PRs without a
+// score sort last.
+func compareRisk(a, b *model.PullRequest) int {
+	return riskScore(b) - riskScore(a)
+}
+
+// riskScore returns the risk score of a PR, or -1 if it has none.
+func riskScore(pr *model.PullRequest) int {
+	if pr.Risk == nil {
+		return -1
+	}
+	return pr.Risk.Score
+}
+
+// compareSeverity orders PRs by the severity of the vulnerabilities they
+// fix, most severe first.
+func compareSeverity(a, b *model.PullRequest) int {
+	return b.Dependency.Severity.Rank() - a.Dependency.Severity.Rank()
+}
+
+// loadPRInfo lists the files changed by a PR and the authors of its
+// commits, which the policy checks against the allowed files and the bot.
+// It lists the PR's approvers if the profile requires approvals, looks up
+// when the new versions were published if the profile has a minimum
+// release age, and finds the vulnerabilities they fix. Release times that
+// can't be looked up are left unknown, which the policy denies.
+func loadPRInfo(ctx context.Context, coll collector.Collector, proxy func() (*goproxy.Client, error), vulns *osv.Database, ref model.RepoRef, pr *model.PullRequest, profile *model.MergeProfile) error {
+	files, err := coll.ListPRFiles(ctx, ref, pr.Number)
+	if err != nil {
+		return fmt.Errorf("failed to list files: %w", err)
+	}
+	collector.SetChangedFiles(pr, files)
+
+	authors, err := coll.ListPRCommitAuthors(ctx, ref, pr.Number)
+	if err != nil {
+		return fmt.Errorf("failed to list commits: %w", err)
+	}
+	pr.CommitAuthors = authors
+
+	if profile.RequiredApprovals > 0 {
+		approvers, err := coll.ListPRApprovers(ctx, ref, pr.Number)
+		if err != nil {
+			return fmt.Errorf("failed to list approvals: %w", err)
+		}
+		pr.Approvers = approvers
+	}
+
+	if proxy != nil && profile.MinReleaseAgeHours > 0 {
+		client, err := proxy()
+		if err != nil {
+			return err
+		}
+		if err := client.SetReleaseTimes(ctx, pr); err != nil && viper.GetBool("verbose") {
+			fmt.Fprintf(os.Stderr, "Error looking up release times for %s#%d: %v\n", ref.FullName(), pr.Number, err)
+		}
+	}
+
+	if vulns != nil {
+		vulns.SetVulnerabilities(pr)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v84/github"
+
+	"github.com/plexusone/versionconductor/internal/collector"
+	"github.com/plexusone/versionconductor/internal/policy"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// rateLimitedCollector serves one dependency PR whose checks are rate
+// limited on the first request.
+type rateLimitedCollector struct {
+	collector.Collector
+
+	checkCalls int
+}
+
+func (c *rateLimitedCollector) GetRepoConfig(context.Context, model.RepoRef) (*model.RepoConfig, error) {
+	return nil, nil
+}
+
+func (c *rateLimitedCollector) GetBranchProtection(context.Context, model.RepoRef, string) (*model.BranchProtection, error) {
+	return nil, nil
+}
+
+func (c *rateLimitedCollector) ListDependencyPRs(_ context.Context, repo model.RepoRef) ([]model.PullRequest, error) {
+	return []model.PullRequest{{Repo: repo, Number: 1, State: "open", IsDependency: true}}, nil
+}
+
+func (c *rateLimitedCollector) GetPRChecks(context.Context, model.RepoRef, int) ([]model.CheckRun, error) {
+	c.checkCalls++
+	if c.checkCalls == 1 {
+		return nil, &github.AbuseRateLimitError{RetryAfter: github.Ptr(time.Millisecond)}
+	}
+	return []model.CheckRun{{Name: "build", Status: "completed", Conclusion: "success"}}, nil
+}
+
+func (c *rateLimitedCollector) ListPRFiles(context.Context, model.RepoRef, int) ([]string, error) {
+	return []string{"go.mod"}, nil
+}
+
+func (c *rateLimitedCollector) ListPRCommitAuthors(context.Context, model.RepoRef, int) ([]string, error) {
+	return []string{"renovate[bot]"}, nil
+}
+
+func TestScanRepos_RetriesPRRateLimit(t *testing.T) {
+	coll := &rateLimitedCollector{}
+	base := policy.ProfileBalanced
+	repos := []model.Repo{{Owner: "example", Name: "repo", FullName: "example/repo"}}
+
+	results := scanRepos(context.Background(), coll, repos, scanOptions{
+		Base:   &base,
+		Filter: func(*model.RepoProfile) model.PRFilter { return model.PRFilter{} },
+	})
+
+	if err := results[0].Err; err != nil {
+		t.Fatalf("expected the repository to be read again after the rate limit, got %v", err)
+	}
+	if coll.checkCalls != 2 {
+		t.Errorf("expected checks to be requested twice, got %d", coll.checkCalls)
+	}
+	prs := results[0].Value.PRs
+	if len(prs) != 1 || prs[0].ChecksErr != nil || len(prs[0].Checks) != 1 {
+		t.Errorf("expected one PR with its checks, got %+v", prs)
+	}
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/spf13/viper"
+
+	"github.com/plexusone/versionconductor/internal/auth"
+	"github.com/plexusone/versionconductor/internal/bitbucket"
+	"github.com/plexusone/versionconductor/internal/collector"
+	"github.com/plexusone/versionconductor/internal/gitea"
+	"github.com/plexusone/versionconductor/internal/gitlab"
+	"github.com/plexusone/versionconductor/internal/goproxy"
+	"github.com/plexusone/versionconductor/internal/merger"
+	"github.com/plexusone/versionconductor/internal/provider"
+	"github.com/plexusone/versionconductor/internal/releaser"
+)
+
+// platforms holds the collector, merger and releaser serving every
+// organization, whichever platform it is hosted on, and the Go module
+// proxy used for release times. The proxy client is only created when
+// first used.
+type platforms struct {
+	Collector collector.Collector
+	Merger    merger.Merger
+	Releaser  releaser.Releaser
+	Proxy     func() (*goproxy.Client, error)
+}
+
+// newPlatforms creates the clients for GitHub (or the server selected
+// with --github-url) and for each entry of the providers config. Requests
+// for an organization listed in a provider go to that provider.
+func newPlatforms() (*platforms, error) {
+	bots, err := dependencyBots()
+	if err != nil {
+		return nil, err
+	}
+	proxy := sync.OnceValues(newGoProxy)
+
+	client, err := newGitHubClient()
+	if err != nil {
+		return nil, err
+	}
+	coll, err := newCollector(client)
+	if err != nil {
+		return nil, err
+	}
+	setBots(coll, bots)
+
+	configs, err := providerConfigs()
+	if err != nil {
+		return nil, err
+	}
+	if len(configs) == 0 {
+		return &platforms{
+			Collector: coll,
+			Merger:    merger.NewGitHubWithClient(client),
+			Releaser:  releaser.NewGitHubWithClient(client),
+			Proxy:     proxy,
+		}, nil
+	}
+
+	collectors := provider.NewRoutes(coll)
+	mergers := provider.NewRoutes(merger.NewGitHubWithClient(client))
+	releasers := provider.NewRoutes(releaser.NewGitHubWithClient(client))
+
+	for _, cfg := range configs {
+		p, err := newPlatform(cfg)
+		if err != nil {
+			return nil, err
+		}
+		setBots(p.Collector, bots)
+		for _, org := range cfg.Orgs {
+			collectors.Add(org, p.Collector)
+			mergers.Add(org, p.Merger)
+			releasers.Add(org, p.Releaser)
+		}
+	}
+
+	return &platforms{
+		Collector: collector.NewRouter(collectors),
+		Merger:    merger.NewRouter(mergers),
+		Releaser:  releaser.NewRouter(releasers),
+		Proxy:     proxy,
+	}, nil
+}
+
+// providerConfigs returns the validated providers config.
+func providerConfigs() ([]provider.Config, error) {
+	var configs []provider.Config
+	if err := viper.UnmarshalKey("providers", &configs); err != nil {
+		return nil, fmt.Errorf("invalid providers config: %w", err)
+	}
+	for _, cfg := range configs {
+		if err := cfg.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid providers config: %w", err)
+		}
+	}
+	return configs, nil
+}
+
+// dependencyBots returns the validated bots config followed by the
+// built-in bots it doesn't replace.
+func dependencyBots() (collector.Bots, error) {
+	var bots collector.Bots
+	if err := viper.UnmarshalKey("bots", &bots); err != nil {
+		return nil, fmt.Errorf("invalid bots config: %w", err)
+	}
+	for _, b := range bots {
+		if err := b.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid bots config: %w", err)
+		}
+	}
+	return bots.WithDefaults(), nil
+}
+
+// setBots sets the dependency bots of a collector that supports them.
+func setBots(coll collector.Collector, bots collector.Bots) {
+	if s, ok := coll.(collector.BotSetter); ok {
+		s.SetBots(bots)
+	}
+}
+
+// providerHost returns the host of a provider's instance, e.g. gitlab.com.
+func providerHost(cfg provider.Config) string {
+	switch {
+	case cfg.URL != "":
+		return auth.Host(cfg.URL)
+	case cfg.Type == provider.GitLab:
+		return auth.Host(gitlab.DefaultURL)
+	case cfg.Type == provider.Bitbucket:
+		return auth.Host(bitbucket.CloudURL)
+	default:
+		return auth.Host(cfg.URL)
+	}
+}
+
+// newPlatform creates the clients for one provider config.
+func newPlatform(cfg provider.Config) (*platforms, error) {
+	authProvider, err := providerAuth(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Type {
+	case provider.GitLab:
+		client, err := gitlab.NewClient(&http.Client{Transport: authProvider.Transport(nil)}, cfg.URL)
+		if err != nil {
+			return nil, err
+		}
+		return &platforms{
+			Collector: collector.NewGitLabCollector(client),
+			Merger:    merger.NewGitLabMerger(client),
+			Releaser:  releaser.NewGitLabReleaser(client),
+		}, nil
+	case provider.Gitea:
+		client, err := gitea.NewClient(&http.Client{Transport: authProvider.Transport(nil)}, cfg.URL)
+		if err != nil {
+			return nil, err
+		}
+		return &platforms{
+			Collector: collector.NewGiteaCollector(client),
+			Merger:    merger.NewGiteaMerger(client),
+			Releaser:  releaser.NewGiteaReleaser(client),
+		}, nil
+	case provider.Bitbucket:
+		client, err := bitbucket.NewClient(&http.Client{Transport: authProvider.Transport(nil)}, cfg.URL)
+		if err != nil {
+			return nil, err
+		}
+		return &platforms{
+			Collector: collector.NewBitbucketCollector(client),
+			Merger:    merger.NewBitbucketMerger(client),
+			Releaser:  releaser.NewBitbucketReleaser(client),
+		}, nil
+	default:
+		client, err := auth.NewEnterpriseClient(authProvider, cfg.URL)
+		if err != nil {
+			return nil, err
+		}
+		coll, err := newCollector(client)
+		if err != nil {
+			return nil, err
+		}
+		return &platforms{
+			Collector: coll,
+			Merger:    merger.NewGitHubWithClient(client),
+			Releaser:  releaser.NewGitHubWithClient(client),
+		}, nil
+	}
+}
+
+// providerAuth returns the authentication for a provider: its own token,
+// or else the credentials configured for its host, which must exist. The
+// default GitHub token is never sent to another provider.
+func providerAuth(cfg provider.Config) (auth.Provider, error) {
+	switch {
+	case cfg.Token != "":
+		return auth.NewTokenProvider(cfg.Token), nil
+	case cfg.TokenCommand != "":
+		return auth.NewCommandProvider(cfg.TokenCommand), nil
+	}
+
+	p, err := newCredentialRouter(nil)
+	if err != nil {
+		return nil, err
+	}
+	host := providerHost(cfg)
+	if router, ok := p.(*auth.Router); ok && !router.Covers("", host) {
+		return nil, fmt.Errorf("%s provider %s: token, tokenCommand or a credentials entry for host %s required", cfg.Type, host, host)
+	}
+	return p, nil
+}
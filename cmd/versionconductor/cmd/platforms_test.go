@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/plexusone/versionconductor/internal/provider"
+)
+
+func TestProviderAuth_RequiresCredential(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	cfg := provider.Config{Type: provider.GitLab, URL: "https://gitlab.example.com", Orgs: []string{"platform"}}
+
+	viper.Set("credentials", []map[string]any{{"orgs": []string{"example"}, "token": "ghp_token"}})
+	_, err := providerAuth(cfg)
+	if err == nil || !strings.Contains(err.Error(), "credentials entry for host gitlab.example.com required") {
+		t.Errorf("expected missing credential error, got %v", err)
+	}
+
+	viper.Set("credentials", []map[string]any{{"host": "gitlab.example.com", "token": "glpat_token"}})
+	if _, err := providerAuth(cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Token = "glpat_own"
+	viper.Set("credentials", nil)
+	if _, err := providerAuth(cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
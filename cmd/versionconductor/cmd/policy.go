@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/plexusone/versionconductor/internal/collector"
+	"github.com/plexusone/versionconductor/internal/policy"
+	"github.com/plexusone/versionconductor/internal/report"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Policy inspection commands",
+	Long:  `Inspect how merge profiles and Cedar policies evaluate dependency PRs.`,
+}
+
+var policyExplainCmd = &cobra.Command{
+	Use:   "explain <owner/repo#number>",
+	Short: "Trace every policy condition evaluated for a PR",
+	Long: `Evaluate a PR against the configured policy and show every condition
+checked, the value seen, the threshold, and whether it passed.
+
+Unlike merge and review, which report only the first failing condition,
+explain reports all of them.
+
+Examples:
+  # Explain the merge decision for a PR
+  versionconductor policy explain myorg/myrepo#123
+
+  # Explain the review decision with the conservative profile
+  versionconductor policy explain myorg/myrepo#123 --action review --profile conservative
+
+  # Explain using Cedar policies
+  versionconductor policy explain myorg/myrepo#123 --policy-dir ./policies --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolicyExplain,
+}
+
+var policyTestCmd = &cobra.Command{
+	Use:   "test <fixtures>",
+	Short: "Run policy test fixtures",
+	Long: `Evaluate policy test fixtures and compare each decision with the
+expected result. Fixtures are YAML or JSON files describing a PR (or a raw
+policy context) and the expected decision; a directory is searched
+recursively.
+
+The command exits non-zero if any fixture fails, so it can be used in CI
+to check policy changes before they are deployed.
+
+Examples:
+  # Run fixtures against the balanced profile
+  versionconductor policy test ./policies/tests
+
+  # Run fixtures against Cedar policies
+  versionconductor policy test ./policies/tests --policy-dir ./policies`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolicyTest,
+}
+
+func init() {
+	rootCmd.AddCommand(policyCmd)
+
+	policyCmd.AddCommand(policyExplainCmd)
+	policyCmd.AddCommand(policyTestCmd)
+
+	policyExplainCmd.Flags().String("action", "merge", "Action to explain: review, merge")
+	policyExplainCmd.Flags().String("profile", "balanced", "Merge profile: aggressive, balanced, conservative, or a YAML profile file")
+
+	_ = viper.BindPFlag("policy.action", policyExplainCmd.Flags().Lookup("action"))
+	_ = viper.BindPFlag("policy.profile", policyExplainCmd.Flags().Lookup("profile"))
+
+	policyTestCmd.Flags().String("profile", "balanced", "Merge profile: aggressive, balanced, conservative, or a YAML profile file")
+
+	_ = viper.BindPFlag("policy.test.profile", policyTestCmd.Flags().Lookup("profile"))
+}
+
+func runPolicyExplain(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	plat, err := newPlatforms()
+	if err != nil {
+		return err
+	}
+
+	ref, number, err := model.ParsePRRef(args[0])
+	if err != nil {
+		return err
+	}
+
+	action := model.PolicyAction(viper.GetString("policy.action"))
+	if action != model.PolicyActionMerge && action != model.PolicyActionReview {
+		return fmt.Errorf("unsupported action: %s", action)
+	}
+
+	profileName := viper.GetString("policy.profile")
+	profile, err := policy.ResolveProfile(profileName)
+	if err != nil {
+		return err
+	}
+
+	engine, err := newPolicyEngine(profile)
+	if err != nil {
+		return err
+	}
+
+	coll := plat.Collector
+
+	repoProfile, err := loadRepoProfile(ctx, coll, ref, engine.Profile())
+	if err != nil {
+		return err
+	}
+	if err := loadRepoProtection(ctx, coll, ref, "", repoProfile); err != nil {
+		return err
+	}
+	engine = engine.WithProfile(&repoProfile.Profile)
+
+	pr, err := coll.GetPRDetails(ctx, ref, number)
+	if err != nil {
+		return fmt.Errorf("failed to get PR: %w", err)
+	}
+
+	checks, err := coll.GetPRChecks(ctx, ref, number)
+	if err != nil {
+		return fmt.Errorf("failed to get checks: %w", err)
+	}
+	pr.TestsPassed = collector.TestsPassed(checks)
+
+	vulns, err := loadVulnDB()
+	if err != nil {
+		return err
+	}
+	if err := loadPRInfo(ctx, coll, plat.Proxy, vulns, ref, pr, &repoProfile.Profile); err != nil {
+		return err
+	}
+
+	trace, err := engine.Explain(ctx, action, pr, checks)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate policy: %w", err)
+	}
+
+	// Generate output
+	format := viper.GetString("format")
+	var formatter report.Formatter
+
+	switch format {
+	case "json":
+		formatter = report.NewJSONFormatter()
+	case "markdown", "md":
+		formatter = report.NewMarkdownFormatter()
+	case "csv":
+		formatter = report.NewCSVFormatter()
+	default:
+		formatter = report.NewTableFormatter()
+	}
+
+	output, err := formatter.FormatPolicyTrace(trace)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+
+	fmt.Print(output)
+
+	return nil
+}
+
+func runPolicyTest(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	profileName := viper.GetString("policy.test.profile")
+	profile, err := policy.ResolveProfile(profileName)
+	if err != nil {
+		return err
+	}
+
+	engine, err := newPolicyEngine(profile)
+	if err != nil {
+		return err
+	}
+
+	fixtures, err := policy.LoadFixtures(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load fixtures: %w", err)
+	}
+
+	if viper.GetBool("verbose") {
+		fmt.Fprintf(os.Stderr, "Running %d fixtures\n", len(fixtures))
+	}
+
+	result := engine.RunFixtures(ctx, fixtures)
+
+	// Generate output
+	format := viper.GetString("format")
+	var formatter report.Formatter
+
+	switch format {
+	case "json":
+		formatter = report.NewJSONFormatter()
+	case "markdown", "md":
+		formatter = report.NewMarkdownFormatter()
+	case "csv":
+		formatter = report.NewCSVFormatter()
+	default:
+		formatter = report.NewTableFormatter()
+	}
+
+	output, err := formatter.FormatPolicyTestReport(result)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+
+	fmt.Print(output)
+
+	if result.FailedCount > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d policy tests failed", result.FailedCount, len(result.Results))
+	}
+
+	return nil
+}
+
+// newPolicyEngine creates a policy engine for the given profile, loading
+// Cedar policies from --policy-dir and applying --merge-window and
+// --freeze-calendar if set.
+func newPolicyEngine(profile *model.MergeProfile) (*policy.Engine, error) {
+	if profile == nil {
+		profile = &policy.ProfileBalanced
+	}
+
+	p := *profile
+	if specs := viper.GetStringSlice("merge-window"); len(specs) > 0 {
+		p.MergeWindows = nil
+		for _, spec := range specs {
+			w, err := policy.ParseMergeWindow(spec)
+			if err != nil {
+				return nil, err
+			}
+			p.MergeWindows = append(p.MergeWindows, w)
+		}
+	}
+	if calendar := viper.GetString("freeze-calendar"); calendar != "" {
+		p.FreezeCalendar = calendar
+	}
+
+	engine, err := policy.NewEngineWithConfig(policy.EngineConfig{
+		Profile:     &p,
+		PolicyPaths: viper.GetStringSlice("policy-dir"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policies: %w", err)
+	}
+
+	scorer, err := newRiskScorer()
+	if err != nil {
+		return nil, err
+	}
+	engine = engine.WithRiskScorer(scorer)
+
+	if viper.GetBool("verbose") && engine.HasCedarPolicies() {
+		fmt.Fprintf(os.Stderr, "Using Cedar policies from %v\n", viper.GetStringSlice("policy-dir"))
+	}
+
+	return engine, nil
+}
+
+// loadRepoProfile reads a repository's in-repo config and returns the
+// effective merge profile after applying it to the base profile.
+func loadRepoProfile(ctx context.Context, coll collector.Collector, ref model.RepoRef, base *model.MergeProfile) (*model.RepoProfile, error) {
+	cfg, err := coll.GetRepoConfig(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", model.RepoConfigPath, err)
+	}
+
+	rp := &model.RepoProfile{
+		Repo:    ref,
+		Profile: *policy.ApplyRepoConfig(base, cfg),
+	}
+	if cfg != nil {
+		rp.ConfigPath = model.RepoConfigPath
+		rp.Disabled = cfg.Disabled
+	}
+
+	return rp, nil
+}
+
+// loadRepoProtection adds the required checks of the repository's branch
+// protection to the repository profile.
+func loadRepoProtection(ctx context.Context, coll collector.Collector, ref model.RepoRef, branch string, rp *model.RepoProfile) error {
+	protection, err := coll.GetBranchProtection(ctx, ref, branch)
+	if err != nil {
+		return fmt.Errorf("failed to read branch protection: %w", err)
+	}
+
+	rp.Protection = protection
+	policy.ApplyBranchProtection(&rp.Profile, protection)
+
+	return nil
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/plexusone/versionconductor/internal/collector"
+	"github.com/plexusone/versionconductor/internal/pipeline"
 	"github.com/plexusone/versionconductor/internal/releaser"
 	"github.com/plexusone/versionconductor/internal/report"
 	"github.com/plexusone/versionconductor/pkg/model"
@@ -63,9 +65,9 @@ func init() {
 func runRelease(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	token := viper.GetString("token")
-	if token == "" {
-		return fmt.Errorf("GitHub token required. Set GITHUB_TOKEN or use --token flag")
+	plat, err := newPlatforms()
+	if err != nil {
+		return err
 	}
 
 	orgs := viper.GetStringSlice("orgs")
@@ -92,9 +94,14 @@ func runRelease(cmd *cobra.Command, args []string) error {
 	minPRs := viper.GetInt("release.min-prs")
 	maxReleases := viper.GetInt("release.max-releases")
 
+	engine, err := newPolicyEngine(nil)
+	if err != nil {
+		return err
+	}
+
 	// Create collector and releaser
-	coll := collector.NewGitHub(token)
-	rel := releaser.NewGitHub(token)
+	coll := plat.Collector
+	rel := plat.Releaser
 
 	// Build filters
 	repoFilter := model.RepoFilter{
@@ -132,55 +139,49 @@ func runRelease(cmd *cobra.Command, args []string) error {
 
 	releaseCount := 0
 
-	for _, repo := range allRepos {
+	// Read each repository's tags and merged PRs concurrently.
+	scans := pipeline.Run(ctx, allRepos, pipelineOptions(), func(ctx context.Context, repo model.Repo) (*releaseScan, error) {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Checking %s...\n", repo.FullName)
+		}
+		return scanRelease(ctx, coll, rel, repo, engine.Profile(), sinceDate)
+	})
+
+	// Releases are created one at a time, in repository order.
+	for _, scan := range scans {
 		if maxReleases > 0 && releaseCount >= maxReleases {
 			break
 		}
 
+		repo := scan.Repo
 		ref := model.RepoRef{Owner: repo.Owner, Name: repo.Name}
+		rs := scan.Value
 
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Checking %s...\n", repo.FullName)
+		if scan.Err != nil {
+			result.Failed = append(result.Failed, model.FailedRelease{
+				Repo:  ref,
+				Error: scan.Err.Error(),
+			})
+			continue
 		}
-
-		// Get latest tag
-		latestTag, err := rel.GetLatestTag(ctx, ref)
-		if err != nil {
+		if rs.Profile.Disabled {
 			result.Skipped = append(result.Skipped, model.SkippedRelease{
 				Repo:   ref,
-				Reason: "no existing semver tags",
+				Reason: "disabled by " + rs.Profile.ConfigPath,
 			})
 			continue
 		}
-
-		// Get merged PRs since last tag
-		mergedPRs, err := coll.GetMergedPRsSinceTag(ctx, ref, latestTag)
-		if err != nil {
-			result.Failed = append(result.Failed, model.FailedRelease{
-				Repo:  ref,
-				Error: fmt.Sprintf("failed to get merged PRs: %v", err),
+		if rs.LatestTag == "" {
+			result.Skipped = append(result.Skipped, model.SkippedRelease{
+				Repo:   ref,
+				Reason: "no existing semver tags",
 			})
 			continue
 		}
 
-		// Filter by date if specified
-		if sinceDate != nil {
-			var filtered []model.PullRequest
-			for _, pr := range mergedPRs {
-				if pr.MergedAt != nil && pr.MergedAt.After(*sinceDate) {
-					filtered = append(filtered, pr)
-				}
-			}
-			mergedPRs = filtered
-		}
-
-		// Filter to only dependency PRs
-		var dependencyPRs []model.PullRequest
-		for _, pr := range mergedPRs {
-			if pr.IsDependency {
-				dependencyPRs = append(dependencyPRs, pr)
-			}
-		}
+		latestTag := rs.LatestTag
+		dependencyPRs := rs.DependencyPRs
+		repoProfile := rs.Profile
 
 		if len(dependencyPRs) < minPRs {
 			result.Skipped = append(result.Skipped, model.SkippedRelease{
@@ -190,6 +191,24 @@ func runRelease(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		// Evaluate release policy
+		decision, err := engine.WithProfile(&repoProfile.Profile).CanRelease(ctx, &repo)
+		if err != nil {
+			result.Failed = append(result.Failed, model.FailedRelease{
+				Repo:  ref,
+				Error: fmt.Sprintf("failed to evaluate policy: %v", err),
+			})
+			continue
+		}
+		if !decision.Allowed {
+			result.Skipped = append(result.Skipped, model.SkippedRelease{
+				Repo:       ref,
+				Reason:     strings.Join(decision.Reasons, "; "),
+				NextWindow: decision.NextWindow,
+			})
+			continue
+		}
+
 		// Calculate next version
 		nextVersion, err := releaser.NextPatchVersion(latestTag)
 		if err != nil {
@@ -276,6 +295,57 @@ func runRelease(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// releaseScan holds what is read about a repository before a release is
+// considered. LatestTag is empty if the repository has no semver tags.
+type releaseScan struct {
+	Profile       *model.RepoProfile
+	LatestTag     string
+	DependencyPRs []model.PullRequest
+}
+
+// scanRelease reads a repository's config, latest tag and the dependency
+// PRs merged since that tag (and since sinceDate, if set).
+func scanRelease(ctx context.Context, coll collector.Collector, rel releaser.Releaser, repo model.Repo, base *model.MergeProfile, sinceDate *time.Time) (*releaseScan, error) {
+	ref := model.RepoRef{Owner: repo.Owner, Name: repo.Name}
+
+	repoProfile, err := loadRepoProfile(ctx, coll, ref, base)
+	if err != nil {
+		return nil, err
+	}
+	rs := &releaseScan{Profile: repoProfile}
+	if repoProfile.Disabled {
+		return rs, nil
+	}
+
+	// Get latest tag
+	latestTag, err := rel.GetLatestTag(ctx, ref)
+	if err != nil {
+		if _, limited := pipeline.RetryAfter(err); limited {
+			return nil, err
+		}
+		return rs, nil
+	}
+	rs.LatestTag = latestTag
+
+	// Get merged PRs since last tag
+	mergedPRs, err := coll.GetMergedPRsSinceTag(ctx, ref, latestTag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merged PRs: %w", err)
+	}
+
+	// Filter to dependency PRs, merged since the date if specified
+	for _, pr := range mergedPRs {
+		if sinceDate != nil && (pr.MergedAt == nil || !pr.MergedAt.After(*sinceDate)) {
+			continue
+		}
+		if pr.IsDependency {
+			rs.DependencyPRs = append(rs.DependencyPRs, pr)
+		}
+	}
+
+	return rs, nil
+}
+
 // generateReleaseBody creates a release body from merged PRs.
 func generateReleaseBody(prs []model.PullRequest) string {
 	if len(prs) == 0 {
@@ -4,13 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	"github.com/plexusone/versionconductor/internal/collector"
-	"github.com/plexusone/versionconductor/internal/merger"
 	"github.com/plexusone/versionconductor/internal/policy"
 	"github.com/plexusone/versionconductor/internal/report"
 	"github.com/plexusone/versionconductor/pkg/model"
@@ -38,10 +37,10 @@ Examples:
 func init() {
 	rootCmd.AddCommand(reviewCmd)
 
-	reviewCmd.Flags().String("profile", "balanced", "Review profile: aggressive, balanced, conservative")
+	reviewCmd.Flags().String("profile", "balanced", "Review profile: aggressive, balanced, conservative, or a YAML profile file")
 	reviewCmd.Flags().Bool("execute", false, "Actually add reviews (default is dry-run)")
 	reviewCmd.Flags().StringSlice("update-type", nil, "Filter by update type: major, minor, patch")
-	reviewCmd.Flags().String("bot", "", "Filter by dependency bot: renovate, dependabot")
+	reviewCmd.Flags().String("bot", "", "Filter by dependency bot: renovate, dependabot, or the name of a configured bot")
 	reviewCmd.Flags().String("review-body", "", "Custom review body message")
 
 	_ = viper.BindPFlag("review.profile", reviewCmd.Flags().Lookup("profile"))
@@ -54,9 +53,9 @@ func init() {
 func runReview(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	token := viper.GetString("token")
-	if token == "" {
-		return fmt.Errorf("GitHub token required. Set GITHUB_TOKEN or use --token flag")
+	plat, err := newPlatforms()
+	if err != nil {
+		return err
 	}
 
 	orgs := viper.GetStringSlice("orgs")
@@ -72,14 +71,24 @@ func runReview(cmd *cobra.Command, args []string) error {
 
 	// Get review profile
 	profileName := viper.GetString("review.profile")
-	profile := policy.GetProfile(profileName)
-	if profile == nil {
-		return fmt.Errorf("unknown profile: %s", profileName)
+	profile, err := policy.ResolveProfile(profileName)
+	if err != nil {
+		return err
+	}
+
+	engine, err := newPolicyEngine(profile)
+	if err != nil {
+		return err
+	}
+
+	vulns, err := loadVulnDB()
+	if err != nil {
+		return err
 	}
 
 	// Create collector and merger (for reviews)
-	coll := collector.NewGitHub(token)
-	merg := merger.NewGitHub(token)
+	coll := plat.Collector
+	merg := plat.Merger
 
 	// Build filters
 	repoFilter := model.RepoFilter{
@@ -134,40 +143,68 @@ func runReview(cmd *cobra.Command, args []string) error {
 		reviewBody = "Automatically approved by VersionConductor. All CI checks have passed."
 	}
 
-	for _, repo := range allRepos {
-		ref := model.RepoRef{Owner: repo.Owner, Name: repo.Name}
-
-		prs, err := coll.ListDependencyPRs(ctx, ref)
-		if err != nil {
+	scans := scanRepos(ctx, coll, allRepos, scanOptions{
+		Base:   engine.Profile(),
+		Filter: func(*model.RepoProfile) model.PRFilter { return prFilter },
+		Proxy:  plat.Proxy,
+		Vulns:  vulns,
+	})
+
+	// Approvals are made one at a time, in repository order.
+	for _, scan := range scans {
+		rs := scan.Value
+		if scan.Err != nil {
+			result.Errors = append(result.Errors, model.ScanError{
+				Repo:    scan.Repo.FullName,
+				Message: scan.Err.Error(),
+			})
+			continue
+		}
+		if rs.Profile.Disabled {
 			if verbose {
-				fmt.Fprintf(os.Stderr, "Error listing PRs for %s: %v\n", repo.FullName, err)
+				fmt.Fprintf(os.Stderr, "Skipping %s: disabled by %s\n", scan.Repo.FullName, rs.Profile.ConfigPath)
 			}
 			continue
 		}
 
-		for _, pr := range prs {
-			if !matchesPRFilter(pr, prFilter) {
+		repoEngine := engine.WithProfile(&rs.Profile.Profile)
+
+		for _, ps := range rs.PRs {
+			pr := ps.PR
+			if rs.ProtectionErr != nil {
+				result.Denied = append(result.Denied, model.DeniedPR{
+					PR:     pr,
+					Reason: rs.ProtectionErr.Error(),
+					Codes:  []model.ReasonCode{model.ReasonProtectionUnavailable},
+				})
 				continue
 			}
-
-			// Get checks
-			checks, err := coll.GetPRChecks(ctx, ref, pr.Number)
-			if err != nil {
-				if verbose {
-					fmt.Fprintf(os.Stderr, "Error getting checks for %s#%d: %v\n", repo.FullName, pr.Number, err)
-				}
+			if code, err := ps.readErr(); err != nil {
+				result.Denied = append(result.Denied, model.DeniedPR{
+					PR:     pr,
+					Reason: err.Error(),
+					Codes:  []model.ReasonCode{code},
+				})
 				continue
 			}
 
-			pr.TestsPassed = collector.TestsPassed(checks)
-
 			// Evaluate for review approval
-			shouldApprove, reason := evaluateForReview(profile, &pr, checks)
-
-			if !shouldApprove {
+			decision, err := repoEngine.CanReview(ctx, &pr, ps.Checks)
+			if err != nil {
 				result.Denied = append(result.Denied, model.DeniedPR{
 					PR:     pr,
-					Reason: reason,
+					Reason: fmt.Sprintf("failed to evaluate policy: %v", err),
+					Codes:  []model.ReasonCode{model.ReasonPolicyError},
+				})
+				continue
+			}
+
+			if !decision.Allowed {
+				result.Denied = append(result.Denied, model.DeniedPR{
+					PR:      pr,
+					Reason:  strings.Join(decision.Reasons, "; "),
+					Codes:   decision.Codes(),
+					Details: decision.Details,
 				})
 				continue
 			}
@@ -175,19 +212,20 @@ func runReview(cmd *cobra.Command, args []string) error {
 			// Approve the PR
 			if dryRun {
 				if verbose {
-					fmt.Fprintf(os.Stderr, "Would approve %s#%d: %s\n", repo.FullName, pr.Number, pr.Title)
+					fmt.Fprintf(os.Stderr, "Would approve %s#%d: %s\n", scan.Repo.FullName, pr.Number, pr.Title)
 				}
 				result.Approved = append(result.Approved, pr)
 			} else {
 				if verbose {
-					fmt.Fprintf(os.Stderr, "Approving %s#%d: %s\n", repo.FullName, pr.Number, pr.Title)
+					fmt.Fprintf(os.Stderr, "Approving %s#%d: %s\n", scan.Repo.FullName, pr.Number, pr.Title)
 				}
 
-				err := merg.ApprovePR(ctx, ref, pr.Number, reviewBody)
+				err := merg.ApprovePR(ctx, rs.Ref, pr.Number, reviewBody)
 				if err != nil {
 					result.Denied = append(result.Denied, model.DeniedPR{
 						PR:     pr,
 						Reason: fmt.Sprintf("failed to approve: %v", err),
+						Codes:  []model.ReasonCode{model.ReasonApprovalFailed},
 					})
 					continue
 				}
@@ -199,6 +237,7 @@ func runReview(cmd *cobra.Command, args []string) error {
 
 	result.ApprovedCount = len(result.Approved)
 	result.DeniedCount = len(result.Denied)
+	result.CountDeniedReasons()
 
 	// Generate output
 	format := viper.GetString("format")
@@ -222,42 +261,3 @@ func runReview(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
-
-// evaluateForReview determines if a PR should receive an approval review.
-func evaluateForReview(profile *model.MergeProfile, pr *model.PullRequest, checks []model.CheckRun) (bool, string) {
-	// Check if tests pass
-	if profile.RequireAllChecks {
-		if !pr.TestsPassed {
-			return false, "CI checks not passed"
-		}
-		// Verify all checks completed successfully
-		for _, check := range checks {
-			if check.Status != "completed" || check.Conclusion != "success" {
-				return false, "not all CI checks passed: " + check.Name
-			}
-		}
-	}
-
-	// Check update type eligibility
-	switch pr.Dependency.UpdateType {
-	case model.UpdateTypeMajor:
-		if !profile.AutoMergeMajor {
-			return false, "major updates require manual review"
-		}
-	case model.UpdateTypeMinor:
-		if !profile.AutoMergeMinor {
-			return false, "minor updates require manual review"
-		}
-	case model.UpdateTypePatch:
-		if !profile.AutoMergePatch {
-			return false, "patch updates require manual review"
-		}
-	}
-
-	// Check if PR is in a reviewable state
-	if pr.Draft {
-		return false, "PR is a draft"
-	}
-
-	return true, ""
-}
@@ -7,6 +7,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/plexusone/versionconductor/internal/collector"
+	"github.com/plexusone/versionconductor/internal/pipeline"
 )
 
 var cfgFile string
@@ -38,17 +41,41 @@ func init() {
 	rootCmd.PersistentFlags().StringSlice("orgs", nil, "GitHub organizations to scan")
 	rootCmd.PersistentFlags().StringSlice("repos", nil, "Specific repositories (owner/repo format)")
 	rootCmd.PersistentFlags().String("token", "", "GitHub token (or set GITHUB_TOKEN env var)")
+	rootCmd.PersistentFlags().String("github-url", "", "GitHub Enterprise Server API URL, e.g. https://ghe.example.com/api/v3 (default: github.com)")
 	rootCmd.PersistentFlags().String("format", "table", "Output format: table, json, markdown, csv")
 	rootCmd.PersistentFlags().Bool("dry-run", false, "Show what would happen without making changes")
 	rootCmd.PersistentFlags().Bool("verbose", false, "Enable verbose output")
+	rootCmd.PersistentFlags().StringSlice("policy-dir", nil, "Cedar policy files or directories (default: use merge profile)")
+	rootCmd.PersistentFlags().StringArray("merge-window", nil, "Allowed merge window, e.g. \"mon-fri 09:00-17:00 Europe/Berlin\" (repeatable)")
+	rootCmd.PersistentFlags().String("freeze-calendar", "", "iCal or YAML file with change freeze periods")
+	rootCmd.PersistentFlags().String("api", collector.APIREST, "GitHub API used to read pull requests: rest, graphql")
+	rootCmd.PersistentFlags().Int("concurrency", pipeline.DefaultConcurrency, "Number of repositories to read in parallel")
+	rootCmd.PersistentFlags().String("goproxy", "", "Go module proxies for release times, in GOPROXY syntax; file:// URLs read a mirror (default: $GOPROXY or proxy.golang.org)")
+	rootCmd.PersistentFlags().String("osv-db", "", "OSV vulnerability database (zip archive or directory from osv.dev) to find security fixes in")
+	rootCmd.PersistentFlags().String("dependency-graph", "", "Dependency graph snapshot from \"graph build --format json\" to count managed dependents in for risk scores")
+	rootCmd.PersistentFlags().Int64("app-id", 0, "GitHub App ID (authenticate as the app instead of with a token)")
+	rootCmd.PersistentFlags().String("app-private-key", "", "GitHub App private key file (PEM)")
+	rootCmd.PersistentFlags().IntSlice("app-installation-ids", nil, "GitHub App installation IDs to use (default: look up per organization)")
 
 	// Bind flags to viper
 	_ = viper.BindPFlag("orgs", rootCmd.PersistentFlags().Lookup("orgs"))
 	_ = viper.BindPFlag("repos", rootCmd.PersistentFlags().Lookup("repos"))
 	_ = viper.BindPFlag("token", rootCmd.PersistentFlags().Lookup("token"))
+	_ = viper.BindPFlag("github-url", rootCmd.PersistentFlags().Lookup("github-url"))
 	_ = viper.BindPFlag("format", rootCmd.PersistentFlags().Lookup("format"))
 	_ = viper.BindPFlag("dry-run", rootCmd.PersistentFlags().Lookup("dry-run"))
 	_ = viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	_ = viper.BindPFlag("policy-dir", rootCmd.PersistentFlags().Lookup("policy-dir"))
+	_ = viper.BindPFlag("merge-window", rootCmd.PersistentFlags().Lookup("merge-window"))
+	_ = viper.BindPFlag("freeze-calendar", rootCmd.PersistentFlags().Lookup("freeze-calendar"))
+	_ = viper.BindPFlag("api", rootCmd.PersistentFlags().Lookup("api"))
+	_ = viper.BindPFlag("concurrency", rootCmd.PersistentFlags().Lookup("concurrency"))
+	_ = viper.BindPFlag("goproxy", rootCmd.PersistentFlags().Lookup("goproxy"))
+	_ = viper.BindPFlag("osv-db", rootCmd.PersistentFlags().Lookup("osv-db"))
+	_ = viper.BindPFlag("dependency-graph", rootCmd.PersistentFlags().Lookup("dependency-graph"))
+	_ = viper.BindPFlag("app.id", rootCmd.PersistentFlags().Lookup("app-id"))
+	_ = viper.BindPFlag("app.private-key", rootCmd.PersistentFlags().Lookup("app-private-key"))
+	_ = viper.BindPFlag("app.installation-ids", rootCmd.PersistentFlags().Lookup("app-installation-ids"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -4,12 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	"github.com/plexusone/versionconductor/internal/collector"
+	"github.com/plexusone/versionconductor/internal/policy"
 	"github.com/plexusone/versionconductor/internal/report"
 	"github.com/plexusone/versionconductor/pkg/model"
 )
@@ -36,6 +37,12 @@ Examples:
   # Filter by update type
   versionconductor scan --orgs myorg --update-type patch,minor
 
+  # Show effective per-repo profiles based on the conservative profile
+  versionconductor scan --orgs myorg --profile conservative
+
+  # List the riskiest PRs first, counting dependents in a graph snapshot
+  versionconductor scan --orgs myorg --sort risk --dependency-graph graph.json
+
   # Output as JSON
   versionconductor scan --orgs myorg --format json`,
 	RunE: runScan,
@@ -44,20 +51,24 @@ Examples:
 func init() {
 	rootCmd.AddCommand(scanCmd)
 
-	scanCmd.Flags().String("bot", "", "Filter by dependency bot: renovate, dependabot")
+	scanCmd.Flags().String("profile", "balanced", "Base merge profile for per-repo overrides: aggressive, balanced, conservative, or a YAML profile file")
+	scanCmd.Flags().String("bot", "", "Filter by dependency bot: renovate, dependabot, or the name of a configured bot")
 	scanCmd.Flags().StringSlice("update-type", nil, "Filter by update type: major, minor, patch")
 	scanCmd.Flags().Int("min-age", 0, "Minimum PR age in hours")
 	scanCmd.Flags().Int("max-age", 0, "Maximum PR age in hours")
 	scanCmd.Flags().Bool("include-archived", false, "Include archived repositories")
 	scanCmd.Flags().Bool("include-private", true, "Include private repositories")
+	scanCmd.Flags().String("sort", "severity", "Sort PRs by: severity (security fixes first), risk (riskiest first)")
 	scanCmd.Flags().String("output", "", "Output file (default: stdout)")
 
+	_ = viper.BindPFlag("scan.profile", scanCmd.Flags().Lookup("profile"))
 	_ = viper.BindPFlag("scan.bot", scanCmd.Flags().Lookup("bot"))
 	_ = viper.BindPFlag("scan.update-type", scanCmd.Flags().Lookup("update-type"))
 	_ = viper.BindPFlag("scan.min-age", scanCmd.Flags().Lookup("min-age"))
 	_ = viper.BindPFlag("scan.max-age", scanCmd.Flags().Lookup("max-age"))
 	_ = viper.BindPFlag("scan.include-archived", scanCmd.Flags().Lookup("include-archived"))
 	_ = viper.BindPFlag("scan.include-private", scanCmd.Flags().Lookup("include-private"))
+	_ = viper.BindPFlag("scan.sort", scanCmd.Flags().Lookup("sort"))
 	_ = viper.BindPFlag("scan.output", scanCmd.Flags().Lookup("output"))
 }
 
@@ -65,9 +76,9 @@ func runScan(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
 	// Get configuration
-	token := viper.GetString("token")
-	if token == "" {
-		return fmt.Errorf("GitHub token required. Set GITHUB_TOKEN or use --token flag")
+	plat, err := newPlatforms()
+	if err != nil {
+		return err
 	}
 
 	orgs := viper.GetStringSlice("orgs")
@@ -79,8 +90,34 @@ func runScan(cmd *cobra.Command, args []string) error {
 
 	verbose := viper.GetBool("verbose")
 
+	profileName := viper.GetString("scan.profile")
+	profile, err := policy.ResolveProfile(profileName)
+	if err != nil {
+		return err
+	}
+
+	var compare func(a, b *model.PullRequest) int
+	switch sortBy := viper.GetString("scan.sort"); sortBy {
+	case "", "severity":
+		compare = compareSeverity
+	case "risk":
+		compare = compareRisk
+	default:
+		return fmt.Errorf("unknown sort order: %s", sortBy)
+	}
+
+	vulns, err := loadVulnDB()
+	if err != nil {
+		return err
+	}
+
+	scorer, err := newRiskScorer()
+	if err != nil {
+		return err
+	}
+
 	// Create collector
-	coll := collector.NewGitHub(token)
+	coll := plat.Collector
 
 	// Build filters
 	repoFilter := model.RepoFilter{
@@ -140,37 +177,49 @@ func runScan(cmd *cobra.Command, args []string) error {
 		ReposScanned: len(allRepos),
 	}
 
-	for _, repo := range allRepos {
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Scanning %s...\n", repo.FullName)
+	scans := scanRepos(ctx, coll, allRepos, scanOptions{
+		Base:   profile,
+		Filter: func(*model.RepoProfile) model.PRFilter { return prFilter },
+		Proxy:  plat.Proxy,
+		Vulns:  vulns,
+		Risk:   scorer,
+	})
+
+	for _, scan := range scans {
+		rs := scan.Value
+		if rs != nil && rs.Profile != nil && rs.Profile.ConfigPath != "" {
+			result.Profiles = append(result.Profiles, *rs.Profile)
 		}
-
-		ref := model.RepoRef{Owner: repo.Owner, Name: repo.Name}
-		prs, err := coll.ListDependencyPRs(ctx, ref)
-		if err != nil {
+		if scan.Err != nil {
 			result.Errors = append(result.Errors, model.ScanError{
-				Repo:    repo.FullName,
-				Message: err.Error(),
+				Repo:    scan.Repo.FullName,
+				Message: scan.Err.Error(),
 			})
 			continue
 		}
-
-		// Apply PR filters
-		for _, pr := range prs {
-			if !matchesPRFilter(pr, prFilter) {
-				continue
+		if rs.Profile.Disabled {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Skipping %s: disabled by %s\n", scan.Repo.FullName, rs.Profile.ConfigPath)
 			}
+			continue
+		}
 
-			// Get check status
-			checks, err := coll.GetPRChecks(ctx, ref, pr.Number)
-			if err == nil {
-				pr.TestsPassed = collector.TestsPassed(checks)
-			}
+		if rs.ProtectionErr != nil {
+			result.Errors = append(result.Errors, model.ScanError{
+				Repo:    scan.Repo.FullName,
+				Message: rs.ProtectionErr.Error(),
+			})
+		}
 
-			result.PRs = append(result.PRs, pr)
+		for _, ps := range rs.PRs {
+			result.PRs = append(result.PRs, ps.PR)
 		}
 	}
 
+	// By default security fixes are listed first, most severe first.
+	slices.SortStableFunc(result.PRs, func(a, b model.PullRequest) int {
+		return compare(&a, &b)
+	})
 	result.PRsFound = len(result.PRs)
 
 	// Generate output
@@ -3,6 +3,8 @@ module github.com/plexusone/versionconductor
 go 1.26.0
 
 require (
+	github.com/bradleyfalzon/ghinstallation/v2 v2.18.0
+	github.com/cedar-policy/cedar-go v1.8.0
 	github.com/google/go-github/v84 v84.0.0
 	github.com/grokify/gogithub v0.12.1
 	github.com/grokify/mogo v0.74.2
@@ -14,6 +16,7 @@ require (
 require (
 	github.com/fsnotify/fsnotify v1.9.0 // indirect
 	github.com/go-viper/mapstructure/v2 v2.5.0 // indirect
+	github.com/golang-jwt/jwt/v4 v4.5.2 // indirect
 	github.com/golang-jwt/jwt/v5 v5.3.1 // indirect
 	github.com/google/go-querystring v1.2.0 // indirect
 	github.com/huandu/xstrings v1.5.0 // indirect
@@ -0,0 +1,166 @@
+// Package apitest provides a stand-in for the REST APIs of code hosting
+// platforms, such as GitLab, Gitea and Bitbucket, in tests of their
+// clients.
+package apitest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/plexusone/versionconductor/internal/bitbucket"
+	"github.com/plexusone/versionconductor/internal/gitea"
+	"github.com/plexusone/versionconductor/internal/gitlab"
+)
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	Path   string // escaped, without the API prefix
+	Query  map[string][]string
+	Body   map[string]any
+}
+
+// Server answers API requests from fixed responses.
+type Server struct {
+	*httptest.Server
+
+	prefix    string
+	mu        sync.Mutex
+	responses map[string]any
+	requests  []Request
+}
+
+// NewServer starts a server answering "METHOD /path" requests, with paths
+// relative to the API prefix (e.g. /api/v4) and escaped as sent (e.g.
+// /projects/group%2Fapp), with the JSON encoding of the response. A
+// response may be a []byte, sent as is, or an int, sent as an error with
+// that status code. Unknown requests get a 404. The server is closed when
+// the test ends.
+func NewServer(t *testing.T, prefix string, responses map[string]any) *Server {
+	t.Helper()
+
+	s := &Server{prefix: prefix, responses: responses}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// NewGitLabServer starts a stand-in for the GitLab REST API v4.
+func NewGitLabServer(t *testing.T, responses map[string]any) *Server {
+	t.Helper()
+	return NewServer(t, "/api/v4", responses)
+}
+
+// GitLabClient returns a GitLab client for the server.
+func (s *Server) GitLabClient(t *testing.T) *gitlab.Client {
+	t.Helper()
+
+	client, err := gitlab.NewClient(s.Server.Client(), s.URL)
+	if err != nil {
+		t.Fatalf("failed to create GitLab client: %v", err)
+	}
+	return client
+}
+
+// NewGiteaServer starts a stand-in for the Gitea API v1.
+func NewGiteaServer(t *testing.T, responses map[string]any) *Server {
+	t.Helper()
+	return NewServer(t, "/api/v1", responses)
+}
+
+// GiteaClient returns a Gitea client for the server.
+func (s *Server) GiteaClient(t *testing.T) *gitea.Client {
+	t.Helper()
+
+	client, err := gitea.NewClient(s.Server.Client(), s.URL)
+	if err != nil {
+		t.Fatalf("failed to create Gitea client: %v", err)
+	}
+	return client
+}
+
+// NewBitbucketCloudServer starts a stand-in for the Bitbucket Cloud API
+// 2.0.
+func NewBitbucketCloudServer(t *testing.T, responses map[string]any) *Server {
+	t.Helper()
+	return NewServer(t, "/2.0", responses)
+}
+
+// NewBitbucketDataCenterServer starts a stand-in for the Bitbucket Data
+// Center REST API, with paths from the server root, such as
+// /rest/api/1.0/projects/PLAT/repos.
+func NewBitbucketDataCenterServer(t *testing.T, responses map[string]any) *Server {
+	t.Helper()
+	return NewServer(t, "", responses)
+}
+
+// BitbucketClient returns a Bitbucket Cloud or Data Center client for the
+// server, depending on how it was started.
+func (s *Server) BitbucketClient(t *testing.T) bitbucket.API {
+	t.Helper()
+
+	client, err := bitbucket.NewClient(s.Server.Client(), s.URL+s.prefix)
+	if err != nil {
+		t.Fatalf("failed to create Bitbucket client: %v", err)
+	}
+	return client
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Find returns the first request with the given method and path.
+func (s *Server) Find(method, path string) (Request, bool) {
+	for _, r := range s.Requests() {
+		if r.Method == method && r.Path == path {
+			return r, true
+		}
+	}
+	return Request{}, false
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	path, ok := strings.CutPrefix(r.URL.EscapedPath(), s.prefix)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	req := Request{Method: r.Method, Path: path, Query: r.URL.Query()}
+	if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+		_ = json.Unmarshal(data, &req.Body)
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	resp, ok := s.responses[r.Method+" "+path]
+	s.mu.Unlock()
+
+	switch v := resp.(type) {
+	case nil:
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"404 Not Found"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case int:
+		w.WriteHeader(v)
+		_ = json.NewEncoder(w).Encode(map[string]string{"message": http.StatusText(v)})
+	case []byte:
+		_, _ = w.Write(v)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
@@ -0,0 +1,244 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/google/go-github/v84/github"
+)
+
+// AppConfig configures GitHub App authentication.
+type AppConfig struct {
+	// AppID is the GitHub App ID.
+	AppID int64
+
+	// PrivateKey is the app's PEM-encoded private key.
+	PrivateKey []byte
+
+	// InstallationIDs limits the provider to these installations. If
+	// empty, the installation of each owner is looked up when needed.
+	InstallationIDs []int64
+
+	// BaseURL is the GitHub API URL. Default is https://api.github.com/.
+	BaseURL string
+
+	// HTTPClient is used for app API requests. Default is http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// AppProvider authenticates requests as a GitHub App installation. Each
+// organization or user the app is installed on has its own installation
+// token, which is created when first needed and refreshed before it
+// expires. Changes made with these tokens are attributed to the app's bot
+// account.
+//
+// Signing the app JWT and refreshing installation tokens is left to
+// ghinstallation. Tokens of different installations are refreshed
+// independently, so a slow refresh for one owner doesn't block requests
+// for the others.
+type AppProvider struct {
+	apps        *ghinstallation.AppsTransport
+	client      *github.Client
+	tokenClient *http.Client
+
+	mu            sync.Mutex
+	configuredIDs []int64
+	resolved      bool
+	installations map[string]int64 // lowercase owner -> installation ID
+	transports    map[int64]*ghinstallation.Transport
+}
+
+// NewAppProvider creates a GitHub App provider.
+func NewAppProvider(cfg AppConfig) (*AppProvider, error) {
+	if cfg.AppID == 0 {
+		return nil, errors.New("GitHub App ID is required")
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	apps, err := ghinstallation.NewAppsTransport(base, cfg.AppID, cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: %w", err)
+	}
+	client := github.NewClient(&http.Client{Transport: apps, Timeout: httpClient.Timeout})
+	if cfg.BaseURL != "" {
+		baseURL, err := url.Parse(strings.TrimSuffix(cfg.BaseURL, "/") + "/")
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub API URL: %w", err)
+		}
+		client.BaseURL = baseURL
+		apps.BaseURL = strings.TrimSuffix(baseURL.String(), "/")
+	}
+
+	return &AppProvider{
+		apps:          apps,
+		client:        client,
+		tokenClient:   &http.Client{Transport: base, Timeout: httpClient.Timeout},
+		configuredIDs: cfg.InstallationIDs,
+		installations: make(map[string]int64),
+		transports:    make(map[int64]*ghinstallation.Transport),
+	}, nil
+}
+
+// NewAppProviderFromFile creates a GitHub App provider reading the private
+// key from a PEM file.
+func NewAppProviderFromFile(cfg AppConfig, keyFile string) (*AppProvider, error) {
+	key, err := os.ReadFile(keyFile) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GitHub App private key: %w", err)
+	}
+	cfg.PrivateKey = key
+	return NewAppProvider(cfg)
+}
+
+// Transport returns a RoundTripper that adds the installation token of
+// the request's owner. The owner is taken from the context (see
+// WithOwner) or from the request path; requests naming no owner use the
+// only installation, if there is exactly one.
+func (p *AppProvider) Transport(base http.RoundTripper) http.RoundTripper {
+	return &bearerTransport{
+		base: base,
+		token: func(req *http.Request) (string, error) {
+			owner := ownerFromContext(req.Context())
+			if owner == "" {
+				owner = ownerFromPath(req.URL.Path)
+			}
+			return p.Token(req.Context(), owner)
+		},
+	}
+}
+
+// Token returns an installation token for the owner, creating or
+// refreshing it if needed.
+func (p *AppProvider) Token(ctx context.Context, owner string) (string, error) {
+	id, err := p.installationID(ctx, owner)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := p.installation(id).Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to create installation token for installation %d: %w", id, err)
+	}
+
+	return token, nil
+}
+
+// installation returns the token source of an installation. The
+// installation's own lock serializes its refreshes.
+func (p *AppProvider) installation(id int64) *ghinstallation.Transport {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	tr, ok := p.transports[id]
+	if !ok {
+		// ghinstallation updates the apps transport it refreshes with, so
+		// each installation gets its own copy.
+		apps := *p.apps
+		tr = ghinstallation.NewFromAppsTransport(&apps, id)
+		tr.Client = p.tokenClient
+		p.transports[id] = tr
+	}
+	return tr
+}
+
+// installationID returns the installation for an owner. Lookups are made
+// without holding p.mu; concurrent lookups of the same owner agree.
+func (p *AppProvider) installationID(ctx context.Context, owner string) (int64, error) {
+	if err := p.resolveConfigured(ctx); err != nil {
+		return 0, err
+	}
+
+	key := strings.ToLower(owner)
+
+	p.mu.Lock()
+	id, ok := p.installations[key]
+	count := len(p.installations)
+	if !ok && owner == "" && count == 1 {
+		for _, only := range p.installations {
+			id, ok = only, true
+		}
+	}
+	p.mu.Unlock()
+
+	if ok {
+		return id, nil
+	}
+	if owner == "" {
+		return 0, errors.New("cannot determine GitHub App installation for request without an owner")
+	}
+	if len(p.configuredIDs) > 0 {
+		return 0, fmt.Errorf("no configured GitHub App installation for %s", owner)
+	}
+
+	inst, resp, err := p.client.Apps.FindOrganizationInstallation(ctx, owner)
+	if err != nil && resp != nil && resp.StatusCode == http.StatusNotFound {
+		inst, _, err = p.client.Apps.FindUserInstallation(ctx, owner)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("GitHub App is not installed for %s: %w", owner, err)
+	}
+
+	p.mu.Lock()
+	p.installations[key] = inst.GetID()
+	p.mu.Unlock()
+
+	return inst.GetID(), nil
+}
+
+// resolveConfigured looks up the account of each configured installation
+// the first time it is called successfully.
+func (p *AppProvider) resolveConfigured(ctx context.Context) error {
+	p.mu.Lock()
+	resolved := p.resolved
+	p.mu.Unlock()
+	if resolved || len(p.configuredIDs) == 0 {
+		return nil
+	}
+
+	accounts := make(map[string]int64, len(p.configuredIDs))
+	for _, id := range p.configuredIDs {
+		inst, _, err := p.client.Apps.GetInstallation(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get GitHub App installation %d: %w", id, err)
+		}
+		accounts[strings.ToLower(inst.GetAccount().GetLogin())] = id
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for account, id := range accounts {
+		p.installations[account] = id
+	}
+	p.resolved = true
+
+	return nil
+}
+
+// ownerFromPath returns the owner named in a REST API path such as
+// /repos/{owner}/{repo}/..., /orgs/{org}/... or /users/{user}/...
+// Any prefix before these segments, such as /api/v3, is skipped.
+func ownerFromPath(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		switch parts[i] {
+		case "repos", "orgs", "users":
+			return parts[i+1]
+		}
+	}
+	return ""
+}
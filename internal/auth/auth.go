@@ -0,0 +1,116 @@
+// Package auth provides GitHub authentication shared by all API clients,
+// using personal access tokens or a GitHub App installation, optionally
+// chosen per organization and host.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v84/github"
+)
+
+// Provider authenticates GitHub API requests.
+type Provider interface {
+	// Transport returns a RoundTripper that authenticates requests and
+	// sends them through base. A nil base uses http.DefaultTransport.
+	Transport(base http.RoundTripper) http.RoundTripper
+}
+
+// NewClient creates a github.com client authenticated by the provider.
+func NewClient(p Provider) *github.Client {
+	return github.NewClient(&http.Client{Transport: p.Transport(nil)})
+}
+
+// NewEnterpriseClient creates a client for the GitHub Enterprise Server
+// at baseURL, such as https://ghe.example.com/api/v3/, authenticated by
+// the provider. An empty baseURL creates a github.com client.
+func NewEnterpriseClient(p Provider, baseURL string) (*github.Client, error) {
+	client := NewClient(p)
+	if baseURL == "" {
+		return client, nil
+	}
+
+	client, err := client.WithEnterpriseURLs(baseURL, baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub URL %q: %w", baseURL, err)
+	}
+	return client, nil
+}
+
+// Host returns the host name of the GitHub instance with the given API
+// URL, e.g. "ghe.example.com" for https://ghe.example.com/api/v3/. It is
+// "github.com" if the URL is empty or that of github.com. Module paths and
+// organization names in the dependency graph are prefixed with it.
+func Host(baseURL string) string {
+	if baseURL == "" {
+		return "github.com"
+	}
+	return normalizeHost(baseURL)
+}
+
+// ownerKey is the context key for the owner a request is made for.
+type ownerKey struct{}
+
+// WithOwner returns a context for requests made on behalf of the given
+// organization or user. Providers that authenticate per owner use it for
+// requests whose URL doesn't name the owner, such as GraphQL queries.
+func WithOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerKey{}, owner)
+}
+
+// ownerFromContext returns the owner set with WithOwner.
+func ownerFromContext(ctx context.Context) string {
+	owner, _ := ctx.Value(ownerKey{}).(string)
+	return owner
+}
+
+// TokenProvider authenticates requests with a static token, such as a
+// personal access token.
+type TokenProvider struct {
+	token string
+}
+
+// NewTokenProvider creates a provider using the given token.
+func NewTokenProvider(token string) *TokenProvider {
+	return &TokenProvider{token: token}
+}
+
+// Transport returns a RoundTripper that adds the token to requests.
+func (p *TokenProvider) Transport(base http.RoundTripper) http.RoundTripper {
+	return &bearerTransport{
+		base: base,
+		token: func(*http.Request) (string, error) {
+			return p.token, nil
+		},
+	}
+}
+
+// bearerTransport sets the Authorization header of each request to a
+// bearer token chosen for the request.
+type bearerTransport struct {
+	base  http.RoundTripper
+	token func(req *http.Request) (string, error)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.token(req)
+	if err != nil {
+		return nil, err
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if token == "" {
+		return base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the caller's request.
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+token)
+	return base.RoundTrip(r)
+}
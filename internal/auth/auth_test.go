@@ -0,0 +1,401 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testKey is an RSA key shared by the tests; generating one is slow.
+var testKey = sync.OnceValue(func() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+})
+
+// testKeyPEM returns testKey as a PKCS#1 PEM block.
+func testKeyPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(testKey()),
+	})
+}
+
+// appTestServer fakes the GitHub App endpoints. Installations maps an
+// installation ID to its account; other requests record the Authorization
+// header they were sent with.
+type appTestServer struct {
+	t             *testing.T
+	installations map[int64]string
+	expiresIn     time.Duration             // lifetime of issued tokens
+	hold          func(installation string) // called before issuing a token
+
+	mu       sync.Mutex
+	issued   int
+	lookups  []string
+	lastAuth map[string]string // owner -> Authorization header of API calls
+}
+
+func (s *appTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) == 4 && parts[0] == "app" && parts[3] == "access_tokens" && s.hold != nil {
+		s.hold(parts[2])
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// App endpoints must be authenticated with the app JWT.
+	if parts[0] == "app" || (len(parts) == 3 && parts[2] == "installation") {
+		if err := verifyAppJWT(r.Header.Get("Authorization")); err != nil {
+			s.t.Errorf("expected app JWT for %s: %v", r.URL.Path, err)
+		}
+	}
+
+	switch {
+	case len(parts) == 4 && parts[0] == "app" && parts[3] == "access_tokens":
+		s.issued++
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"token":      fmt.Sprintf("ghs_%s_%d", parts[2], s.issued),
+			"expires_at": time.Now().Add(s.expiresIn).Format(time.RFC3339),
+		})
+	case len(parts) == 3 && parts[0] == "app" && parts[1] == "installations":
+		for id, account := range s.installations {
+			if fmt.Sprint(id) == parts[2] {
+				_ = json.NewEncoder(w).Encode(map[string]any{"id": id, "account": map[string]any{"login": account}})
+				return
+			}
+		}
+		http.NotFound(w, r)
+	case len(parts) == 3 && parts[2] == "installation":
+		s.lookups = append(s.lookups, parts[0]+"/"+parts[1])
+		for id, account := range s.installations {
+			if strings.EqualFold(account, parts[1]) && (parts[0] == "orgs" || parts[0] == "users") {
+				if parts[0] == "orgs" && account == "octocat" {
+					break // octocat is a user, not an organization
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{"id": id, "account": map[string]any{"login": account}})
+				return
+			}
+		}
+		http.NotFound(w, r)
+	default:
+		owner := ownerFromPath(r.URL.Path)
+		if owner == "" {
+			owner = r.Header.Get("X-Test-Owner")
+		}
+		s.lastAuth[owner] = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte("{}"))
+	}
+}
+
+// verifyAppJWT checks that an Authorization header holds a JWT for app 42
+// signed with testKey.
+func verifyAppJWT(header string) error {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return fmt.Errorf("not a bearer token: %q", header)
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("expected 3 JWT parts, got %d", len(parts))
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&testKey().PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		return fmt.Errorf("invalid JWT signature: %w", err)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return err
+	}
+	var claims struct {
+		IAT int64  `json:"iat"`
+		EXP int64  `json:"exp"`
+		ISS string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return err
+	}
+	if claims.ISS != "42" {
+		return fmt.Errorf("expected issuer 42, got %q", claims.ISS)
+	}
+	if claims.EXP <= claims.IAT || claims.EXP-claims.IAT > 600 {
+		return fmt.Errorf("unexpected JWT lifetime: iat=%d exp=%d", claims.IAT, claims.EXP)
+	}
+	return nil
+}
+
+// newAppTestServer starts a fake GitHub API and returns a provider using it.
+func newAppTestServer(t *testing.T, installations map[int64]string, configured []int64) (*AppProvider, *appTestServer) {
+	t.Helper()
+
+	s := &appTestServer{t: t, installations: installations, expiresIn: time.Hour, lastAuth: make(map[string]string)}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	p, err := NewAppProvider(AppConfig{
+		AppID:           42,
+		PrivateKey:      testKeyPEM(),
+		InstallationIDs: configured,
+		BaseURL:         server.URL,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return p, s
+}
+
+// get sends a GET request through the provider's transport.
+func get(t *testing.T, p Provider, url string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := p.Transport(nil).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestAppProvider_TokenPerOwner(t *testing.T) {
+	p, s := newAppTestServer(t, map[int64]string{1: "example", 2: "octocat"}, nil)
+	base := p.client.BaseURL.String()
+
+	get(t, p, base+"repos/example/repo/pulls")
+	get(t, p, base+"repos/octocat/hello/pulls")
+	get(t, p, base+"repos/Example/other/pulls")
+
+	// Requests without an owner in the path use the owner from the context.
+	req, _ := http.NewRequestWithContext(WithOwner(context.Background(), "octocat"), http.MethodPost, base+"graphql", nil)
+	req.Header.Set("X-Test-Owner", "graphql")
+	resp, err := p.Transport(nil).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	want := map[string]string{
+		"example": "Bearer ghs_1_1",
+		"octocat": "Bearer ghs_2_2",
+		"Example": "Bearer ghs_1_1",
+		"graphql": "Bearer ghs_2_2",
+	}
+	for owner, auth := range want {
+		if got := s.lastAuth[owner]; got != auth {
+			t.Errorf("expected %s to use %q, got %q", owner, auth, got)
+		}
+	}
+	if s.issued != 2 {
+		t.Errorf("expected 2 installation tokens, got %d", s.issued)
+	}
+
+	// octocat is a user, so the organization lookup falls back.
+	wantLookups := []string{"orgs/example", "orgs/octocat", "users/octocat"}
+	if fmt.Sprint(s.lookups) != fmt.Sprint(wantLookups) {
+		t.Errorf("expected lookups %v, got %v", wantLookups, s.lookups)
+	}
+}
+
+func TestAppProvider_Refresh(t *testing.T) {
+	p, s := newAppTestServer(t, map[int64]string{1: "example"}, nil)
+	ctx := context.Background()
+
+	// A token about to expire is replaced on the next call.
+	s.expiresIn = 30 * time.Second
+	first, err := p.Token(ctx, "example")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s.expiresIn = time.Hour
+	refreshed, err := p.Token(ctx, "example")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refreshed == first {
+		t.Error("expected token to be refreshed")
+	}
+
+	if again, _ := p.Token(ctx, "example"); again != refreshed {
+		t.Errorf("expected cached token %q, got %q", refreshed, again)
+	}
+	if s.issued != 2 {
+		t.Errorf("expected 2 installation tokens, got %d", s.issued)
+	}
+}
+
+func TestAppProvider_RefreshDoesNotBlockOtherOwners(t *testing.T) {
+	p, s := newAppTestServer(t, map[int64]string{1: "example", 2: "octocat"}, []int64{1, 2})
+	ctx := context.Background()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	s.hold = func(installation string) {
+		if installation == "1" {
+			close(entered)
+			<-release
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.Token(ctx, "example")
+		done <- err
+	}()
+	<-entered
+
+	// example's token request is still in flight.
+	tctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if _, err := p.Token(tctx, "octocat"); err != nil {
+		t.Errorf("expected octocat token while example refreshes, got %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAppProvider_ConfiguredInstallations(t *testing.T) {
+	p, s := newAppTestServer(t, map[int64]string{1: "example", 2: "other"}, []int64{1})
+	ctx := context.Background()
+
+	if _, err := p.Token(ctx, "example"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Without an owner the single configured installation is used.
+	if _, err := p.Token(ctx, ""); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	_, err := p.Token(ctx, "other")
+	if err == nil || !strings.Contains(err.Error(), "no configured GitHub App installation for other") {
+		t.Errorf("expected error for unconfigured owner, got %v", err)
+	}
+	if len(s.lookups) != 0 {
+		t.Errorf("expected no installation lookups, got %v", s.lookups)
+	}
+}
+
+func TestNewAppProvider_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     AppConfig
+		wantErr string
+	}{
+		{"missing app ID", AppConfig{PrivateKey: testKeyPEM()}, "App ID is required"},
+		{"no PEM", AppConfig{AppID: 1, PrivateKey: []byte("not a key")}, "failed to parse GitHub App private key"},
+		{"bad key", AppConfig{AppID: 1, PrivateKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("x")})}, "failed to parse GitHub App private key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAppProvider(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTokenProvider(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+	}))
+	t.Cleanup(server.Close)
+
+	get(t, NewTokenProvider("secret"), server.URL+"/user")
+	if got != "Bearer secret" {
+		t.Errorf("expected bearer token, got %q", got)
+	}
+}
+
+func TestOwnerFromPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/repos/example/repo/pulls", "example"},
+		{"/api/v3/repos/example/repo", "example"},
+		{"/orgs/example/repos", "example"},
+		{"/users/octocat/repos", "octocat"},
+		{"/graphql", ""},
+		{"/repos", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ownerFromPath(tt.path); got != tt.want {
+			t.Errorf("ownerFromPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestNewEnterpriseClient(t *testing.T) {
+	client, err := NewEnterpriseClient(NewTokenProvider("token"), "https://ghe.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := client.BaseURL.String(); got != "https://ghe.example.com/api/v3/" {
+		t.Errorf("expected enterprise API URL, got %q", got)
+	}
+	if got := client.UploadURL.String(); got != "https://ghe.example.com/api/uploads/" {
+		t.Errorf("expected enterprise upload URL, got %q", got)
+	}
+
+	client, err = NewEnterpriseClient(NewTokenProvider("token"), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := client.BaseURL.String(); got != "https://api.github.com/" {
+		t.Errorf("expected github.com API URL, got %q", got)
+	}
+}
+
+func TestHost(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"", "github.com"},
+		{"https://api.github.com/", "github.com"},
+		{"https://ghe.example.com/api/v3/", "ghe.example.com"},
+		{"https://GHE.example.com:8443/api/v3", "ghe.example.com:8443"},
+		{"https://api.bitbucket.org/2.0", "bitbucket.org"},
+	}
+
+	for _, tt := range tests {
+		if got := Host(tt.url); got != tt.want {
+			t.Errorf("Host(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
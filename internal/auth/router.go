@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Credential assigns a token to organizations, a host, or both.
+type Credential struct {
+	// Orgs are the organizations or users the token is used for. If
+	// empty, the token is used for every owner on Host.
+	Orgs []string `mapstructure:"orgs" yaml:"orgs" json:"orgs"`
+
+	// Host is the host the token is used for, as a host name
+	// (ghe.example.com) or API base URL (https://ghe.example.com/api/v3).
+	// If empty, the token is used for Orgs on any host.
+	Host string `mapstructure:"host" yaml:"host" json:"host"`
+
+	// Token is the token to use.
+	Token string `mapstructure:"token" yaml:"token" json:"token"`
+
+	// TokenCommand is a shell command printing the token to use, such as
+	// "gh auth token --hostname ghe.example.com". It is run once, when the
+	// token is first needed.
+	TokenCommand string `mapstructure:"tokenCommand" yaml:"tokenCommand" json:"tokenCommand"`
+}
+
+// Router authenticates each request with the credential configured for
+// the request's owner and host, falling back to a default provider.
+type Router struct {
+	routes   []route
+	fallback Provider
+}
+
+// route is a credential with its provider.
+type route struct {
+	orgs     map[string]bool // lowercase
+	host     string
+	provider Provider
+}
+
+// NewRouter creates a provider routing requests by owner and host. The
+// fallback is used for requests no credential matches; if nil, such
+// requests fail.
+func NewRouter(creds []Credential, fallback Provider) (*Router, error) {
+	r := &Router{fallback: fallback}
+
+	for i, cred := range creds {
+		if len(cred.Orgs) == 0 && cred.Host == "" {
+			return nil, fmt.Errorf("credential %d: orgs or host required", i+1)
+		}
+
+		rt := route{host: normalizeHost(cred.Host)}
+		if len(cred.Orgs) > 0 {
+			rt.orgs = make(map[string]bool, len(cred.Orgs))
+			for _, org := range cred.Orgs {
+				rt.orgs[strings.ToLower(org)] = true
+			}
+		}
+
+		switch {
+		case cred.Token != "" && cred.TokenCommand != "":
+			return nil, fmt.Errorf("credential %d: token and tokenCommand are mutually exclusive", i+1)
+		case cred.Token != "":
+			rt.provider = NewTokenProvider(cred.Token)
+		case cred.TokenCommand != "":
+			rt.provider = NewCommandProvider(cred.TokenCommand)
+		default:
+			return nil, fmt.Errorf("credential %d: token or tokenCommand required", i+1)
+		}
+
+		r.routes = append(r.routes, rt)
+	}
+
+	return r, nil
+}
+
+// Transport returns a RoundTripper that authenticates each request with
+// the provider chosen for it. The owner is taken from the context (see
+// WithOwner) or from the request path.
+func (r *Router) Transport(base http.RoundTripper) http.RoundTripper {
+	t := &routerTransport{router: r}
+	for _, rt := range r.routes {
+		t.transports = append(t.transports, rt.provider.Transport(base))
+	}
+	if r.fallback != nil {
+		t.fallback = r.fallback.Transport(base)
+	}
+	return t
+}
+
+// Covers reports whether requests for an owner on a host are
+// authenticated, by a matching credential or the fallback. An empty owner
+// stands for requests whose owner isn't known, which only credentials for
+// the whole host match.
+func (r *Router) Covers(owner, host string) bool {
+	return r.fallback != nil || r.match(owner, host) >= 0
+}
+
+// match returns the index of the route for an owner and host, or -1. A
+// route naming both orgs and a host is preferred over one naming only
+// orgs, which is preferred over one naming only a host.
+func (r *Router) match(owner, host string) int {
+	owner = strings.ToLower(owner)
+	host = normalizeHost(host)
+
+	best, bestScore := -1, 0
+	for i, rt := range r.routes {
+		if rt.host != "" && rt.host != host {
+			continue
+		}
+		if rt.orgs != nil && !rt.orgs[owner] {
+			continue
+		}
+
+		score := 1
+		if rt.orgs != nil {
+			score += 2
+		}
+		if rt.host != "" {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// routerTransport sends each request through the transport of its route.
+type routerTransport struct {
+	router     *Router
+	transports []http.RoundTripper
+	fallback   http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *routerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	owner := ownerFromContext(req.Context())
+	if owner == "" {
+		owner = ownerFromPath(req.URL.Path)
+	}
+
+	if i := t.router.match(owner, req.URL.Host); i >= 0 {
+		return t.transports[i].RoundTrip(req)
+	}
+	if t.fallback != nil {
+		return t.fallback.RoundTrip(req)
+	}
+	return nil, fmt.Errorf("no credential configured for %s on %s", owner, req.URL.Host)
+}
+
+// normalizeHost returns the host name of a host or URL. The API hosts of
+// github.com and bitbucket.org are treated as those hosts.
+func normalizeHost(host string) string {
+	if strings.Contains(host, "://") {
+		if u, err := url.Parse(host); err == nil {
+			host = u.Host
+		}
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "/"))
+	switch host {
+	case "api.github.com":
+		return "github.com"
+	case "api.bitbucket.org":
+		return "bitbucket.org"
+	default:
+		return host
+	}
+}
+
+// CommandProvider authenticates requests with a token printed by a shell
+// command, such as a password manager or credential helper.
+type CommandProvider struct {
+	command string
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewCommandProvider creates a provider using the token printed by command.
+func NewCommandProvider(command string) *CommandProvider {
+	return &CommandProvider{command: command}
+}
+
+// Transport returns a RoundTripper that adds the token to requests.
+func (p *CommandProvider) Transport(base http.RoundTripper) http.RoundTripper {
+	return &bearerTransport{
+		base: base,
+		token: func(req *http.Request) (string, error) {
+			return p.Token(req.Context())
+		},
+	}
+}
+
+// Token runs the command on first use and returns the token it printed.
+func (p *CommandProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" {
+		return p.token, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", p.command) // #nosec G204
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("failed to run token command: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("failed to run token command: %w", err)
+	}
+
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("token command printed no token")
+	}
+	p.token = token
+
+	return token, nil
+}
@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouter_Match(t *testing.T) {
+	router, err := NewRouter([]Credential{
+		{Orgs: []string{"Example", "other"}, Token: "orgs"},
+		{Host: "https://ghe.example.com/api/v3", Token: "ghe"},
+		{Orgs: []string{"example"}, Host: "ghe.example.com", Token: "ghe-example"},
+		{Host: "github.com", Token: "public"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		owner string
+		host  string
+		want  int
+	}{
+		{"example", "api.github.com", 0},
+		{"OTHER", "api.github.com", 0},
+		{"example", "ghe.example.com", 2},
+		{"team", "ghe.example.com", 1},
+		{"octocat", "api.github.com", 3},
+		{"octocat", "git.example.org", -1},
+	}
+
+	for _, tt := range tests {
+		if got := router.match(tt.owner, tt.host); got != tt.want {
+			t.Errorf("match(%q, %q) = %d, want %d", tt.owner, tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestRouter_Transport(t *testing.T) {
+	seen := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := ownerFromPath(r.URL.Path)
+		if key == "" {
+			key = r.URL.Path
+		}
+		seen[key] = r.Header.Get("Authorization")
+	}))
+	t.Cleanup(server.Close)
+
+	router, err := NewRouter([]Credential{
+		{Orgs: []string{"example"}, Token: "example-token"},
+		{Orgs: []string{"scripted"}, TokenCommand: "echo scripted-token"},
+	}, NewTokenProvider("default-token"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	get(t, router, server.URL+"/repos/example/repo/pulls")
+	get(t, router, server.URL+"/repos/scripted/repo/pulls")
+	get(t, router, server.URL+"/orgs/unknown/repos")
+
+	req, _ := http.NewRequestWithContext(WithOwner(context.Background(), "example"), http.MethodPost, server.URL+"/graphql", nil)
+	resp, err := router.Transport(nil).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	want := map[string]string{
+		"example":  "Bearer example-token",
+		"scripted": "Bearer scripted-token",
+		"unknown":  "Bearer default-token",
+		"/graphql": "Bearer example-token",
+	}
+	for key, auth := range want {
+		if got := seen[key]; got != auth {
+			t.Errorf("expected %s to use %q, got %q", key, auth, got)
+		}
+	}
+}
+
+func TestRouter_NoFallback(t *testing.T) {
+	router, err := NewRouter([]Credential{{Orgs: []string{"example"}, Token: "token"}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/other/repo", nil)
+	_, err = router.Transport(nil).RoundTrip(req)
+	if err == nil || !strings.Contains(err.Error(), "no credential configured for other on api.github.com") {
+		t.Errorf("expected missing credential error, got %v", err)
+	}
+}
+
+func TestRouter_Covers(t *testing.T) {
+	router, err := NewRouter([]Credential{
+		{Orgs: []string{"example"}, Token: "token"},
+		{Host: "https://gitlab.example.com", Token: "gitlab"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		owner string
+		host  string
+		want  bool
+	}{
+		{"example", "github.com", true},
+		{"other", "github.com", false},
+		{"", "gitlab.example.com", true},
+		{"", "gitea.example.com", false},
+	}
+	for _, tt := range tests {
+		if got := router.Covers(tt.owner, tt.host); got != tt.want {
+			t.Errorf("Covers(%q, %q) = %v, want %v", tt.owner, tt.host, got, tt.want)
+		}
+	}
+
+	withFallback, _ := NewRouter(nil, NewTokenProvider("token"))
+	if !withFallback.Covers("", "gitea.example.com") {
+		t.Error("expected the fallback to cover every host")
+	}
+}
+
+func TestNewRouter_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		cred    Credential
+		wantErr string
+	}{
+		{"no orgs or host", Credential{Token: "t"}, "orgs or host required"},
+		{"no token", Credential{Orgs: []string{"example"}}, "token or tokenCommand required"},
+		{"both tokens", Credential{Orgs: []string{"example"}, Token: "t", TokenCommand: "echo t"}, "mutually exclusive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRouter([]Credential{tt.cred}, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCommandProvider(t *testing.T) {
+	ctx := context.Background()
+
+	p := NewCommandProvider("echo first; echo ignored >&2")
+	token, err := p.Token(ctx)
+	if err != nil || token != "first" {
+		t.Fatalf("expected token first, got %q, %v", token, err)
+	}
+
+	// The command runs only once.
+	p.command = "echo second"
+	if token, _ := p.Token(ctx); token != "first" {
+		t.Errorf("expected cached token, got %q", token)
+	}
+
+	_, err = NewCommandProvider("echo denied >&2; exit 1").Token(ctx)
+	if err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("expected command error with stderr, got %v", err)
+	}
+
+	_, err = NewCommandProvider("true").Token(ctx)
+	if err == nil || !strings.Contains(err.Error(), "printed no token") {
+		t.Errorf("expected empty token error, got %v", err)
+	}
+}
@@ -0,0 +1,276 @@
+// Package bitbucket is a minimal client for Bitbucket Cloud (API 2.0) and
+// Bitbucket Data Center (REST API 1.0), covering the repositories, pull
+// requests, build statuses and tags used by the Bitbucket collector,
+// merger and releaser. Both are served through the API interface, which
+// returns the same types for either.
+//
+// A repository's owner is its workspace on Bitbucket Cloud, and its
+// project key (e.g. "PLAT", or "~jdoe" for a personal project) on
+// Bitbucket Data Center.
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/plexusone/versionconductor/internal/rest"
+)
+
+// CloudURL is the API URL of Bitbucket Cloud.
+const CloudURL = "https://api.bitbucket.org/2.0"
+
+// API is a Bitbucket Cloud or Data Center server.
+type API interface {
+	// ListRepos returns the repositories of a workspace or project.
+	ListRepos(ctx context.Context, owner string) ([]Repository, error)
+
+	// GetRepo returns a repository.
+	GetRepo(ctx context.Context, owner, slug string) (*Repository, error)
+
+	// ListPullRequests returns the pull requests targeting a repository in
+	// a state: OPEN or MERGED. Merged pull requests are the most recently
+	// updated first.
+	ListPullRequests(ctx context.Context, owner, slug, state string) ([]PullRequest, error)
+
+	// GetPullRequest returns a pull request.
+	GetPullRequest(ctx context.Context, owner, slug string, id int) (*PullRequest, error)
+
+	// GetMergeStatus returns whether a pull request can be merged.
+	GetMergeStatus(ctx context.Context, owner, slug string, id int) (*MergeStatus, error)
+
+	// ApprovePullRequest approves a pull request as the authenticated user.
+	ApprovePullRequest(ctx context.Context, owner, slug string, id int) error
+
+	// CommentPullRequest adds a comment to a pull request.
+	CommentPullRequest(ctx context.Context, owner, slug string, id int, text string) error
+
+	// MergePullRequest merges a pull request.
+	MergePullRequest(ctx context.Context, owner, slug string, id int, opts MergeOptions) (*PullRequest, error)
+
+	// ListChangedFiles returns the paths of the files changed by a pull
+	// request, including the old path of renamed files.
+	ListChangedFiles(ctx context.Context, owner, slug string, id int) ([]string, error)
+
+	// ListCommitAuthors returns the author of each commit of a pull
+	// request.
+	ListCommitAuthors(ctx context.Context, owner, slug string, id int) ([]CommitAuthor, error)
+
+	// ListBuildStatuses returns the build statuses of a commit.
+	ListBuildStatuses(ctx context.Context, owner, slug, commit string) ([]BuildStatus, error)
+
+	// GetBranchRestrictions returns the merge requirements of a branch.
+	GetBranchRestrictions(ctx context.Context, owner, slug, branch string) (*BranchRestrictions, error)
+
+	// GetBranch returns a branch.
+	GetBranch(ctx context.Context, owner, slug, branch string) (*Branch, error)
+
+	// DeleteBranch deletes a branch.
+	DeleteBranch(ctx context.Context, owner, slug, branch string) error
+
+	// ListTags returns the tags of a repository.
+	ListTags(ctx context.Context, owner, slug string) ([]Tag, error)
+
+	// GetTag returns a tag, including the date of its commit.
+	GetTag(ctx context.Context, owner, slug, name string) (*Tag, error)
+
+	// CreateTag creates a tag on a commit, annotated if it has a message.
+	CreateTag(ctx context.Context, owner, slug string, opts CreateTagOptions) (*Tag, error)
+
+	// GetRawFile returns the content of a file at ref, a branch, tag or
+	// commit hash.
+	GetRawFile(ctx context.Context, owner, slug, path, ref string) ([]byte, error)
+}
+
+// Repository is a Bitbucket repository.
+type Repository struct {
+	Owner         string
+	Slug          string
+	Name          string
+	Description   string
+	DefaultBranch string
+	Private       bool
+	Fork          bool
+	Language      string
+	UpdatedAt     time.Time
+	URL           string
+}
+
+// PullRequest is a Bitbucket pull request.
+type PullRequest struct {
+	ID           int
+	Version      int // Data Center only, required to merge
+	Title        string
+	Description  string
+	State        string // OPEN, MERGED, DECLINED, SUPERSEDED
+	Author       string
+	Draft        bool
+	URL          string
+	SourceBranch string
+	SourceCommit string
+	TargetBranch string
+	MergeCommit  string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	ClosedAt     *time.Time
+	Approvers    []string // users approving it; Cloud lists them only in GetPullRequest
+}
+
+// MergeStatus tells whether a pull request can be merged.
+type MergeStatus struct {
+	CanMerge   bool
+	Conflicted bool
+	Vetoes     []string // why it can't be merged
+}
+
+// MergeOptions are the parameters of a merge.
+type MergeOptions struct {
+	// Strategy is the merge strategy ID, e.g. "squash". Empty uses the
+	// repository's default.
+	Strategy string
+
+	// Message is the commit message.
+	Message string
+}
+
+// BuildStatus is the status reported by a CI system for a commit.
+type BuildStatus struct {
+	Key   string
+	Name  string
+	State string // SUCCESSFUL, FAILED, INPROGRESS, STOPPED, UNKNOWN
+	URL   string
+}
+
+// BranchRestrictions are the merge requirements of a branch. Restricted
+// is false if there are none.
+type BranchRestrictions struct {
+	Restricted        bool
+	RequiredApprovals int
+	RequiredBuilds    []string // build keys
+}
+
+// Branch is a repository branch.
+type Branch struct {
+	Name   string
+	Commit string
+}
+
+// CommitAuthor is the author of a commit. Login is the account the
+// commit is linked to, or empty if it isn't linked to one.
+type CommitAuthor struct {
+	Login string
+	Name  string
+}
+
+// Tag is a repository tag.
+type Tag struct {
+	Name       string
+	Commit     string
+	Message    string
+	CommitDate time.Time // on Data Center, only set by GetTag
+}
+
+// CreateTagOptions are the parameters of a new tag.
+type CreateTagOptions struct {
+	Name    string
+	Commit  string
+	Message string
+}
+
+// NewClient creates a client for Bitbucket Cloud if baseURL is empty, on
+// bitbucket.org or ends with the API path /2.0, or else for the Data
+// Center server at baseURL, e.g. https://bitbucket.example.com. Requests
+// are sent with httpClient, which must authenticate them, e.g. with an
+// auth.Provider transport adding a bearer token (a Cloud or Data Center
+// access token).
+func NewClient(httpClient *http.Client, baseURL string) (API, error) {
+	if baseURL == "" {
+		baseURL = CloudURL
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid Bitbucket URL %q", baseURL)
+	}
+
+	base := strings.TrimSuffix(u.String(), "/")
+	switch {
+	case u.Host == "bitbucket.org" || u.Host == "api.bitbucket.org":
+		return newCloudClient(httpClient, CloudURL), nil
+	case strings.HasSuffix(base, "/2.0"):
+		return newCloudClient(httpClient, base), nil
+	default:
+		return newServerClient(httpClient, base), nil
+	}
+}
+
+// Error is an error response from the Bitbucket API.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return fmt.Sprintf("Bitbucket API error %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 response.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsForbidden reports whether err is a 401 or 403 response, such as when
+// reading settings that need admin access.
+func IsForbidden(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden)
+}
+
+// newError reads an error response. Bitbucket Cloud reports errors as
+// {"error": {"message": ...}}, Data Center as {"errors": [{"message": ...}]}.
+func newError(resp *http.Response) error {
+	data := rest.ReadError(resp)
+
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	msg := strings.TrimSpace(string(data))
+	if json.Unmarshal(data, &body) == nil {
+		var msgs []string
+		if body.Error.Message != "" {
+			msgs = append(msgs, body.Error.Message)
+		}
+		for _, e := range body.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		if len(msgs) > 0 {
+			msg = strings.Join(msgs, "; ")
+		}
+	}
+	if msg == "" {
+		msg = http.StatusText(resp.StatusCode)
+	}
+
+	return &Error{StatusCode: resp.StatusCode, Message: msg}
+}
+
+// escapePath escapes each segment of a file path.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,234 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		url       string
+		wantCloud bool
+		want      string
+		wantErr   bool
+	}{
+		{"", true, "https://api.bitbucket.org/2.0/", false},
+		{"https://bitbucket.org", true, "https://api.bitbucket.org/2.0/", false},
+		{"https://api.bitbucket.org/2.0/", true, "https://api.bitbucket.org/2.0/", false},
+		{"https://bitbucket.example.com", false, "https://bitbucket.example.com/", false},
+		{"https://example.com/bitbucket/", false, "https://example.com/bitbucket/", false},
+		{"bitbucket.example.com", false, "", true},
+	}
+
+	for _, tt := range tests {
+		c, err := NewClient(nil, tt.url)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NewClient(%q): expected error", tt.url)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NewClient(%q): unexpected error: %v", tt.url, err)
+			continue
+		}
+
+		var base string
+		switch c := c.(type) {
+		case *CloudClient:
+			base = c.api.BaseURL()
+			if !tt.wantCloud {
+				t.Errorf("NewClient(%q): expected Data Center client", tt.url)
+			}
+		case *ServerClient:
+			base = c.api.BaseURL()
+			if tt.wantCloud {
+				t.Errorf("NewClient(%q): expected Cloud client", tt.url)
+			}
+		}
+		if base != tt.want {
+			t.Errorf("NewClient(%q) base URL = %q, want %q", tt.url, base, tt.want)
+		}
+	}
+}
+
+func TestCloudClient_ListPagination(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			fmt.Fprintf(w, `{"values":[{"slug":"tools","full_name":"team/tools","mainbranch":{"name":"main"}}],"next":"%s%s?pagelen=50&page=2"}`,
+				server.URL, r.URL.Path)
+			return
+		}
+		fmt.Fprint(w, `{"values":[{"slug":"infra","full_name":"team/infra","is_private":true,"parent":{"full_name":"other/infra"}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := NewClient(server.Client(), server.URL+"/2.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repos, err := c.ListRepos(context.Background(), "team")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("expected repos from both pages, got %+v", repos)
+	}
+	if repos[0].Owner != "team" || repos[0].DefaultBranch != "main" {
+		t.Errorf("unexpected repo: %+v", repos[0])
+	}
+	if !repos[1].Private || !repos[1].Fork {
+		t.Errorf("expected private fork, got %+v", repos[1])
+	}
+}
+
+func TestServerClient_ListPagination(t *testing.T) {
+	var starts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := r.URL.Query().Get("start")
+		starts = append(starts, start)
+		if start == "0" {
+			fmt.Fprint(w, `{"values":[{"id":1,"title":"Update golang.org/x/net","state":"OPEN","createdDate":1735689600000,`+
+				`"fromRef":{"displayId":"renovate/x-net","latestCommit":"abc123"},"author":{"user":{"name":"renovate-bot"}}}],`+
+				`"isLastPage":false,"nextPageStart":1}`)
+			return
+		}
+		fmt.Fprint(w, `{"values":[{"id":2,"title":"Update golang.org/x/text","state":"OPEN"}],"isLastPage":true}`)
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := NewClient(server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pulls, err := c.ListPullRequests(context.Background(), "PLAT", "tools", "OPEN")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pulls) != 2 || fmt.Sprint(starts) != "[0 1]" {
+		t.Fatalf("expected PRs from both pages, got %+v (starts %v)", pulls, starts)
+	}
+	pr := pulls[0]
+	if pr.Author != "renovate-bot" || pr.SourceBranch != "renovate/x-net" || pr.SourceCommit != "abc123" {
+		t.Errorf("unexpected PR: %+v", pr)
+	}
+	if !pr.CreatedAt.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected created date from epoch milliseconds, got %v", pr.CreatedAt)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/2.0/"):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"type":"error","error":{"message":"Repository not found"}}`)
+		default:
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"errors":[{"message":"The pull request has conflicts"},{"message":"Not approved"}]}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	ctx := context.Background()
+
+	cloud, err := NewClient(server.Client(), server.URL+"/2.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = cloud.GetRepo(ctx, "team", "missing")
+	if !IsNotFound(err) || !strings.Contains(err.Error(), "Repository not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	dc, err := NewClient(server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = dc.CommentPullRequest(ctx, "PLAT", "tools", 1, "LGTM")
+	if IsNotFound(err) || err == nil || !strings.Contains(err.Error(), "has conflicts; Not approved") {
+		t.Errorf("expected conflict error, got %v", err)
+	}
+}
+
+func TestServerClient_MergePullRequest(t *testing.T) {
+	var query, strategy string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `{"id":7,"version":3,"state":"OPEN"}`)
+			return
+		}
+		query = r.URL.RawQuery
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		strategy = body["strategyId"]
+		fmt.Fprint(w, `{"id":7,"version":4,"state":"MERGED","closedDate":1735689600000,"properties":{"mergeCommit":{"id":"def456"}}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := NewClient(server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pr, err := c.MergePullRequest(context.Background(), "PLAT", "tools", 7, MergeOptions{Strategy: "squash"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.State != "MERGED" || pr.MergeCommit != "def456" || pr.ClosedAt == nil {
+		t.Errorf("unexpected merged PR: %+v", pr)
+	}
+	if query != "version=3" || strategy != "squash" {
+		t.Errorf("expected squash merge of version 3, got %q, %q", query, strategy)
+	}
+
+	_, err = c.MergePullRequest(context.Background(), "PLAT", "tools", 7, MergeOptions{Strategy: "octopus"})
+	if err == nil {
+		t.Error("expected error for unsupported strategy")
+	}
+}
+
+func TestServerClient_ApprovePullRequest(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.EscapedPath())
+		switch r.URL.Path {
+		case "/plugins/servlet/applinks/whoami":
+			fmt.Fprint(w, "jane.doe@example.com")
+		case "/rest/api/1.0/users/jane.doe@example.com":
+			fmt.Fprint(w, `{"name":"jane.doe@example.com","slug":"jane.doe_example.com"}`)
+		default:
+			fmt.Fprint(w, `{"status":"APPROVED"}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := NewClient(server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, id := range []int{1, 2} {
+		if err := c.ApprovePullRequest(context.Background(), "PLAT", "tools", id); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	want := []string{
+		"GET /plugins/servlet/applinks/whoami",
+		"GET /rest/api/1.0/users/jane.doe@example.com",
+		"PUT /rest/api/1.0/projects/PLAT/repos/tools/pull-requests/1/participants/jane.doe_example.com",
+		"PUT /rest/api/1.0/projects/PLAT/repos/tools/pull-requests/2/participants/jane.doe_example.com",
+	}
+	if fmt.Sprint(paths) != fmt.Sprint(want) {
+		t.Errorf("expected requests %v, got %v", want, paths)
+	}
+}
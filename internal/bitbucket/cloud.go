@@ -0,0 +1,515 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plexusone/versionconductor/internal/rest"
+)
+
+// cloudPageLen is the page size of Bitbucket Cloud list requests, the
+// maximum allowed for pull requests.
+const cloudPageLen = 50
+
+// CloudClient is a Bitbucket Cloud API 2.0 client.
+type CloudClient struct {
+	api *rest.Client // base URL ends with /2.0/
+}
+
+func newCloudClient(httpClient *http.Client, base string) *CloudClient {
+	return &CloudClient{api: rest.NewClient(httpClient, base+"/", newError)}
+}
+
+type cloudLinks struct {
+	HTML struct {
+		Href string `json:"href"`
+	} `json:"html"`
+}
+
+type cloudRef struct {
+	Name   string `json:"name"`
+	Target struct {
+		Hash string    `json:"hash"`
+		Date time.Time `json:"date"`
+	} `json:"target"`
+	Message string `json:"message"`
+}
+
+type cloudRepo struct {
+	Slug        string    `json:"slug"`
+	Name        string    `json:"name"`
+	FullName    string    `json:"full_name"`
+	Description string    `json:"description"`
+	IsPrivate   bool      `json:"is_private"`
+	Language    string    `json:"language"`
+	UpdatedOn   time.Time `json:"updated_on"`
+	Mainbranch  *struct {
+		Name string `json:"name"`
+	} `json:"mainbranch"`
+	Parent *struct {
+		FullName string `json:"full_name"`
+	} `json:"parent"`
+	Links cloudLinks `json:"links"`
+}
+
+func (r *cloudRepo) convert() Repository {
+	owner, _, _ := strings.Cut(r.FullName, "/")
+	repo := Repository{
+		Owner:       owner,
+		Slug:        r.Slug,
+		Name:        r.Name,
+		Description: r.Description,
+		Private:     r.IsPrivate,
+		Fork:        r.Parent != nil,
+		Language:    r.Language,
+		UpdatedAt:   r.UpdatedOn,
+		URL:         r.Links.HTML.Href,
+	}
+	if r.Mainbranch != nil {
+		repo.DefaultBranch = r.Mainbranch.Name
+	}
+	return repo
+}
+
+type cloudPR struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	Draft       bool   `json:"draft"`
+	Author      struct {
+		DisplayName string `json:"display_name"`
+		Nickname    string `json:"nickname"`
+	} `json:"author"`
+	Source struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+		Commit *struct {
+			Hash string `json:"hash"`
+		} `json:"commit"`
+	} `json:"source"`
+	Destination struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+	} `json:"destination"`
+	MergeCommit *struct {
+		Hash string `json:"hash"`
+	} `json:"merge_commit"`
+	Participants []struct {
+		User struct {
+			DisplayName string `json:"display_name"`
+			Nickname    string `json:"nickname"`
+		} `json:"user"`
+		Approved bool `json:"approved"`
+	} `json:"participants"`
+	CreatedOn time.Time  `json:"created_on"`
+	UpdatedOn time.Time  `json:"updated_on"`
+	Links     cloudLinks `json:"links"`
+}
+
+func (p *cloudPR) convert() *PullRequest {
+	pr := &PullRequest{
+		ID:           p.ID,
+		Title:        p.Title,
+		Description:  p.Description,
+		State:        p.State,
+		Author:       p.Author.Nickname,
+		Draft:        p.Draft,
+		URL:          p.Links.HTML.Href,
+		SourceBranch: p.Source.Branch.Name,
+		TargetBranch: p.Destination.Branch.Name,
+		CreatedAt:    p.CreatedOn,
+		UpdatedAt:    p.UpdatedOn,
+	}
+	if pr.Author == "" {
+		pr.Author = p.Author.DisplayName
+	}
+	if p.Source.Commit != nil {
+		pr.SourceCommit = p.Source.Commit.Hash
+	}
+	if p.MergeCommit != nil {
+		pr.MergeCommit = p.MergeCommit.Hash
+	}
+	for _, part := range p.Participants {
+		if !part.Approved {
+			continue
+		}
+		name := part.User.Nickname
+		if name == "" {
+			name = part.User.DisplayName
+		}
+		pr.Approvers = append(pr.Approvers, name)
+	}
+	// Pull requests have no close date, and can't change once closed.
+	if p.State != "OPEN" {
+		closed := p.UpdatedOn
+		pr.ClosedAt = &closed
+	}
+	return pr
+}
+
+// cloudMergeStrategies maps merge strategies to Bitbucket Cloud's.
+var cloudMergeStrategies = map[string]string{
+	"merge":  "merge_commit",
+	"squash": "squash",
+	"rebase": "rebase_fast_forward",
+}
+
+// cloudRepoPath returns the API path of a repository.
+func cloudRepoPath(workspace, slug string) string {
+	return "repositories/" + url.PathEscape(workspace) + "/" + url.PathEscape(slug)
+}
+
+// cloudPullPath returns the API path of a pull request.
+func cloudPullPath(workspace, slug string, id int) string {
+	return cloudRepoPath(workspace, slug) + "/pullrequests/" + strconv.Itoa(id)
+}
+
+// ListRepos returns the repositories of a workspace.
+func (c *CloudClient) ListRepos(ctx context.Context, workspace string) ([]Repository, error) {
+	repos, err := cloudList[cloudRepo](ctx, c, "repositories/"+url.PathEscape(workspace), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Repository, 0, len(repos))
+	for i := range repos {
+		result = append(result, repos[i].convert())
+	}
+	return result, nil
+}
+
+// GetRepo returns a repository.
+func (c *CloudClient) GetRepo(ctx context.Context, workspace, slug string) (*Repository, error) {
+	var r cloudRepo
+	if _, err := c.api.Do(ctx, http.MethodGet, cloudRepoPath(workspace, slug), nil, nil, &r); err != nil {
+		return nil, err
+	}
+	repo := r.convert()
+	return &repo, nil
+}
+
+// ListPullRequests returns the pull requests of a repository in a state.
+func (c *CloudClient) ListPullRequests(ctx context.Context, workspace, slug, state string) ([]PullRequest, error) {
+	query := url.Values{"state": {state}, "sort": {"-updated_on"}}
+	pulls, err := cloudList[cloudPR](ctx, c, cloudRepoPath(workspace, slug)+"/pullrequests", query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PullRequest, 0, len(pulls))
+	for i := range pulls {
+		result = append(result, *pulls[i].convert())
+	}
+	return result, nil
+}
+
+// GetPullRequest returns a pull request.
+func (c *CloudClient) GetPullRequest(ctx context.Context, workspace, slug string, id int) (*PullRequest, error) {
+	var pr cloudPR
+	if _, err := c.api.Do(ctx, http.MethodGet, cloudPullPath(workspace, slug, id), nil, nil, &pr); err != nil {
+		return nil, err
+	}
+	return pr.convert(), nil
+}
+
+// GetMergeStatus returns whether a pull request can be merged. Bitbucket
+// Cloud doesn't report it, so an open pull request can be merged unless
+// its diff has conflicts. Merge checks are enforced by the merge itself.
+func (c *CloudClient) GetMergeStatus(ctx context.Context, workspace, slug string, id int) (*MergeStatus, error) {
+	pr, err := c.GetPullRequest(ctx, workspace, slug, id)
+	if err != nil {
+		return nil, err
+	}
+	if pr.State != "OPEN" {
+		return &MergeStatus{Vetoes: []string{"pull request is " + strings.ToLower(pr.State)}}, nil
+	}
+
+	type diffStat struct {
+		Status string `json:"status"`
+	}
+	stats, err := cloudList[diffStat](ctx, c, cloudPullPath(workspace, slug, id)+"/diffstat", nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stats {
+		if s.Status == "merge conflict" {
+			return &MergeStatus{Conflicted: true, Vetoes: []string{"merge conflicts"}}, nil
+		}
+	}
+
+	return &MergeStatus{CanMerge: true}, nil
+}
+
+// ListChangedFiles returns the paths of the files changed by a pull
+// request, read from its diffstat.
+func (c *CloudClient) ListChangedFiles(ctx context.Context, workspace, slug string, id int) ([]string, error) {
+	type file struct {
+		Path string `json:"path"`
+	}
+	type diffStat struct {
+		Old *file `json:"old"`
+		New *file `json:"new"`
+	}
+	stats, err := cloudList[diffStat](ctx, c, cloudPullPath(workspace, slug, id)+"/diffstat", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, s := range stats {
+		if s.New != nil {
+			paths = append(paths, s.New.Path)
+		}
+		if s.Old != nil && (s.New == nil || s.Old.Path != s.New.Path) {
+			paths = append(paths, s.Old.Path)
+		}
+	}
+	return paths, nil
+}
+
+// ListCommitAuthors returns the author of each commit of a pull request.
+func (c *CloudClient) ListCommitAuthors(ctx context.Context, workspace, slug string, id int) ([]CommitAuthor, error) {
+	type commit struct {
+		Author struct {
+			Raw  string `json:"raw"` // "Name <email>"
+			User *struct {
+				Nickname    string `json:"nickname"`
+				DisplayName string `json:"display_name"`
+			} `json:"user"`
+		} `json:"author"`
+	}
+	commits, err := cloudList[commit](ctx, c, cloudPullPath(workspace, slug, id)+"/commits", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	authors := make([]CommitAuthor, 0, len(commits))
+	for _, cm := range commits {
+		a := cm.Author
+		name, _, _ := strings.Cut(a.Raw, "<")
+		author := CommitAuthor{Name: strings.TrimSpace(name)}
+		if a.User != nil {
+			author.Login = a.User.Nickname
+			if author.Login == "" {
+				author.Login = a.User.DisplayName
+			}
+		}
+		authors = append(authors, author)
+	}
+	return authors, nil
+}
+
+// ApprovePullRequest approves a pull request.
+func (c *CloudClient) ApprovePullRequest(ctx context.Context, workspace, slug string, id int) error {
+	_, err := c.api.Do(ctx, http.MethodPost, cloudPullPath(workspace, slug, id)+"/approve", nil, nil, nil)
+	return err
+}
+
+// CommentPullRequest adds a comment to a pull request.
+func (c *CloudClient) CommentPullRequest(ctx context.Context, workspace, slug string, id int, text string) error {
+	body := map[string]any{"content": map[string]string{"raw": text}}
+	_, err := c.api.Do(ctx, http.MethodPost, cloudPullPath(workspace, slug, id)+"/comments", nil, body, nil)
+	return err
+}
+
+// MergePullRequest merges a pull request, keeping its source branch.
+// Strategies merge, squash and rebase map to merge_commit, squash and
+// rebase_fast_forward.
+func (c *CloudClient) MergePullRequest(ctx context.Context, workspace, slug string, id int, opts MergeOptions) (*PullRequest, error) {
+	body := map[string]any{"close_source_branch": false}
+	if opts.Strategy != "" {
+		strategy, ok := cloudMergeStrategies[opts.Strategy]
+		if !ok {
+			return nil, fmt.Errorf("unsupported merge strategy %q", opts.Strategy)
+		}
+		body["merge_strategy"] = strategy
+	}
+	if opts.Message != "" {
+		body["message"] = opts.Message
+	}
+
+	var pr cloudPR
+	if _, err := c.api.Do(ctx, http.MethodPost, cloudPullPath(workspace, slug, id)+"/merge", nil, body, &pr); err != nil {
+		return nil, err
+	}
+	return pr.convert(), nil
+}
+
+// ListBuildStatuses returns the build statuses of a commit.
+func (c *CloudClient) ListBuildStatuses(ctx context.Context, workspace, slug, commit string) ([]BuildStatus, error) {
+	type status struct {
+		Key   string `json:"key"`
+		Name  string `json:"name"`
+		State string `json:"state"`
+		URL   string `json:"url"`
+	}
+	statuses, err := cloudList[status](ctx, c, cloudRepoPath(workspace, slug)+"/commit/"+url.PathEscape(commit)+"/statuses", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]BuildStatus, 0, len(statuses))
+	for _, s := range statuses {
+		result = append(result, BuildStatus(s))
+	}
+	return result, nil
+}
+
+// GetBranchRestrictions returns the merge checks of the branch
+// restrictions matching a branch by glob pattern. Bitbucket Cloud
+// requires passing builds without naming them, so RequiredBuilds is
+// empty. Reading restrictions needs admin access to the repository.
+func (c *CloudClient) GetBranchRestrictions(ctx context.Context, workspace, slug, branch string) (*BranchRestrictions, error) {
+	type restriction struct {
+		Kind            string `json:"kind"`
+		BranchMatchKind string `json:"branch_match_kind"`
+		Pattern         string `json:"pattern"`
+		Value           *int   `json:"value"`
+	}
+	restrictions, err := cloudList[restriction](ctx, c, cloudRepoPath(workspace, slug)+"/branch-restrictions", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BranchRestrictions{}
+	for _, r := range restrictions {
+		if r.BranchMatchKind != "glob" {
+			continue
+		}
+		if ok, _ := path.Match(r.Pattern, branch); !ok {
+			continue
+		}
+		switch r.Kind {
+		case "require_approvals_to_merge", "require_default_reviewer_approvals_to_merge":
+			result.Restricted = true
+			if r.Value != nil && *r.Value > result.RequiredApprovals {
+				result.RequiredApprovals = *r.Value
+			}
+		case "require_passing_builds_to_merge", "require_all_dependencies_merged",
+			"require_tasks_to_be_completed", "require_no_changes_requested":
+			result.Restricted = true
+		}
+	}
+	return result, nil
+}
+
+// GetBranch returns a branch.
+func (c *CloudClient) GetBranch(ctx context.Context, workspace, slug, branch string) (*Branch, error) {
+	var ref cloudRef
+	if _, err := c.api.Do(ctx, http.MethodGet, cloudRepoPath(workspace, slug)+"/refs/branches/"+url.PathEscape(branch), nil, nil, &ref); err != nil {
+		return nil, err
+	}
+	return &Branch{Name: ref.Name, Commit: ref.Target.Hash}, nil
+}
+
+// DeleteBranch deletes a branch.
+func (c *CloudClient) DeleteBranch(ctx context.Context, workspace, slug, branch string) error {
+	_, err := c.api.Do(ctx, http.MethodDelete, cloudRepoPath(workspace, slug)+"/refs/branches/"+url.PathEscape(branch), nil, nil, nil)
+	return err
+}
+
+// ListTags returns the tags of a repository.
+func (c *CloudClient) ListTags(ctx context.Context, workspace, slug string) ([]Tag, error) {
+	refs, err := cloudList[cloudRef](ctx, c, cloudRepoPath(workspace, slug)+"/refs/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Tag, 0, len(refs))
+	for _, r := range refs {
+		result = append(result, convertCloudTag(r))
+	}
+	return result, nil
+}
+
+// GetTag returns a tag.
+func (c *CloudClient) GetTag(ctx context.Context, workspace, slug, name string) (*Tag, error) {
+	var ref cloudRef
+	if _, err := c.api.Do(ctx, http.MethodGet, cloudRepoPath(workspace, slug)+"/refs/tags/"+url.PathEscape(name), nil, nil, &ref); err != nil {
+		return nil, err
+	}
+	t := convertCloudTag(ref)
+	return &t, nil
+}
+
+// CreateTag creates a tag.
+func (c *CloudClient) CreateTag(ctx context.Context, workspace, slug string, opts CreateTagOptions) (*Tag, error) {
+	body := map[string]any{
+		"name":   opts.Name,
+		"target": map[string]string{"hash": opts.Commit},
+	}
+	if opts.Message != "" {
+		body["message"] = opts.Message
+	}
+
+	var ref cloudRef
+	if _, err := c.api.Do(ctx, http.MethodPost, cloudRepoPath(workspace, slug)+"/refs/tags", nil, body, &ref); err != nil {
+		return nil, err
+	}
+	t := convertCloudTag(ref)
+	return &t, nil
+}
+
+// GetRawFile returns the content of a file at ref.
+func (c *CloudClient) GetRawFile(ctx context.Context, workspace, slug, filePath, ref string) ([]byte, error) {
+	var data []byte
+	p := cloudRepoPath(workspace, slug) + "/src/" + url.PathEscape(ref) + "/" + escapePath(filePath)
+	if _, err := c.api.Do(ctx, http.MethodGet, p, nil, nil, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func convertCloudTag(r cloudRef) Tag {
+	return Tag{
+		Name:       r.Name,
+		Commit:     r.Target.Hash,
+		Message:    strings.TrimSpace(r.Message),
+		CommitDate: r.Target.Date,
+	}
+}
+
+// cloudList fetches all pages of a list endpoint, following the next page
+// URL of each page.
+func cloudList[T any](ctx context.Context, c *CloudClient, p string, query url.Values) ([]T, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("pagelen", strconv.Itoa(cloudPageLen))
+
+	var all []T
+	for {
+		var page struct {
+			Values []T    `json:"values"`
+			Next   string `json:"next"`
+		}
+		if _, err := c.api.Do(ctx, http.MethodGet, p, query, nil, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Values...)
+
+		if page.Next == "" {
+			break
+		}
+		next, err := url.Parse(page.Next)
+		if err != nil {
+			return nil, fmt.Errorf("invalid next page URL %q: %w", page.Next, err)
+		}
+		rel, ok := strings.CutPrefix(next.Scheme+"://"+next.Host+next.EscapedPath(), c.api.BaseURL())
+		if !ok {
+			return nil, fmt.Errorf("unexpected next page URL %q", page.Next)
+		}
+		p, query = rel, next.Query()
+	}
+
+	return all, nil
+}
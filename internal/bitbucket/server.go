@@ -0,0 +1,564 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/plexusone/versionconductor/internal/rest"
+)
+
+// serverPageLimit is the page size of Bitbucket Data Center list requests.
+const serverPageLimit = 100
+
+// ServerClient is a Bitbucket Data Center (or Server) REST API client.
+type ServerClient struct {
+	api *rest.Client // base URL is the server root, ending with /
+
+	mu       sync.Mutex
+	userSlug string // of the authenticated user, once known
+}
+
+func newServerClient(httpClient *http.Client, base string) *ServerClient {
+	return &ServerClient{api: rest.NewClient(httpClient, base+"/", newError)}
+}
+
+type serverRef struct {
+	ID           string `json:"id"`
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+}
+
+type serverLinks struct {
+	Self []struct {
+		Href string `json:"href"`
+	} `json:"self"`
+}
+
+func (l serverLinks) href() string {
+	if len(l.Self) == 0 {
+		return ""
+	}
+	return l.Self[0].Href
+}
+
+type serverRepo struct {
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Public      bool   `json:"public"`
+	Origin      *struct {
+		Slug string `json:"slug"`
+	} `json:"origin"`
+	Project struct {
+		Key string `json:"key"`
+	} `json:"project"`
+	Links serverLinks `json:"links"`
+}
+
+func (r *serverRepo) convert() Repository {
+	return Repository{
+		Owner:       r.Project.Key,
+		Slug:        r.Slug,
+		Name:        r.Name,
+		Description: r.Description,
+		Private:     !r.Public,
+		Fork:        r.Origin != nil,
+		URL:         r.Links.href(),
+	}
+}
+
+type serverPR struct {
+	ID          int    `json:"id"`
+	Version     int    `json:"version"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	Draft       bool   `json:"draft"`
+	Author      struct {
+		User struct {
+			Name string `json:"name"`
+		} `json:"user"`
+	} `json:"author"`
+	Reviewers []struct {
+		User struct {
+			Name string `json:"name"`
+		} `json:"user"`
+		Approved bool `json:"approved"`
+	} `json:"reviewers"`
+	FromRef     serverRef `json:"fromRef"`
+	ToRef       serverRef `json:"toRef"`
+	CreatedDate int64     `json:"createdDate"`
+	UpdatedDate int64     `json:"updatedDate"`
+	ClosedDate  int64     `json:"closedDate"`
+	Properties  struct {
+		MergeCommit *struct {
+			ID string `json:"id"`
+		} `json:"mergeCommit"`
+	} `json:"properties"`
+	Links serverLinks `json:"links"`
+}
+
+func (p *serverPR) convert() *PullRequest {
+	pr := &PullRequest{
+		ID:           p.ID,
+		Version:      p.Version,
+		Title:        p.Title,
+		Description:  p.Description,
+		State:        p.State,
+		Author:       p.Author.User.Name,
+		Draft:        p.Draft,
+		URL:          p.Links.href(),
+		SourceBranch: p.FromRef.DisplayID,
+		SourceCommit: p.FromRef.LatestCommit,
+		TargetBranch: p.ToRef.DisplayID,
+		CreatedAt:    time.UnixMilli(p.CreatedDate),
+		UpdatedAt:    time.UnixMilli(p.UpdatedDate),
+	}
+	if p.ClosedDate != 0 {
+		closed := time.UnixMilli(p.ClosedDate)
+		pr.ClosedAt = &closed
+	}
+	if p.Properties.MergeCommit != nil {
+		pr.MergeCommit = p.Properties.MergeCommit.ID
+	}
+	for _, r := range p.Reviewers {
+		if r.Approved {
+			pr.Approvers = append(pr.Approvers, r.User.Name)
+		}
+	}
+	return pr
+}
+
+type serverTag struct {
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+}
+
+// serverMergeStrategies maps merge strategies to Bitbucket Data Center's.
+var serverMergeStrategies = map[string]string{
+	"merge":  "no-ff",
+	"squash": "squash",
+	"rebase": "rebase-ff-only",
+}
+
+// serverRepoPath returns the path of a repository under an API root, such
+// as rest/api/1.0.
+func serverRepoPath(api, project, slug string) string {
+	return api + "/projects/" + url.PathEscape(project) + "/repos/" + url.PathEscape(slug)
+}
+
+// serverPullPath returns the API path of a pull request.
+func serverPullPath(project, slug string, id int) string {
+	return serverRepoPath("rest/api/1.0", project, slug) + "/pull-requests/" + strconv.Itoa(id)
+}
+
+// ListRepos returns the repositories of a project.
+func (c *ServerClient) ListRepos(ctx context.Context, project string) ([]Repository, error) {
+	repos, err := serverList[serverRepo](ctx, c, "rest/api/1.0/projects/"+url.PathEscape(project)+"/repos", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Repository, 0, len(repos))
+	for i := range repos {
+		result = append(result, repos[i].convert())
+	}
+	return result, nil
+}
+
+// GetRepo returns a repository, with its default branch.
+func (c *ServerClient) GetRepo(ctx context.Context, project, slug string) (*Repository, error) {
+	p := serverRepoPath("rest/api/1.0", project, slug)
+
+	var r serverRepo
+	if _, err := c.api.Do(ctx, http.MethodGet, p, nil, nil, &r); err != nil {
+		return nil, err
+	}
+	repo := r.convert()
+
+	var branch serverRef
+	_, err := c.api.Do(ctx, http.MethodGet, p+"/default-branch", nil, nil, &branch)
+	switch {
+	case err == nil:
+		repo.DefaultBranch = branch.DisplayID
+	case !IsNotFound(err): // empty repositories have none
+		return nil, fmt.Errorf("failed to get default branch: %w", err)
+	}
+
+	return &repo, nil
+}
+
+// ListPullRequests returns the pull requests of a repository in a state.
+func (c *ServerClient) ListPullRequests(ctx context.Context, project, slug, state string) ([]PullRequest, error) {
+	query := url.Values{"state": {state}, "order": {"NEWEST"}}
+	pulls, err := serverList[serverPR](ctx, c, serverRepoPath("rest/api/1.0", project, slug)+"/pull-requests", query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PullRequest, 0, len(pulls))
+	for i := range pulls {
+		result = append(result, *pulls[i].convert())
+	}
+	return result, nil
+}
+
+// GetPullRequest returns a pull request.
+func (c *ServerClient) GetPullRequest(ctx context.Context, project, slug string, id int) (*PullRequest, error) {
+	var pr serverPR
+	if _, err := c.api.Do(ctx, http.MethodGet, serverPullPath(project, slug, id), nil, nil, &pr); err != nil {
+		return nil, err
+	}
+	return pr.convert(), nil
+}
+
+// GetMergeStatus returns whether a pull request can be merged, including
+// its merge checks, such as required builds and approvals.
+func (c *ServerClient) GetMergeStatus(ctx context.Context, project, slug string, id int) (*MergeStatus, error) {
+	var status struct {
+		CanMerge   bool `json:"canMerge"`
+		Conflicted bool `json:"conflicted"`
+		Vetoes     []struct {
+			SummaryMessage string `json:"summaryMessage"`
+		} `json:"vetoes"`
+	}
+	if _, err := c.api.Do(ctx, http.MethodGet, serverPullPath(project, slug, id)+"/merge", nil, nil, &status); err != nil {
+		return nil, err
+	}
+
+	result := &MergeStatus{CanMerge: status.CanMerge, Conflicted: status.Conflicted}
+	for _, v := range status.Vetoes {
+		result.Vetoes = append(result.Vetoes, v.SummaryMessage)
+	}
+	return result, nil
+}
+
+// ListChangedFiles returns the paths of the files changed by a pull
+// request.
+func (c *ServerClient) ListChangedFiles(ctx context.Context, project, slug string, id int) ([]string, error) {
+	type path struct {
+		ToString string `json:"toString"`
+	}
+	type change struct {
+		Path    path  `json:"path"`
+		SrcPath *path `json:"srcPath"`
+	}
+	changes, err := serverList[change](ctx, c, serverPullPath(project, slug, id)+"/changes", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, ch := range changes {
+		paths = append(paths, ch.Path.ToString)
+		if ch.SrcPath != nil && ch.SrcPath.ToString != ch.Path.ToString {
+			paths = append(paths, ch.SrcPath.ToString)
+		}
+	}
+	return paths, nil
+}
+
+// ListCommitAuthors returns the author of each commit of a pull request.
+func (c *ServerClient) ListCommitAuthors(ctx context.Context, project, slug string, id int) ([]CommitAuthor, error) {
+	type commit struct {
+		Author struct {
+			Name string `json:"name"`
+			Slug string `json:"slug"` // set only for commits linked to a user
+		} `json:"author"`
+	}
+	commits, err := serverList[commit](ctx, c, serverPullPath(project, slug, id)+"/commits", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	authors := make([]CommitAuthor, 0, len(commits))
+	for _, cm := range commits {
+		authors = append(authors, CommitAuthor{Login: cm.Author.Slug, Name: cm.Author.Name})
+	}
+	return authors, nil
+}
+
+// ApprovePullRequest approves a pull request as the authenticated user.
+func (c *ServerClient) ApprovePullRequest(ctx context.Context, project, slug string, id int) error {
+	user, err := c.currentUserSlug(ctx)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]string{"status": "APPROVED"}
+	_, err = c.api.Do(ctx, http.MethodPut, serverPullPath(project, slug, id)+"/participants/"+url.PathEscape(user), nil, body, nil)
+	return err
+}
+
+// CommentPullRequest adds a comment to a pull request.
+func (c *ServerClient) CommentPullRequest(ctx context.Context, project, slug string, id int, text string) error {
+	body := map[string]string{"text": text}
+	_, err := c.api.Do(ctx, http.MethodPost, serverPullPath(project, slug, id)+"/comments", nil, body, nil)
+	return err
+}
+
+// MergePullRequest merges the current version of a pull request.
+// Strategies merge, squash and rebase map to no-ff, squash and
+// rebase-ff-only, which must be enabled on the repository.
+func (c *ServerClient) MergePullRequest(ctx context.Context, project, slug string, id int, opts MergeOptions) (*PullRequest, error) {
+	body := map[string]string{}
+	if opts.Strategy != "" {
+		strategy, ok := serverMergeStrategies[opts.Strategy]
+		if !ok {
+			return nil, fmt.Errorf("unsupported merge strategy %q", opts.Strategy)
+		}
+		body["strategyId"] = strategy
+	}
+	if opts.Message != "" {
+		body["message"] = opts.Message
+	}
+
+	pr, err := c.GetPullRequest(ctx, project, slug, id)
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{"version": {strconv.Itoa(pr.Version)}}
+
+	var merged serverPR
+	if _, err := c.api.Do(ctx, http.MethodPost, serverPullPath(project, slug, id)+"/merge", query, body, &merged); err != nil {
+		return nil, err
+	}
+	return merged.convert(), nil
+}
+
+// ListBuildStatuses returns the build statuses of a commit.
+func (c *ServerClient) ListBuildStatuses(ctx context.Context, _, _, commit string) ([]BuildStatus, error) {
+	type status struct {
+		Key   string `json:"key"`
+		Name  string `json:"name"`
+		State string `json:"state"`
+		URL   string `json:"url"`
+	}
+	statuses, err := serverList[status](ctx, c, "rest/build-status/1.0/commits/"+url.PathEscape(commit), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]BuildStatus, 0, len(statuses))
+	for _, s := range statuses {
+		result = append(result, BuildStatus(s))
+	}
+	return result, nil
+}
+
+// GetBranchRestrictions returns the required builds and default reviewer
+// approvals of a branch. A server without these features (before
+// Bitbucket 7.14 for required builds) has no restrictions.
+func (c *ServerClient) GetBranchRestrictions(ctx context.Context, project, slug, branch string) (*BranchRestrictions, error) {
+	type refMatcher struct {
+		ID   string `json:"id"`
+		Type struct {
+			ID string `json:"id"`
+		} `json:"type"`
+	}
+	type buildCondition struct {
+		BuildParentKeys []string    `json:"buildParentKeys"`
+		RefMatcher      refMatcher  `json:"refMatcher"`
+		ExemptMatcher   *refMatcher `json:"exemptRefMatcher"`
+	}
+	type reviewerCondition struct {
+		TargetRefMatcher  refMatcher `json:"targetRefMatcher"`
+		RequiredApprovals int        `json:"requiredApprovals"`
+	}
+	matches := func(m *refMatcher) bool {
+		switch m.Type.ID {
+		case "ANY_REF":
+			return true
+		case "BRANCH":
+			return m.ID == "refs/heads/"+branch || m.ID == branch
+		case "PATTERN":
+			ok, _ := path.Match(m.ID, branch)
+			return ok
+		default: // branching model categories aren't resolved
+			return false
+		}
+	}
+
+	result := &BranchRestrictions{}
+
+	builds, err := serverList[buildCondition](ctx, c, serverRepoPath("rest/required-builds/latest", project, slug)+"/conditions", nil)
+	if err != nil && !IsNotFound(err) {
+		return nil, fmt.Errorf("failed to list required builds: %w", err)
+	}
+	for i := range builds {
+		b := &builds[i]
+		if !matches(&b.RefMatcher) || (b.ExemptMatcher != nil && matches(b.ExemptMatcher)) {
+			continue
+		}
+		result.Restricted = true
+		result.RequiredBuilds = append(result.RequiredBuilds, b.BuildParentKeys...)
+	}
+
+	var reviewers []reviewerCondition
+	_, err = c.api.Do(ctx, http.MethodGet, serverRepoPath("rest/default-reviewers/1.0", project, slug)+"/conditions", nil, nil, &reviewers)
+	if err != nil && !IsNotFound(err) {
+		return nil, fmt.Errorf("failed to list default reviewers: %w", err)
+	}
+	for i := range reviewers {
+		r := &reviewers[i]
+		if !matches(&r.TargetRefMatcher) || r.RequiredApprovals == 0 {
+			continue
+		}
+		result.Restricted = true
+		result.RequiredApprovals = max(result.RequiredApprovals, r.RequiredApprovals)
+	}
+
+	return result, nil
+}
+
+// GetBranch returns a branch.
+func (c *ServerClient) GetBranch(ctx context.Context, project, slug, branch string) (*Branch, error) {
+	query := url.Values{"filterText": {branch}}
+	branches, err := serverList[serverRef](ctx, c, serverRepoPath("rest/api/1.0", project, slug)+"/branches", query)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range branches {
+		if b.DisplayID == branch {
+			return &Branch{Name: b.DisplayID, Commit: b.LatestCommit}, nil
+		}
+	}
+	return nil, &Error{StatusCode: http.StatusNotFound, Message: fmt.Sprintf("branch %s not found", branch)}
+}
+
+// DeleteBranch deletes a branch.
+func (c *ServerClient) DeleteBranch(ctx context.Context, project, slug, branch string) error {
+	body := map[string]any{"name": "refs/heads/" + branch, "dryRun": false}
+	_, err := c.api.Do(ctx, http.MethodDelete, serverRepoPath("rest/branch-utils/1.0", project, slug)+"/branches", nil, body, nil)
+	return err
+}
+
+// ListTags returns the tags of a repository.
+func (c *ServerClient) ListTags(ctx context.Context, project, slug string) ([]Tag, error) {
+	tags, err := serverList[serverTag](ctx, c, serverRepoPath("rest/api/1.0", project, slug)+"/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Tag, 0, len(tags))
+	for _, t := range tags {
+		result = append(result, Tag{Name: t.DisplayID, Commit: t.LatestCommit})
+	}
+	return result, nil
+}
+
+// GetTag returns a tag, with the date of its commit.
+func (c *ServerClient) GetTag(ctx context.Context, project, slug, name string) (*Tag, error) {
+	p := serverRepoPath("rest/api/1.0", project, slug)
+
+	var t serverTag
+	if _, err := c.api.Do(ctx, http.MethodGet, p+"/tags/"+escapePath(name), nil, nil, &t); err != nil {
+		return nil, err
+	}
+
+	var commit struct {
+		CommitterTimestamp int64 `json:"committerTimestamp"`
+	}
+	if _, err := c.api.Do(ctx, http.MethodGet, p+"/commits/"+url.PathEscape(t.LatestCommit), nil, nil, &commit); err != nil {
+		return nil, fmt.Errorf("failed to get tag commit: %w", err)
+	}
+
+	return &Tag{
+		Name:       t.DisplayID,
+		Commit:     t.LatestCommit,
+		CommitDate: time.UnixMilli(commit.CommitterTimestamp),
+	}, nil
+}
+
+// CreateTag creates a tag.
+func (c *ServerClient) CreateTag(ctx context.Context, project, slug string, opts CreateTagOptions) (*Tag, error) {
+	body := map[string]string{"name": opts.Name, "startPoint": opts.Commit}
+	if opts.Message != "" {
+		body["message"] = opts.Message
+	}
+
+	var t serverTag
+	if _, err := c.api.Do(ctx, http.MethodPost, serverRepoPath("rest/api/1.0", project, slug)+"/tags", nil, body, &t); err != nil {
+		return nil, err
+	}
+	return &Tag{Name: t.DisplayID, Commit: t.LatestCommit, Message: opts.Message}, nil
+}
+
+// GetRawFile returns the content of a file at ref.
+func (c *ServerClient) GetRawFile(ctx context.Context, project, slug, filePath, ref string) ([]byte, error) {
+	var query url.Values
+	if ref != "" {
+		query = url.Values{"at": {ref}}
+	}
+
+	var data []byte
+	p := serverRepoPath("rest/api/1.0", project, slug) + "/raw/" + escapePath(filePath)
+	if _, err := c.api.Do(ctx, http.MethodGet, p, query, nil, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// currentUserSlug returns the slug of the authenticated user, which the
+// REST API doesn't report, so it's found from the username given by the
+// application links servlet.
+func (c *ServerClient) currentUserSlug(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.userSlug != "" {
+		return c.userSlug, nil
+	}
+
+	var name []byte
+	if _, err := c.api.Do(ctx, http.MethodGet, "plugins/servlet/applinks/whoami", nil, nil, &name); err != nil {
+		return "", fmt.Errorf("failed to get current user: %w", err)
+	}
+	if strings.TrimSpace(string(name)) == "" {
+		return "", fmt.Errorf("failed to get current user: not authenticated")
+	}
+
+	var user struct {
+		Slug string `json:"slug"`
+	}
+	if _, err := c.api.Do(ctx, http.MethodGet, "rest/api/1.0/users/"+url.PathEscape(strings.TrimSpace(string(name))), nil, nil, &user); err != nil {
+		return "", fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	c.userSlug = user.Slug
+	return c.userSlug, nil
+}
+
+// serverList fetches all pages of a list endpoint.
+func serverList[T any](ctx context.Context, c *ServerClient, p string, query url.Values) ([]T, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("limit", strconv.Itoa(serverPageLimit))
+
+	var all []T
+	for start := 0; ; {
+		query.Set("start", strconv.Itoa(start))
+
+		var page struct {
+			Values        []T  `json:"values"`
+			IsLastPage    bool `json:"isLastPage"`
+			NextPageStart int  `json:"nextPageStart"`
+		}
+		if _, err := c.api.Do(ctx, http.MethodGet, p, query, nil, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Values...)
+
+		if page.IsLastPage || page.NextPageStart <= start {
+			return all, nil
+		}
+		start = page.NextPageStart
+	}
+}
@@ -0,0 +1,26 @@
+package collector
+
+import "slices"
+
+// review is a PR review that approves it or withdraws approval, such as a
+// request for changes or a dismissed review. Reviews that only comment
+// don't change a user's approval and are left out.
+type review struct {
+	user     string
+	approved bool
+}
+
+// approvers returns the users whose latest review approves the PR, in
+// order of their first review. Reviews are oldest first.
+func approvers(reviews []review) []string {
+	var users []string
+	latest := make(map[string]bool)
+	for _, r := range reviews {
+		if _, ok := latest[r.user]; !ok {
+			users = append(users, r.user)
+		}
+		latest[r.user] = r.approved
+	}
+
+	return slices.DeleteFunc(users, func(u string) bool { return !latest[u] })
+}
@@ -0,0 +1,356 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/plexusone/versionconductor/internal/bitbucket"
+	"github.com/plexusone/versionconductor/internal/releaser"
+	"github.com/plexusone/versionconductor/internal/repoconfig"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// BitbucketCollector implements Collector for Bitbucket Cloud and Data
+// Center repositories. Organizations are Cloud workspaces or Data Center
+// project keys.
+type BitbucketCollector struct {
+	botDetector
+	client bitbucket.API
+}
+
+// NewBitbucketCollector creates a new Bitbucket collector using the given
+// client.
+func NewBitbucketCollector(client bitbucket.API) *BitbucketCollector {
+	return &BitbucketCollector{
+		client: client,
+	}
+}
+
+// ListRepos returns the repositories of the given workspaces or projects
+// matching the filter criteria. Bitbucket has no archived repositories.
+func (c *BitbucketCollector) ListRepos(ctx context.Context, orgs []string, filter model.RepoFilter) ([]model.Repo, error) {
+	var repos []model.Repo
+
+	for _, org := range orgs {
+		bbRepos, err := c.client.ListRepos(ctx, org)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range bbRepos {
+			repo := convertBitbucketRepo(r)
+
+			if repo.Private && !filter.IncludePrivate {
+				continue
+			}
+			if r.Fork && !filter.IncludeForks {
+				continue
+			}
+			if isExcluded(repo.FullName, filter.ExcludeRepos) {
+				continue
+			}
+
+			repos = append(repos, repo)
+		}
+	}
+
+	return repos, nil
+}
+
+// ListDependencyPRs returns open dependency PRs for a repository.
+func (c *BitbucketCollector) ListDependencyPRs(ctx context.Context, repo model.RepoRef) ([]model.PullRequest, error) {
+	pulls, err := c.client.ListPullRequests(ctx, repo.Owner, repo.Name, "OPEN")
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []model.PullRequest
+	for i := range pulls {
+		mpr := convertBitbucketPR(&pulls[i], repo)
+		if c.detectDependency(&mpr) {
+			prs = append(prs, mpr)
+		}
+	}
+
+	return prs, nil
+}
+
+// GetPRDetails returns detailed information about a PR, including
+// whether it can be merged.
+func (c *BitbucketCollector) GetPRDetails(ctx context.Context, repo model.RepoRef, prNumber int) (*model.PullRequest, error) {
+	pr, err := c.client.GetPullRequest(ctx, repo.Owner, repo.Name, prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	mpr := convertBitbucketPR(pr, repo)
+	c.detectDependency(&mpr)
+
+	if pr.State == "OPEN" {
+		status, err := c.client.GetMergeStatus(ctx, repo.Owner, repo.Name, prNumber)
+		if err != nil {
+			return nil, err
+		}
+		mpr.Mergeable = status.CanMerge
+		mpr.MergeableStr = bitbucketMergeableState(status)
+	}
+
+	return &mpr, nil
+}
+
+// GetPRChecks returns the build statuses of a PR's source commit.
+func (c *BitbucketCollector) GetPRChecks(ctx context.Context, repo model.RepoRef, prNumber int) ([]model.CheckRun, error) {
+	pr, err := c.client.GetPullRequest(ctx, repo.Owner, repo.Name, prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := c.client.ListBuildStatuses(ctx, repo.Owner, repo.Name, pr.SourceCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []model.CheckRun
+	for _, s := range statuses {
+		result = append(result, convertBitbucketStatus(s))
+	}
+
+	return result, nil
+}
+
+// ListPRFiles returns the paths of the files changed by a PR.
+func (c *BitbucketCollector) ListPRFiles(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	return c.client.ListChangedFiles(ctx, repo.Owner, repo.Name, prNumber)
+}
+
+// ListPRCommitAuthors returns the distinct authors of a PR's commits.
+func (c *BitbucketCollector) ListPRCommitAuthors(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	commits, err := c.client.ListCommitAuthors(ctx, repo.Owner, repo.Name, prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	authors := make([]string, 0, len(commits))
+	for _, a := range commits {
+		authors = append(authors, commitAuthor(a.Login, a.Name))
+	}
+	return uniqueAuthors(authors), nil
+}
+
+// ListPRApprovers returns the users approving a PR.
+func (c *BitbucketCollector) ListPRApprovers(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	pr, err := c.client.GetPullRequest(ctx, repo.Owner, repo.Name, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	return pr.Approvers, nil
+}
+
+// GetLatestRelease returns the latest semver tag as a release, since
+// Bitbucket has no releases, or nil if there is none.
+func (c *BitbucketCollector) GetLatestRelease(ctx context.Context, repo model.RepoRef) (*model.Release, error) {
+	tags, err := c.client.ListTags(ctx, repo.Owner, repo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var tagNames []string
+	for _, t := range tags {
+		tagNames = append(tagNames, t.Name)
+	}
+	latest := releaser.FindLatestVersion(tagNames)
+	if latest == "" {
+		return nil, nil
+	}
+
+	for _, t := range tags {
+		if t.Name == latest {
+			return &model.Release{
+				TagName:     t.Name,
+				Name:        t.Name,
+				Body:        t.Message,
+				CreatedAt:   t.CommitDate,
+				PublishedAt: t.CommitDate,
+				Repo:        repo,
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ListTags returns all tags for a repository.
+func (c *BitbucketCollector) ListTags(ctx context.Context, repo model.RepoRef) ([]model.Tag, error) {
+	bbTags, err := c.client.ListTags(ctx, repo.Owner, repo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []model.Tag
+	for _, t := range bbTags {
+		tags = append(tags, model.Tag{
+			Name: t.Name,
+			SHA:  t.Commit,
+			Repo: repo,
+		})
+	}
+
+	return tags, nil
+}
+
+// GetMergedPRsSinceTag returns PRs merged since the date of the given
+// tag's commit.
+func (c *BitbucketCollector) GetMergedPRsSinceTag(ctx context.Context, repo model.RepoRef, tagName string) ([]model.PullRequest, error) {
+	t, err := c.client.GetTag(ctx, repo.Owner, repo.Name, tagName)
+	if err != nil {
+		return nil, err
+	}
+
+	pulls, err := c.client.ListPullRequests(ctx, repo.Owner, repo.Name, "MERGED")
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []model.PullRequest
+	for i := range pulls {
+		if pulls[i].ClosedAt == nil || pulls[i].ClosedAt.Before(t.CommitDate) {
+			continue
+		}
+		mpr := convertBitbucketPR(&pulls[i], repo)
+		c.detectDependency(&mpr)
+		prs = append(prs, mpr)
+	}
+
+	return prs, nil
+}
+
+// GetRepoConfig returns the repository's in-repo config from its default
+// branch, or nil if the repository has none.
+func (c *BitbucketCollector) GetRepoConfig(ctx context.Context, repo model.RepoRef) (*model.RepoConfig, error) {
+	r, err := c.client.GetRepo(ctx, repo.Owner, repo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.client.GetRawFile(ctx, repo.Owner, repo.Name, model.RepoConfigPath, r.DefaultBranch)
+	if err != nil {
+		if bitbucket.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return repoconfig.Parse(data)
+}
+
+// GetBranchProtection returns the merge requirements of a branch, or nil
+// if it has none or they can't be read, which on Bitbucket Cloud needs
+// admin access to the repository.
+func (c *BitbucketCollector) GetBranchProtection(ctx context.Context, repo model.RepoRef, branch string) (*model.BranchProtection, error) {
+	if branch == "" {
+		r, err := c.client.GetRepo(ctx, repo.Owner, repo.Name)
+		if err != nil {
+			return nil, err
+		}
+		branch = r.DefaultBranch
+	}
+
+	restrictions, err := c.client.GetBranchRestrictions(ctx, repo.Owner, repo.Name, branch)
+	if err != nil {
+		if bitbucket.IsForbidden(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !restrictions.Restricted {
+		return nil, nil
+	}
+
+	return &model.BranchProtection{
+		Branch:            branch,
+		RequiredChecks:    restrictions.RequiredBuilds,
+		RequiredApprovals: restrictions.RequiredApprovals,
+	}, nil
+}
+
+// convertBitbucketRepo converts a Bitbucket repository to our model.
+func convertBitbucketRepo(r bitbucket.Repository) model.Repo {
+	return model.Repo{
+		Owner:         r.Owner,
+		Name:          r.Slug,
+		FullName:      r.Owner + "/" + r.Slug,
+		Description:   r.Description,
+		DefaultBranch: r.DefaultBranch,
+		Private:       r.Private,
+		Language:      r.Language,
+		UpdatedAt:     r.UpdatedAt,
+		HTMLURL:       r.URL,
+	}
+}
+
+// convertBitbucketPR converts a Bitbucket pull request to our model.
+// Whether it can be merged is only known from its merge status.
+func convertBitbucketPR(pr *bitbucket.PullRequest, repo model.RepoRef) model.PullRequest {
+	state := "closed"
+	if pr.State == "OPEN" {
+		state = "open"
+	}
+
+	mpr := model.PullRequest{
+		Number:     pr.ID,
+		Title:      pr.Title,
+		Body:       pr.Description,
+		State:      state,
+		Author:     pr.Author,
+		HTMLURL:    pr.URL,
+		Draft:      pr.Draft,
+		HeadBranch: pr.SourceBranch,
+		CreatedAt:  pr.CreatedAt,
+		UpdatedAt:  pr.UpdatedAt,
+		Repo:       repo,
+	}
+	if pr.State == "MERGED" {
+		mpr.MergedAt = pr.ClosedAt
+	}
+
+	return mpr
+}
+
+// bitbucketMergeableState maps a merge status to GitHub's mergeable
+// states: clean, dirty (conflicts) or blocked (vetoed by merge checks).
+func bitbucketMergeableState(s *bitbucket.MergeStatus) string {
+	switch {
+	case s.Conflicted:
+		return "dirty"
+	case s.CanMerge:
+		return "clean"
+	default:
+		return "blocked"
+	}
+}
+
+// convertBitbucketStatus converts a Bitbucket build status to a check run,
+// named by its build key as required builds are.
+func convertBitbucketStatus(s bitbucket.BuildStatus) model.CheckRun {
+	name := s.Key
+	if name == "" {
+		name = s.Name
+	}
+
+	switch s.State {
+	case "SUCCESSFUL":
+		return model.NewStatusCheck(name, "success")
+	case "FAILED":
+		return model.NewStatusCheck(name, "failure")
+	case "STOPPED":
+		c := model.NewStatusCheck(name, "")
+		c.Status = "completed"
+		c.Conclusion = "cancelled"
+		return c
+	case "INPROGRESS":
+		return model.NewStatusCheck(name, "pending")
+	default:
+		c := model.NewStatusCheck(name, "")
+		c.Status = "queued"
+		return c
+	}
+}
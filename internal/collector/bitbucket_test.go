@@ -0,0 +1,240 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/plexusone/versionconductor/internal/apitest"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestBitbucketCollector_ListDependencyPRs(t *testing.T) {
+	server := apitest.NewBitbucketCloudServer(t, map[string]any{
+		"GET /repositories/team/tools/pullrequests": map[string]any{"values": []map[string]any{
+			{"id": 12, "title": "Update module golang.org/x/net to v0.30.0", "state": "OPEN",
+				"author": map[string]any{"nickname": "renovate-bot"}, "created_on": "2025-01-01T00:00:00Z"},
+			{"id": 13, "title": "Fix typo", "state": "OPEN", "author": map[string]any{"nickname": "alice"}},
+		}},
+		"GET /repositories/team/tools/pullrequests/12": map[string]any{
+			"id": 12, "title": "Update module golang.org/x/net to v0.30.0", "state": "OPEN",
+			"author": map[string]any{"nickname": "renovate-bot"},
+		},
+		"GET /repositories/team/tools/pullrequests/12/diffstat": map[string]any{"values": []map[string]any{
+			{"status": "modified"}, {"status": "merge conflict"},
+		}},
+	})
+	c := NewBitbucketCollector(server.BitbucketClient(t))
+	ref := model.RepoRef{Owner: "team", Name: "tools"}
+
+	prs, err := c.ListDependencyPRs(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 1 || prs[0].Number != 12 || prs[0].State != "open" || prs[0].DependBot != model.DependBotRenovate {
+		t.Fatalf("expected Renovate PR 12, got %+v", prs)
+	}
+
+	req, _ := server.Find(http.MethodGet, "/repositories/team/tools/pullrequests")
+	if req.Query["state"][0] != "OPEN" {
+		t.Errorf("expected open PRs, got %v", req.Query)
+	}
+
+	pr, err := c.GetPRDetails(context.Background(), ref, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.Mergeable || pr.MergeableStr != "dirty" {
+		t.Errorf("expected conflicting PR, got %+v", pr)
+	}
+}
+
+func TestBitbucketCollector_GetPRChecks(t *testing.T) {
+	server := apitest.NewBitbucketDataCenterServer(t, map[string]any{
+		"GET /rest/api/1.0/projects/PLAT/repos/tools/pull-requests/7": map[string]any{
+			"id": 7, "state": "OPEN", "fromRef": map[string]any{"latestCommit": "abc123"},
+		},
+		"GET /rest/build-status/1.0/commits/abc123": map[string]any{"isLastPage": true, "values": []map[string]any{
+			{"key": "ci-build", "name": "Build #42", "state": "SUCCESSFUL"},
+			{"key": "ci-test", "state": "FAILED"},
+			{"key": "ci-lint", "state": "INPROGRESS"},
+			{"key": "deploy", "state": "STOPPED"},
+			{"name": "legacy", "state": "UNKNOWN"},
+		}},
+	})
+	c := NewBitbucketCollector(server.BitbucketClient(t))
+
+	checks, err := c.GetPRChecks(context.Background(), model.RepoRef{Owner: "PLAT", Name: "tools"}, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []model.CheckRun{
+		{Name: "ci-build", Status: "completed", Conclusion: "success", Source: model.CheckSourceStatus},
+		{Name: "ci-test", Status: "completed", Conclusion: "failure", Source: model.CheckSourceStatus},
+		{Name: "ci-lint", Status: "in_progress", Source: model.CheckSourceStatus},
+		{Name: "deploy", Status: "completed", Conclusion: "cancelled", Source: model.CheckSourceStatus},
+		{Name: "legacy", Status: "queued", Source: model.CheckSourceStatus},
+	}
+	if len(checks) != len(want) {
+		t.Fatalf("expected %d checks, got %+v", len(want), checks)
+	}
+	for i := range want {
+		if checks[i] != want[i] {
+			t.Errorf("check %d = %+v, want %+v", i, checks[i], want[i])
+		}
+	}
+}
+
+func TestBitbucketCollector_GetBranchProtection(t *testing.T) {
+	server := apitest.NewBitbucketDataCenterServer(t, map[string]any{
+		"GET /rest/required-builds/latest/projects/PLAT/repos/tools/conditions": map[string]any{"isLastPage": true, "values": []map[string]any{
+			{"buildParentKeys": []string{"ci-build", "ci-test"},
+				"refMatcher": map[string]any{"id": "refs/heads/main", "type": map[string]any{"id": "BRANCH"}}},
+			{"buildParentKeys": []string{"release"},
+				"refMatcher": map[string]any{"id": "release/*", "type": map[string]any{"id": "PATTERN"}}},
+		}},
+		"GET /rest/default-reviewers/1.0/projects/PLAT/repos/tools/conditions": []map[string]any{
+			{"requiredApprovals": 2, "targetRefMatcher": map[string]any{"id": "ANY_REF_MATCHER_ID", "type": map[string]any{"id": "ANY_REF"}}},
+		},
+	})
+	c := NewBitbucketCollector(server.BitbucketClient(t))
+
+	bp, err := c.GetBranchProtection(context.Background(), model.RepoRef{Owner: "PLAT", Name: "tools"}, "main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bp == nil || len(bp.RequiredChecks) != 2 || bp.RequiredChecks[1] != "ci-test" || bp.RequiredApprovals != 2 {
+		t.Errorf("unexpected protection: %+v", bp)
+	}
+}
+
+func TestBitbucketCollector_GetBranchProtection_Forbidden(t *testing.T) {
+	server := apitest.NewBitbucketCloudServer(t, map[string]any{
+		"GET /repositories/team/tools/branch-restrictions": http.StatusForbidden,
+	})
+	c := NewBitbucketCollector(server.BitbucketClient(t))
+
+	bp, err := c.GetBranchProtection(context.Background(), model.RepoRef{Owner: "team", Name: "tools"}, "main")
+	if err != nil || bp != nil {
+		t.Errorf("expected no protection without admin access, got %+v, %v", bp, err)
+	}
+}
+
+func TestBitbucketCollector_GetMergedPRsSinceTag(t *testing.T) {
+	server := apitest.NewBitbucketCloudServer(t, map[string]any{
+		"GET /repositories/team/tools/refs/tags/v1.2.0": map[string]any{
+			"name": "v1.2.0", "target": map[string]any{"hash": "abc123", "date": "2025-03-01T00:00:00Z"},
+		},
+		"GET /repositories/team/tools/pullrequests": map[string]any{"values": []map[string]any{
+			{"id": 20, "title": "Update module golang.org/x/net to v0.31.0", "state": "MERGED",
+				"author": map[string]any{"nickname": "renovate-bot"}, "updated_on": "2025-03-05T00:00:00Z"},
+			{"id": 15, "title": "Update module golang.org/x/net to v0.30.0", "state": "MERGED",
+				"author": map[string]any{"nickname": "renovate-bot"}, "updated_on": "2025-02-01T00:00:00Z"},
+		}},
+	})
+	c := NewBitbucketCollector(server.BitbucketClient(t))
+
+	prs, err := c.GetMergedPRsSinceTag(context.Background(), model.RepoRef{Owner: "team", Name: "tools"}, "v1.2.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 1 || prs[0].Number != 20 || !prs[0].IsMerged() {
+		t.Errorf("expected PR 20 merged after the tag, got %+v", prs)
+	}
+}
+
+func TestBitbucketCollector_ListPRFiles(t *testing.T) {
+	cloud := apitest.NewBitbucketCloudServer(t, map[string]any{
+		"GET /repositories/team/tools/pullrequests/12/diffstat": map[string]any{"values": []map[string]any{
+			{"status": "modified", "old": map[string]any{"path": "go.mod"}, "new": map[string]any{"path": "go.mod"}},
+			{"status": "renamed", "old": map[string]any{"path": "tools.go"}, "new": map[string]any{"path": "internal/tools.go"}},
+			{"status": "removed", "old": map[string]any{"path": "vendor.txt"}},
+		}},
+	})
+	dc := apitest.NewBitbucketDataCenterServer(t, map[string]any{
+		"GET /rest/api/1.0/projects/PLAT/repos/tools/pull-requests/7/changes": map[string]any{"isLastPage": true, "values": []map[string]any{
+			{"path": map[string]any{"toString": "go.mod"}},
+			{"path": map[string]any{"toString": "internal/tools.go"}, "srcPath": map[string]any{"toString": "tools.go"}},
+		}},
+	})
+
+	files, err := NewBitbucketCollector(cloud.BitbucketClient(t)).ListPRFiles(context.Background(), model.RepoRef{Owner: "team", Name: "tools"}, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "go.mod internal/tools.go tools.go vendor.txt"; strings.Join(files, " ") != want {
+		t.Errorf("expected Cloud files %q, got %v", want, files)
+	}
+
+	files, err = NewBitbucketCollector(dc.BitbucketClient(t)).ListPRFiles(context.Background(), model.RepoRef{Owner: "PLAT", Name: "tools"}, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "go.mod internal/tools.go tools.go"; strings.Join(files, " ") != want {
+		t.Errorf("expected Data Center files %q, got %v", want, files)
+	}
+}
+
+func TestBitbucketCollector_ListPRApprovers(t *testing.T) {
+	cloud := apitest.NewBitbucketCloudServer(t, map[string]any{
+		"GET /repositories/team/tools/pullrequests/12": map[string]any{"id": 12, "state": "OPEN", "participants": []map[string]any{
+			{"user": map[string]any{"nickname": "alice"}, "approved": true},
+			{"user": map[string]any{"nickname": "bob"}, "approved": false},
+		}},
+	})
+	dc := apitest.NewBitbucketDataCenterServer(t, map[string]any{
+		"GET /rest/api/1.0/projects/PLAT/repos/tools/pull-requests/7": map[string]any{"id": 7, "state": "OPEN", "reviewers": []map[string]any{
+			{"user": map[string]any{"name": "carol"}, "approved": true},
+		}},
+	})
+
+	approvers, err := NewBitbucketCollector(cloud.BitbucketClient(t)).ListPRApprovers(context.Background(), model.RepoRef{Owner: "team", Name: "tools"}, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(approvers, " ") != "alice" {
+		t.Errorf("expected Cloud approver alice, got %v", approvers)
+	}
+
+	approvers, err = NewBitbucketCollector(dc.BitbucketClient(t)).ListPRApprovers(context.Background(), model.RepoRef{Owner: "PLAT", Name: "tools"}, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(approvers, " ") != "carol" {
+		t.Errorf("expected Data Center approver carol, got %v", approvers)
+	}
+}
+
+func TestBitbucketCollector_ListPRCommitAuthors(t *testing.T) {
+	cloud := apitest.NewBitbucketCloudServer(t, map[string]any{
+		"GET /repositories/team/tools/pullrequests/12/commits": map[string]any{"values": []map[string]any{
+			{"author": map[string]any{"raw": "Renovate Bot <bot@renovateapp.com>", "user": map[string]any{"nickname": "renovate-bot"}}},
+			{"author": map[string]any{"raw": "Mallory <mallory@example.com>"}},
+			{"author": map[string]any{"raw": "Renovate Bot <bot@renovateapp.com>", "user": map[string]any{"nickname": "renovate-bot"}}},
+		}},
+	})
+	dc := apitest.NewBitbucketDataCenterServer(t, map[string]any{
+		"GET /rest/api/1.0/projects/PLAT/repos/tools/pull-requests/7/commits": map[string]any{"isLastPage": true, "values": []map[string]any{
+			{"author": map[string]any{"name": "renovate", "emailAddress": "renovate@example.com", "slug": "renovate"}},
+			{"author": map[string]any{"name": "Mallory", "emailAddress": "mallory@example.com"}},
+		}},
+	})
+
+	authors, err := NewBitbucketCollector(cloud.BitbucketClient(t)).ListPRCommitAuthors(context.Background(), model.RepoRef{Owner: "team", Name: "tools"}, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "renovate-bot unlinked:Mallory"; strings.Join(authors, " ") != want {
+		t.Errorf("expected Cloud authors %q, got %v", want, authors)
+	}
+
+	authors, err = NewBitbucketCollector(dc.BitbucketClient(t)).ListPRCommitAuthors(context.Background(), model.RepoRef{Owner: "PLAT", Name: "tools"}, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "renovate unlinked:Mallory"; strings.Join(authors, " ") != want {
+		t.Errorf("expected Data Center authors %q, got %v", want, authors)
+	}
+}
@@ -0,0 +1,136 @@
+package collector
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/plexusone/versionconductor/internal/glob"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// Parser selects how the packages of a bot's PRs are read.
+type Parser string
+
+const (
+	ParserRenovate   Parser = "renovate"   // Renovate's updates table
+	ParserDependabot Parser = "dependabot" // Dependabot's "Bumps" lines and metadata block
+	ParserPreCommit  Parser = "pre-commit" // pre-commit.ci's hook update list
+	ParserTitle      Parser = "title"      // the PR title only
+)
+
+// Parsers lists the supported parsers.
+var Parsers = []Parser{ParserRenovate, ParserDependabot, ParserPreCommit, ParserTitle}
+
+// Bot identifies the PRs of a dependency bot. A PR belongs to the bot if
+// its author, one of its labels or its head branch matches. Author and
+// label patterns are case-insensitive and may use "*" as a wildcard.
+type Bot struct {
+	// Name is the bot's DependBot value in filters, policies and reports.
+	Name model.DependBot `mapstructure:"name" yaml:"name" json:"name"`
+
+	// Authors are author login patterns, e.g. "platform-deps-bot". The
+	// commits of the bot's PRs must be by a matching login.
+	Authors []string `mapstructure:"authors" yaml:"authors" json:"authors,omitempty"`
+
+	// Labels are label patterns, e.g. "snyk:*".
+	Labels []string `mapstructure:"labels" yaml:"labels" json:"labels,omitempty"`
+
+	// Branches are head branch prefixes, e.g. "snyk-upgrade-".
+	Branches []string `mapstructure:"branches" yaml:"branches" json:"branches,omitempty"`
+
+	// Parser reads the updated packages from the PR. Default is title.
+	Parser Parser `mapstructure:"parser" yaml:"parser" json:"parser,omitempty"`
+}
+
+// DefaultBots are the built-in bots, Renovate and Dependabot, detected by
+// their author logins.
+var DefaultBots = Bots{
+	{Name: model.DependBotRenovate, Authors: []string{"*renovate*"}, Parser: ParserRenovate},
+	{Name: model.DependBotDependabot, Authors: []string{"*dependabot*"}, Parser: ParserDependabot},
+}
+
+// Validate checks the bot config is complete.
+func (b Bot) Validate() error {
+	if b.Name == "" {
+		return fmt.Errorf("bot name required")
+	}
+	if len(b.Authors) == 0 && len(b.Labels) == 0 && len(b.Branches) == 0 {
+		return fmt.Errorf("bot %s: authors, labels or branches required", b.Name)
+	}
+	if b.Parser != "" && !slices.Contains(Parsers, b.Parser) {
+		return fmt.Errorf("bot %s: unknown parser %q", b.Name, b.Parser)
+	}
+	return nil
+}
+
+// Matches reports whether a PR belongs to the bot.
+func (b Bot) Matches(pr *model.PullRequest) bool {
+	for _, pattern := range b.Authors {
+		if glob.MatchFold(pattern, pr.Author) {
+			return true
+		}
+	}
+	for _, pattern := range b.Labels {
+		for _, label := range pr.Labels {
+			if glob.MatchFold(pattern, label) {
+				return true
+			}
+		}
+	}
+	for _, prefix := range b.Branches {
+		if pr.HeadBranch != "" && strings.HasPrefix(pr.HeadBranch, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Bots is a registry of dependency bots. The first bot matching a PR
+// wins, so specific entries go before broad ones.
+type Bots []Bot
+
+// WithDefaults returns the bots followed by the built-in bots they don't
+// replace. A configured bot named renovate or dependabot replaces the
+// built-in one.
+func (bs Bots) WithDefaults() Bots {
+	result := slices.Clone(bs)
+	for _, d := range DefaultBots {
+		if !slices.ContainsFunc(bs, func(b Bot) bool { return b.Name == d.Name }) {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// Detect returns the first bot a PR belongs to.
+func (bs Bots) Detect(pr *model.PullRequest) (Bot, bool) {
+	for _, b := range bs {
+		if b.Matches(pr) {
+			return b, true
+		}
+	}
+	return Bot{}, false
+}
+
+// BotSetter is implemented by collectors whose dependency bots can be
+// configured.
+type BotSetter interface {
+	SetBots(bots Bots)
+}
+
+// botDetector detects dependency PRs for the collector embedding it. The
+// zero value uses DefaultBots.
+type botDetector struct {
+	bots Bots
+}
+
+// SetBots sets the bots whose PRs are dependency PRs.
+func (d *botDetector) SetBots(bots Bots) {
+	d.bots = bots
+}
+
+// detectDependency is detectDependency with the configured bots.
+func (d *botDetector) detectDependency(mpr *model.PullRequest) bool {
+	return detectDependency(mpr, d.bots)
+}
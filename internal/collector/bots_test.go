@@ -0,0 +1,119 @@
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/plexusone/versionconductor/internal/apitest"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+var testBots = Bots{
+	{Name: "platform-deps", Authors: []string{"platform-deps-bot"}, Parser: ParserRenovate},
+	{Name: "snyk", Authors: []string{"snyk-bot"}, Branches: []string{"snyk-upgrade-", "snyk-fix-"}},
+	{Name: "pre-commit", Authors: []string{"pre-commit-ci[bot]"}, Parser: ParserPreCommit},
+	{Name: "go-get", Labels: []string{"go-get-*"}},
+}.WithDefaults()
+
+func TestBots_Detect(t *testing.T) {
+	tests := []struct {
+		name string
+		pr   model.PullRequest
+		want model.DependBot
+	}{
+		{"author", model.PullRequest{Author: "Platform-Deps-Bot"}, "platform-deps"},
+		{"branch", model.PullRequest{Author: "svc-account", HeadBranch: "snyk-fix-1a2b3c"}, "snyk"},
+		{"label", model.PullRequest{Author: "alice", Labels: []string{"go-get-update"}}, "go-get"},
+		{"literal brackets", model.PullRequest{Author: "pre-commit-ci[bot]"}, "pre-commit"},
+		{"built-in", model.PullRequest{Author: "renovate[bot]"}, model.DependBotRenovate},
+		{"none", model.PullRequest{Author: "alice", HeadBranch: "feature/snyk-upgrade-"}, model.DependBotUnknown},
+	}
+
+	for _, tt := range tests {
+		bot, _ := testBots.Detect(&tt.pr)
+		if bot.Name != tt.want {
+			t.Errorf("%s: expected bot %q, got %q", tt.name, tt.want, bot.Name)
+		}
+	}
+}
+
+func TestBots_WithDefaults(t *testing.T) {
+	bots := Bots{{Name: model.DependBotRenovate, Authors: []string{"platform-deps-bot"}, Parser: ParserRenovate}}.WithDefaults()
+	if len(bots) != 2 || bots[0].Authors[0] != "platform-deps-bot" || bots[1].Name != model.DependBotDependabot {
+		t.Errorf("expected configured renovate to replace the built-in one, got %+v", bots)
+	}
+}
+
+func TestBot_Validate(t *testing.T) {
+	tests := []struct {
+		bot     Bot
+		wantErr bool
+	}{
+		{Bot{Name: "snyk", Authors: []string{"snyk-bot"}}, false},
+		{Bot{Authors: []string{"snyk-bot"}}, true},
+		{Bot{Name: "snyk"}, true},
+		{Bot{Name: "snyk", Authors: []string{"snyk-bot"}, Parser: "snyk"}, true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.bot.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.bot, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDetectDependency_CustomBots(t *testing.T) {
+	snyk := model.PullRequest{
+		Title:      "[Snyk] Upgrade lodash from 4.17.20 to 4.17.21",
+		Author:     "snyk-bot",
+		HeadBranch: "snyk-upgrade-0123abcd",
+	}
+	if !detectDependency(&snyk, testBots) {
+		t.Fatal("expected Snyk PR to be a dependency PR")
+	}
+	if snyk.DependBot != "snyk" || snyk.Dependency.Name != "lodash" || snyk.Dependency.UpdateType != model.UpdateTypePatch {
+		t.Errorf("expected Snyk lodash patch update, got %s %+v", snyk.DependBot, snyk.Dependency)
+	}
+	if len(snyk.BotAuthors) != 1 || snyk.BotAuthors[0] != "snyk-bot" {
+		t.Errorf("expected Snyk's author patterns on the PR, got %v", snyk.BotAuthors)
+	}
+
+	preCommit := model.PullRequest{
+		Title:  "[pre-commit.ci] pre-commit autoupdate",
+		Author: "pre-commit-ci[bot]",
+		Body: "<!--pre-commit.ci start-->\nupdates:\n" +
+			"- [github.com/psf/black: 23.1.0 → 23.3.0](https://github.com/psf/black/compare/23.1.0...23.3.0)\n" +
+			"- [github.com/pre-commit/mirrors-mypy: v1.0.0 → v1.0.1](https://github.com/pre-commit/mirrors-mypy/compare/v1.0.0...v1.0.1)\n" +
+			"<!--pre-commit.ci end-->",
+	}
+	if !detectDependency(&preCommit, testBots) {
+		t.Fatal("expected pre-commit.ci PR to be a dependency PR")
+	}
+	want := model.Dependency{Ecosystem: "pre-commit", UpdateType: model.UpdateTypeMinor, Manager: "pre-commit"}
+	if len(preCommit.Dependencies) != 2 || preCommit.Dependency != want {
+		t.Errorf("expected group of 2 hook updates, got %+v (%+v)", preCommit.Dependencies, preCommit.Dependency)
+	}
+
+	if detectDependency(&model.PullRequest{Author: "platform-deps-bot"}, nil) {
+		t.Error("expected custom bot to be unknown with the built-in bots")
+	}
+}
+
+func TestGitLabCollector_SetBots(t *testing.T) {
+	server := apitest.NewGitLabServer(t, map[string]any{
+		"GET /projects/group%2Fapp/merge_requests": []map[string]any{
+			{"iid": 4, "title": "Update module golang.org/x/net to v0.30.0", "state": "opened",
+				"author": map[string]any{"username": "platform-deps-bot"}, "source_branch": "renovate/golang.org-x-net-0.x"},
+		},
+	})
+	c := NewGitLabCollector(server.GitLabClient(t))
+	c.SetBots(testBots)
+
+	prs, err := c.ListDependencyPRs(context.Background(), model.RepoRef{Owner: "group", Name: "app"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 1 || prs[0].DependBot != "platform-deps" || prs[0].HeadBranch != "renovate/golang.org-x-net-0.x" {
+		t.Errorf("expected MR of the configured bot, got %+v", prs)
+	}
+}
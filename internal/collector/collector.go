@@ -2,6 +2,9 @@ package collector
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/google/go-github/v84/github"
 
 	"github.com/plexusone/versionconductor/pkg/model"
 )
@@ -20,6 +23,18 @@ type Collector interface {
 	// GetPRChecks returns the CI check runs for a PR.
 	GetPRChecks(ctx context.Context, repo model.RepoRef, prNumber int) ([]model.CheckRun, error)
 
+	// ListPRFiles returns the paths of the files changed by a PR,
+	// including the old path of renamed files.
+	ListPRFiles(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error)
+
+	// ListPRCommitAuthors returns the distinct authors of a PR's commits:
+	// their login where the platform knows it, else their name.
+	ListPRCommitAuthors(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error)
+
+	// ListPRApprovers returns the users whose review currently approves
+	// a PR.
+	ListPRApprovers(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error)
+
 	// GetLatestRelease returns the most recent release for a repository.
 	GetLatestRelease(ctx context.Context, repo model.RepoRef) (*model.Release, error)
 
@@ -28,9 +43,43 @@ type Collector interface {
 
 	// GetMergedPRsSinceTag returns PRs merged since the given tag.
 	GetMergedPRsSinceTag(ctx context.Context, repo model.RepoRef, tagName string) ([]model.PullRequest, error)
+
+	// GetRepoConfig returns the repository's in-repo config from its
+	// default branch, or nil if the repository has none.
+	GetRepoConfig(ctx context.Context, repo model.RepoRef) (*model.RepoConfig, error)
+
+	// GetBranchProtection returns the protection rules of a branch, or nil
+	// if the branch is not protected. An empty branch means the
+	// repository's default branch.
+	GetBranchProtection(ctx context.Context, repo model.RepoRef, branch string) (*model.BranchProtection, error)
 }
 
+// Prefetcher is implemented by collectors that can load the PRs of many
+// repositories in bulk before they are read one repository at a time.
+type Prefetcher interface {
+	Prefetch(ctx context.Context, repos []model.RepoRef) error
+}
+
+// GitHub APIs a collector can use.
+const (
+	APIREST    = "rest"
+	APIGraphQL = "graphql"
+)
+
 // NewGitHub creates a new GitHub collector with the given token.
 func NewGitHub(token string) Collector {
 	return NewGitHubCollector(token)
 }
+
+// New creates a GitHub collector using the given API, "rest" (the
+// default) or "graphql", and client.
+func New(api string, client *github.Client) (Collector, error) {
+	switch api {
+	case "", APIREST:
+		return NewGitHubCollectorWithClient(client), nil
+	case APIGraphQL:
+		return NewGraphQLCollectorWithClient(client), nil
+	default:
+		return nil, fmt.Errorf("unknown API %q, expected %s or %s", api, APIREST, APIGraphQL)
+	}
+}
@@ -0,0 +1,28 @@
+package collector
+
+import (
+	"slices"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// commitAuthor returns a commit author's login, or their name marked as
+// unlinked if the commit isn't linked to an account.
+func commitAuthor(login, name string) string {
+	if login != "" {
+		return login
+	}
+	return model.UnlinkedAuthor(name)
+}
+
+// uniqueAuthors returns the authors without duplicates, in order of first
+// appearance.
+func uniqueAuthors(authors []string) []string {
+	result := make([]string, 0, len(authors))
+	for _, a := range authors {
+		if !slices.Contains(result, a) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
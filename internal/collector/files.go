@@ -0,0 +1,86 @@
+package collector
+
+import (
+	"path"
+	"strings"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// lockfileEcosystems maps lockfiles and other manifests without a manager
+// of their own to the ecosystem of their packages.
+var lockfileEcosystems = map[string]string{
+	"go.sum":                  "go",
+	"go.work":                 "go",
+	"go.work.sum":             "go",
+	"package-lock.json":       "npm",
+	"npm-shrinkwrap.json":     "npm",
+	"yarn.lock":               "npm",
+	"pnpm-lock.yaml":          "npm",
+	"bun.lockb":               "npm",
+	"poetry.lock":             "pip",
+	"Pipfile":                 "pip",
+	"Pipfile.lock":            "pip",
+	"uv.lock":                 "pip",
+	"setup.py":                "pip",
+	"setup.cfg":               "pip",
+	"Cargo.lock":              "cargo",
+	"Gemfile.lock":            "rubygems",
+	"composer.lock":           "composer",
+	"packages.lock.json":      "nuget",
+	".terraform.lock.hcl":     "terraform",
+	".pre-commit-config.yaml": "pre-commit",
+}
+
+// fileEcosystem returns the ecosystem of a manifest or lockfile, or "" if
+// the file is neither.
+func fileEcosystem(file string) string {
+	base := path.Base(file)
+	if eco, ok := lockfileEcosystems[base]; ok {
+		return eco
+	}
+	if strings.HasPrefix(file, ".github/workflows/") &&
+		!strings.HasSuffix(base, ".yml") && !strings.HasSuffix(base, ".yaml") {
+		return ""
+	}
+	if strings.HasPrefix(base, "docker-compose") || strings.HasPrefix(base, "compose.") {
+		return "docker"
+	}
+	return managerEcosystems[managerFromFile(file)]
+}
+
+// ecosystemFromFiles returns the ecosystem of the manifests and lockfiles
+// among the files changed by a PR, or "" if they belong to no ecosystem or
+// to several.
+func ecosystemFromFiles(files []string) string {
+	ecosystem := ""
+	for _, f := range files {
+		eco := fileEcosystem(f)
+		switch {
+		case eco == "":
+			continue
+		case ecosystem == "":
+			ecosystem = eco
+		case eco != ecosystem:
+			return ""
+		}
+	}
+	return ecosystem
+}
+
+// SetChangedFiles records the files changed by a dependency PR. If the
+// manifests and lockfiles among them belong to a single ecosystem, it
+// becomes the ecosystem of each dependency, replacing the guess made from
+// the dependency name.
+func SetChangedFiles(pr *model.PullRequest, files []string) {
+	pr.Files = files
+
+	ecosystem := ecosystemFromFiles(files)
+	if ecosystem == "" {
+		return
+	}
+	for i := range pr.Dependencies {
+		pr.Dependencies[i].Ecosystem = ecosystem
+	}
+	pr.Dependency.Ecosystem = ecosystem
+}
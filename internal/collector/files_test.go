@@ -0,0 +1,51 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestEcosystemFromFiles(t *testing.T) {
+	tests := []struct {
+		files []string
+		want  string
+	}{
+		{[]string{"go.mod", "go.sum"}, "go"},
+		{[]string{"web/package.json", "web/pnpm-lock.yaml"}, "npm"},
+		{[]string{"requirements-dev.txt"}, "pip"},
+		{[]string{"pyproject.toml", "poetry.lock"}, "pip"},
+		{[]string{"build/Dockerfile"}, "docker"},
+		{[]string{".github/workflows/ci.yml"}, "github-actions"},
+		{[]string{"infra/main.tf", "infra/.terraform.lock.hcl"}, "terraform"},
+		{[]string{"go.mod", "README.md"}, "go"},
+		{[]string{".github/workflows/README.md"}, ""},
+		{[]string{"go.mod", "package.json"}, ""},
+		{[]string{"main.go"}, ""},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := ecosystemFromFiles(tt.files); got != tt.want {
+			t.Errorf("ecosystemFromFiles(%v) = %q, want %q", tt.files, got, tt.want)
+		}
+	}
+}
+
+func TestSetChangedFiles(t *testing.T) {
+	pr := model.PullRequest{
+		Dependencies: []model.Dependency{{Name: "requests"}, {Name: "urllib3"}},
+	}
+	SetChangedFiles(&pr, []string{"requirements.txt"})
+
+	if len(pr.Files) != 1 || pr.Dependency.Ecosystem != "pip" ||
+		pr.Dependencies[0].Ecosystem != "pip" || pr.Dependencies[1].Ecosystem != "pip" {
+		t.Errorf("expected pip ecosystem for the group, got %+v", pr)
+	}
+
+	pr = model.PullRequest{Dependency: model.Dependency{Name: "github.com/foo/bar", Ecosystem: "go"}}
+	SetChangedFiles(&pr, []string{"docs/index.md"})
+	if pr.Dependency.Ecosystem != "go" {
+		t.Errorf("expected ecosystem to be kept without manifests, got %q", pr.Dependency.Ecosystem)
+	}
+}
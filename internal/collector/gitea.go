@@ -0,0 +1,349 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/plexusone/versionconductor/internal/gitea"
+	"github.com/plexusone/versionconductor/internal/repoconfig"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// GiteaCollector implements Collector for Gitea and Forgejo repositories.
+type GiteaCollector struct {
+	botDetector
+	client *gitea.Client
+}
+
+// NewGiteaCollector creates a new Gitea collector using the given client.
+func NewGiteaCollector(client *gitea.Client) *GiteaCollector {
+	return &GiteaCollector{
+		client: client,
+	}
+}
+
+// ListRepos returns the repositories of the given organizations or users
+// matching the filter criteria.
+func (c *GiteaCollector) ListRepos(ctx context.Context, orgs []string, filter model.RepoFilter) ([]model.Repo, error) {
+	var repos []model.Repo
+
+	for _, org := range orgs {
+		giteaRepos, err := c.client.ListOrgRepos(ctx, org)
+		if gitea.IsNotFound(err) {
+			giteaRepos, err = c.client.ListUserRepos(ctx, org)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range giteaRepos {
+			repo := convertGiteaRepo(r)
+
+			if repo.Archived && !filter.IncludeArchived {
+				continue
+			}
+			if repo.Private && !filter.IncludePrivate {
+				continue
+			}
+			if r.Fork && !filter.IncludeForks {
+				continue
+			}
+			if r.Mirror {
+				continue
+			}
+			if isExcluded(repo.FullName, filter.ExcludeRepos) {
+				continue
+			}
+
+			repos = append(repos, repo)
+		}
+	}
+
+	return repos, nil
+}
+
+// ListDependencyPRs returns open dependency PRs for a repository.
+func (c *GiteaCollector) ListDependencyPRs(ctx context.Context, repo model.RepoRef) ([]model.PullRequest, error) {
+	pulls, err := c.client.ListPullRequests(ctx, repo.Owner, repo.Name, "open")
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []model.PullRequest
+	for i := range pulls {
+		mpr := convertGiteaPR(&pulls[i], repo)
+		if c.detectDependency(&mpr) {
+			prs = append(prs, mpr)
+		}
+	}
+
+	return prs, nil
+}
+
+// GetPRDetails returns detailed information about a PR.
+func (c *GiteaCollector) GetPRDetails(ctx context.Context, repo model.RepoRef, prNumber int) (*model.PullRequest, error) {
+	pr, err := c.client.GetPullRequest(ctx, repo.Owner, repo.Name, prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	mpr := convertGiteaPR(pr, repo)
+	c.detectDependency(&mpr)
+
+	return &mpr, nil
+}
+
+// GetPRChecks returns the commit statuses of a PR's head commit, which
+// include Gitea and Forgejo Actions jobs.
+func (c *GiteaCollector) GetPRChecks(ctx context.Context, repo model.RepoRef, prNumber int) ([]model.CheckRun, error) {
+	pr, err := c.client.GetPullRequest(ctx, repo.Owner, repo.Name, prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	combined, err := c.client.GetCombinedStatus(ctx, repo.Owner, repo.Name, pr.Head.SHA)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []model.CheckRun
+	for _, s := range combined.Statuses {
+		result = append(result, convertGiteaStatus(s))
+	}
+
+	return result, nil
+}
+
+// ListPRFiles returns the paths of the files changed by a PR.
+func (c *GiteaCollector) ListPRFiles(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	files, err := c.client.ListPullRequestFiles(ctx, repo.Owner, repo.Name, prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Filename)
+		if f.PreviousFilename != "" && f.PreviousFilename != f.Filename {
+			paths = append(paths, f.PreviousFilename)
+		}
+	}
+	return paths, nil
+}
+
+// ListPRCommitAuthors returns the distinct authors of a PR's commits.
+func (c *GiteaCollector) ListPRCommitAuthors(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	commits, err := c.client.ListPullRequestCommits(ctx, repo.Owner, repo.Name, prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var authors []string
+	for _, cm := range commits {
+		login := ""
+		if cm.Author != nil {
+			login = cm.Author.Login
+		}
+		authors = append(authors, commitAuthor(login, cm.Commit.Author.Name))
+	}
+	return uniqueAuthors(authors), nil
+}
+
+// ListPRApprovers returns the users whose latest review approves a PR.
+// Dismissed reviews and requests for changes withdraw an approval.
+func (c *GiteaCollector) ListPRApprovers(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	list, err := c.client.ListReviews(ctx, repo.Owner, repo.Name, prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviews []review
+	for _, r := range list {
+		switch {
+		case r.Dismissed, r.State == "REQUEST_CHANGES":
+			reviews = append(reviews, review{user: r.User.Login})
+		case r.State == "APPROVED":
+			reviews = append(reviews, review{user: r.User.Login, approved: true})
+		}
+	}
+	return approvers(reviews), nil
+}
+
+// GetLatestRelease returns the most recent release for a repository.
+func (c *GiteaCollector) GetLatestRelease(ctx context.Context, repo model.RepoRef) (*model.Release, error) {
+	r, err := c.client.GetLatestRelease(ctx, repo.Owner, repo.Name)
+	if err != nil || r == nil {
+		return nil, err
+	}
+
+	return convertGiteaRelease(r, repo), nil
+}
+
+// ListTags returns all tags for a repository.
+func (c *GiteaCollector) ListTags(ctx context.Context, repo model.RepoRef) ([]model.Tag, error) {
+	giteaTags, err := c.client.ListTags(ctx, repo.Owner, repo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []model.Tag
+	for _, t := range giteaTags {
+		tags = append(tags, model.Tag{
+			Name: t.Name,
+			SHA:  t.Commit.SHA,
+			Repo: repo,
+		})
+	}
+
+	return tags, nil
+}
+
+// GetMergedPRsSinceTag returns PRs merged since the given tag.
+func (c *GiteaCollector) GetMergedPRsSinceTag(ctx context.Context, repo model.RepoRef, tagName string) ([]model.PullRequest, error) {
+	t, err := c.client.GetTag(ctx, repo.Owner, repo.Name, tagName)
+	if err != nil {
+		return nil, err
+	}
+	since := t.Commit.Created
+
+	pulls, err := c.client.ListPullRequests(ctx, repo.Owner, repo.Name, "closed")
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []model.PullRequest
+	for i := range pulls {
+		if !pulls[i].Merged || pulls[i].MergedAt == nil || pulls[i].MergedAt.Before(since) {
+			continue
+		}
+		mpr := convertGiteaPR(&pulls[i], repo)
+		c.detectDependency(&mpr)
+		prs = append(prs, mpr)
+	}
+
+	return prs, nil
+}
+
+// GetRepoConfig returns the repository's in-repo config from its default
+// branch, or nil if the repository has none.
+func (c *GiteaCollector) GetRepoConfig(ctx context.Context, repo model.RepoRef) (*model.RepoConfig, error) {
+	r, err := c.client.GetRepo(ctx, repo.Owner, repo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.client.GetRawFile(ctx, repo.Owner, repo.Name, model.RepoConfigPath, r.DefaultBranch)
+	if err != nil {
+		if gitea.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return repoconfig.Parse(data)
+}
+
+// GetBranchProtection returns the protection rules of a branch, or nil if
+// the branch is not protected.
+func (c *GiteaCollector) GetBranchProtection(ctx context.Context, repo model.RepoRef, branch string) (*model.BranchProtection, error) {
+	if branch == "" {
+		r, err := c.client.GetRepo(ctx, repo.Owner, repo.Name)
+		if err != nil {
+			return nil, err
+		}
+		branch = r.DefaultBranch
+	}
+
+	b, err := c.client.GetBranch(ctx, repo.Owner, repo.Name, branch)
+	if err != nil {
+		return nil, err
+	}
+	if !b.Protected {
+		return nil, nil
+	}
+
+	bp := &model.BranchProtection{
+		Branch:            branch,
+		RequiredApprovals: b.RequiredApprovals,
+	}
+	if b.EnableStatusCheck {
+		bp.RequiredChecks = b.StatusCheckContexts
+	}
+
+	return bp, nil
+}
+
+// convertGiteaRepo converts a Gitea repository to our model.
+func convertGiteaRepo(r gitea.Repository) model.Repo {
+	return model.Repo{
+		Owner:         r.Owner.Login,
+		Name:          r.Name,
+		FullName:      r.FullName,
+		Description:   r.Description,
+		DefaultBranch: r.DefaultBranch,
+		Private:       r.Private || r.Internal,
+		Archived:      r.Archived,
+		Language:      r.Language,
+		Topics:        r.Topics,
+		UpdatedAt:     r.UpdatedAt,
+		HTMLURL:       r.HTMLURL,
+	}
+}
+
+// convertGiteaPR converts a Gitea pull request to our model. Gitea only
+// reports whether a PR can be merged without conflicts, which is mapped to
+// GitHub's clean and dirty mergeable states.
+func convertGiteaPR(pr *gitea.PullRequest, repo model.RepoRef) model.PullRequest {
+	mergeable := "clean"
+	if !pr.Mergeable {
+		mergeable = "dirty"
+	}
+
+	var labels []string
+	for _, l := range pr.Labels {
+		labels = append(labels, l.Name)
+	}
+
+	return model.PullRequest{
+		Number:       pr.Number,
+		Title:        pr.Title,
+		Body:         pr.Body,
+		State:        pr.State,
+		Author:       pr.User.Login,
+		HTMLURL:      pr.HTMLURL,
+		Mergeable:    pr.Mergeable,
+		MergeableStr: mergeable,
+		Draft:        pr.IsDraft(),
+		Labels:       labels,
+		HeadBranch:   pr.Head.Ref,
+		CreatedAt:    pr.CreatedAt,
+		UpdatedAt:    pr.UpdatedAt,
+		MergedAt:     pr.MergedAt,
+		Repo:         repo,
+	}
+}
+
+// convertGiteaStatus converts a Gitea commit status to a check run. A
+// warning doesn't block merging on Gitea, so it counts as neutral.
+func convertGiteaStatus(s gitea.CommitStatus) model.CheckRun {
+	c := model.NewStatusCheck(s.Context, s.Status)
+	if s.Status == "warning" {
+		c.Status = "completed"
+		c.Conclusion = "neutral"
+	}
+	return c
+}
+
+// convertGiteaRelease converts a Gitea release to our model.
+func convertGiteaRelease(r *gitea.Release, repo model.RepoRef) *model.Release {
+	return &model.Release{
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Body:        r.Body,
+		Draft:       r.Draft,
+		Prerelease:  r.Prerelease,
+		CreatedAt:   r.CreatedAt,
+		PublishedAt: r.PublishedAt,
+		HTMLURL:     r.HTMLURL,
+		Repo:        repo,
+	}
+}
@@ -0,0 +1,222 @@
+package collector
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/plexusone/versionconductor/internal/apitest"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestGiteaCollector_ListRepos(t *testing.T) {
+	server := apitest.NewGiteaServer(t, map[string]any{
+		"GET /orgs/team/repos": []map[string]any{
+			{"name": "tools", "full_name": "team/tools", "owner": map[string]any{"login": "team"}, "internal": true},
+			{"name": "web", "full_name": "team/web", "owner": map[string]any{"login": "team"}},
+			{"name": "upstream", "full_name": "team/upstream", "owner": map[string]any{"login": "team"}, "mirror": true},
+			{"name": "fork", "full_name": "team/fork", "owner": map[string]any{"login": "team"}, "fork": true},
+		},
+		"GET /users/alice/repos": []map[string]any{
+			{"name": "dotfiles", "full_name": "alice/dotfiles", "owner": map[string]any{"login": "alice"}},
+		},
+	})
+	c := NewGiteaCollector(server.GiteaClient(t))
+
+	repos, err := c.ListRepos(context.Background(), []string{"team", "alice"}, model.RepoFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, r := range repos {
+		names = append(names, r.FullName)
+	}
+	// The internal repo is private; mirrors and forks are skipped.
+	if len(names) != 2 || names[0] != "team/web" || names[1] != "alice/dotfiles" {
+		t.Errorf("expected [team/web alice/dotfiles], got %v", names)
+	}
+}
+
+func TestGiteaCollector_ListDependencyPRs(t *testing.T) {
+	server := apitest.NewGiteaServer(t, map[string]any{
+		"GET /repos/team/tools/pulls": []map[string]any{
+			{"number": 12, "title": "Update module golang.org/x/net to v0.30.0", "state": "open",
+				"user": map[string]any{"login": "renovate"}, "mergeable": true,
+				"labels": []map[string]any{{"name": "dependencies"}}},
+			{"number": 13, "title": "WIP: Update module github.com/spf13/cobra to v1.9.0", "state": "open",
+				"user": map[string]any{"login": "renovate"}, "mergeable": false},
+			{"number": 14, "title": "Fix typo", "state": "open", "user": map[string]any{"login": "alice"}},
+		},
+	})
+	c := NewGiteaCollector(server.GiteaClient(t))
+
+	prs, err := c.ListDependencyPRs(context.Background(), model.RepoRef{Owner: "team", Name: "tools"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 2 {
+		t.Fatalf("expected 2 dependency PRs, got %d", len(prs))
+	}
+
+	if pr := prs[0]; pr.Number != 12 || !pr.Mergeable || pr.MergeableStr != "clean" || pr.Draft ||
+		pr.DependBot != model.DependBotRenovate || len(pr.Labels) != 1 {
+		t.Errorf("unexpected PR: %+v", pr)
+	}
+	if pr := prs[1]; pr.Mergeable || pr.MergeableStr != "dirty" || !pr.Draft {
+		t.Errorf("expected conflicting draft PR, got %+v", pr)
+	}
+}
+
+func TestGiteaCollector_GetPRChecks(t *testing.T) {
+	server := apitest.NewGiteaServer(t, map[string]any{
+		"GET /repos/team/tools/pulls/12": map[string]any{"number": 12, "head": map[string]any{"sha": "abc123"}},
+		"GET /repos/team/tools/commits/abc123/status": map[string]any{
+			"state": "pending",
+			"statuses": []map[string]any{
+				{"context": "ci / build (push)", "status": "success"},
+				{"context": "ci / test (push)", "status": "failure"},
+				{"context": "lint", "status": "warning"},
+				{"context": "deploy", "status": "pending"},
+			},
+		},
+	})
+	c := NewGiteaCollector(server.GiteaClient(t))
+
+	checks, err := c.GetPRChecks(context.Background(), model.RepoRef{Owner: "team", Name: "tools"}, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct{ name, status, conclusion string }{
+		{"ci / build (push)", "completed", "success"},
+		{"ci / test (push)", "completed", "failure"},
+		{"lint", "completed", "neutral"},
+		{"deploy", "in_progress", ""},
+	}
+	if len(checks) != len(want) {
+		t.Fatalf("expected %d checks, got %+v", len(want), checks)
+	}
+	for i, w := range want {
+		c := checks[i]
+		if c.Name != w.name || c.Status != w.status || c.Conclusion != w.conclusion {
+			t.Errorf("check %d = %s %s/%s, want %s %s/%s", i, c.Name, c.Status, c.Conclusion, w.name, w.status, w.conclusion)
+		}
+	}
+}
+
+func TestGiteaCollector_GetBranchProtection(t *testing.T) {
+	server := apitest.NewGiteaServer(t, map[string]any{
+		"GET /repos/team/tools": map[string]any{"default_branch": "main"},
+		"GET /repos/team/tools/branches/main": map[string]any{
+			"name": "main", "protected": true, "required_approvals": 1,
+			"enable_status_check": true, "status_check_contexts": []string{"ci / build (push)"},
+		},
+		"GET /repos/team/tools/branches/dev": map[string]any{"name": "dev"},
+	})
+	c := NewGiteaCollector(server.GiteaClient(t))
+	ctx := context.Background()
+	repo := model.RepoRef{Owner: "team", Name: "tools"}
+
+	bp, err := c.GetBranchProtection(ctx, repo, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bp == nil || bp.Branch != "main" || bp.RequiredApprovals != 1 ||
+		len(bp.RequiredChecks) != 1 || bp.RequiredChecks[0] != "ci / build (push)" {
+		t.Errorf("unexpected protection: %+v", bp)
+	}
+
+	bp, err = c.GetBranchProtection(ctx, repo, "dev")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bp != nil {
+		t.Errorf("expected unprotected branch, got %+v", bp)
+	}
+}
+
+func TestGiteaCollector_GetRepoConfig(t *testing.T) {
+	server := apitest.NewGiteaServer(t, map[string]any{
+		"GET /repos/team/tools":                                   map[string]any{"default_branch": "main"},
+		"GET /repos/team/tools/raw/.github/versionconductor.yaml": []byte("disabled: true\n"),
+		"GET /repos/team/web":                                     map[string]any{"default_branch": "main"},
+	})
+	c := NewGiteaCollector(server.GiteaClient(t))
+	ctx := context.Background()
+
+	cfg, err := c.GetRepoConfig(ctx, model.RepoRef{Owner: "team", Name: "tools"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg == nil || !cfg.Disabled {
+		t.Errorf("expected disabling repo config, got %+v", cfg)
+	}
+
+	cfg, err = c.GetRepoConfig(ctx, model.RepoRef{Owner: "team", Name: "web"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg != nil {
+		t.Errorf("expected no config, got %+v", cfg)
+	}
+}
+
+func TestGiteaCollector_ListPRFiles(t *testing.T) {
+	server := apitest.NewGiteaServer(t, map[string]any{
+		"GET /repos/team/tools/pulls/12/files": []map[string]any{
+			{"filename": "package.json", "status": "modified"},
+			{"filename": "package-lock.json", "status": "renamed", "previous_filename": "npm-shrinkwrap.json"},
+		},
+	})
+	c := NewGiteaCollector(server.GiteaClient(t))
+
+	files, err := c.ListPRFiles(context.Background(), model.RepoRef{Owner: "team", Name: "tools"}, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"package.json", "package-lock.json", "npm-shrinkwrap.json"}; !slices.Equal(files, want) {
+		t.Errorf("expected files %v, got %v", want, files)
+	}
+}
+
+func TestGiteaCollector_ListPRApprovers(t *testing.T) {
+	server := apitest.NewGiteaServer(t, map[string]any{
+		"GET /repos/team/tools/pulls/12/reviews": []map[string]any{
+			{"user": map[string]any{"login": "alice"}, "state": "APPROVED"},
+			{"user": map[string]any{"login": "bob"}, "state": "APPROVED"},
+			{"user": map[string]any{"login": "carol"}, "state": "APPROVED", "dismissed": true},
+			{"user": map[string]any{"login": "bob"}, "state": "REQUEST_CHANGES"},
+			{"user": map[string]any{"login": "alice"}, "state": "COMMENT"},
+			{"user": map[string]any{"login": "dave"}, "state": "REQUEST_CHANGES"},
+			{"user": map[string]any{"login": "dave"}, "state": "APPROVED"},
+		},
+	})
+	c := NewGiteaCollector(server.GiteaClient(t))
+
+	approvers, err := c.ListPRApprovers(context.Background(), model.RepoRef{Owner: "team", Name: "tools"}, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"alice", "dave"}; !slices.Equal(approvers, want) {
+		t.Errorf("expected approvers %v, got %v", want, approvers)
+	}
+}
+
+func TestGiteaCollector_ListPRCommitAuthors(t *testing.T) {
+	server := apitest.NewGiteaServer(t, map[string]any{
+		"GET /repos/team/tools/pulls/12/commits": []map[string]any{
+			{"sha": "c1", "author": map[string]any{"login": "renovate-bot"}, "commit": map[string]any{"author": map[string]any{"name": "Renovate Bot"}}},
+			{"sha": "c2", "author": nil, "commit": map[string]any{"author": map[string]any{"name": "Mallory"}}},
+		},
+	})
+	c := NewGiteaCollector(server.GiteaClient(t))
+
+	authors, err := c.ListPRCommitAuthors(context.Background(), model.RepoRef{Owner: "team", Name: "tools"}, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"renovate-bot", "unlinked:Mallory"}; !slices.Equal(authors, want) {
+		t.Errorf("expected authors %v, got %v", want, authors)
+	}
+}
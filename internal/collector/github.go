@@ -2,29 +2,38 @@ package collector
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/google/go-github/v84/github"
-	"github.com/grokify/gogithub/auth"
 	"github.com/grokify/gogithub/checks"
 	"github.com/grokify/gogithub/pr"
 	"github.com/grokify/gogithub/release"
 	"github.com/grokify/gogithub/tag"
 
+	"github.com/plexusone/versionconductor/internal/auth"
+	"github.com/plexusone/versionconductor/internal/repoconfig"
 	"github.com/plexusone/versionconductor/pkg/model"
 )
 
 // GitHubCollector implements Collector for GitHub repositories.
 type GitHubCollector struct {
+	botDetector
 	client *github.Client
 }
 
 // NewGitHubCollector creates a new GitHub collector.
 func NewGitHubCollector(token string) *GitHubCollector {
-	ctx := context.Background()
-	client := auth.NewGitHubClient(ctx, token)
+	return NewGitHubCollectorWithClient(auth.NewClient(auth.NewTokenProvider(token)))
+}
+
+// NewGitHubCollectorWithClient creates a new GitHub collector using the
+// given client, e.g. one authenticated as a GitHub App or pointing at a
+// GitHub Enterprise Server.
+func NewGitHubCollectorWithClient(client *github.Client) *GitHubCollector {
 	return &GitHubCollector{
 		client: client,
 	}
@@ -98,10 +107,7 @@ func (c *GitHubCollector) ListDependencyPRs(ctx context.Context, repo model.Repo
 		mpr := convertPR(ghPR, repo)
 
 		// Check if this is a dependency PR
-		mpr.DependBot = model.DetectDependBot(mpr.Author)
-		if mpr.DependBot != model.DependBotUnknown {
-			mpr.IsDependency = true
-			mpr.Dependency = parseDependencyFromTitle(mpr.Title)
+		if c.detectDependency(&mpr) {
 			prs = append(prs, mpr)
 		}
 	}
@@ -117,11 +123,7 @@ func (c *GitHubCollector) GetPRDetails(ctx context.Context, repo model.RepoRef,
 	}
 
 	mpr := convertPR(ghPR, repo)
-	mpr.DependBot = model.DetectDependBot(mpr.Author)
-	if mpr.DependBot != model.DependBotUnknown {
-		mpr.IsDependency = true
-		mpr.Dependency = parseDependencyFromTitle(mpr.Title)
-	}
+	c.detectDependency(&mpr)
 
 	// Get mergeable status
 	if ghPR.Mergeable != nil {
@@ -134,23 +136,145 @@ func (c *GitHubCollector) GetPRDetails(ctx context.Context, repo model.RepoRef,
 	return &mpr, nil
 }
 
-// GetPRChecks returns the CI check runs for a PR.
+// GetPRChecks returns the CI check runs and commit statuses for a PR's
+// head commit.
 func (c *GitHubCollector) GetPRChecks(ctx context.Context, repo model.RepoRef, prNumber int) ([]model.CheckRun, error) {
 	ghChecks, err := checks.ListCheckRunsForPR(ctx, c.client, repo.Owner, repo.Name, prNumber)
 	if err != nil {
 		return nil, err
 	}
 
+	ghPR, err := pr.GetPR(ctx, c.client, repo.Owner, repo.Name, prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := c.listCommitStatuses(ctx, repo, ghPR.GetHead().GetSHA())
+	if err != nil {
+		return nil, err
+	}
+
+	return append(convertCheckRuns(ghChecks), statuses...), nil
+}
+
+// listCommitStatuses returns the latest commit status of each context for
+// a commit, as reported through the legacy Status API.
+func (c *GitHubCollector) listCommitStatuses(ctx context.Context, repo model.RepoRef, sha string) ([]model.CheckRun, error) {
+	var result []model.CheckRun
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		combined, resp, err := c.client.Repositories.GetCombinedStatus(ctx, repo.Owner, repo.Name, sha, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range combined.Statuses {
+			result = append(result, model.NewStatusCheck(s.GetContext(), s.GetState()))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
+
+// convertCheckRuns converts GitHub check runs to our model.
+func convertCheckRuns(ghChecks []*github.CheckRun) []model.CheckRun {
 	var result []model.CheckRun
 	for _, cr := range ghChecks {
 		result = append(result, model.CheckRun{
 			Name:       cr.GetName(),
 			Status:     cr.GetStatus(),
 			Conclusion: cr.GetConclusion(),
+			Source:     model.CheckSourceCheckRun,
 		})
 	}
+	return result
+}
 
-	return result, nil
+// ListPRFiles returns the paths of the files changed by a PR.
+func (c *GitHubCollector) ListPRFiles(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	var paths []string
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		files, resp, err := c.client.PullRequests.ListFiles(ctx, repo.Owner, repo.Name, prNumber, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range files {
+			paths = append(paths, f.GetFilename())
+			if prev := f.GetPreviousFilename(); prev != "" && prev != f.GetFilename() {
+				paths = append(paths, prev)
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return paths, nil
+}
+
+// ListPRCommitAuthors returns the distinct authors of a PR's commits.
+func (c *GitHubCollector) ListPRCommitAuthors(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	var authors []string
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		commits, resp, err := c.client.PullRequests.ListCommits(ctx, repo.Owner, repo.Name, prNumber, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cm := range commits {
+			authors = append(authors, commitAuthor(cm.GetAuthor().GetLogin(), cm.GetCommit().GetAuthor().GetName()))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return uniqueAuthors(authors), nil
+}
+
+// ListPRApprovers returns the users whose latest review approves a PR.
+// Dismissed reviews and requests for changes withdraw an approval.
+func (c *GitHubCollector) ListPRApprovers(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	var reviews []review
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		page, resp, err := c.client.PullRequests.ListReviews(ctx, repo.Owner, repo.Name, prNumber, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range page {
+			switch r.GetState() {
+			case "APPROVED":
+				reviews = append(reviews, review{user: r.GetUser().GetLogin(), approved: true})
+			case "CHANGES_REQUESTED", "DISMISSED":
+				reviews = append(reviews, review{user: r.GetUser().GetLogin()})
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return approvers(reviews), nil
 }
 
 // GetLatestRelease returns the most recent release for a repository.
@@ -241,11 +365,7 @@ func (c *GitHubCollector) GetMergedPRsSinceTag(ctx context.Context, repo model.R
 			}
 
 			mpr := convertPR(ghPR, repo)
-			mpr.DependBot = model.DetectDependBot(mpr.Author)
-			if mpr.DependBot != model.DependBotUnknown {
-				mpr.IsDependency = true
-				mpr.Dependency = parseDependencyFromTitle(mpr.Title)
-			}
+			c.detectDependency(&mpr)
 			prs = append(prs, mpr)
 		}
 
@@ -258,6 +378,95 @@ func (c *GitHubCollector) GetMergedPRsSinceTag(ctx context.Context, repo model.R
 	return prs, nil
 }
 
+// GetRepoConfig returns the repository's in-repo config from its default
+// branch, or nil if the repository has none.
+func (c *GitHubCollector) GetRepoConfig(ctx context.Context, repo model.RepoRef) (*model.RepoConfig, error) {
+	file, _, resp, err := c.client.Repositories.GetContents(ctx, repo.Owner, repo.Name, model.RepoConfigPath, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if file == nil {
+		return nil, nil
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+
+	return repoconfig.Parse([]byte(content))
+}
+
+// GetBranchProtection returns the protection rules of a branch, or nil if
+// the branch is not protected. Reading protection settings requires admin
+// access, so without it the required checks are read from the branch
+// itself.
+func (c *GitHubCollector) GetBranchProtection(ctx context.Context, repo model.RepoRef, branch string) (*model.BranchProtection, error) {
+	if branch == "" {
+		r, _, err := c.client.Repositories.Get(ctx, repo.Owner, repo.Name)
+		if err != nil {
+			return nil, err
+		}
+		branch = r.GetDefaultBranch()
+	}
+
+	protection, resp, err := c.client.Repositories.GetBranchProtection(ctx, repo.Owner, repo.Name, branch)
+	if err != nil {
+		if errors.Is(err, github.ErrBranchNotProtected) {
+			return nil, nil
+		}
+		if resp == nil || (resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusNotFound) {
+			return nil, err
+		}
+
+		b, _, err := c.client.Repositories.GetBranch(ctx, repo.Owner, repo.Name, branch, 1)
+		if err != nil {
+			return nil, err
+		}
+		if !b.GetProtected() || b.Protection == nil {
+			return nil, nil
+		}
+		protection = b.Protection
+	}
+
+	return convertProtection(branch, protection), nil
+}
+
+// convertProtection converts GitHub branch protection to our model.
+// Required checks are the union of legacy status contexts and checks.
+func convertProtection(branch string, p *github.Protection) *model.BranchProtection {
+	bp := &model.BranchProtection{Branch: branch}
+
+	if rsc := p.GetRequiredStatusChecks(); rsc != nil {
+		seen := make(map[string]bool)
+		add := func(name string) {
+			if name != "" && !seen[name] {
+				seen[name] = true
+				bp.RequiredChecks = append(bp.RequiredChecks, name)
+			}
+		}
+		if rsc.Contexts != nil {
+			for _, name := range *rsc.Contexts {
+				add(name)
+			}
+		}
+		if rsc.Checks != nil {
+			for _, check := range *rsc.Checks {
+				add(check.Context)
+			}
+		}
+	}
+
+	if reviews := p.GetRequiredPullRequestReviews(); reviews != nil {
+		bp.RequiredApprovals = reviews.RequiredApprovingReviewCount
+	}
+
+	return bp
+}
+
 // convertRepo converts a GitHub repository to our model.
 func convertRepo(r *github.Repository) model.Repo {
 	var topics []string
@@ -288,17 +497,18 @@ func convertPR(ghPR *github.PullRequest, repo model.RepoRef) model.PullRequest {
 	}
 
 	mpr := model.PullRequest{
-		Number:    ghPR.GetNumber(),
-		Title:     ghPR.GetTitle(),
-		Body:      ghPR.GetBody(),
-		State:     ghPR.GetState(),
-		Author:    ghPR.GetUser().GetLogin(),
-		HTMLURL:   ghPR.GetHTMLURL(),
-		Draft:     ghPR.GetDraft(),
-		Labels:    labels,
-		CreatedAt: ghPR.GetCreatedAt().Time,
-		UpdatedAt: ghPR.GetUpdatedAt().Time,
-		Repo:      repo,
+		Number:     ghPR.GetNumber(),
+		Title:      ghPR.GetTitle(),
+		Body:       ghPR.GetBody(),
+		State:      ghPR.GetState(),
+		Author:     ghPR.GetUser().GetLogin(),
+		HTMLURL:    ghPR.GetHTMLURL(),
+		Draft:      ghPR.GetDraft(),
+		Labels:     labels,
+		HeadBranch: ghPR.GetHead().GetRef(),
+		CreatedAt:  ghPR.GetCreatedAt().Time,
+		UpdatedAt:  ghPR.GetUpdatedAt().Time,
+		Repo:       repo,
 	}
 
 	if ghPR.MergedAt != nil {
@@ -309,6 +519,35 @@ func convertPR(ghPR *github.PullRequest, repo model.RepoRef) model.PullRequest {
 	return mpr
 }
 
+// detectDependency marks a PR belonging to one of the bots, or to the
+// built-in bots if none are given, as a dependency PR and parses its
+// dependencies with the bot's parser, or else from the title. It returns
+// true if the PR is a dependency PR.
+func detectDependency(mpr *model.PullRequest, bots Bots) bool {
+	if bots == nil {
+		bots = DefaultBots
+	}
+	bot, ok := bots.Detect(mpr)
+	if !ok {
+		mpr.DependBot = model.DependBotUnknown
+		return false
+	}
+	mpr.DependBot = bot.Name
+	mpr.BotAuthors = bot.Authors
+	mpr.IsDependency = true
+
+	switch deps := parseDependencies(bot.Parser, mpr.Body, mpr.Labels); len(deps) {
+	case 0:
+		mpr.Dependency = parseDependencyFromTitle(mpr.Title)
+	case 1:
+		mpr.Dependency = deps[0]
+	default:
+		mpr.Dependencies = deps
+		mpr.Dependency = model.GroupDependency(deps)
+	}
+	return true
+}
+
 // parseDependencyFromTitle extracts dependency information from a PR title.
 func parseDependencyFromTitle(title string) model.Dependency {
 	dep := model.Dependency{}
@@ -433,7 +672,8 @@ func TestsPassed(checkRuns []model.CheckRun) bool {
 	return true
 }
 
-// WaitForChecks polls until all checks complete or timeout.
+// WaitForChecks polls until all check runs complete or timeout. Commit
+// statuses are not waited for; their state at the end is included.
 func (c *GitHubCollector) WaitForChecks(ctx context.Context, repo model.RepoRef, prNumber int, timeout time.Duration) ([]model.CheckRun, error) {
 	// Get PR to get head SHA
 	ghPR, err := pr.GetPR(ctx, c.client, repo.Owner, repo.Name, prNumber)
@@ -449,14 +689,10 @@ func (c *GitHubCollector) WaitForChecks(ctx context.Context, repo model.RepoRef,
 		return nil, err
 	}
 
-	var result []model.CheckRun
-	for _, cr := range ghChecks {
-		result = append(result, model.CheckRun{
-			Name:       cr.GetName(),
-			Status:     cr.GetStatus(),
-			Conclusion: cr.GetConclusion(),
-		})
+	statuses, err := c.listCommitStatuses(ctx, repo, sha)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	return append(convertCheckRuns(ghChecks), statuses...), nil
 }
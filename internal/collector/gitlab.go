@@ -0,0 +1,406 @@
+package collector
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/plexusone/versionconductor/internal/gitlab"
+	"github.com/plexusone/versionconductor/internal/repoconfig"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// GitLabCollector implements Collector for GitLab projects. Groups take
+// the place of organizations and merge requests that of pull requests; a
+// repository's owner is the full path of its group, e.g. "group/subgroup".
+type GitLabCollector struct {
+	botDetector
+	client *gitlab.Client
+
+	mu        sync.Mutex
+	usernames map[string]string // lowercase commit email -> username, "" if none
+}
+
+// NewGitLabCollector creates a new GitLab collector using the given client.
+func NewGitLabCollector(client *gitlab.Client) *GitLabCollector {
+	return &GitLabCollector{
+		client:    client,
+		usernames: make(map[string]string),
+	}
+}
+
+// ListRepos returns the projects of the given groups or users, including
+// those in subgroups, matching the filter criteria.
+func (c *GitLabCollector) ListRepos(ctx context.Context, orgs []string, filter model.RepoFilter) ([]model.Repo, error) {
+	var repos []model.Repo
+
+	for _, org := range orgs {
+		projects, err := c.client.ListGroupProjects(ctx, org)
+		if gitlab.IsNotFound(err) {
+			projects, err = c.client.ListUserProjects(ctx, org)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range projects {
+			repo := convertGitLabProject(p)
+
+			if repo.Archived && !filter.IncludeArchived {
+				continue
+			}
+			if repo.Private && !filter.IncludePrivate {
+				continue
+			}
+			if p.ForkedFromProject != nil && !filter.IncludeForks {
+				continue
+			}
+			if isExcluded(repo.FullName, filter.ExcludeRepos) {
+				continue
+			}
+
+			repos = append(repos, repo)
+		}
+	}
+
+	return repos, nil
+}
+
+// ListDependencyPRs returns open dependency merge requests for a project.
+func (c *GitLabCollector) ListDependencyPRs(ctx context.Context, repo model.RepoRef) ([]model.PullRequest, error) {
+	mrs, err := c.client.ListMergeRequests(ctx, repo.FullName(), gitlab.ListMergeRequestsOptions{State: "opened"})
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []model.PullRequest
+	for i := range mrs {
+		mpr := convertGitLabMR(&mrs[i], repo)
+		if c.detectDependency(&mpr) {
+			prs = append(prs, mpr)
+		}
+	}
+
+	return prs, nil
+}
+
+// GetPRDetails returns detailed information about a merge request.
+func (c *GitLabCollector) GetPRDetails(ctx context.Context, repo model.RepoRef, prNumber int) (*model.PullRequest, error) {
+	mr, err := c.client.GetMergeRequest(ctx, repo.FullName(), prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	mpr := convertGitLabMR(mr, repo)
+	c.detectDependency(&mpr)
+
+	return &mpr, nil
+}
+
+// GetPRChecks returns the pipeline jobs and external statuses of a merge
+// request's head commit.
+func (c *GitLabCollector) GetPRChecks(ctx context.Context, repo model.RepoRef, prNumber int) ([]model.CheckRun, error) {
+	mr, err := c.client.GetMergeRequest(ctx, repo.FullName(), prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := c.client.ListCommitStatuses(ctx, repo.FullName(), mr.SHA)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []model.CheckRun
+	for _, s := range statuses {
+		if check, ok := convertGitLabStatus(s); ok {
+			result = append(result, check)
+		}
+	}
+
+	return result, nil
+}
+
+// ListPRFiles returns the paths of the files changed by a merge request.
+func (c *GitLabCollector) ListPRFiles(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	diffs, err := c.client.ListMergeRequestDiffs(ctx, repo.FullName(), prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, d := range diffs {
+		paths = append(paths, d.NewPath)
+		if d.OldPath != d.NewPath {
+			paths = append(paths, d.OldPath)
+		}
+	}
+	return paths, nil
+}
+
+// ListPRCommitAuthors returns the distinct authors of a merge request's
+// commits. GitLab doesn't link commits to users, so each author email is
+// looked up among the users' public emails; authors without exactly one
+// matching user are unlinked.
+func (c *GitLabCollector) ListPRCommitAuthors(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	commits, err := c.client.ListMergeRequestCommits(ctx, repo.FullName(), prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var authors []string
+	for _, cm := range commits {
+		username, err := c.username(ctx, cm.AuthorEmail)
+		if err != nil {
+			return nil, err
+		}
+		authors = append(authors, commitAuthor(username, cm.AuthorName))
+	}
+	return uniqueAuthors(authors), nil
+}
+
+// username returns the user with the given email, or "" if there is no
+// single such user. Lookups are cached for the collector's lifetime.
+func (c *GitLabCollector) username(ctx context.Context, email string) (string, error) {
+	if email == "" {
+		return "", nil
+	}
+	key := strings.ToLower(email)
+
+	c.mu.Lock()
+	username, ok := c.usernames[key]
+	c.mu.Unlock()
+	if ok {
+		return username, nil
+	}
+
+	users, err := c.client.SearchUsers(ctx, email)
+	if err != nil {
+		return "", err
+	}
+	if len(users) == 1 {
+		username = users[0].Username
+	}
+
+	c.mu.Lock()
+	c.usernames[key] = username
+	c.mu.Unlock()
+
+	return username, nil
+}
+
+// ListPRApprovers returns the users approving a merge request.
+func (c *GitLabCollector) ListPRApprovers(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	approvals, err := c.client.GetMergeRequestApprovals(ctx, repo.FullName(), prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]string, 0, len(approvals.ApprovedBy))
+	for _, a := range approvals.ApprovedBy {
+		users = append(users, a.User.Username)
+	}
+	return users, nil
+}
+
+// GetLatestRelease returns the most recent release for a project.
+func (c *GitLabCollector) GetLatestRelease(ctx context.Context, repo model.RepoRef) (*model.Release, error) {
+	r, err := c.client.GetLatestRelease(ctx, repo.FullName())
+	if err != nil || r == nil {
+		return nil, err
+	}
+
+	return convertGitLabRelease(r, repo), nil
+}
+
+// ListTags returns all tags for a project.
+func (c *GitLabCollector) ListTags(ctx context.Context, repo model.RepoRef) ([]model.Tag, error) {
+	glTags, err := c.client.ListTags(ctx, repo.FullName())
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []model.Tag
+	for _, t := range glTags {
+		tags = append(tags, model.Tag{
+			Name: t.Name,
+			SHA:  t.Commit.ID,
+			Repo: repo,
+		})
+	}
+
+	return tags, nil
+}
+
+// GetMergedPRsSinceTag returns merge requests merged since the given tag.
+func (c *GitLabCollector) GetMergedPRsSinceTag(ctx context.Context, repo model.RepoRef, tagName string) ([]model.PullRequest, error) {
+	t, err := c.client.GetTag(ctx, repo.FullName(), tagName)
+	if err != nil {
+		return nil, err
+	}
+	since := t.Commit.CommittedDate
+
+	mrs, err := c.client.ListMergeRequests(ctx, repo.FullName(), gitlab.ListMergeRequestsOptions{
+		State:        "merged",
+		UpdatedAfter: since,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []model.PullRequest
+	for i := range mrs {
+		if mrs[i].MergedAt == nil || mrs[i].MergedAt.Before(since) {
+			continue
+		}
+		mpr := convertGitLabMR(&mrs[i], repo)
+		c.detectDependency(&mpr)
+		prs = append(prs, mpr)
+	}
+
+	return prs, nil
+}
+
+// GetRepoConfig returns the project's in-repo config from its default
+// branch, or nil if the project has none.
+func (c *GitLabCollector) GetRepoConfig(ctx context.Context, repo model.RepoRef) (*model.RepoConfig, error) {
+	data, err := c.client.GetRawFile(ctx, repo.FullName(), model.RepoConfigPath, "HEAD")
+	if err != nil {
+		if gitlab.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return repoconfig.Parse(data)
+}
+
+// GetBranchProtection returns the protection rules of a branch, or nil if
+// the branch is not protected. GitLab has no per-branch required checks;
+// the required approvals are the sum of the project's approval rules.
+func (c *GitLabCollector) GetBranchProtection(ctx context.Context, repo model.RepoRef, branch string) (*model.BranchProtection, error) {
+	if branch == "" {
+		p, err := c.client.GetProject(ctx, repo.FullName())
+		if err != nil {
+			return nil, err
+		}
+		branch = p.DefaultBranch
+	}
+
+	if _, err := c.client.GetProtectedBranch(ctx, repo.FullName(), branch); err != nil {
+		if gitlab.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	bp := &model.BranchProtection{Branch: branch}
+
+	// Approval rules are a paid feature; without them none are required.
+	rules, err := c.client.ListApprovalRules(ctx, repo.FullName())
+	if err != nil && !gitlab.IsNotFound(err) {
+		return nil, err
+	}
+	for _, rule := range rules {
+		bp.RequiredApprovals += rule.ApprovalsRequired
+	}
+
+	return bp, nil
+}
+
+// convertGitLabProject converts a GitLab project to our model.
+func convertGitLabProject(p gitlab.Project) model.Repo {
+	owner := p.Namespace.FullPath
+	if owner == "" {
+		owner, _, _ = strings.Cut(p.PathWithNamespace, "/")
+	}
+
+	return model.Repo{
+		Owner:         owner,
+		Name:          p.Path,
+		FullName:      p.PathWithNamespace,
+		Description:   p.Description,
+		DefaultBranch: p.DefaultBranch,
+		Private:       p.Visibility != "public",
+		Archived:      p.Archived,
+		Topics:        p.Topics,
+		UpdatedAt:     p.LastActivityAt,
+		HTMLURL:       p.WebURL,
+	}
+}
+
+// convertGitLabMR converts a GitLab merge request to our model.
+func convertGitLabMR(mr *gitlab.MergeRequest, repo model.RepoRef) model.PullRequest {
+	state := "open"
+	if mr.State != "opened" {
+		state = "closed"
+	}
+
+	return model.PullRequest{
+		Number:       mr.IID,
+		Title:        mr.Title,
+		Body:         mr.Description,
+		State:        state,
+		Author:       mr.Author.Username,
+		HTMLURL:      mr.WebURL,
+		Mergeable:    mr.Mergeable(),
+		MergeableStr: mr.MergeStatusDetail(),
+		Draft:        mr.Draft,
+		Labels:       mr.Labels,
+		HeadBranch:   mr.SourceBranch,
+		CreatedAt:    mr.CreatedAt,
+		UpdatedAt:    mr.UpdatedAt,
+		MergedAt:     mr.MergedAt,
+		Repo:         repo,
+	}
+}
+
+// convertGitLabStatus converts a GitLab commit status to a check run.
+// Jobs allowed to fail, which includes manual jobs by default, and skipped
+// jobs don't affect a pipeline's result, so they are left out unless they
+// succeeded.
+func convertGitLabStatus(s gitlab.CommitStatus) (model.CheckRun, bool) {
+	c := model.CheckRun{
+		Name:   s.Name,
+		Status: "completed",
+		Source: model.CheckSourceStatus,
+	}
+
+	switch s.Status {
+	case "success":
+		c.Conclusion = "success"
+		return c, true
+	case "skipped":
+		return c, false
+	}
+	if s.AllowFailure {
+		return c, false
+	}
+
+	switch s.Status {
+	case "failed":
+		c.Conclusion = "failure"
+	case "canceled":
+		c.Conclusion = "cancelled"
+	case "manual":
+		c.Conclusion = "action_required"
+	case "running":
+		c.Status = "in_progress"
+	default:
+		c.Status = "queued"
+	}
+
+	return c, true
+}
+
+// convertGitLabRelease converts a GitLab release to our model.
+func convertGitLabRelease(r *gitlab.Release, repo model.RepoRef) *model.Release {
+	return &model.Release{
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Body:        r.Description,
+		CreatedAt:   r.CreatedAt,
+		PublishedAt: r.ReleasedAt,
+		HTMLURL:     r.Links.Self,
+		Repo:        repo,
+	}
+}
@@ -0,0 +1,296 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/plexusone/versionconductor/internal/apitest"
+	"github.com/plexusone/versionconductor/internal/policy"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestGitLabCollector_ListRepos(t *testing.T) {
+	server := apitest.NewGitLabServer(t, map[string]any{
+		"GET /groups/platform/projects": []map[string]any{
+			{"path": "api", "path_with_namespace": "platform/api", "visibility": "internal",
+				"namespace": map[string]any{"full_path": "platform"}},
+			{"path": "web", "path_with_namespace": "platform/frontend/web", "visibility": "public",
+				"namespace": map[string]any{"full_path": "platform/frontend"}},
+			{"path": "old", "path_with_namespace": "platform/old", "visibility": "public", "archived": true,
+				"namespace": map[string]any{"full_path": "platform"}},
+			{"path": "fork", "path_with_namespace": "platform/fork", "visibility": "public",
+				"namespace": map[string]any{"full_path": "platform"}, "forked_from_project": map[string]any{"id": 1}},
+		},
+		"GET /users/alice/projects": []map[string]any{
+			{"path": "dotfiles", "path_with_namespace": "alice/dotfiles", "visibility": "public",
+				"namespace": map[string]any{"full_path": "alice"}},
+		},
+	})
+	c := NewGitLabCollector(server.GitLabClient(t))
+
+	repos, err := c.ListRepos(context.Background(), []string{"platform", "alice"}, model.RepoFilter{IncludePrivate: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct{ owner, name, fullName string }{
+		{"platform", "api", "platform/api"},
+		{"platform/frontend", "web", "platform/frontend/web"},
+		{"alice", "dotfiles", "alice/dotfiles"},
+	}
+	if len(repos) != len(want) {
+		t.Fatalf("expected %d repos, got %+v", len(want), repos)
+	}
+	for i, w := range want {
+		r := repos[i]
+		if r.Owner != w.owner || r.Name != w.name || r.FullName != w.fullName {
+			t.Errorf("repo %d = %s %s %s, want %s %s %s", i, r.Owner, r.Name, r.FullName, w.owner, w.name, w.fullName)
+		}
+	}
+	if !repos[0].Private || repos[1].Private {
+		t.Errorf("expected internal project to be private and public project not, got %v and %v", repos[0].Private, repos[1].Private)
+	}
+}
+
+func TestGitLabCollector_ListDependencyPRs(t *testing.T) {
+	server := apitest.NewGitLabServer(t, map[string]any{
+		"GET /projects/platform%2Ffrontend%2Fweb/merge_requests": []map[string]any{
+			{"iid": 7, "title": "Update dependency lodash to v4.17.21", "state": "opened",
+				"author": map[string]any{"username": "renovate-bot"}, "detailed_merge_status": "mergeable"},
+			{"iid": 8, "title": "Add feature", "state": "opened",
+				"author": map[string]any{"username": "alice"}},
+		},
+	})
+	c := NewGitLabCollector(server.GitLabClient(t))
+
+	repo := model.RepoRef{Owner: "platform/frontend", Name: "web"}
+	prs, err := c.ListDependencyPRs(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("expected 1 dependency MR, got %d", len(prs))
+	}
+
+	pr := prs[0]
+	if pr.Number != 7 || pr.State != "open" || !pr.Mergeable || pr.DependBot != model.DependBotRenovate {
+		t.Errorf("unexpected MR: %+v", pr)
+	}
+	if pr.Dependency.ToVersion != "v4.17.21" {
+		t.Errorf("expected to version v4.17.21, got %q", pr.Dependency.ToVersion)
+	}
+
+	req, ok := server.Find(http.MethodGet, "/projects/platform%2Ffrontend%2Fweb/merge_requests")
+	if !ok || req.Query["state"][0] != "opened" {
+		t.Errorf("expected open merge requests to be listed, got %+v", req)
+	}
+}
+
+func TestGitLabCollector_GetPRChecks(t *testing.T) {
+	server := apitest.NewGitLabServer(t, map[string]any{
+		"GET /projects/group%2Fapp/merge_requests/3": map[string]any{"iid": 3, "sha": "abc123"},
+		"GET /projects/group%2Fapp/repository/commits/abc123/statuses": []map[string]any{
+			{"name": "build", "status": "success"},
+			{"name": "test", "status": "failed"},
+			{"name": "lint", "status": "failed", "allow_failure": true},
+			{"name": "deploy", "status": "manual", "allow_failure": true},
+			{"name": "docs", "status": "skipped"},
+			{"name": "e2e", "status": "running"},
+			{"name": "scan", "status": "created"},
+			{"name": "approve", "status": "manual"},
+		},
+	})
+	c := NewGitLabCollector(server.GitLabClient(t))
+
+	checks, err := c.GetPRChecks(context.Background(), model.RepoRef{Owner: "group", Name: "app"}, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string][2]string{
+		"build":   {"completed", "success"},
+		"test":    {"completed", "failure"},
+		"e2e":     {"in_progress", ""},
+		"scan":    {"queued", ""},
+		"approve": {"completed", "action_required"},
+	}
+	if len(checks) != len(want) {
+		t.Fatalf("expected %d checks, got %+v", len(want), checks)
+	}
+	for _, check := range checks {
+		w, ok := want[check.Name]
+		if !ok {
+			t.Errorf("unexpected check %q", check.Name)
+			continue
+		}
+		if check.Status != w[0] || check.Conclusion != w[1] {
+			t.Errorf("check %q = %s/%s, want %s/%s", check.Name, check.Status, check.Conclusion, w[0], w[1])
+		}
+		if check.Source != model.CheckSourceStatus {
+			t.Errorf("check %q source = %q, want %q", check.Name, check.Source, model.CheckSourceStatus)
+		}
+	}
+}
+
+func TestGitLabCollector_GetBranchProtection(t *testing.T) {
+	server := apitest.NewGitLabServer(t, map[string]any{
+		"GET /projects/group%2Fapp":                         map[string]any{"default_branch": "main"},
+		"GET /projects/group%2Fapp/protected_branches/main": map[string]any{"name": "main"},
+		"GET /projects/group%2Fapp/approval_rules": []map[string]any{
+			{"name": "maintainers", "approvals_required": 1},
+			{"name": "security", "approvals_required": 1},
+		},
+		"GET /projects/group%2Fother":                         map[string]any{"default_branch": "main"},
+		"GET /projects/group%2Fother/protected_branches/main": map[string]any{"name": "main"},
+	})
+	c := NewGitLabCollector(server.GitLabClient(t))
+	ctx := context.Background()
+
+	bp, err := c.GetBranchProtection(ctx, model.RepoRef{Owner: "group", Name: "app"}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bp == nil || bp.Branch != "main" || bp.RequiredApprovals != 2 {
+		t.Errorf("expected main to require 2 approvals, got %+v", bp)
+	}
+
+	// Without approval rules, the branch is protected but needs no approvals.
+	bp, err = c.GetBranchProtection(ctx, model.RepoRef{Owner: "group", Name: "other"}, "main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bp == nil || bp.RequiredApprovals != 0 {
+		t.Errorf("expected protection without approvals, got %+v", bp)
+	}
+
+	bp, err = c.GetBranchProtection(ctx, model.RepoRef{Owner: "group", Name: "app"}, "develop")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bp != nil {
+		t.Errorf("expected unprotected branch, got %+v", bp)
+	}
+}
+
+func TestGitLabCollector_GetRepoConfig(t *testing.T) {
+	server := apitest.NewGitLabServer(t, map[string]any{
+		"GET /projects/group%2Fapp/repository/files/.github%2Fversionconductor.yaml/raw": []byte("disabled: true\n"),
+	})
+	c := NewGitLabCollector(server.GitLabClient(t))
+	ctx := context.Background()
+
+	cfg, err := c.GetRepoConfig(ctx, model.RepoRef{Owner: "group", Name: "app"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg == nil || !cfg.Disabled {
+		t.Errorf("expected disabling repo config, got %+v", cfg)
+	}
+
+	cfg, err = c.GetRepoConfig(ctx, model.RepoRef{Owner: "group", Name: "other"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg != nil {
+		t.Errorf("expected no config for project without file, got %+v", cfg)
+	}
+}
+
+func TestGitLabCollector_ListPRFiles(t *testing.T) {
+	server := apitest.NewGitLabServer(t, map[string]any{
+		"GET /projects/group%2Fapp/merge_requests/3/diffs": []map[string]any{
+			{"old_path": "go.mod", "new_path": "go.mod"},
+			{"old_path": "go.sum", "new_path": "go.sum"},
+		},
+	})
+	c := NewGitLabCollector(server.GitLabClient(t))
+
+	files, err := c.ListPRFiles(context.Background(), model.RepoRef{Owner: "group", Name: "app"}, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 2 || files[0] != "go.mod" || files[1] != "go.sum" {
+		t.Errorf("expected go.mod and go.sum, got %v", files)
+	}
+}
+
+func TestGitLabCollector_ListPRApprovers(t *testing.T) {
+	server := apitest.NewGitLabServer(t, map[string]any{
+		"GET /projects/group%2Fapp/merge_requests/3/approvals": map[string]any{
+			"approved_by": []map[string]any{{"user": map[string]any{"username": "alice"}}},
+		},
+	})
+	c := NewGitLabCollector(server.GitLabClient(t))
+
+	approvers, err := c.ListPRApprovers(context.Background(), model.RepoRef{Owner: "group", Name: "app"}, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"alice"}; !slices.Equal(approvers, want) {
+		t.Errorf("expected approvers %v, got %v", want, approvers)
+	}
+}
+
+func TestGitLabCollector_ListPRCommitAuthors(t *testing.T) {
+	server := apitest.NewGitLabServer(t, map[string]any{
+		"GET /projects/group%2Fapp/merge_requests/3/commits": []map[string]any{
+			{"id": "c2", "author_name": "Renovate Bot"},
+			{"id": "c1", "author_name": "Renovate Bot"},
+			{"id": "c0", "author_name": "Alice"},
+		},
+	})
+	c := NewGitLabCollector(server.GitLabClient(t))
+
+	authors, err := c.ListPRCommitAuthors(context.Background(), model.RepoRef{Owner: "group", Name: "app"}, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"unlinked:Renovate Bot", "unlinked:Alice"}; !slices.Equal(authors, want) {
+		t.Errorf("expected authors %v, got %v", want, authors)
+	}
+}
+
+func TestGitLabCollector_ListPRCommitAuthors_ResolvesEmails(t *testing.T) {
+	server := apitest.NewGitLabServer(t, map[string]any{
+		"GET /projects/group%2Fapp/merge_requests": []map[string]any{
+			{"iid": 3, "title": "Update dependency lodash to v4.17.21", "state": "opened",
+				"author": map[string]any{"username": "renovate-bot", "bot": true}},
+		},
+		"GET /projects/group%2Fapp/merge_requests/3/commits": []map[string]any{
+			{"id": "c1", "author_name": "Renovate Bot", "author_email": "bot@renovateapp.com"},
+			{"id": "c0", "author_name": "Renovate Bot", "author_email": "Bot@renovateapp.com"},
+		},
+		"GET /users": []map[string]any{{"username": "renovate-bot", "bot": true}},
+	})
+	c := NewGitLabCollector(server.GitLabClient(t))
+	repo := model.RepoRef{Owner: "group", Name: "app"}
+
+	prs, err := c.ListDependencyPRs(context.Background(), repo)
+	if err != nil || len(prs) != 1 {
+		t.Fatalf("expected 1 dependency MR, got %d (%v)", len(prs), err)
+	}
+	pr := prs[0]
+
+	pr.CommitAuthors, err = c.ListPRCommitAuthors(context.Background(), repo, pr.Number)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"renovate-bot"}; !slices.Equal(pr.CommitAuthors, want) {
+		t.Errorf("expected authors %v, got %v", want, pr.CommitAuthors)
+	}
+	if others := policy.OtherCommitAuthors(&pr, nil); len(others) != 0 {
+		t.Errorf("expected the Renovate MR to pass the commit author check, got %v", others)
+	}
+
+	var searches []string
+	for _, req := range server.Requests() {
+		if req.Path == "/users" {
+			searches = append(searches, req.Query["search"][0])
+		}
+	}
+	if want := []string{"bot@renovateapp.com"}; !slices.Equal(searches, want) {
+		t.Errorf("expected one cached user search %v, got %v", want, searches)
+	}
+}
@@ -0,0 +1,580 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v84/github"
+
+	"github.com/plexusone/versionconductor/internal/auth"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+const (
+	// graphQLBatchSize is the number of repositories queried at once.
+	graphQLBatchSize = 10
+
+	// graphQLPageSize is the number of PRs fetched per repository and page.
+	graphQLPageSize = 25
+)
+
+// graphQLPRFields are the fields read for each pull request, including
+// the check runs and commit statuses of its head commit.
+const graphQLPRFields = `number title body state url isDraft headRefName createdAt updatedAt mergedAt
+mergeable mergeStateStatus
+author { __typename login }
+labels(first: 20) { nodes { name } }
+files(first: 100) { totalCount nodes { path changeType } }
+allCommits: commits(first: 100) { totalCount nodes { commit { author { name user { login } } } } }
+commits(last: 1) { nodes { commit { statusCheckRollup { contexts(first: 100) { pageInfo { hasNextPage } nodes {
+  __typename
+  ... on CheckRun { name status conclusion }
+  ... on StatusContext { context state }
+} } } } } }`
+
+// graphQLPRConnection is the selection for a page of open PRs.
+const graphQLPRConnection = `pageInfo { hasNextPage endCursor } nodes { ` + graphQLPRFields + ` }`
+
+// GraphQLCollector implements Collector using the GitHub GraphQL API for
+// pull requests and their checks, so a PR's list entry, checks and
+// mergeable state cost one query instead of three REST calls. Other
+// methods use the REST API.
+type GraphQLCollector struct {
+	*GitHubCollector
+
+	mu    sync.Mutex
+	cache map[string][]*graphQLCachedPR
+}
+
+// graphQLCachedPR is a prefetched PR. Details are served from the cache
+// only once, so later reads see the current mergeable state.
+type graphQLCachedPR struct {
+	pr          model.PullRequest
+	checks      []model.CheckRun
+	checksRead  bool     // false if the PR's checks must be listed with REST
+	files       []string // nil if the PR's files must be listed with REST
+	authors     []string // nil if the PR's commits must be listed with REST
+	detailsRead bool
+}
+
+// NewGraphQLCollector creates a new GitHub GraphQL collector.
+func NewGraphQLCollector(token string) *GraphQLCollector {
+	return NewGraphQLCollectorWithClient(auth.NewClient(auth.NewTokenProvider(token)))
+}
+
+// NewGraphQLCollectorWithClient creates a new GitHub GraphQL collector
+// using the given client. REST requests go to the client's base URL and
+// GraphQL queries to the matching GraphQL endpoint.
+func NewGraphQLCollectorWithClient(client *github.Client) *GraphQLCollector {
+	return &GraphQLCollector{
+		GitHubCollector: &GitHubCollector{client: client},
+		cache:           make(map[string][]*graphQLCachedPR),
+	}
+}
+
+// Prefetch loads the open PRs and their checks for many repositories,
+// querying several repositories of the same owner per request. Later
+// calls to ListDependencyPRs, GetPRChecks and GetPRDetails for these
+// repositories are served from the prefetched data.
+func (c *GraphQLCollector) Prefetch(ctx context.Context, repos []model.RepoRef) error {
+	// Batches don't mix owners, so each query can be authenticated for
+	// its owner's installation.
+	var owners []string
+	byOwner := make(map[string][]model.RepoRef)
+	for _, repo := range repos {
+		if _, ok := byOwner[repo.Owner]; !ok {
+			owners = append(owners, repo.Owner)
+		}
+		byOwner[repo.Owner] = append(byOwner[repo.Owner], repo)
+	}
+
+	for _, owner := range owners {
+		ownerRepos := byOwner[owner]
+		ownerCtx := auth.WithOwner(ctx, owner)
+		for start := 0; start < len(ownerRepos); start += graphQLBatchSize {
+			batch := ownerRepos[start:min(start+graphQLBatchSize, len(ownerRepos))]
+			if err := c.prefetchBatch(ownerCtx, batch); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// prefetchBatch queries one batch of repositories using an alias per
+// repository. Repositories with more PRs than fit on a page are paged
+// through individually.
+func (c *GraphQLCollector) prefetchBatch(ctx context.Context, repos []model.RepoRef) error {
+	var params, fields []string
+	vars := map[string]any{"first": graphQLPageSize}
+
+	for i, repo := range repos {
+		params = append(params, fmt.Sprintf("$o%d: String!, $n%d: String!", i, i))
+		fields = append(fields, fmt.Sprintf("r%d: repository(owner: $o%d, name: $n%d) { pullRequests(states: OPEN, first: $first) { %s } }",
+			i, i, i, graphQLPRConnection))
+		vars[fmt.Sprintf("o%d", i)] = repo.Owner
+		vars[fmt.Sprintf("n%d", i)] = repo.Name
+	}
+
+	query := fmt.Sprintf("query($first: Int!, %s) { %s }", strings.Join(params, ", "), strings.Join(fields, " "))
+
+	// A missing repository is reported as an error alongside the data of
+	// the others, so partial results are kept.
+	var data map[string]*graphQLRepository
+	if err := c.query(ctx, query, vars, &data, true); err != nil {
+		return err
+	}
+
+	for i, repo := range repos {
+		r := data[fmt.Sprintf("r%d", i)]
+		if r == nil || r.PullRequests == nil {
+			continue
+		}
+
+		nodes := r.PullRequests.Nodes
+		if r.PullRequests.PageInfo.HasNextPage {
+			rest, err := c.listOpenPRs(ctx, repo, r.PullRequests.PageInfo.EndCursor)
+			if err != nil {
+				return err
+			}
+			nodes = append(nodes, rest...)
+		}
+
+		c.store(repo, nodes)
+	}
+
+	return nil
+}
+
+// listOpenPRs pages through a repository's open PRs after the cursor.
+func (c *GraphQLCollector) listOpenPRs(ctx context.Context, repo model.RepoRef, after string) ([]graphQLPR, error) {
+	query := `query($owner: String!, $name: String!, $first: Int!, $after: String) {
+  repository(owner: $owner, name: $name) { pullRequests(states: OPEN, first: $first, after: $after) { ` + graphQLPRConnection + ` } }
+}`
+
+	var nodes []graphQLPR
+	for {
+		vars := map[string]any{"owner": repo.Owner, "name": repo.Name, "first": graphQLPageSize}
+		if after != "" {
+			vars["after"] = after
+		}
+
+		var data struct {
+			Repository *graphQLRepository `json:"repository"`
+		}
+		if err := c.query(auth.WithOwner(ctx, repo.Owner), query, vars, &data, false); err != nil {
+			return nil, err
+		}
+		if data.Repository == nil || data.Repository.PullRequests == nil {
+			return nil, fmt.Errorf("repository %s not found", repo.FullName())
+		}
+
+		conn := data.Repository.PullRequests
+		nodes = append(nodes, conn.Nodes...)
+		if !conn.PageInfo.HasNextPage {
+			return nodes, nil
+		}
+		after = conn.PageInfo.EndCursor
+	}
+}
+
+// getPR queries a single PR with its checks.
+func (c *GraphQLCollector) getPR(ctx context.Context, repo model.RepoRef, prNumber int) (*graphQLPR, error) {
+	query := `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) { pullRequest(number: $number) { ` + graphQLPRFields + ` } }
+}`
+
+	var data struct {
+		Repository *struct {
+			PullRequest *graphQLPR `json:"pullRequest"`
+		} `json:"repository"`
+	}
+	vars := map[string]any{"owner": repo.Owner, "name": repo.Name, "number": prNumber}
+	if err := c.query(auth.WithOwner(ctx, repo.Owner), query, vars, &data, false); err != nil {
+		return nil, err
+	}
+	if data.Repository == nil || data.Repository.PullRequest == nil {
+		return nil, fmt.Errorf("pull request %s#%d not found", repo.FullName(), prNumber)
+	}
+
+	return data.Repository.PullRequest, nil
+}
+
+// store caches the converted PRs of a repository.
+func (c *GraphQLCollector) store(repo model.RepoRef, nodes []graphQLPR) {
+	cached := make([]*graphQLCachedPR, 0, len(nodes))
+	for _, n := range nodes {
+		checks, checksRead := n.checks()
+		cached = append(cached, &graphQLCachedPR{
+			pr:         n.convert(repo, c.bots),
+			checks:     checks,
+			checksRead: checksRead,
+			files:      n.files(),
+			authors:    n.commitAuthors(),
+		})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache[repo.FullName()] = cached
+}
+
+// cached returns the prefetched PRs of a repository.
+func (c *GraphQLCollector) cached(repo model.RepoRef) ([]*graphQLCachedPR, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prs, ok := c.cache[repo.FullName()]
+	return prs, ok
+}
+
+// cachedPR returns a prefetched PR.
+func (c *GraphQLCollector) cachedPR(repo model.RepoRef, prNumber int) *graphQLCachedPR {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cachedPRLocked(repo, prNumber)
+}
+
+// ListDependencyPRs returns open dependency PRs for a repository.
+func (c *GraphQLCollector) ListDependencyPRs(ctx context.Context, repo model.RepoRef) ([]model.PullRequest, error) {
+	if _, ok := c.cached(repo); !ok {
+		if err := c.Prefetch(ctx, []model.RepoRef{repo}); err != nil {
+			return nil, err
+		}
+		if _, ok := c.cached(repo); !ok {
+			return nil, fmt.Errorf("repository %s not found", repo.FullName())
+		}
+	}
+
+	prs, _ := c.cached(repo)
+	var result []model.PullRequest
+	for _, p := range prs {
+		if p.pr.IsDependency {
+			result = append(result, p.pr)
+		}
+	}
+
+	return result, nil
+}
+
+// GetPRDetails returns detailed information about a specific PR.
+func (c *GraphQLCollector) GetPRDetails(ctx context.Context, repo model.RepoRef, prNumber int) (*model.PullRequest, error) {
+	c.mu.Lock()
+	p := c.cachedPRLocked(repo, prNumber)
+	if p != nil && !p.detailsRead {
+		p.detailsRead = true
+		pr := p.pr
+		c.mu.Unlock()
+		return &pr, nil
+	}
+	c.mu.Unlock()
+
+	n, err := c.getPR(ctx, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// The checks, files and commits of a prefetched PR are refreshed
+	// along with its details, so a PR re-read before it is merged isn't
+	// evaluated on the prefetched head commit.
+	if p != nil {
+		checks, checksRead := n.checks()
+		c.mu.Lock()
+		p.checks, p.checksRead = checks, checksRead
+		p.files = n.files()
+		p.authors = n.commitAuthors()
+		c.mu.Unlock()
+	}
+
+	pr := n.convert(repo, c.bots)
+	return &pr, nil
+}
+
+// cachedPRLocked is cachedPR for callers holding c.mu.
+func (c *GraphQLCollector) cachedPRLocked(repo model.RepoRef, prNumber int) *graphQLCachedPR {
+	for _, p := range c.cache[repo.FullName()] {
+		if p.pr.Number == prNumber {
+			return p
+		}
+	}
+	return nil
+}
+
+// GetPRChecks returns the CI check runs and commit statuses for a PR's
+// head commit. Commits with more than one page of checks are listed with
+// REST, so no check is missed.
+func (c *GraphQLCollector) GetPRChecks(ctx context.Context, repo model.RepoRef, prNumber int) ([]model.CheckRun, error) {
+	if p := c.cachedPR(repo, prNumber); p != nil {
+		if p.checksRead {
+			return p.checks, nil
+		}
+		return c.GitHubCollector.GetPRChecks(ctx, repo, prNumber)
+	}
+
+	n, err := c.getPR(ctx, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if checks, ok := n.checks(); ok {
+		return checks, nil
+	}
+	return c.GitHubCollector.GetPRChecks(ctx, repo, prNumber)
+}
+
+// ListPRFiles returns the paths of the files changed by a PR, from the
+// prefetched PRs if possible.
+func (c *GraphQLCollector) ListPRFiles(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	if p := c.cachedPR(repo, prNumber); p != nil && p.files != nil {
+		return p.files, nil
+	}
+	return c.GitHubCollector.ListPRFiles(ctx, repo, prNumber)
+}
+
+// ListPRCommitAuthors returns the distinct authors of a PR's commits,
+// from the prefetched PRs if possible.
+func (c *GraphQLCollector) ListPRCommitAuthors(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	if p := c.cachedPR(repo, prNumber); p != nil && p.authors != nil {
+		return p.authors, nil
+	}
+	return c.GitHubCollector.ListPRCommitAuthors(ctx, repo, prNumber)
+}
+
+// graphQLError is an error returned in a GraphQL response.
+type graphQLError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// graphQLEndpoint returns the GraphQL URL relative to the REST base URL.
+// GitHub Enterprise Server serves REST under /api/v3/ and GraphQL at
+// /api/graphql; github.com serves both from the API root.
+func graphQLEndpoint(base *url.URL) string {
+	if strings.HasSuffix(base.Path, "/api/v3/") {
+		return "../graphql"
+	}
+	return "graphql"
+}
+
+// query runs a GraphQL query and decodes its data into v. If allowPartial
+// is set, errors are ignored as long as some data was returned.
+func (c *GraphQLCollector) query(ctx context.Context, query string, vars map[string]any, v any, allowPartial bool) error {
+	req, err := c.client.NewRequest("POST", graphQLEndpoint(c.client.BaseURL), map[string]any{
+		"query":     query,
+		"variables": vars,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create GraphQL request: %w", err)
+	}
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	if _, err := c.client.Do(ctx, req, &resp); err != nil {
+		return err
+	}
+
+	hasData := len(resp.Data) > 0 && string(resp.Data) != "null"
+	if len(resp.Errors) > 0 && (!allowPartial || !hasData) {
+		var msgs []string
+		for _, e := range resp.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return errors.New("GraphQL query failed: " + strings.Join(msgs, "; "))
+	}
+	if !hasData {
+		return errors.New("GraphQL query returned no data")
+	}
+
+	if err := json.Unmarshal(resp.Data, v); err != nil {
+		return fmt.Errorf("failed to decode GraphQL response: %w", err)
+	}
+	return nil
+}
+
+// graphQLRepository is a repository in a GraphQL response.
+type graphQLRepository struct {
+	PullRequests *struct {
+		PageInfo struct {
+			HasNextPage bool   `json:"hasNextPage"`
+			EndCursor   string `json:"endCursor"`
+		} `json:"pageInfo"`
+		Nodes []graphQLPR `json:"nodes"`
+	} `json:"pullRequests"`
+}
+
+// graphQLPR is a pull request in a GraphQL response.
+type graphQLPR struct {
+	Number           int        `json:"number"`
+	Title            string     `json:"title"`
+	Body             string     `json:"body"`
+	State            string     `json:"state"`
+	URL              string     `json:"url"`
+	IsDraft          bool       `json:"isDraft"`
+	HeadRefName      string     `json:"headRefName"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+	MergedAt         *time.Time `json:"mergedAt"`
+	Mergeable        string     `json:"mergeable"`
+	MergeStateStatus string     `json:"mergeStateStatus"`
+	Author           *struct {
+		Typename string `json:"__typename"`
+		Login    string `json:"login"`
+	} `json:"author"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Files struct {
+		TotalCount int `json:"totalCount"`
+		Nodes      []struct {
+			Path       string `json:"path"`
+			ChangeType string `json:"changeType"`
+		} `json:"nodes"`
+	} `json:"files"`
+	AllCommits struct {
+		TotalCount int `json:"totalCount"`
+		Nodes      []struct {
+			Commit struct {
+				Author struct {
+					Name string `json:"name"`
+					User *struct {
+						Login string `json:"login"`
+					} `json:"user"`
+				} `json:"author"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"allCommits"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					Contexts struct {
+						PageInfo struct {
+							HasNextPage bool `json:"hasNextPage"`
+						} `json:"pageInfo"`
+						Nodes []graphQLCheckContext `json:"nodes"`
+					} `json:"contexts"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+}
+
+// graphQLCheckContext is a check run or commit status in a status rollup.
+type graphQLCheckContext struct {
+	Typename   string `json:"__typename"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	Context    string `json:"context"`
+	State      string `json:"state"`
+}
+
+// convert converts a GraphQL pull request to our model, matching the REST
+// collector's values.
+func (n *graphQLPR) convert(repo model.RepoRef, bots Bots) model.PullRequest {
+	mpr := model.PullRequest{
+		Number:       n.Number,
+		Title:        n.Title,
+		Body:         n.Body,
+		State:        strings.ToLower(n.State),
+		HTMLURL:      n.URL,
+		Draft:        n.IsDraft,
+		HeadBranch:   n.HeadRefName,
+		Mergeable:    n.Mergeable == "MERGEABLE",
+		MergeableStr: strings.ToLower(n.MergeStateStatus),
+		CreatedAt:    n.CreatedAt,
+		UpdatedAt:    n.UpdatedAt,
+		MergedAt:     n.MergedAt,
+		Repo:         repo,
+	}
+
+	if n.Author != nil {
+		mpr.Author = n.Author.Login
+		// GraphQL omits the "[bot]" suffix of app logins.
+		if n.Author.Typename == "Bot" {
+			mpr.Author += "[bot]"
+		}
+	}
+	for _, l := range n.Labels.Nodes {
+		mpr.Labels = append(mpr.Labels, l.Name)
+	}
+
+	detectDependency(&mpr, bots)
+	return mpr
+}
+
+// files returns the paths of the changed files, or nil if they must be
+// listed with REST: GraphQL returns at most one page of files and doesn't
+// give the old path of renamed files.
+func (n *graphQLPR) files() []string {
+	if n.Files.TotalCount > len(n.Files.Nodes) {
+		return nil
+	}
+
+	paths := make([]string, 0, len(n.Files.Nodes))
+	for _, f := range n.Files.Nodes {
+		if f.ChangeType == "RENAMED" || f.ChangeType == "COPIED" {
+			return nil
+		}
+		paths = append(paths, f.Path)
+	}
+	return paths
+}
+
+// commitAuthors returns the distinct commit authors, or nil if they must
+// be listed with REST because the PR has more than one page of commits.
+func (n *graphQLPR) commitAuthors() []string {
+	if n.AllCommits.TotalCount > len(n.AllCommits.Nodes) {
+		return nil
+	}
+
+	authors := make([]string, 0, len(n.AllCommits.Nodes))
+	for _, node := range n.AllCommits.Nodes {
+		a := node.Commit.Author
+		login := ""
+		if a.User != nil {
+			login = a.User.Login
+		}
+		authors = append(authors, commitAuthor(login, a.Name))
+	}
+	return uniqueAuthors(authors)
+}
+
+// checks returns the check runs and commit statuses of the head commit,
+// or false if there are more than one page of them and they must be
+// listed with REST.
+func (n *graphQLPR) checks() ([]model.CheckRun, bool) {
+	var result []model.CheckRun
+	for _, commit := range n.Commits.Nodes {
+		rollup := commit.Commit.StatusCheckRollup
+		if rollup == nil {
+			continue
+		}
+		if rollup.Contexts.PageInfo.HasNextPage {
+			return nil, false
+		}
+		for _, ctx := range rollup.Contexts.Nodes {
+			switch ctx.Typename {
+			case "CheckRun":
+				result = append(result, model.CheckRun{
+					Name:       ctx.Name,
+					Status:     strings.ToLower(ctx.Status),
+					Conclusion: strings.ToLower(ctx.Conclusion),
+					Source:     model.CheckSourceCheckRun,
+				})
+			case "StatusContext":
+				result = append(result, model.NewStatusCheck(ctx.Context, strings.ToLower(ctx.State)))
+			}
+		}
+	}
+	return result, true
+}
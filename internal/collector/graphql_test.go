@@ -0,0 +1,393 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-github/v84/github"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// graphQLTestPR returns a PR node as returned by the GraphQL API.
+func graphQLTestPR(number int, author, authorType, title string) map[string]any {
+	return map[string]any{
+		"number":           number,
+		"title":            title,
+		"state":            "OPEN",
+		"url":              fmt.Sprintf("https://github.com/example/repo/pull/%d", number),
+		"createdAt":        "2026-01-01T00:00:00Z",
+		"updatedAt":        "2026-01-02T00:00:00Z",
+		"mergeable":        "MERGEABLE",
+		"mergeStateStatus": "CLEAN",
+		"author":           map[string]any{"__typename": authorType, "login": author},
+		"labels":           map[string]any{"nodes": []any{map[string]any{"name": "dependencies"}}},
+		"commits": map[string]any{"nodes": []any{map[string]any{"commit": map[string]any{
+			"statusCheckRollup": map[string]any{"contexts": map[string]any{"nodes": []any{
+				map[string]any{"__typename": "CheckRun", "name": "build", "status": "COMPLETED", "conclusion": "SUCCESS"},
+				map[string]any{"__typename": "StatusContext", "context": "ci/jenkins", "state": "PENDING"},
+			}}},
+		}}}},
+	}
+}
+
+// newGraphQLTestServer starts a server answering GraphQL queries with
+// handler and returns a collector using it.
+func newGraphQLTestServer(t *testing.T, handler func(query string, vars map[string]any) any) (*GraphQLCollector, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		requests.Add(1)
+
+		var body struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(handler(body.Query, body.Variables))
+	}))
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	return NewGraphQLCollectorWithClient(client), &requests
+}
+
+func TestGraphQLCollector_Prefetch(t *testing.T) {
+	coll, requests := newGraphQLTestServer(t, func(query string, vars map[string]any) any {
+		if !strings.Contains(query, "r0: repository") || !strings.Contains(query, "r1: repository") {
+			t.Errorf("expected both repositories in one query, got %s", query)
+		}
+		return map[string]any{"data": map[string]any{
+			"r0": map[string]any{"pullRequests": map[string]any{
+				"pageInfo": map[string]any{"hasNextPage": false},
+				"nodes": []any{
+					graphQLTestPR(1, "renovate", "Bot", "fix(deps): update golang.org/x/mod to v0.32.0"),
+					graphQLTestPR(2, "octocat", "User", "Add feature"),
+				},
+			}},
+			"r1": map[string]any{"pullRequests": map[string]any{
+				"pageInfo": map[string]any{"hasNextPage": false},
+				"nodes":    []any{},
+			}},
+		}}
+	})
+
+	ctx := context.Background()
+	repo := model.RepoRef{Owner: "example", Name: "repo"}
+	other := model.RepoRef{Owner: "example", Name: "other"}
+
+	if err := coll.Prefetch(ctx, []model.RepoRef{repo, other}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	prs, err := coll.ListDependencyPRs(ctx, repo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("expected 1 dependency PR, got %d", len(prs))
+	}
+
+	pr := prs[0]
+	if pr.Author != "renovate[bot]" || pr.DependBot != model.DependBotRenovate {
+		t.Errorf("expected renovate[bot] author, got %q (%q)", pr.Author, pr.DependBot)
+	}
+	if pr.State != "open" || !pr.Mergeable || pr.MergeableStr != "clean" {
+		t.Errorf("expected open, mergeable, clean PR, got %q %v %q", pr.State, pr.Mergeable, pr.MergeableStr)
+	}
+	if pr.Dependency.Name != "golang.org/x/mod" {
+		t.Errorf("expected dependency golang.org/x/mod, got %q", pr.Dependency.Name)
+	}
+
+	checks, err := coll.GetPRChecks(ctx, repo, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []model.CheckRun{
+		{Name: "build", Status: "completed", Conclusion: "success", Source: model.CheckSourceCheckRun},
+		{Name: "ci/jenkins", Status: "in_progress", Source: model.CheckSourceStatus},
+	}
+	if fmt.Sprint(checks) != fmt.Sprint(want) {
+		t.Errorf("expected checks %v, got %v", want, checks)
+	}
+
+	if _, err := coll.GetPRDetails(ctx, repo, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected 1 request, got %d", got)
+	}
+
+	if prs, _ := coll.ListDependencyPRs(ctx, other); len(prs) != 0 {
+		t.Errorf("expected no PRs for other repo, got %d", len(prs))
+	}
+}
+
+func TestGraphQLCollector_Pagination(t *testing.T) {
+	coll, requests := newGraphQLTestServer(t, func(query string, vars map[string]any) any {
+		if strings.Contains(query, "r0: repository") {
+			return map[string]any{"data": map[string]any{
+				"r0": map[string]any{"pullRequests": map[string]any{
+					"pageInfo": map[string]any{"hasNextPage": true, "endCursor": "c1"},
+					"nodes":    []any{graphQLTestPR(1, "renovate", "Bot", "Update pkg")},
+				}},
+			}}
+		}
+		if vars["after"] != "c1" {
+			t.Errorf("expected cursor c1, got %v", vars["after"])
+		}
+		return map[string]any{"data": map[string]any{
+			"repository": map[string]any{"pullRequests": map[string]any{
+				"pageInfo": map[string]any{"hasNextPage": false},
+				"nodes":    []any{graphQLTestPR(2, "dependabot", "Bot", "Bump pkg from 1.0.0 to 1.0.1")},
+			}},
+		}}
+	})
+
+	prs, err := coll.ListDependencyPRs(context.Background(), model.RepoRef{Owner: "example", Name: "repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 2 {
+		t.Fatalf("expected 2 PRs, got %d", len(prs))
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+}
+
+func TestGraphQLCollector_DetailsRefetched(t *testing.T) {
+	mergeable := "MERGEABLE"
+	coll, requests := newGraphQLTestServer(t, func(query string, _ map[string]any) any {
+		pr := graphQLTestPR(1, "renovate", "Bot", "Update pkg")
+		pr["mergeable"] = mergeable
+		if mergeable == "CONFLICTING" {
+			pr["commits"] = map[string]any{"nodes": []any{map[string]any{"commit": map[string]any{
+				"statusCheckRollup": map[string]any{"contexts": map[string]any{"nodes": []any{
+					map[string]any{"__typename": "CheckRun", "name": "build", "status": "COMPLETED", "conclusion": "FAILURE"},
+				}}},
+			}}}}
+		}
+		if strings.Contains(query, "pullRequest(number") {
+			return map[string]any{"data": map[string]any{"repository": map[string]any{"pullRequest": pr}}}
+		}
+		return map[string]any{"data": map[string]any{
+			"r0": map[string]any{"pullRequests": map[string]any{
+				"pageInfo": map[string]any{"hasNextPage": false},
+				"nodes":    []any{pr},
+			}},
+		}}
+	})
+
+	ctx := context.Background()
+	repo := model.RepoRef{Owner: "example", Name: "repo"}
+	if err := coll.Prefetch(ctx, []model.RepoRef{repo}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The first read is served from the prefetched data; later reads
+	// query again so a changed mergeable state is seen.
+	mergeable = "CONFLICTING"
+	first, err := coll.GetPRDetails(ctx, repo, 1)
+	if err != nil || !first.Mergeable {
+		t.Fatalf("expected prefetched mergeable PR, got %+v, %v", first, err)
+	}
+	second, err := coll.GetPRDetails(ctx, repo, 1)
+	if err != nil || second.Mergeable {
+		t.Fatalf("expected refetched conflicting PR, got %+v, %v", second, err)
+	}
+
+	// The checks are refreshed along with the details.
+	checks, err := coll.GetPRChecks(ctx, repo, 1)
+	if err != nil || len(checks) != 1 || checks[0].Conclusion != "failure" {
+		t.Errorf("expected refetched failing check, got %+v, %v", checks, err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+}
+
+func TestGraphQLCollector_Errors(t *testing.T) {
+	coll, _ := newGraphQLTestServer(t, func(string, map[string]any) any {
+		return map[string]any{
+			"data":   map[string]any{"repository": nil},
+			"errors": []any{map[string]any{"type": "NOT_FOUND", "message": "Could not resolve to a Repository"}},
+		}
+	})
+
+	_, err := coll.GetPRChecks(context.Background(), model.RepoRef{Owner: "example", Name: "missing"}, 1)
+	if err == nil || !strings.Contains(err.Error(), "Could not resolve to a Repository") {
+		t.Errorf("expected GraphQL error, got %v", err)
+	}
+}
+
+func TestGraphQLCollector_Enterprise(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+			"repository": map[string]any{"pullRequest": graphQLTestPR(1, "renovate", "Bot", "Update pkg")},
+		}})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	coll := NewGraphQLCollectorWithClient(client)
+	if _, err := coll.GetPRChecks(context.Background(), model.RepoRef{Owner: "example", Name: "repo"}, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "/api/graphql" {
+		t.Errorf("expected query to /api/graphql, got %v", paths)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New("rest", github.NewClient(nil)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if c, err := New("graphql", github.NewClient(nil)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	} else if _, ok := c.(Prefetcher); !ok {
+		t.Error("expected GraphQL collector to support prefetching")
+	}
+	if _, err := New("soap", github.NewClient(nil)); err == nil {
+		t.Error("expected error for unknown API")
+	}
+}
+
+func TestGraphQLCollector_ListPRFiles(t *testing.T) {
+	coll, requests := newGraphQLTestServer(t, func(string, map[string]any) any {
+		clean := graphQLTestPR(1, "renovate", "Bot", "Update pkg")
+		clean["files"] = map[string]any{"totalCount": 2, "nodes": []any{
+			map[string]any{"path": "go.mod", "changeType": "MODIFIED"},
+			map[string]any{"path": "go.sum", "changeType": "MODIFIED"},
+		}}
+		renamed := graphQLTestPR(2, "renovate", "Bot", "Update pkg")
+		renamed["files"] = map[string]any{"totalCount": 1, "nodes": []any{
+			map[string]any{"path": "go.mod", "changeType": "RENAMED"},
+		}}
+		return map[string]any{"data": map[string]any{
+			"r0": map[string]any{"pullRequests": map[string]any{
+				"pageInfo": map[string]any{"hasNextPage": false},
+				"nodes":    []any{clean, renamed},
+			}},
+		}}
+	})
+
+	ctx := context.Background()
+	repo := model.RepoRef{Owner: "example", Name: "repo"}
+	if err := coll.Prefetch(ctx, []model.RepoRef{repo}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	files, err := coll.ListPRFiles(ctx, repo, 1)
+	if err != nil || strings.Join(files, ",") != "go.mod,go.sum" {
+		t.Errorf("expected prefetched files, got %v, %v", files, err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected 1 request, got %d", got)
+	}
+
+	// Renames need the old path, which only REST returns; the test
+	// server doesn't serve REST.
+	if _, err := coll.ListPRFiles(ctx, repo, 2); err == nil {
+		t.Error("expected renamed files to be listed with REST")
+	}
+}
+
+func TestGraphQLCollector_ListPRCommitAuthors(t *testing.T) {
+	coll, requests := newGraphQLTestServer(t, func(string, map[string]any) any {
+		pr := graphQLTestPR(1, "renovate", "Bot", "Update pkg")
+		pr["allCommits"] = map[string]any{"totalCount": 2, "nodes": []any{
+			map[string]any{"commit": map[string]any{"author": map[string]any{"name": "renovate[bot]", "user": nil}}},
+			map[string]any{"commit": map[string]any{"author": map[string]any{"name": "Mallory M.", "user": map[string]any{"login": "mallory"}}}},
+		}}
+		truncated := graphQLTestPR(2, "renovate", "Bot", "Update pkg")
+		truncated["allCommits"] = map[string]any{"totalCount": 101, "nodes": []any{}}
+		return map[string]any{"data": map[string]any{
+			"r0": map[string]any{"pullRequests": map[string]any{
+				"pageInfo": map[string]any{"hasNextPage": false},
+				"nodes":    []any{pr, truncated},
+			}},
+		}}
+	})
+
+	ctx := context.Background()
+	repo := model.RepoRef{Owner: "example", Name: "repo"}
+	if err := coll.Prefetch(ctx, []model.RepoRef{repo}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	authors, err := coll.ListPRCommitAuthors(ctx, repo, 1)
+	if err != nil || strings.Join(authors, ",") != "unlinked:renovate[bot],mallory" {
+		t.Errorf("expected prefetched authors, got %v, %v", authors, err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected 1 request, got %d", got)
+	}
+
+	// More than one page of commits is listed with REST, which the test
+	// server doesn't serve.
+	if _, err := coll.ListPRCommitAuthors(ctx, repo, 2); err == nil {
+		t.Error("expected truncated commits to be listed with REST")
+	}
+}
+
+func TestGraphQLCollector_TruncatedChecks(t *testing.T) {
+	coll, requests := newGraphQLTestServer(t, func(string, map[string]any) any {
+		truncated := graphQLTestPR(1, "renovate", "Bot", "Update pkg")
+		truncated["commits"] = map[string]any{"nodes": []any{map[string]any{"commit": map[string]any{
+			"statusCheckRollup": map[string]any{"contexts": map[string]any{
+				"pageInfo": map[string]any{"hasNextPage": true},
+				"nodes": []any{
+					map[string]any{"__typename": "CheckRun", "name": "build", "status": "COMPLETED", "conclusion": "SUCCESS"},
+				},
+			}},
+		}}}}
+		return map[string]any{"data": map[string]any{
+			"r0": map[string]any{"pullRequests": map[string]any{
+				"pageInfo": map[string]any{"hasNextPage": false},
+				"nodes":    []any{truncated},
+			}},
+		}}
+	})
+
+	ctx := context.Background()
+	repo := model.RepoRef{Owner: "example", Name: "repo"}
+	if err := coll.Prefetch(ctx, []model.RepoRef{repo}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// More than one page of checks is listed with REST, which the test
+	// server doesn't serve, instead of passing on the first page.
+	if checks, err := coll.GetPRChecks(ctx, repo, 1); err == nil {
+		t.Errorf("expected truncated checks to be listed with REST, got %v", checks)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected 1 request, got %d", got)
+	}
+}
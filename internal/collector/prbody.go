@@ -0,0 +1,488 @@
+package collector
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// parseDependencies extracts the packages updated by a dependency PR from
+// the metadata its bot writes in the PR body: the updates table of
+// Renovate, the "Bumps"/"Updates" sentences and updated-dependencies
+// block of Dependabot, or the hook list of pre-commit.ci. It returns nil
+// if the body has none, in which case the title is the only source.
+func parseDependencies(parser Parser, body string, labels []string) []model.Dependency {
+	var deps []model.Dependency
+	switch parser {
+	case ParserRenovate:
+		deps = parseRenovateBody(body)
+	case ParserDependabot:
+		deps = parseDependabotBody(body, labels)
+	case ParserPreCommit:
+		deps = parsePreCommitBody(body)
+	}
+
+	for i := range deps {
+		completeDependency(&deps[i])
+	}
+	return deps
+}
+
+// completeDependency fills in the ecosystem and update type of a parsed
+// dependency when its bot didn't give them.
+func completeDependency(dep *model.Dependency) {
+	if dep.Ecosystem == "" {
+		dep.Ecosystem = managerEcosystems[dep.Manager]
+	}
+	if dep.Ecosystem == "" {
+		dep.Ecosystem = detectEcosystem(dep.Name)
+	}
+	if dep.UpdateType == "" {
+		dep.UpdateType = model.UpdateTypeUnknown
+		if dep.FromVersion != "" && dep.ToVersion != "" {
+			dep.UpdateType = determineUpdateType(dep.FromVersion, dep.ToVersion)
+		}
+	}
+}
+
+// managerEcosystems maps Renovate managers and Dependabot package
+// ecosystems to the ecosystem of their packages.
+var managerEcosystems = map[string]string{
+	"gomod":            "go",
+	"go_modules":       "go",
+	"npm":              "npm",
+	"npm_and_yarn":     "npm",
+	"pip":              "pip",
+	"pip_requirements": "pip",
+	"pep621":           "pip",
+	"poetry":           "pip",
+	"pipenv":           "pip",
+	"dockerfile":       "docker",
+	"docker":           "docker",
+	"docker-compose":   "docker",
+	"github-actions":   "github-actions",
+	"github_actions":   "github-actions",
+	"terraform":        "terraform",
+	"cargo":            "cargo",
+	"maven":            "maven",
+	"gradle":           "maven",
+	"bundler":          "rubygems",
+	"composer":         "composer",
+	"nuget":            "nuget",
+	"pre-commit":       "pre-commit",
+}
+
+var (
+	markdownLinkRe = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	codeSpanRe     = regexp.MustCompile("`([^`]+)`")
+)
+
+// parseRenovateBody parses the updates table Renovate starts its PR body
+// with, e.g.
+//
+//	| Package | Type | Update | Change |
+//	|---|---|---|---|
+//	| [golang.org/x/net](https://...) | require | minor | `v0.29.0` -> `v0.30.0` |
+//
+// Only the Package and Change columns are required. The manager comes
+// from a Package file column, or else from the dependency type.
+func parseRenovateBody(body string) []model.Dependency {
+	var (
+		columns map[string]int
+		deps    []model.Dependency
+	)
+
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "|") {
+			if deps != nil {
+				break // only the first table lists the updates
+			}
+			columns = nil
+			continue
+		}
+
+		cells := splitTableRow(line)
+		switch {
+		case columns == nil:
+			columns = renovateColumns(cells)
+		case isTableSeparator(cells):
+			continue
+		default:
+			if dep, ok := parseRenovateRow(cells, columns); ok {
+				deps = append(deps, dep)
+			}
+		}
+	}
+
+	return deps
+}
+
+// renovateColumns returns the index of each column of a table header, or
+// an empty map if it isn't the updates table.
+func renovateColumns(header []string) map[string]int {
+	columns := make(map[string]int)
+	for i, cell := range header {
+		columns[strings.ToLower(cell)] = i
+	}
+	_, hasPackage := columns["package"]
+	_, hasChange := columns["change"]
+	if !hasPackage || !hasChange {
+		return map[string]int{} // some other table; skip its rows
+	}
+	return columns
+}
+
+// parseRenovateRow parses one row of the updates table.
+func parseRenovateRow(cells []string, columns map[string]int) (model.Dependency, bool) {
+	cell := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(cells) {
+			return ""
+		}
+		return cells[i]
+	}
+
+	name := packageName(cell("package"))
+	if name == "" {
+		return model.Dependency{}, false
+	}
+	dep := model.Dependency{Name: name}
+
+	versions := codeSpanRe.FindAllStringSubmatch(cell("change"), 2)
+	switch len(versions) {
+	case 2:
+		dep.FromVersion, dep.ToVersion = versions[0][1], versions[1][1]
+	case 1:
+		dep.ToVersion = versions[0][1]
+	}
+
+	switch update := strings.ToLower(stripMarkdown(cell("update"))); update {
+	case "major", "minor", "patch":
+		dep.UpdateType = model.UpdateType(update)
+	case "":
+	default: // digest, pin, lockFileMaintenance, ...
+		dep.UpdateType = model.UpdateTypeUnknown
+	}
+
+	depType := stripMarkdown(cell("type"))
+	dep.DepType = renovateDepType(depType)
+	dep.Manager = managerFromFile(stripMarkdown(cell("package file")))
+	if dep.Manager == "" {
+		dep.Manager = renovateDepTypeManagers[depType]
+	}
+
+	return dep, true
+}
+
+// renovateDepTypeManagers maps Renovate dependency types to the manager
+// they are unique to.
+var renovateDepTypeManagers = map[string]string{
+	"require":              "gomod",
+	"indirect":             "gomod",
+	"toolchain":            "gomod",
+	"golang":               "gomod",
+	"dependencies":         "npm",
+	"devDependencies":      "npm",
+	"peerDependencies":     "npm",
+	"optionalDependencies": "npm",
+	"packageManager":       "npm",
+	"engines":              "npm",
+	"action":               "github-actions",
+	"stage":                "dockerfile",
+	"final":                "dockerfile",
+	"required_provider":    "terraform",
+	"provider":             "terraform",
+	"module":               "terraform",
+}
+
+// renovateDepType maps a Renovate dependency type to ours. Anything
+// listed in a manifest other than an indirect or development dependency
+// is a direct dependency.
+func renovateDepType(depType string) model.DependencyType {
+	lower := strings.ToLower(depType)
+	switch {
+	case lower == "":
+		return ""
+	case lower == "indirect":
+		return model.DependencyTypeIndirect
+	case strings.HasPrefix(lower, "dev"), strings.Contains(lower, "dev-dependencies"):
+		return model.DependencyTypeDevelopment
+	default:
+		return model.DependencyTypeDirect
+	}
+}
+
+// managerFromFile returns the Renovate manager of a package file.
+func managerFromFile(file string) string {
+	base := path.Base(file)
+	switch {
+	case file == "":
+		return ""
+	case base == "go.mod":
+		return "gomod"
+	case base == "package.json":
+		return "npm"
+	case base == "pyproject.toml":
+		return "pep621"
+	case strings.HasPrefix(base, "requirements") && strings.HasSuffix(base, ".txt"):
+		return "pip_requirements"
+	case strings.HasPrefix(base, "Dockerfile"), strings.HasSuffix(base, ".dockerfile"):
+		return "dockerfile"
+	case strings.HasPrefix(file, ".github/workflows/"):
+		return "github-actions"
+	case strings.HasSuffix(base, ".tf"):
+		return "terraform"
+	case base == "Cargo.toml":
+		return "cargo"
+	case base == "pom.xml":
+		return "maven"
+	case strings.HasPrefix(base, "build.gradle"):
+		return "gradle"
+	case base == "Gemfile":
+		return "bundler"
+	case base == "composer.json":
+		return "composer"
+	default:
+		return ""
+	}
+}
+
+var (
+	// Updates `golang.org/x/net` from 0.29.0 to 0.30.0
+	dependabotUpdatesRe = regexp.MustCompile("(?m)^Updates `([^`]+)` from (\\S+) to (\\S+?)\\.?\\s*$")
+
+	// Bumps [golang.org/x/net](https://...) from 0.29.0 to 0.30.0.
+	dependabotBumpsRe = regexp.MustCompile(`(?m)^Bumps \[?([^\]\s]+)\]?(?:\([^)]*\))? from (\S+) to (\S+?)\.?\s*$`)
+)
+
+// dependabotLabelManagers maps the language labels Dependabot adds to
+// its PRs to the package ecosystem.
+var dependabotLabelManagers = map[string]string{
+	"go":             "go_modules",
+	"javascript":     "npm_and_yarn",
+	"python":         "pip",
+	"docker":         "docker",
+	"github_actions": "github_actions",
+	"terraform":      "terraform",
+	"rust":           "cargo",
+	"java":           "maven",
+	"ruby":           "bundler",
+	"php":            "composer",
+	".net":           "nuget",
+}
+
+// parseDependabotBody parses a Dependabot PR body: the "Updates `name`
+// from x to y" lines of grouped updates, or else the "Bumps name from x
+// to y" sentence. The dependency and update types come from the
+// updated-dependencies block Dependabot writes in its commit messages,
+// when the body includes it. The package ecosystem comes from the PR's
+// language label.
+func parseDependabotBody(body string, labels []string) []model.Dependency {
+	matches := dependabotUpdatesRe.FindAllStringSubmatch(body, -1)
+	if len(matches) == 0 {
+		matches = dependabotBumpsRe.FindAllStringSubmatch(body, 1)
+	}
+
+	var manager string
+	for _, l := range labels {
+		if m, ok := dependabotLabelManagers[strings.ToLower(l)]; ok {
+			manager = m
+			break
+		}
+	}
+
+	metadata := parseDependabotMetadata(body)
+	byName := make(map[string]model.Dependency, len(metadata))
+	for _, meta := range metadata {
+		byName[meta.Name] = meta
+	}
+
+	seen := make(map[string]bool)
+	var deps []model.Dependency
+	for _, m := range matches {
+		name := m[1]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		dep := model.Dependency{
+			Name:        name,
+			FromVersion: m[2],
+			ToVersion:   m[3],
+			Manager:     manager,
+		}
+		if meta, ok := byName[name]; ok {
+			dep.DepType = meta.DepType
+			dep.UpdateType = meta.UpdateType
+		}
+		deps = append(deps, dep)
+	}
+
+	// A body with only the metadata block, e.g. a commit message.
+	if len(deps) == 0 {
+		for _, meta := range metadata {
+			meta.Manager = manager
+			deps = append(deps, meta)
+		}
+	}
+
+	return deps
+}
+
+// parseDependabotMetadata parses the updated-dependencies block, e.g.
+//
+//	updated-dependencies:
+//	- dependency-name: golang.org/x/net
+//	  dependency-version: 0.30.0
+//	  dependency-type: direct:production
+//	  update-type: version-update:semver-minor
+//
+// in the order listed.
+func parseDependabotMetadata(body string) []model.Dependency {
+	_, block, ok := strings.Cut(body, "updated-dependencies:")
+	if !ok {
+		return nil
+	}
+
+	var (
+		deps    []model.Dependency
+		current *model.Dependency
+	)
+	flush := func() {
+		if current != nil && current.Name != "" {
+			deps = append(deps, *current)
+		}
+	}
+	for _, line := range strings.Split(block, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if trimmed == "..." || strings.HasPrefix(trimmed, "---") || strings.HasPrefix(trimmed, "Signed-off-by:") {
+			break
+		}
+
+		item := strings.HasPrefix(trimmed, "- ")
+		key, value, ok := strings.Cut(strings.TrimPrefix(trimmed, "- "), ":")
+		if !ok {
+			break
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		if item {
+			flush()
+			current = &model.Dependency{}
+		}
+		if current == nil {
+			break
+		}
+
+		switch key {
+		case "dependency-name":
+			current.Name = value
+		case "dependency-version":
+			current.ToVersion = value
+		case "dependency-type":
+			current.DepType = dependabotDepType(value)
+		case "update-type":
+			current.UpdateType = dependabotUpdateType(value)
+		}
+	}
+	flush()
+
+	return deps
+}
+
+// dependabotDepType maps a Dependabot dependency type, such as
+// direct:production, to ours.
+func dependabotDepType(depType string) model.DependencyType {
+	switch depType {
+	case "direct:production":
+		return model.DependencyTypeDirect
+	case "direct:development":
+		return model.DependencyTypeDevelopment
+	case "indirect":
+		return model.DependencyTypeIndirect
+	default:
+		return ""
+	}
+}
+
+// dependabotUpdateType maps a Dependabot update type, such as
+// version-update:semver-minor, to ours.
+func dependabotUpdateType(updateType string) model.UpdateType {
+	switch updateType {
+	case "version-update:semver-major":
+		return model.UpdateTypeMajor
+	case "version-update:semver-minor":
+		return model.UpdateTypeMinor
+	case "version-update:semver-patch":
+		return model.UpdateTypePatch
+	default:
+		return ""
+	}
+}
+
+// preCommitUpdateRe matches an entry of pre-commit.ci's autoupdate list,
+// e.g. "- [github.com/psf/black: 23.1.0 → 23.3.0](https://...)".
+var preCommitUpdateRe = regexp.MustCompile(`(?m)^- \[([^:\]\s]+): (\S+) → (\S+)\]`)
+
+// parsePreCommitBody parses the hook repositories updated by a
+// pre-commit.ci autoupdate PR.
+func parsePreCommitBody(body string) []model.Dependency {
+	var deps []model.Dependency
+	for _, m := range preCommitUpdateRe.FindAllStringSubmatch(body, -1) {
+		deps = append(deps, model.Dependency{
+			Name:        m[1],
+			FromVersion: m[2],
+			ToVersion:   m[3],
+			Manager:     "pre-commit",
+		})
+	}
+	return deps
+}
+
+// splitTableRow returns the trimmed cells of a Markdown table row.
+func splitTableRow(line string) []string {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	cells := strings.Split(line, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// isTableSeparator reports whether a row is the separator below a table
+// header, such as |---|:---:|.
+func isTableSeparator(cells []string) bool {
+	for _, c := range cells {
+		if strings.Trim(c, "-: ") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// stripMarkdown replaces links by their text and removes code spans'
+// backticks.
+func stripMarkdown(s string) string {
+	s = markdownLinkRe.ReplaceAllString(s, "$1")
+	return strings.TrimSpace(strings.ReplaceAll(s, "`", ""))
+}
+
+// packageName returns the package of a Package cell, such as
+// "[golang.org/x/net](https://...) ([source](https://...))".
+func packageName(cell string) string {
+	if m := markdownLinkRe.FindStringSubmatch(cell); m != nil && !strings.HasPrefix(m[0], "!") {
+		return strings.Trim(m[1], "`")
+	}
+	fields := strings.Fields(stripMarkdown(cell))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
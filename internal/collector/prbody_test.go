@@ -0,0 +1,140 @@
+package collector
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+const renovateBody = `This PR contains the following updates:
+
+| Package | Type | Update | Change | Age | Confidence |
+|---|---|---|---|---|---|
+| [golang.org/x/net](https://pkg.go.dev/golang.org/x/net) | require | minor | ` + "`v0.29.0` -> `v0.30.0`" + ` | [![age](https://developer.mend.io/api/mc/badges/age/go/golang.org%2fx%2fnet/v0.30.0?slim=true)](https://docs.renovatebot.com/merge-confidence/) | [![confidence](https://developer.mend.io/api/mc/badges/confidence/go/x/v0.30.0?slim=true)](https://docs.renovatebot.com/merge-confidence/) |
+| [github.com/stretchr/objx](https://redirect.github.com/stretchr/objx) ([source](https://github.com/stretchr/objx)) | indirect | digest | ` + "`v0.5.2-0.20240101000000-abcdef123456` -> `v0.5.3-0.20240601000000-0123456789ab`" + ` | | |
+| [@types/node](https://redirect.github.com/DefinitelyTyped/DefinitelyTyped) | devDependencies | major | [` + "`^20.0.0` -> `^22.0.0`" + `](https://renovatebot.com/diffs/npm/@types%2fnode/20.0.0/22.0.0) | | |
+
+---
+
+### Release Notes
+
+| Package | Change |
+|---|---|
+| ignored | ` + "`1` -> `2`" + ` |
+`
+
+func TestParseRenovateBody(t *testing.T) {
+	deps := parseDependencies(ParserRenovate, renovateBody, nil)
+
+	want := []model.Dependency{
+		{Name: "golang.org/x/net", Ecosystem: "go", FromVersion: "v0.29.0", ToVersion: "v0.30.0",
+			UpdateType: model.UpdateTypeMinor, DepType: model.DependencyTypeDirect, Manager: "gomod"},
+		{Name: "github.com/stretchr/objx", Ecosystem: "go",
+			FromVersion: "v0.5.2-0.20240101000000-abcdef123456", ToVersion: "v0.5.3-0.20240601000000-0123456789ab",
+			UpdateType: model.UpdateTypeUnknown, DepType: model.DependencyTypeIndirect, Manager: "gomod"},
+		{Name: "@types/node", Ecosystem: "npm", FromVersion: "^20.0.0", ToVersion: "^22.0.0",
+			UpdateType: model.UpdateTypeMajor, DepType: model.DependencyTypeDevelopment, Manager: "npm"},
+	}
+	if !reflect.DeepEqual(deps, want) {
+		t.Errorf("parseDependencies() =\n%+v\nwant\n%+v", deps, want)
+	}
+}
+
+func TestParseRenovateBody_PackageFile(t *testing.T) {
+	body := "| Package | Change | Package file |\n|---|---|---|\n" +
+		"| docker.io/library/golang | `1.22` -> `1.23` | build/Dockerfile |\n"
+
+	deps := parseDependencies(ParserRenovate, body, nil)
+	if len(deps) != 1 || deps[0].Manager != "dockerfile" || deps[0].Ecosystem != "docker" || deps[0].DepType != "" {
+		t.Errorf("expected Docker update, got %+v", deps)
+	}
+}
+
+func TestParseDependabotBody(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		labels []string
+		want   []model.Dependency
+	}{
+		{
+			name: "single",
+			body: "Bumps [golang.org/x/net](https://github.com/golang/net) from 0.29.0 to 0.30.0.\n" +
+				"<details>\n<summary>Commits</summary>\n</details>\n",
+			labels: []string{"dependencies", "go"},
+			want: []model.Dependency{{Name: "golang.org/x/net", Ecosystem: "go", FromVersion: "0.29.0", ToVersion: "0.30.0",
+				UpdateType: model.UpdateTypeMinor, Manager: "go_modules"}},
+		},
+		{
+			name: "group with metadata",
+			body: "Bumps the npm group with 2 updates: [lodash](https://github.com/lodash/lodash) and [eslint](https://github.com/eslint/eslint).\n\n" +
+				"Updates `lodash` from 4.17.20 to 4.17.21\n<details>\n</details>\n\n" +
+				"Updates `eslint` from 8.57.0 to 9.0.0\n<details>\n</details>\n\n" +
+				"---\nupdated-dependencies:\n" +
+				"- dependency-name: lodash\n  dependency-type: direct:production\n  update-type: version-update:semver-patch\n  dependency-group: npm\n" +
+				"- dependency-name: eslint\n  dependency-type: direct:development\n  update-type: version-update:semver-major\n  dependency-group: npm\n" +
+				"...\n",
+			labels: []string{"javascript"},
+			want: []model.Dependency{
+				{Name: "lodash", Ecosystem: "npm", FromVersion: "4.17.20", ToVersion: "4.17.21",
+					UpdateType: model.UpdateTypePatch, DepType: model.DependencyTypeDirect, Manager: "npm_and_yarn"},
+				{Name: "eslint", Ecosystem: "npm", FromVersion: "8.57.0", ToVersion: "9.0.0",
+					UpdateType: model.UpdateTypeMajor, DepType: model.DependencyTypeDevelopment, Manager: "npm_and_yarn"},
+			},
+		},
+		{
+			name: "metadata only",
+			body: "Bump golang.org/x/text\n\n---\nupdated-dependencies:\n" +
+				"- dependency-name: golang.org/x/text\n  dependency-version: 0.20.0\n  dependency-type: indirect\n" +
+				"  update-type: version-update:semver-minor\n...\n\nSigned-off-by: dependabot[bot] <support@github.com>\n",
+			want: []model.Dependency{{Name: "golang.org/x/text", Ecosystem: "go", ToVersion: "0.20.0",
+				UpdateType: model.UpdateTypeMinor, DepType: model.DependencyTypeIndirect}},
+		},
+		{
+			name: "no metadata",
+			body: "Dependabot can't resolve your Go dependency files.",
+		},
+	}
+
+	for _, tt := range tests {
+		deps := parseDependencies(ParserDependabot, tt.body, tt.labels)
+		if !reflect.DeepEqual(deps, tt.want) {
+			t.Errorf("%s: parseDependencies() =\n%+v\nwant\n%+v", tt.name, deps, tt.want)
+		}
+	}
+}
+
+func TestDetectDependency_TitleFallback(t *testing.T) {
+	pr := model.PullRequest{
+		Title:  "chore(deps): update golang.org/x/net from v0.29.0 to v0.30.0",
+		Author: "renovate[bot]",
+		Body:   "Renovate could not list the updates.",
+	}
+	if !detectDependency(&pr, nil) {
+		t.Fatal("expected dependency PR")
+	}
+	if pr.Dependency.Name != "golang.org/x/net" || pr.Dependency.UpdateType != model.UpdateTypeMinor {
+		t.Errorf("expected dependency from title, got %+v", pr.Dependency)
+	}
+}
+
+func TestDetectDependency_Group(t *testing.T) {
+	pr := model.PullRequest{
+		Title:  "chore(deps): update go modules",
+		Author: "renovate[bot]",
+		Body:   renovateBody,
+	}
+	if !detectDependency(&pr, nil) {
+		t.Fatal("expected dependency PR")
+	}
+	if !pr.IsGroup() || len(pr.Dependencies) != 3 {
+		t.Fatalf("expected group of 3 dependencies, got %+v", pr.Dependencies)
+	}
+
+	// The digest update has an unknown update type, which outranks major.
+	want := model.Dependency{UpdateType: model.UpdateTypeUnknown}
+	if pr.Dependency != want {
+		t.Errorf("expected aggregate %+v, got %+v", want, pr.Dependency)
+	}
+}
@@ -0,0 +1,112 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/plexusone/versionconductor/internal/provider"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// Router implements Collector by sending each request to the collector
+// serving the repository's owner, so repositories on different platforms
+// can be read in one run.
+type Router struct {
+	routes *provider.Routes[Collector]
+}
+
+// NewRouter creates a collector routing requests by owner.
+func NewRouter(routes *provider.Routes[Collector]) *Router {
+	return &Router{routes: routes}
+}
+
+// ListRepos returns the repositories of each organization from its
+// collector, in the order of orgs.
+func (r *Router) ListRepos(ctx context.Context, orgs []string, filter model.RepoFilter) ([]model.Repo, error) {
+	var repos []model.Repo
+	for _, org := range orgs {
+		orgRepos, err := r.routes.For(org).ListRepos(ctx, []string{org}, filter)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, orgRepos...)
+	}
+	return repos, nil
+}
+
+// ListDependencyPRs returns open dependency PRs for a repository.
+func (r *Router) ListDependencyPRs(ctx context.Context, repo model.RepoRef) ([]model.PullRequest, error) {
+	return r.routes.For(repo.Owner).ListDependencyPRs(ctx, repo)
+}
+
+// GetPRDetails returns detailed information about a specific PR.
+func (r *Router) GetPRDetails(ctx context.Context, repo model.RepoRef, prNumber int) (*model.PullRequest, error) {
+	return r.routes.For(repo.Owner).GetPRDetails(ctx, repo, prNumber)
+}
+
+// GetPRChecks returns the CI check runs for a PR.
+func (r *Router) GetPRChecks(ctx context.Context, repo model.RepoRef, prNumber int) ([]model.CheckRun, error) {
+	return r.routes.For(repo.Owner).GetPRChecks(ctx, repo, prNumber)
+}
+
+// ListPRFiles returns the paths of the files changed by a PR.
+func (r *Router) ListPRFiles(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	return r.routes.For(repo.Owner).ListPRFiles(ctx, repo, prNumber)
+}
+
+// ListPRCommitAuthors returns the distinct authors of a PR's commits.
+func (r *Router) ListPRCommitAuthors(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	return r.routes.For(repo.Owner).ListPRCommitAuthors(ctx, repo, prNumber)
+}
+
+// ListPRApprovers returns the users whose review approves a PR.
+func (r *Router) ListPRApprovers(ctx context.Context, repo model.RepoRef, prNumber int) ([]string, error) {
+	return r.routes.For(repo.Owner).ListPRApprovers(ctx, repo, prNumber)
+}
+
+// GetLatestRelease returns the most recent release for a repository.
+func (r *Router) GetLatestRelease(ctx context.Context, repo model.RepoRef) (*model.Release, error) {
+	return r.routes.For(repo.Owner).GetLatestRelease(ctx, repo)
+}
+
+// ListTags returns all tags for a repository.
+func (r *Router) ListTags(ctx context.Context, repo model.RepoRef) ([]model.Tag, error) {
+	return r.routes.For(repo.Owner).ListTags(ctx, repo)
+}
+
+// GetMergedPRsSinceTag returns PRs merged since the given tag.
+func (r *Router) GetMergedPRsSinceTag(ctx context.Context, repo model.RepoRef, tagName string) ([]model.PullRequest, error) {
+	return r.routes.For(repo.Owner).GetMergedPRsSinceTag(ctx, repo, tagName)
+}
+
+// GetRepoConfig returns the repository's in-repo config.
+func (r *Router) GetRepoConfig(ctx context.Context, repo model.RepoRef) (*model.RepoConfig, error) {
+	return r.routes.For(repo.Owner).GetRepoConfig(ctx, repo)
+}
+
+// GetBranchProtection returns the protection rules of a branch.
+func (r *Router) GetBranchProtection(ctx context.Context, repo model.RepoRef, branch string) (*model.BranchProtection, error) {
+	return r.routes.For(repo.Owner).GetBranchProtection(ctx, repo, branch)
+}
+
+// Prefetch prefetches the repositories of each collector that supports it.
+func (r *Router) Prefetch(ctx context.Context, repos []model.RepoRef) error {
+	var order []Prefetcher
+	byCollector := make(map[Prefetcher][]model.RepoRef)
+	for _, repo := range repos {
+		p, ok := r.routes.For(repo.Owner).(Prefetcher)
+		if !ok {
+			continue
+		}
+		if _, seen := byCollector[p]; !seen {
+			order = append(order, p)
+		}
+		byCollector[p] = append(byCollector[p], repo)
+	}
+
+	for _, p := range order {
+		if err := p.Prefetch(ctx, byCollector[p]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Package gitea is a minimal client for the Gitea API v1, which Forgejo
+// also serves, covering the repositories, pull requests, commit statuses,
+// tags and releases used by the Gitea collector, merger and releaser.
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/plexusone/versionconductor/internal/rest"
+)
+
+// perPage is the page size of list requests. Gitea caps it at the
+// server's MAX_RESPONSE_ITEMS, 50 by default.
+const perPage = 50
+
+// Client is a Gitea API client.
+type Client struct {
+	api *rest.Client // base URL ends with /api/v1/
+}
+
+// NewClient creates a client for the Gitea or Forgejo instance at baseURL,
+// e.g. https://git.example.com. The API path /api/v1 is added if missing.
+// Requests are sent with httpClient, which must authenticate them, e.g.
+// with an auth.Provider transport adding a bearer token.
+func NewClient(httpClient *http.Client, baseURL string) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid Gitea URL %q", baseURL)
+	}
+
+	base := strings.TrimSuffix(u.String(), "/")
+	if !strings.HasSuffix(base, "/api/v1") {
+		base += "/api/v1"
+	}
+
+	return &Client{api: rest.NewClient(httpClient, base+"/", newError)}, nil
+}
+
+// Error is an error response from the Gitea API.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return fmt.Sprintf("Gitea API error %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 response.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// repoPath returns the API path of a repository.
+func repoPath(owner, repo string) string {
+	return "repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+}
+
+// do sends a request and decodes the JSON response into v (see
+// rest.Client.Do).
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, v any) (*http.Response, error) {
+	return c.api.Do(ctx, method, path, query, body, v)
+}
+
+// newError reads an error response. Gitea reports errors as
+// {"message": ..., "url": ...}.
+func newError(resp *http.Response) error {
+	data := rest.ReadError(resp)
+
+	var body struct {
+		Message string `json:"message"`
+	}
+	msg := strings.TrimSpace(string(data))
+	if json.Unmarshal(data, &body) == nil && body.Message != "" {
+		msg = body.Message
+	}
+	if msg == "" {
+		msg = http.StatusText(resp.StatusCode)
+	}
+
+	return &Error{StatusCode: resp.StatusCode, Message: msg}
+}
+
+// list fetches all pages of a list endpoint.
+func list[T any](ctx context.Context, c *Client, path string, query url.Values) ([]T, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("limit", strconv.Itoa(perPage))
+
+	var all []T
+	for page := "1"; page != ""; {
+		query.Set("page", page)
+
+		var items []T
+		resp, err := c.do(ctx, http.MethodGet, path, query, nil, &items)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+
+		page = nextPage(resp.Header.Get("Link"))
+	}
+
+	return all, nil
+}
+
+// nextPage returns the page number of the rel="next" link of a Link
+// header, or "" on the last page.
+func nextPage(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		u, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+		if err != nil {
+			return ""
+		}
+		return u.Query().Get("page")
+	}
+	return ""
+}
@@ -0,0 +1,122 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{"https://git.example.com", "https://git.example.com/api/v1/", false},
+		{"https://git.example.com/forgejo/", "https://git.example.com/forgejo/api/v1/", false},
+		{"https://git.example.com/api/v1", "https://git.example.com/api/v1/", false},
+		{"", "", true},
+		{"git.example.com", "", true},
+	}
+
+	for _, tt := range tests {
+		c, err := NewClient(nil, tt.url)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NewClient(%q): expected error", tt.url)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NewClient(%q): unexpected error: %v", tt.url, err)
+			continue
+		}
+		if c.api.BaseURL() != tt.want {
+			t.Errorf("NewClient(%q) base URL = %q, want %q", tt.url, c.api.BaseURL(), tt.want)
+		}
+	}
+}
+
+func TestClient_ListPagination(t *testing.T) {
+	var pages []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		if page == "1" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?limit=50&page=2>; rel="next",<%s%s?limit=50&page=2>; rel="last"`,
+				server.URL, r.URL.Path, server.URL, r.URL.Path))
+			fmt.Fprint(w, `[{"name":"tools","full_name":"team/tools"}]`)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s?limit=50&page=1>; rel="first"`, server.URL, r.URL.Path))
+		fmt.Fprint(w, `[{"name":"infra","full_name":"team/infra"}]`)
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := NewClient(server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repos, err := c.ListOrgRepos(context.Background(), "team")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repos) != 2 || repos[1].Name != "infra" {
+		t.Errorf("expected repos from both pages, got %+v", repos)
+	}
+	if fmt.Sprint(pages) != "[1 2]" {
+		t.Errorf("expected pages [1 2], got %v", pages)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"GetRepositoryByName","url":"https://git.example.com/api/swagger"}`)
+			return
+		}
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		fmt.Fprint(w, `{"message":"Please try again later","url":"https://git.example.com/api/swagger"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := NewClient(server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	_, err = c.GetRepo(ctx, "team", "missing")
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	err = c.MergePullRequest(ctx, "team", "tools", 1, MergePullRequestOptions{Do: "squash"})
+	if IsNotFound(err) || err == nil || !strings.Contains(err.Error(), "Please try again later") {
+		t.Errorf("expected merge error, got %v", err)
+	}
+}
+
+func TestPullRequest_IsDraft(t *testing.T) {
+	tests := []struct {
+		pr   PullRequest
+		want bool
+	}{
+		{PullRequest{Title: "Update module golang.org/x/net to v0.30.0"}, false},
+		{PullRequest{Title: "WIP: Update module golang.org/x/net"}, true},
+		{PullRequest{Title: "[wip] Update module golang.org/x/net"}, true},
+		{PullRequest{Title: "Update module golang.org/x/net", Draft: true}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.pr.IsDraft(); got != tt.want {
+			t.Errorf("IsDraft() for %q = %v, want %v", tt.pr.Title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PullRequest is a Gitea pull request.
+type PullRequest struct {
+	Number         int        `json:"number"`
+	Title          string     `json:"title"`
+	Body           string     `json:"body"`
+	State          string     `json:"state"` // open, closed
+	User           User       `json:"user"`
+	HTMLURL        string     `json:"html_url"`
+	Mergeable      bool       `json:"mergeable"`
+	Merged         bool       `json:"merged"`
+	MergedAt       *time.Time `json:"merged_at"`
+	MergeCommitSHA string     `json:"merge_commit_sha"`
+	Draft          bool       `json:"draft"`
+	Labels         []Label    `json:"labels"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	Head           PRBranch   `json:"head"`
+	Base           PRBranch   `json:"base"`
+}
+
+// Label is an issue or pull request label.
+type Label struct {
+	Name string `json:"name"`
+}
+
+// PRBranch is the head or base branch of a pull request.
+type PRBranch struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+// wipPrefixes are Gitea's default WORK_IN_PROGRESS_PREFIXES.
+var wipPrefixes = []string{"WIP:", "[WIP]"}
+
+// IsDraft reports whether the pull request is a draft. Servers older than
+// Gitea 1.22 only mark drafts with a work-in-progress title prefix.
+func (pr *PullRequest) IsDraft() bool {
+	if pr.Draft {
+		return true
+	}
+	title := strings.ToUpper(pr.Title)
+	for _, p := range wipPrefixes {
+		if strings.HasPrefix(title, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListPullRequests returns the pull requests of a repository in a state:
+// open, closed or all. Closed pull requests are the most recently updated
+// first.
+func (c *Client) ListPullRequests(ctx context.Context, owner, repo, state string) ([]PullRequest, error) {
+	query := url.Values{"state": {state}, "sort": {"recentupdate"}}
+	return list[PullRequest](ctx, c, repoPath(owner, repo)+"/pulls", query)
+}
+
+// GetPullRequest returns a pull request.
+func (c *Client) GetPullRequest(ctx context.Context, owner, repo string, index int) (*PullRequest, error) {
+	var pr PullRequest
+	if _, err := c.do(ctx, http.MethodGet, pullPath(owner, repo, index), nil, nil, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// ChangedFile is a file changed by a pull request.
+type ChangedFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename"`
+	Status           string `json:"status"` // added, modified, deleted, renamed, ...
+}
+
+// ListPullRequestFiles returns the files changed by a pull request.
+func (c *Client) ListPullRequestFiles(ctx context.Context, owner, repo string, index int) ([]ChangedFile, error) {
+	return list[ChangedFile](ctx, c, pullPath(owner, repo, index)+"/files", nil)
+}
+
+// PullRequestCommit is a commit of a pull request. Author is nil if the
+// commit's author has no account.
+type PullRequestCommit struct {
+	SHA    string `json:"sha"`
+	Author *User  `json:"author"`
+	Commit struct {
+		Author struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+// ListPullRequestCommits returns the commits of a pull request.
+func (c *Client) ListPullRequestCommits(ctx context.Context, owner, repo string, index int) ([]PullRequestCommit, error) {
+	return list[PullRequestCommit](ctx, c, pullPath(owner, repo, index)+"/commits", nil)
+}
+
+// CreateReviewOptions are the parameters of a pull request review.
+type CreateReviewOptions struct {
+	Event string `json:"event"` // APPROVED, REQUEST_CHANGES, COMMENT
+	Body  string `json:"body,omitempty"`
+}
+
+// CreateReview submits a review of a pull request.
+func (c *Client) CreateReview(ctx context.Context, owner, repo string, index int, opts CreateReviewOptions) error {
+	_, err := c.do(ctx, http.MethodPost, pullPath(owner, repo, index)+"/reviews", nil, opts, nil)
+	return err
+}
+
+// Review is a pull request review.
+type Review struct {
+	User      User   `json:"user"`
+	State     string `json:"state"` // APPROVED, REQUEST_CHANGES, COMMENT, PENDING, ...
+	Dismissed bool   `json:"dismissed"`
+}
+
+// ListReviews returns the reviews of a pull request, oldest first.
+func (c *Client) ListReviews(ctx context.Context, owner, repo string, index int) ([]Review, error) {
+	return list[Review](ctx, c, pullPath(owner, repo, index)+"/reviews", nil)
+}
+
+// MergePullRequestOptions are the parameters of a merge.
+type MergePullRequestOptions struct {
+	// Do is the merge style: merge, rebase, rebase-merge, squash or
+	// fast-forward-only.
+	Do                     string `json:"Do"`
+	MergeTitleField        string `json:"MergeTitleField,omitempty"`
+	MergeMessageField      string `json:"MergeMessageField,omitempty"`
+	DeleteBranchAfterMerge bool   `json:"delete_branch_after_merge,omitempty"`
+}
+
+// MergePullRequest merges a pull request.
+func (c *Client) MergePullRequest(ctx context.Context, owner, repo string, index int, opts MergePullRequestOptions) error {
+	_, err := c.do(ctx, http.MethodPost, pullPath(owner, repo, index)+"/merge", nil, opts, nil)
+	return err
+}
+
+// pullPath returns the API path of a pull request.
+func pullPath(owner, repo string, index int) string {
+	return repoPath(owner, repo) + "/pulls/" + strconv.Itoa(index)
+}
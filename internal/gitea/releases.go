@@ -0,0 +1,52 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Release is a Gitea release.
+type Release struct {
+	TagName         string    `json:"tag_name"`
+	TargetCommitish string    `json:"target_commitish"`
+	Name            string    `json:"name"`
+	Body            string    `json:"body"`
+	Draft           bool      `json:"draft"`
+	Prerelease      bool      `json:"prerelease"`
+	CreatedAt       time.Time `json:"created_at"`
+	PublishedAt     time.Time `json:"published_at"`
+	HTMLURL         string    `json:"html_url"`
+}
+
+// GetLatestRelease returns the most recent published release that is not
+// a prerelease, or nil if there is none.
+func (c *Client) GetLatestRelease(ctx context.Context, owner, repo string) (*Release, error) {
+	var r Release
+	if _, err := c.do(ctx, http.MethodGet, repoPath(owner, repo)+"/releases/latest", nil, nil, &r); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &r, nil
+}
+
+// CreateReleaseOptions are the parameters of a new release.
+type CreateReleaseOptions struct {
+	TagName         string `json:"tag_name"`
+	TargetCommitish string `json:"target_commitish,omitempty"` // creates the tag if missing
+	Name            string `json:"name,omitempty"`
+	Body            string `json:"body,omitempty"`
+	Draft           bool   `json:"draft,omitempty"`
+	Prerelease      bool   `json:"prerelease,omitempty"`
+}
+
+// CreateRelease creates a release.
+func (c *Client) CreateRelease(ctx context.Context, owner, repo string, opts CreateReleaseOptions) (*Release, error) {
+	var r Release
+	if _, err := c.do(ctx, http.MethodPost, repoPath(owner, repo)+"/releases", nil, opts, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
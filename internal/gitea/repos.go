@@ -0,0 +1,165 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// User is a Gitea user or organization.
+type User struct {
+	Login string `json:"login"`
+}
+
+// Repository is a Gitea repository.
+type Repository struct {
+	Name          string    `json:"name"`
+	FullName      string    `json:"full_name"`
+	Owner         User      `json:"owner"`
+	Description   string    `json:"description"`
+	DefaultBranch string    `json:"default_branch"`
+	Private       bool      `json:"private"`
+	Internal      bool      `json:"internal"`
+	Archived      bool      `json:"archived"`
+	Fork          bool      `json:"fork"`
+	Mirror        bool      `json:"mirror"`
+	Language      string    `json:"language"`
+	Topics        []string  `json:"topics"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	HTMLURL       string    `json:"html_url"`
+}
+
+// ListOrgRepos returns the repositories of an organization.
+func (c *Client) ListOrgRepos(ctx context.Context, org string) ([]Repository, error) {
+	return list[Repository](ctx, c, "orgs/"+url.PathEscape(org)+"/repos", nil)
+}
+
+// ListUserRepos returns the repositories owned by a user.
+func (c *Client) ListUserRepos(ctx context.Context, user string) ([]Repository, error) {
+	return list[Repository](ctx, c, "users/"+url.PathEscape(user)+"/repos", nil)
+}
+
+// GetRepo returns a repository.
+func (c *Client) GetRepo(ctx context.Context, owner, repo string) (*Repository, error) {
+	var r Repository
+	if _, err := c.do(ctx, http.MethodGet, repoPath(owner, repo), nil, nil, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Branch is a repository branch. The protection fields are those of the
+// branch protection rule applying to it, if Protected.
+type Branch struct {
+	Name   string `json:"name"`
+	Commit struct {
+		ID        string    `json:"id"`
+		Timestamp time.Time `json:"timestamp"`
+	} `json:"commit"`
+	Protected           bool     `json:"protected"`
+	RequiredApprovals   int      `json:"required_approvals"`
+	EnableStatusCheck   bool     `json:"enable_status_check"`
+	StatusCheckContexts []string `json:"status_check_contexts"`
+}
+
+// GetBranch returns a branch.
+func (c *Client) GetBranch(ctx context.Context, owner, repo, branch string) (*Branch, error) {
+	var b Branch
+	path := repoPath(owner, repo) + "/branches/" + url.PathEscape(branch)
+	if _, err := c.do(ctx, http.MethodGet, path, nil, nil, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// DeleteBranch deletes a branch.
+func (c *Client) DeleteBranch(ctx context.Context, owner, repo, branch string) error {
+	path := repoPath(owner, repo) + "/branches/" + url.PathEscape(branch)
+	_, err := c.do(ctx, http.MethodDelete, path, nil, nil, nil)
+	return err
+}
+
+// CommitStatus is the status reported by a CI system, including Gitea or
+// Forgejo Actions, for a commit.
+type CommitStatus struct {
+	Context     string `json:"context"`
+	Status      string `json:"status"` // pending, success, error, failure, warning
+	Description string `json:"description"`
+	TargetURL   string `json:"target_url"`
+}
+
+// CombinedStatus is the latest status of each context for a commit.
+type CombinedStatus struct {
+	State    string         `json:"state"`
+	Statuses []CommitStatus `json:"statuses"`
+}
+
+// GetCombinedStatus returns the latest status of each CI context for a
+// commit.
+func (c *Client) GetCombinedStatus(ctx context.Context, owner, repo, ref string) (*CombinedStatus, error) {
+	var s CombinedStatus
+	path := repoPath(owner, repo) + "/commits/" + url.PathEscape(ref) + "/status"
+	if _, err := c.do(ctx, http.MethodGet, path, nil, nil, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Tag is a repository tag.
+type Tag struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	Commit  struct {
+		SHA     string    `json:"sha"`
+		Created time.Time `json:"created"`
+	} `json:"commit"`
+}
+
+// ListTags returns the tags of a repository.
+func (c *Client) ListTags(ctx context.Context, owner, repo string) ([]Tag, error) {
+	return list[Tag](ctx, c, repoPath(owner, repo)+"/tags", nil)
+}
+
+// GetTag returns a tag.
+func (c *Client) GetTag(ctx context.Context, owner, repo, name string) (*Tag, error) {
+	var t Tag
+	path := repoPath(owner, repo) + "/tags/" + url.PathEscape(name)
+	if _, err := c.do(ctx, http.MethodGet, path, nil, nil, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// CreateTagOptions are the parameters of a new tag.
+type CreateTagOptions struct {
+	TagName string `json:"tag_name"`
+	Target  string `json:"target,omitempty"` // branch or commit SHA
+	Message string `json:"message,omitempty"`
+}
+
+// CreateTag creates a tag, annotated if it has a message.
+func (c *Client) CreateTag(ctx context.Context, owner, repo string, opts CreateTagOptions) (*Tag, error) {
+	var t Tag
+	if _, err := c.do(ctx, http.MethodPost, repoPath(owner, repo)+"/tags", nil, opts, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetRawFile returns the content of a file at ref, a branch, tag or
+// commit SHA.
+func (c *Client) GetRawFile(ctx context.Context, owner, repo, file, ref string) ([]byte, error) {
+	segments := strings.Split(file, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+
+	var data []byte
+	path := repoPath(owner, repo) + "/raw/" + strings.Join(segments, "/")
+	if _, err := c.do(ctx, http.MethodGet, path, url.Values{"ref": {ref}}, nil, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,132 @@
+// Package gitlab is a minimal client for the GitLab REST API v4, covering
+// the projects, merge requests, pipelines, tags and releases used by the
+// GitLab collector, merger and releaser.
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/plexusone/versionconductor/internal/rest"
+)
+
+// DefaultURL is the URL of gitlab.com.
+const DefaultURL = "https://gitlab.com"
+
+// perPage is the page size of list requests.
+const perPage = 100
+
+// Client is a GitLab API client.
+type Client struct {
+	api *rest.Client // base URL ends with /api/v4/
+}
+
+// NewClient creates a client for the GitLab instance at baseURL, e.g.
+// https://gitlab.example.com. The API path /api/v4 is added if missing.
+// Requests are sent with httpClient, which must authenticate them, e.g.
+// with an auth.Provider transport adding a bearer token.
+func NewClient(httpClient *http.Client, baseURL string) (*Client, error) {
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid GitLab URL %q", baseURL)
+	}
+
+	base := strings.TrimSuffix(u.String(), "/")
+	if !strings.HasSuffix(base, "/api/v4") {
+		base += "/api/v4"
+	}
+
+	return &Client{api: rest.NewClient(httpClient, base+"/", newError)}, nil
+}
+
+// Error is an error response from the GitLab API.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return fmt.Sprintf("GitLab API error %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 response.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// projectPath returns the API path of a project given by its full path,
+// such as "group/subgroup/project".
+func projectPath(project string) string {
+	return "projects/" + url.PathEscape(project)
+}
+
+// do sends a request and decodes the JSON response into v (see
+// rest.Client.Do).
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, v any) (*http.Response, error) {
+	return c.api.Do(ctx, method, path, query, body, v)
+}
+
+// newError reads an error response. GitLab reports errors as
+// {"message": ...} or {"error": ...}, where message may be an object.
+func newError(resp *http.Response) error {
+	data := rest.ReadError(resp)
+
+	var body struct {
+		Message any    `json:"message"`
+		Error   string `json:"error"`
+	}
+	msg := strings.TrimSpace(string(data))
+	if json.Unmarshal(data, &body) == nil {
+		switch {
+		case body.Message != nil:
+			if s, ok := body.Message.(string); ok {
+				msg = s
+			} else {
+				b, _ := json.Marshal(body.Message)
+				msg = string(b)
+			}
+		case body.Error != "":
+			msg = body.Error
+		}
+	}
+	if msg == "" {
+		msg = http.StatusText(resp.StatusCode)
+	}
+
+	return &Error{StatusCode: resp.StatusCode, Message: msg}
+}
+
+// list fetches all pages of a list endpoint.
+func list[T any](ctx context.Context, c *Client, path string, query url.Values) ([]T, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("per_page", strconv.Itoa(perPage))
+
+	var all []T
+	for page := "1"; page != ""; {
+		query.Set("page", page)
+
+		var items []T
+		resp, err := c.do(ctx, http.MethodGet, path, query, nil, &items)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+
+		page = resp.Header.Get("X-Next-Page")
+	}
+
+	return all, nil
+}
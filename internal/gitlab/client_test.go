@@ -0,0 +1,137 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{"", "https://gitlab.com/api/v4/", false},
+		{"https://gitlab.example.com", "https://gitlab.example.com/api/v4/", false},
+		{"https://gitlab.example.com/", "https://gitlab.example.com/api/v4/", false},
+		{"https://gitlab.example.com/api/v4", "https://gitlab.example.com/api/v4/", false},
+		{"gitlab.example.com", "", true},
+	}
+
+	for _, tt := range tests {
+		c, err := NewClient(nil, tt.url)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NewClient(%q): expected error", tt.url)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NewClient(%q): unexpected error: %v", tt.url, err)
+			continue
+		}
+		if c.api.BaseURL() != tt.want {
+			t.Errorf("NewClient(%q) base URL = %q, want %q", tt.url, c.api.BaseURL(), tt.want)
+		}
+	}
+}
+
+func TestClient_ListPagination(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath()+"?"+r.URL.Query().Get("page"))
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set("X-Next-Page", "2")
+			fmt.Fprint(w, `[{"name":"v1.0.0","commit":{"id":"a"}}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"name":"v1.1.0","commit":{"id":"b"}}]`)
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := NewClient(server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tags, err := c.ListTags(context.Background(), "group/sub/app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tags) != 2 || tags[1].Commit.ID != "b" {
+		t.Errorf("expected tags from both pages, got %+v", tags)
+	}
+
+	// The project path is sent as a single escaped segment.
+	want := []string{
+		"/api/v4/projects/group%2Fsub%2Fapp/repository/tags?1",
+		"/api/v4/projects/group%2Fsub%2Fapp/repository/tags?2",
+	}
+	if fmt.Sprint(paths) != fmt.Sprint(want) {
+		t.Errorf("expected requests %v, got %v", want, paths)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "missing"):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"404 Project Not Found"}`)
+		case strings.HasSuffix(r.URL.Path, "/merge"):
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			fmt.Fprint(w, `{"message":{"base":["Branch cannot be merged"]}}`)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid_token"}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := NewClient(server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	_, err = c.GetProject(ctx, "group/missing")
+	if !IsNotFound(err) || !strings.Contains(err.Error(), "404 Project Not Found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	_, err = c.AcceptMergeRequest(ctx, "group/app", 1, AcceptMergeRequestOptions{})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusMethodNotAllowed ||
+		!strings.Contains(apiErr.Message, "Branch cannot be merged") {
+		t.Errorf("expected merge error, got %v", err)
+	}
+
+	_, err = c.GetProject(ctx, "group/app")
+	if IsNotFound(err) || err == nil || !strings.Contains(err.Error(), "invalid_token") {
+		t.Errorf("expected unauthorized error, got %v", err)
+	}
+}
+
+func TestMergeRequest_Mergeable(t *testing.T) {
+	tests := []struct {
+		mr   MergeRequest
+		want bool
+	}{
+		{MergeRequest{DetailedMergeStatus: "mergeable"}, true},
+		{MergeRequest{DetailedMergeStatus: "ci_still_running", MergeStatus: "can_be_merged"}, false},
+		{MergeRequest{MergeStatus: "can_be_merged"}, true},
+		{MergeRequest{MergeStatus: "can_be_merged", HasConflicts: true}, false},
+		{MergeRequest{MergeStatus: "cannot_be_merged"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.mr.Mergeable(); got != tt.want {
+			t.Errorf("Mergeable() for %+v = %v, want %v", tt.mr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// MergeRequest is a GitLab merge request.
+type MergeRequest struct {
+	IID                 int        `json:"iid"`
+	Title               string     `json:"title"`
+	Description         string     `json:"description"`
+	State               string     `json:"state"` // opened, closed, locked, merged
+	Draft               bool       `json:"draft"`
+	Labels              []string   `json:"labels"`
+	Author              User       `json:"author"`
+	WebURL              string     `json:"web_url"`
+	SourceBranch        string     `json:"source_branch"`
+	SHA                 string     `json:"sha"`
+	MergeCommitSHA      string     `json:"merge_commit_sha"`
+	SquashCommitSHA     string     `json:"squash_commit_sha"`
+	MergeStatus         string     `json:"merge_status"`          // deprecated, e.g. can_be_merged
+	DetailedMergeStatus string     `json:"detailed_merge_status"` // e.g. mergeable, ci_still_running
+	HasConflicts        bool       `json:"has_conflicts"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	MergedAt            *time.Time `json:"merged_at"`
+}
+
+// User is a GitLab user.
+type User struct {
+	Username string `json:"username"`
+	Bot      bool   `json:"bot"`
+}
+
+// SearchUsers returns the users whose name, username or email matches.
+// Searching by email only finds users with that public email, unless the
+// token belongs to an administrator.
+func (c *Client) SearchUsers(ctx context.Context, search string) ([]User, error) {
+	return list[User](ctx, c, "users", url.Values{"search": {search}})
+}
+
+// Mergeable reports whether GitLab considers the merge request ready to
+// merge.
+func (mr *MergeRequest) Mergeable() bool {
+	if mr.DetailedMergeStatus != "" {
+		return mr.DetailedMergeStatus == "mergeable"
+	}
+	return mr.MergeStatus == "can_be_merged" && !mr.HasConflicts
+}
+
+// MergeStatusDetail returns the detailed merge status, or the legacy merge
+// status on GitLab versions without it.
+func (mr *MergeRequest) MergeStatusDetail() string {
+	if mr.DetailedMergeStatus != "" {
+		return mr.DetailedMergeStatus
+	}
+	return mr.MergeStatus
+}
+
+// ListMergeRequestsOptions filters merge requests.
+type ListMergeRequestsOptions struct {
+	State        string // opened, closed, locked, merged, all
+	UpdatedAfter time.Time
+}
+
+// ListMergeRequests returns the merge requests of a project, most
+// recently updated first.
+func (c *Client) ListMergeRequests(ctx context.Context, project string, opts ListMergeRequestsOptions) ([]MergeRequest, error) {
+	query := url.Values{"order_by": {"updated_at"}, "sort": {"desc"}}
+	if opts.State != "" {
+		query.Set("state", opts.State)
+	}
+	if !opts.UpdatedAfter.IsZero() {
+		query.Set("updated_after", opts.UpdatedAfter.UTC().Format(time.RFC3339))
+	}
+	return list[MergeRequest](ctx, c, projectPath(project)+"/merge_requests", query)
+}
+
+// GetMergeRequest returns a merge request by its project-scoped IID.
+func (c *Client) GetMergeRequest(ctx context.Context, project string, iid int) (*MergeRequest, error) {
+	var mr MergeRequest
+	if _, err := c.do(ctx, http.MethodGet, mergeRequestPath(project, iid), nil, nil, &mr); err != nil {
+		return nil, err
+	}
+	return &mr, nil
+}
+
+// ApproveMergeRequest approves a merge request as the current user.
+func (c *Client) ApproveMergeRequest(ctx context.Context, project string, iid int) error {
+	_, err := c.do(ctx, http.MethodPost, mergeRequestPath(project, iid)+"/approve", nil, nil, nil)
+	return err
+}
+
+// Approvals are the approvals of a merge request.
+type Approvals struct {
+	ApprovedBy []struct {
+		User User `json:"user"`
+	} `json:"approved_by"`
+}
+
+// GetMergeRequestApprovals returns the approvals of a merge request.
+func (c *Client) GetMergeRequestApprovals(ctx context.Context, project string, iid int) (*Approvals, error) {
+	var approvals Approvals
+	if _, err := c.do(ctx, http.MethodGet, mergeRequestPath(project, iid)+"/approvals", nil, nil, &approvals); err != nil {
+		return nil, err
+	}
+	return &approvals, nil
+}
+
+// CreateMergeRequestNote adds a comment to a merge request.
+func (c *Client) CreateMergeRequestNote(ctx context.Context, project string, iid int, body string) error {
+	_, err := c.do(ctx, http.MethodPost, mergeRequestPath(project, iid)+"/notes", nil,
+		map[string]string{"body": body}, nil)
+	return err
+}
+
+// AcceptMergeRequestOptions configures a merge.
+type AcceptMergeRequestOptions struct {
+	Squash                   bool   `json:"squash,omitempty"`
+	MergeCommitMessage       string `json:"merge_commit_message,omitempty"`
+	SquashCommitMessage      string `json:"squash_commit_message,omitempty"`
+	ShouldRemoveSourceBranch bool   `json:"should_remove_source_branch,omitempty"`
+}
+
+// AcceptMergeRequest merges a merge request and returns its new state.
+func (c *Client) AcceptMergeRequest(ctx context.Context, project string, iid int, opts AcceptMergeRequestOptions) (*MergeRequest, error) {
+	var mr MergeRequest
+	if _, err := c.do(ctx, http.MethodPut, mergeRequestPath(project, iid)+"/merge", nil, opts, &mr); err != nil {
+		return nil, err
+	}
+	return &mr, nil
+}
+
+// Diff is a file changed by a merge request.
+type Diff struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+}
+
+// ListMergeRequestDiffs returns the files changed by a merge request.
+func (c *Client) ListMergeRequestDiffs(ctx context.Context, project string, iid int) ([]Diff, error) {
+	return list[Diff](ctx, c, mergeRequestPath(project, iid)+"/diffs", nil)
+}
+
+// ListMergeRequestCommits returns the commits of a merge request.
+func (c *Client) ListMergeRequestCommits(ctx context.Context, project string, iid int) ([]Commit, error) {
+	return list[Commit](ctx, c, mergeRequestPath(project, iid)+"/commits", nil)
+}
+
+// mergeRequestPath returns the API path of a merge request.
+func mergeRequestPath(project string, iid int) string {
+	return projectPath(project) + "/merge_requests/" + strconv.Itoa(iid)
+}
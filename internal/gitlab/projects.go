@@ -0,0 +1,79 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Project is a GitLab project.
+type Project struct {
+	ID                int64     `json:"id"`
+	Path              string    `json:"path"`
+	PathWithNamespace string    `json:"path_with_namespace"`
+	Description       string    `json:"description"`
+	DefaultBranch     string    `json:"default_branch"`
+	Visibility        string    `json:"visibility"` // private, internal, public
+	Archived          bool      `json:"archived"`
+	Topics            []string  `json:"topics"`
+	LastActivityAt    time.Time `json:"last_activity_at"`
+	WebURL            string    `json:"web_url"`
+	Namespace         Namespace `json:"namespace"`
+	ForkedFromProject *struct {
+		ID int64 `json:"id"`
+	} `json:"forked_from_project"`
+}
+
+// Namespace is the group or user a project belongs to.
+type Namespace struct {
+	FullPath string `json:"full_path"`
+	Kind     string `json:"kind"` // group, user
+}
+
+// ListGroupProjects returns the projects of a group and its subgroups.
+func (c *Client) ListGroupProjects(ctx context.Context, group string) ([]Project, error) {
+	query := url.Values{"include_subgroups": {"true"}}
+	return list[Project](ctx, c, "groups/"+url.PathEscape(group)+"/projects", query)
+}
+
+// ListUserProjects returns the projects owned by a user.
+func (c *Client) ListUserProjects(ctx context.Context, user string) ([]Project, error) {
+	return list[Project](ctx, c, "users/"+url.PathEscape(user)+"/projects", nil)
+}
+
+// GetProject returns a project by its full path.
+func (c *Client) GetProject(ctx context.Context, project string) (*Project, error) {
+	var p Project
+	if _, err := c.do(ctx, http.MethodGet, projectPath(project), nil, nil, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ProtectedBranch is a protected branch of a project.
+type ProtectedBranch struct {
+	Name string `json:"name"`
+}
+
+// GetProtectedBranch returns the protection of a branch.
+func (c *Client) GetProtectedBranch(ctx context.Context, project, branch string) (*ProtectedBranch, error) {
+	var b ProtectedBranch
+	path := projectPath(project) + "/protected_branches/" + url.PathEscape(branch)
+	if _, err := c.do(ctx, http.MethodGet, path, nil, nil, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ApprovalRule is a merge request approval rule of a project.
+type ApprovalRule struct {
+	Name              string `json:"name"`
+	RuleType          string `json:"rule_type"`
+	ApprovalsRequired int    `json:"approvals_required"`
+}
+
+// ListApprovalRules returns the merge request approval rules of a project.
+func (c *Client) ListApprovalRules(ctx context.Context, project string) ([]ApprovalRule, error) {
+	return list[ApprovalRule](ctx, c, projectPath(project)+"/approval_rules", nil)
+}
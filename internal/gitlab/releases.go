@@ -0,0 +1,55 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Release is a GitLab release.
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	ReleasedAt  time.Time `json:"released_at"`
+	Upcoming    bool      `json:"upcoming_release"`
+	Links       struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+// GetLatestRelease returns the most recently released release of a
+// project, or nil if it has none.
+func (c *Client) GetLatestRelease(ctx context.Context, project string) (*Release, error) {
+	var releases []Release
+	query := url.Values{"order_by": {"released_at"}, "sort": {"desc"}, "per_page": {"1"}}
+	if _, err := c.do(ctx, http.MethodGet, projectPath(project)+"/releases", query, nil, &releases); err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 {
+		return nil, nil
+	}
+	return &releases[0], nil
+}
+
+// CreateReleaseOptions describes a release to create.
+type CreateReleaseOptions struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Ref is the branch or commit the tag is created from if it doesn't
+	// exist yet.
+	Ref string `json:"ref,omitempty"`
+}
+
+// CreateRelease creates a release, creating its tag from Ref if needed.
+func (c *Client) CreateRelease(ctx context.Context, project string, opts CreateReleaseOptions) (*Release, error) {
+	var r Release
+	if _, err := c.do(ctx, http.MethodPost, projectPath(project)+"/releases", nil, opts, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
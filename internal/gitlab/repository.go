@@ -0,0 +1,101 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Commit is a repository commit.
+type Commit struct {
+	ID            string    `json:"id"`
+	AuthorName    string    `json:"author_name"`
+	AuthorEmail   string    `json:"author_email"`
+	CommittedDate time.Time `json:"committed_date"`
+}
+
+// CommitStatus is the status of a pipeline job or external CI system for
+// a commit.
+type CommitStatus struct {
+	Name         string `json:"name"`
+	Status       string `json:"status"` // created, pending, running, success, failed, canceled, skipped, manual
+	AllowFailure bool   `json:"allow_failure"`
+}
+
+// ListCommitStatuses returns the latest status of each job and external
+// CI system for a commit.
+func (c *Client) ListCommitStatuses(ctx context.Context, project, sha string) ([]CommitStatus, error) {
+	return list[CommitStatus](ctx, c, projectPath(project)+"/repository/commits/"+url.PathEscape(sha)+"/statuses", nil)
+}
+
+// Branch is a repository branch.
+type Branch struct {
+	Name   string `json:"name"`
+	Commit Commit `json:"commit"`
+}
+
+// GetBranch returns a branch.
+func (c *Client) GetBranch(ctx context.Context, project, branch string) (*Branch, error) {
+	var b Branch
+	path := projectPath(project) + "/repository/branches/" + url.PathEscape(branch)
+	if _, err := c.do(ctx, http.MethodGet, path, nil, nil, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// DeleteBranch deletes a branch.
+func (c *Client) DeleteBranch(ctx context.Context, project, branch string) error {
+	path := projectPath(project) + "/repository/branches/" + url.PathEscape(branch)
+	_, err := c.do(ctx, http.MethodDelete, path, nil, nil, nil)
+	return err
+}
+
+// Tag is a repository tag.
+type Tag struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	Commit  Commit `json:"commit"`
+}
+
+// ListTags returns the tags of a project.
+func (c *Client) ListTags(ctx context.Context, project string) ([]Tag, error) {
+	return list[Tag](ctx, c, projectPath(project)+"/repository/tags", nil)
+}
+
+// GetTag returns a tag.
+func (c *Client) GetTag(ctx context.Context, project, name string) (*Tag, error) {
+	var t Tag
+	path := projectPath(project) + "/repository/tags/" + url.PathEscape(name)
+	if _, err := c.do(ctx, http.MethodGet, path, nil, nil, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// CreateTagOptions describes a tag to create.
+type CreateTagOptions struct {
+	TagName string `json:"tag_name"`
+	Ref     string `json:"ref"`
+	Message string `json:"message,omitempty"`
+}
+
+// CreateTag creates a tag. A message makes it an annotated tag.
+func (c *Client) CreateTag(ctx context.Context, project string, opts CreateTagOptions) (*Tag, error) {
+	var t Tag
+	if _, err := c.do(ctx, http.MethodPost, projectPath(project)+"/repository/tags", nil, opts, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetRawFile returns the content of a file at ref, such as a branch name.
+func (c *Client) GetRawFile(ctx context.Context, project, file, ref string) ([]byte, error) {
+	var data []byte
+	path := projectPath(project) + "/repository/files/" + url.PathEscape(file) + "/raw"
+	if _, err := c.do(ctx, http.MethodGet, path, url.Values{"ref": {ref}}, nil, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
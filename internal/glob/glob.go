@@ -0,0 +1,43 @@
+// Package glob matches names, such as module paths, file names and bot
+// logins, against patterns in which "*" matches any sequence of
+// characters.
+package glob
+
+import "strings"
+
+// Match reports whether name matches a pattern. "*" matches any sequence
+// of characters, including "/", so "github.com/aws/*" matches every
+// module under github.com/aws. All other characters match themselves.
+func Match(pattern, name string) bool {
+	// Match greedily, and on a mismatch let the last star take one more
+	// character. Earlier stars never need to be revisited, so this takes
+	// at most len(pattern)*len(name) steps: quadratic in the worst case,
+	// not exponential like naive backtracking, but not linear either.
+	p, n := 0, 0
+	star, starN := -1, 0
+	for n < len(name) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, starN = p, n
+			p++
+		case p < len(pattern) && pattern[p] == name[n]:
+			p++
+			n++
+		case star >= 0:
+			starN++
+			p, n = star+1, starN
+		default:
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// MatchFold is Match ignoring case.
+func MatchFold(pattern, name string) bool {
+	return Match(strings.ToLower(pattern), strings.ToLower(name))
+}
@@ -0,0 +1,56 @@
+package glob
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"golang.org/x/net", "golang.org/x/net", true},
+		{"golang.org/x/net", "golang.org/x/netx", false},
+		{"golang.org/x/*", "golang.org/x/net", true},
+		{"github.com/aws/aws-sdk-go-v2/*", "github.com/aws/aws-sdk-go-v2/service/s3", true},
+		{"github.com/aws/aws-sdk-go-v2/*", "github.com/aws/aws-sdk-go-v2", false},
+		{"@types/*", "@types/node", true},
+		{"*-plugin", "eslint-plugin", true},
+		{"*-plugin", "eslint-plugins", false},
+		{"*", "anything/at/all", true},
+		{"", "", true},
+		{"**", "", true},
+		{"a*b*c", "abxbc", true},
+		{"a*b*c", "abxbd", false},
+		{"Renovate*", "renovate[bot]", false},
+		{strings.Repeat("a*", 30) + "b", strings.Repeat("a", 100), false},
+	}
+
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchFold(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*renovate*", "Renovate-Bot", true},
+		{"*Dependabot*", "dependabot[bot]", true},
+		{"snyk:*", "SNYK:high", true},
+		{"*renovate*", "alice", false},
+		{"a.b", "aXb", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchFold(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("MatchFold(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+// Package goproxy reads when Go module versions were published from a
+// module proxy speaking the GOPROXY protocol, such as proxy.golang.org, a
+// private proxy like Athens, or a file-based mirror.
+package goproxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/plexusone/versionconductor/internal/rest"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// DefaultURL is the proxy used when none is configured.
+const DefaultURL = "https://proxy.golang.org"
+
+// ErrNotFound is returned when no proxy knows a module version.
+var ErrNotFound = errors.New("module version not found")
+
+// Info is the metadata of a module version, as served at
+// <proxy>/<module>/@v/<version>.info.
+type Info struct {
+	Version string    `json:"Version"`
+	Time    time.Time `json:"Time"`
+}
+
+// proxy is one entry of a GOPROXY list. If fallThrough is set, any error
+// moves on to the next entry, as after "|"; otherwise only ErrNotFound
+// does, as after ",".
+type proxy struct {
+	api         *rest.Client // nil for file:// proxies
+	dir         string
+	fallThrough bool
+}
+
+// Client looks up module versions in a list of proxies. Results are cached
+// for the lifetime of the client.
+type Client struct {
+	proxies []proxy
+	private string
+
+	mu    sync.Mutex
+	cache map[string]*Info
+}
+
+// NewClient creates a client for a GOPROXY list such as
+// "https://proxy.example.com,https://proxy.golang.org" or
+// "file:///srv/gomods". "direct" entries are skipped, since there is no
+// proxy to ask, and "off" ends the list. An empty list means DefaultURL.
+// Requests are sent with httpClient, or http.DefaultClient if nil.
+func NewClient(httpClient *http.Client, list string) (*Client, error) {
+	if list == "" {
+		list = DefaultURL
+	}
+
+	c := &Client{cache: make(map[string]*Info)}
+	for list != "" {
+		entry, sep := list, byte(0)
+		if i := strings.IndexAny(list, ",|"); i >= 0 {
+			entry, sep, list = list[:i], list[i], list[i+1:]
+		} else {
+			list = ""
+		}
+
+		entry = strings.TrimSpace(entry)
+		switch entry {
+		case "", "direct":
+			continue
+		case "off":
+			return c, nil
+		}
+
+		p, err := newProxy(httpClient, entry)
+		if err != nil {
+			return nil, err
+		}
+		p.fallThrough = sep == '|'
+		c.proxies = append(c.proxies, p)
+	}
+	return c, nil
+}
+
+// newProxy creates the proxy for an http(s) or file URL.
+func newProxy(httpClient *http.Client, rawURL string) (proxy, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return proxy{}, fmt.Errorf("invalid GOPROXY entry %q: %w", rawURL, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		base := strings.TrimSuffix(u.String(), "/") + "/"
+		return proxy{api: rest.NewClient(httpClient, base, newError)}, nil
+	case "file":
+		return proxy{dir: filepath.FromSlash(u.Path)}, nil
+	default:
+		return proxy{}, fmt.Errorf("invalid GOPROXY entry %q: expected an http, https or file URL", rawURL)
+	}
+}
+
+// newError converts an error response of a proxy. As in the go command,
+// 404 and 410 mean the proxy doesn't have the version.
+func newError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return ErrNotFound
+	}
+	return fmt.Errorf("proxy returned %s: %s", resp.Status, strings.TrimSpace(string(rest.ReadError(resp))))
+}
+
+// SetPrivate sets the modules that are never looked up, so their paths
+// aren't sent to a proxy. As in GONOPROXY and GOPRIVATE, patterns are
+// comma-separated globs matching a prefix of the module path, e.g.
+// "*.corp.example.com,github.com/myorg".
+func (c *Client) SetPrivate(patterns string) {
+	c.private = patterns
+}
+
+// Enabled reports whether the client has a proxy to ask.
+func (c *Client) Enabled() bool {
+	return len(c.proxies) > 0
+}
+
+// Info returns the metadata of a module version from the first proxy that
+// has it. The error is ErrNotFound if none has, if the module is private
+// or if the module path or version is invalid.
+func (c *Client) Info(ctx context.Context, module, version string) (*Info, error) {
+	key := module + "@" + version
+
+	c.mu.Lock()
+	info, ok := c.cache[key]
+	c.mu.Unlock()
+	if ok {
+		return info, nil
+	}
+
+	path, err := infoPath(module, version)
+	if err != nil {
+		return nil, err
+	}
+	if matchPrefixPatterns(c.private, module) {
+		return nil, fmt.Errorf("module %s is private: %w", module, ErrNotFound)
+	}
+
+	err = ErrNotFound
+	for _, p := range c.proxies {
+		info, err = p.info(ctx, path)
+		if err == nil || (!errors.Is(err, ErrNotFound) && !p.fallThrough) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cache[key] = info
+	c.mu.Unlock()
+	return info, nil
+}
+
+// info reads the .info file at path from the proxy.
+func (p proxy) info(ctx context.Context, path string) (*Info, error) {
+	var info Info
+	if p.api != nil {
+		if _, err := p.api.Do(ctx, http.MethodGet, path, nil, nil, &info); err != nil {
+			return nil, err
+		}
+		return &info, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(p.dir, filepath.FromSlash(path))) // #nosec G304
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module info: %w", err)
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse module info: %w", err)
+	}
+	return &info, nil
+}
+
+// infoPath returns the proxy path of a version's .info file.
+func infoPath(module, version string) (string, error) {
+	if module == "" || version == "" {
+		return "", fmt.Errorf("module and version required: %w", ErrNotFound)
+	}
+	if !validPath(module) || strings.Contains(module, "..") || !validPath(version) || strings.Contains(version, "/") {
+		return "", fmt.Errorf("invalid module version %s@%s: %w", module, version, ErrNotFound)
+	}
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return EscapePath(module) + "/@v/" + EscapePath(version) + ".info", nil
+}
+
+// matchPrefixPatterns reports whether a glob in a comma-separated list
+// matches a prefix of the module path with as many elements as the glob,
+// like the go command's GOPRIVATE matching.
+func matchPrefixPatterns(globs, module string) bool {
+	for _, glob := range strings.Split(globs, ",") {
+		glob = strings.TrimSuffix(strings.TrimSpace(glob), "/")
+		if glob == "" {
+			continue
+		}
+
+		elems := strings.Count(glob, "/") + 1
+		parts := strings.SplitN(module, "/", elems+1)
+		if len(parts) < elems {
+			continue
+		}
+		if ok, _ := path.Match(glob, strings.Join(parts[:elems], "/")); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// EscapePath escapes a module path or version for a proxy URL: each upper
+// case letter becomes "!" followed by the lower case letter, so paths stay
+// unique on case-insensitive file systems.
+func EscapePath(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if 'A' <= r && r <= 'Z' {
+			b.WriteByte('!')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// validPath reports whether s only has the characters allowed in module
+// paths and versions.
+func validPath(s string) bool {
+	for _, r := range s {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		case strings.ContainsRune("-._~/+", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// SetReleaseTimes sets the publish time of each Go module a PR updates to.
+// Versions no proxy knows, such as the go directive, are left unknown. A
+// grouped PR's own time is that of its most recent release, if all known.
+func (c *Client) SetReleaseTimes(ctx context.Context, pr *model.PullRequest) error {
+	if !pr.IsGroup() {
+		return c.setReleaseTime(ctx, &pr.Dependency)
+	}
+
+	var latest *time.Time
+	known := true
+	for i := range pr.Dependencies {
+		dep := &pr.Dependencies[i]
+		if err := c.setReleaseTime(ctx, dep); err != nil {
+			return err
+		}
+		switch {
+		case dep.ReleasedAt == nil:
+			known = false
+		case latest == nil || dep.ReleasedAt.After(*latest):
+			latest = dep.ReleasedAt
+		}
+	}
+	if known {
+		pr.Dependency.ReleasedAt = latest
+	}
+	return nil
+}
+
+// setReleaseTime sets the publish time of a Go module dependency.
+func (c *Client) setReleaseTime(ctx context.Context, dep *model.Dependency) error {
+	if dep.Ecosystem != "go" || dep.Name == "" || dep.ToVersion == "" {
+		return nil
+	}
+
+	info, err := c.Info(ctx, dep.Name, dep.ToVersion)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up %s@%s: %w", dep.Name, dep.ToVersion, err)
+	}
+
+	t := info.Time
+	dep.ReleasedAt = &t
+	return nil
+}
@@ -0,0 +1,270 @@
+package goproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// newTestProxy starts a proxy serving the given .info paths and counting
+// the requests it receives.
+func newTestProxy(t *testing.T, infos map[string]string, requests *int) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		body, ok := infos[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		list    string
+		proxies int
+		wantErr bool
+	}{
+		{"", 1, false},
+		{"https://proxy.example.com,https://proxy.golang.org", 2, false},
+		{"https://proxy.example.com|direct", 1, false},
+		{"direct", 0, false},
+		{"off", 0, false},
+		{"https://proxy.example.com,off,https://proxy.golang.org", 1, false},
+		{"file:///srv/gomods", 1, false},
+		{"proxy.example.com", 0, true},
+	}
+
+	for _, tt := range tests {
+		c, err := NewClient(nil, tt.list)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NewClient(%q): expected error", tt.list)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NewClient(%q): unexpected error: %v", tt.list, err)
+			continue
+		}
+		if len(c.proxies) != tt.proxies {
+			t.Errorf("NewClient(%q): expected %d proxies, got %d", tt.list, tt.proxies, len(c.proxies))
+		}
+	}
+}
+
+func TestEscapePath(t *testing.T) {
+	tests := map[string]string{
+		"golang.org/x/net":                         "golang.org/x/net",
+		"github.com/Azure/azure-sdk-for-go":        "github.com/!azure/azure-sdk-for-go",
+		"github.com/BurntSushi/toml":               "github.com/!burnt!sushi/toml",
+		"v1.0.0-RC1":                               "v1.0.0-!r!c1",
+		"github.com/plexusone/versionconductor/v2": "github.com/plexusone/versionconductor/v2",
+	}
+
+	for in, want := range tests {
+		if got := EscapePath(in); got != want {
+			t.Errorf("EscapePath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestClient_Info(t *testing.T) {
+	requests := 0
+	srv := newTestProxy(t, map[string]string{
+		"/golang.org/x/net/@v/v0.30.0.info":    `{"Version":"v0.30.0","Time":"2024-10-04T16:47:06Z"}`,
+		"/github.com/!azure/go/@v/v1.2.0.info": `{"Version":"v1.2.0","Time":"2024-01-02T03:04:05Z"}`,
+	}, &requests)
+
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	info, err := c.Info(context.Background(), "golang.org/x/net", "v0.30.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2024, 10, 4, 16, 47, 6, 0, time.UTC); !info.Time.Equal(want) {
+		t.Errorf("expected time %v, got %v", want, info.Time)
+	}
+
+	if _, err := c.Info(context.Background(), "golang.org/x/net", "v0.30.0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected cached lookup, got %d requests", requests)
+	}
+
+	if _, err := c.Info(context.Background(), "github.com/Azure/go", "1.2.0"); err != nil {
+		t.Errorf("expected escaped path without v prefix to be found: %v", err)
+	}
+
+	for _, mv := range [][2]string{
+		{"golang.org/x/net", "v0.31.0"},
+		{"golang.org/x/../net", "v0.30.0"},
+		{"golang.org/x/net", "../v0.30.0"},
+		{"golang.org/x/net?x=1", "v0.30.0"},
+	} {
+		if _, err := c.Info(context.Background(), mv[0], mv[1]); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Info(%s, %s): expected ErrNotFound, got %v", mv[0], mv[1], err)
+		}
+	}
+}
+
+func TestClient_SetPrivate(t *testing.T) {
+	requests := 0
+	srv := newTestProxy(t, map[string]string{
+		"/github.com/myorganization/lib/@v/v1.0.0.info": `{"Version":"v1.0.0","Time":"2024-01-02T03:04:05Z"}`,
+	}, &requests)
+
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.SetPrivate("*.corp.example.com, github.com/myorg/")
+
+	for _, module := range []string{"git.corp.example.com/team/lib", "github.com/myorg/lib", "github.com/myorg"} {
+		if _, err := c.Info(context.Background(), module, "v1.0.0"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Info(%s): expected ErrNotFound, got %v", module, err)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("expected private modules not to be requested, got %d requests", requests)
+	}
+
+	if _, err := c.Info(context.Background(), "github.com/myorganization/lib", "v1.0.0"); err != nil {
+		t.Errorf("expected public module to be found: %v", err)
+	}
+}
+
+func TestClient_InfoFallback(t *testing.T) {
+	var privateRequests, publicRequests int
+	private := newTestProxy(t, map[string]string{}, &privateRequests)
+	public := newTestProxy(t, map[string]string{
+		"/golang.org/x/net/@v/v0.30.0.info": `{"Version":"v0.30.0","Time":"2024-10-04T16:47:06Z"}`,
+	}, &publicRequests)
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(broken.Close)
+
+	tests := []struct {
+		list    string
+		wantErr bool
+	}{
+		{private.URL + "," + public.URL, false},
+		{broken.URL + "|" + public.URL, false},
+		{broken.URL + "," + public.URL, true},
+	}
+
+	for _, tt := range tests {
+		c, err := NewClient(nil, tt.list)
+		if err != nil {
+			t.Fatalf("NewClient(%q): unexpected error: %v", tt.list, err)
+		}
+		_, err = c.Info(context.Background(), "golang.org/x/net", "v0.30.0")
+		if tt.wantErr {
+			if err == nil || errors.Is(err, ErrNotFound) {
+				t.Errorf("%s: expected proxy error, got %v", tt.list, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.list, err)
+		}
+	}
+
+	if privateRequests != 1 {
+		t.Errorf("expected private proxy to be asked once, got %d", privateRequests)
+	}
+}
+
+func TestClient_InfoFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "github.com", "!burnt!sushi", "toml", "@v")
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	info := `{"Version":"v1.4.0","Time":"2024-06-01T00:00:00Z"}`
+	if err := os.WriteFile(filepath.Join(path, "v1.4.0.info"), []byte(info), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewClient(nil, "file://"+filepath.ToSlash(dir))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := c.Info(context.Background(), "github.com/BurntSushi/toml", "v1.4.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Version != "v1.4.0" {
+		t.Errorf("expected version v1.4.0, got %s", got.Version)
+	}
+
+	if _, err := c.Info(context.Background(), "github.com/BurntSushi/toml", "v1.5.0"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for missing version, got %v", err)
+	}
+}
+
+func TestClient_SetReleaseTimes(t *testing.T) {
+	requests := 0
+	infos := make(map[string]string)
+	for i, mv := range [][2]string{{"golang.org/x/net", "v0.30.0"}, {"golang.org/x/sys", "v0.26.0"}} {
+		infos["/"+mv[0]+"/@v/"+mv[1]+".info"] = fmt.Sprintf(`{"Version":%q,"Time":"2024-10-0%dT00:00:00Z"}`, mv[1], i+1)
+	}
+	srv := newTestProxy(t, infos, &requests)
+
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pr := &model.PullRequest{
+		Dependency: model.Dependency{Name: "golang.org/x", Ecosystem: "go"},
+		Dependencies: []model.Dependency{
+			{Name: "golang.org/x/net", Ecosystem: "go", ToVersion: "v0.30.0"},
+			{Name: "golang.org/x/sys", Ecosystem: "go", ToVersion: "v0.26.0"},
+		},
+	}
+	if err := c.SetReleaseTimes(context.Background(), pr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.Dependency.ReleasedAt == nil || pr.Dependency.ReleasedAt.Day() != 2 {
+		t.Errorf("expected group to be released with its latest member, got %v", pr.Dependency.ReleasedAt)
+	}
+
+	pr.Dependencies = append(pr.Dependencies, model.Dependency{Name: "go", Ecosystem: "go", ToVersion: "1.23"})
+	pr.Dependency.ReleasedAt = nil
+	if err := c.SetReleaseTimes(context.Background(), pr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.Dependency.ReleasedAt != nil {
+		t.Errorf("expected group with an unknown release to stay unknown, got %v", pr.Dependency.ReleasedAt)
+	}
+
+	npm := &model.PullRequest{Dependency: model.Dependency{Name: "lodash", Ecosystem: "npm", ToVersion: "4.17.21"}}
+	before := requests
+	if err := c.SetReleaseTimes(context.Background(), npm); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if npm.Dependency.ReleasedAt != nil || requests != before {
+		t.Errorf("expected non-Go dependency to be skipped")
+	}
+}
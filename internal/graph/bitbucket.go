@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/plexusone/versionconductor/internal/bitbucket"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// BitbucketSource is a Source for Bitbucket Cloud or a Bitbucket Data
+// Center server.
+type BitbucketSource struct {
+	client bitbucket.API
+}
+
+// NewBitbucketSource creates a source reading repositories with client.
+func NewBitbucketSource(client bitbucket.API) *BitbucketSource {
+	return &BitbucketSource{client: client}
+}
+
+// ListRepos returns the repositories of a workspace or project, without
+// forks.
+func (s *BitbucketSource) ListRepos(ctx context.Context, owner string) ([]model.Repo, error) {
+	repos, err := s.client.ListRepos(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []model.Repo
+	for _, r := range repos {
+		if r.Fork {
+			continue
+		}
+		result = append(result, model.Repo{
+			Owner:         r.Owner,
+			Name:          r.Slug,
+			FullName:      r.Owner + "/" + r.Slug,
+			Description:   r.Description,
+			DefaultBranch: r.DefaultBranch,
+			Private:       r.Private,
+			Language:      r.Language,
+			HTMLURL:       r.URL,
+		})
+	}
+
+	return result, nil
+}
+
+// GetFile returns the content of a file at ref.
+func (s *BitbucketSource) GetFile(ctx context.Context, repo model.RepoRef, path, ref string) ([]byte, error) {
+	return s.client.GetRawFile(ctx, repo.Owner, repo.Name, path, ref)
+}
@@ -2,28 +2,53 @@ package graph
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/google/go-github/v84/github"
 	"github.com/grokify/mogo/net/http/retryhttp"
+
+	"github.com/plexusone/versionconductor/internal/auth"
 	"github.com/plexusone/versionconductor/pkg/model"
 )
 
-// Builder constructs a dependency graph from GitHub repositories.
+// Builder constructs a dependency graph from GitHub repositories, and
+// from repositories on other platforms through a Source.
 type Builder struct {
 	client    *github.Client
+	host      string            // e.g. "github.com" or "ghe.example.com"
+	sources   map[string]Source // by host
 	portfolio Portfolio
 	cache     *Cache
 }
 
+// Source lists repositories and reads their files on a code hosting
+// platform other than GitHub.
+type Source interface {
+	// ListRepos returns the repositories of an organization or user.
+	ListRepos(ctx context.Context, owner string) ([]model.Repo, error)
+
+	// GetFile returns the content of a file at ref, a branch, tag or
+	// commit SHA.
+	GetFile(ctx context.Context, repo model.RepoRef, path, ref string) ([]byte, error)
+}
+
 // BuilderConfig configures the graph builder.
 type BuilderConfig struct {
-	// Token is the GitHub personal access token.
+	// Token is the GitHub personal access token. Ignored if Auth is set.
 	Token string
 
+	// Auth authenticates API requests, e.g. as a GitHub App installation.
+	Auth auth.Provider
+
+	// BaseURL is the API URL of a GitHub Enterprise Server, such as
+	// https://ghe.example.com/api/v3/. Default is github.com.
+	BaseURL string
+
 	// MaxRetries is the maximum number of retry attempts for API calls.
 	// Default is 3.
 	MaxRetries int
@@ -34,15 +59,21 @@ type BuilderConfig struct {
 
 	// Cache is an optional cache for API responses.
 	Cache *Cache
+
+	// Sources serve the portfolio orgs on other platforms, by host, e.g.
+	// "git.example.com" for the org git.example.com/tools.
+	Sources map[string]Source
 }
 
 // NewBuilder creates a new graph builder with GitHub authentication.
 func NewBuilder(token string) *Builder {
-	return NewBuilderWithConfig(BuilderConfig{Token: token})
+	// Without a BaseURL the configuration is always valid.
+	b, _ := NewBuilderWithConfig(BuilderConfig{Token: token})
+	return b
 }
 
 // NewBuilderWithConfig creates a new graph builder with configuration.
-func NewBuilderWithConfig(cfg BuilderConfig) *Builder {
+func NewBuilderWithConfig(cfg BuilderConfig) (*Builder, error) {
 	// Create HTTP client with retry transport
 	retryOpts := []retryhttp.Option{}
 
@@ -54,19 +85,33 @@ func NewBuilderWithConfig(cfg BuilderConfig) *Builder {
 	}
 
 	// Create retry transport - handles 429 rate limits automatically
-	rt := retryhttp.NewWithOptions(retryOpts...)
-	httpClient := &http.Client{Transport: rt}
+	var rt http.RoundTripper = retryhttp.NewWithOptions(retryOpts...)
+
+	// Authenticate requests before they reach the retry transport
+	provider := cfg.Auth
+	if provider == nil && cfg.Token != "" {
+		provider = auth.NewTokenProvider(cfg.Token)
+	}
+	if provider != nil {
+		rt = provider.Transport(rt)
+	}
 
 	// Create GitHub client with retry-enabled HTTP client
-	client := github.NewClient(httpClient)
-	if cfg.Token != "" {
-		client = client.WithAuthToken(cfg.Token)
+	client := github.NewClient(&http.Client{Transport: rt})
+	if cfg.BaseURL != "" {
+		var err error
+		client, err = client.WithEnterpriseURLs(cfg.BaseURL, cfg.BaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub URL %q: %w", cfg.BaseURL, err)
+		}
 	}
 
 	return &Builder{
-		client: client,
-		cache:  cfg.Cache,
-	}
+		client:  client,
+		host:    auth.Host(cfg.BaseURL),
+		sources: cfg.Sources,
+		cache:   cfg.Cache,
+	}, nil
 }
 
 // Build constructs a dependency graph from the portfolio configuration.
@@ -75,21 +120,34 @@ func (b *Builder) Build(ctx context.Context, portfolio Portfolio) (*DependencyGr
 	graph := NewGraph()
 	graph.portfolio = portfolio
 
-	// Build set of managed orgs
+	// Build set of managed orgs, qualifying bare names with the host
+	host := b.host
+	if host == "" {
+		host = auth.Host("")
+	}
+	orgs := make([]string, len(portfolio.Orgs))
 	managedOrgs := make(map[string]bool)
-	for _, org := range portfolio.Orgs {
+	for i, org := range portfolio.Orgs {
+		if !strings.Contains(org, "/") {
+			org = host + "/" + org
+		}
+		orgs[i] = org
 		managedOrgs[org] = true
 	}
 
 	// Collect repos from all orgs
-	for _, org := range portfolio.Orgs {
+	for _, org := range orgs {
 		// Extract owner from org (e.g., "github.com/grokify" -> "grokify")
 		owner := extractOwner(org)
 		if owner == "" {
 			continue
 		}
+		source := b.sources[extractHost(org)]
+		if source == nil && extractHost(org) != host {
+			return nil, fmt.Errorf("no repository source for %s", org)
+		}
 
-		repos, err := b.listRepos(ctx, owner)
+		repos, err := b.listRepos(ctx, source, owner)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list repos for %s: %w", org, err)
 		}
@@ -97,7 +155,7 @@ func (b *Builder) Build(ctx context.Context, portfolio Portfolio) (*DependencyGr
 		for _, repo := range repos {
 			// Check for Go modules
 			if containsLanguage(portfolio.Languages, string(LanguageGo)) || len(portfolio.Languages) == 0 {
-				gomod, err := b.fetchGoMod(ctx, owner, repo.GetName(), repo.GetDefaultBranch())
+				gomod, err := b.fetchGoMod(ctx, source, org, repo)
 				if err != nil {
 					// No go.mod, skip
 					continue
@@ -119,9 +177,24 @@ func (b *Builder) Build(ctx context.Context, portfolio Portfolio) (*DependencyGr
 	return graph, nil
 }
 
-// listRepos lists all repositories for an owner.
-func (b *Builder) listRepos(ctx context.Context, owner string) ([]*github.Repository, error) {
-	var allRepos []*github.Repository
+// listRepos lists all repositories for an owner, from source if not nil
+// or else from GitHub. Archived and forked repos are left out.
+func (b *Builder) listRepos(ctx context.Context, source Source, owner string) ([]model.Repo, error) {
+	if source != nil {
+		repos, err := source.ListRepos(ctx, owner)
+		if err != nil {
+			return nil, err
+		}
+		var active []model.Repo
+		for _, repo := range repos {
+			if !repo.Archived {
+				active = append(active, repo)
+			}
+		}
+		return active, nil
+	}
+
+	var allRepos []model.Repo
 
 	opts := &github.RepositoryListByUserOptions{
 		ListOptions: github.ListOptions{PerPage: 100},
@@ -145,7 +218,7 @@ func (b *Builder) listRepos(ctx context.Context, owner string) ([]*github.Reposi
 		// Filter out archived and forked repos
 		for _, repo := range repos {
 			if !repo.GetArchived() && !repo.GetFork() {
-				allRepos = append(allRepos, repo)
+				allRepos = append(allRepos, convertRepo(repo))
 			}
 		}
 
@@ -158,38 +231,52 @@ func (b *Builder) listRepos(ctx context.Context, owner string) ([]*github.Reposi
 	return allRepos, nil
 }
 
-// fetchGoMod fetches the go.mod file from a repository.
-func (b *Builder) fetchGoMod(ctx context.Context, owner, repo, branch string) ([]byte, error) {
+// fetchGoMod fetches the go.mod file from a repository's default branch,
+// through source if not nil or else from GitHub.
+func (b *Builder) fetchGoMod(ctx context.Context, source Source, org string, repo model.Repo) ([]byte, error) {
+	owner, branch := repo.Owner, repo.DefaultBranch
+	if source != nil {
+		// Keep repos on other hosts apart in the cache
+		owner = extractHost(org) + "/" + owner
+	}
+	cacheKey := fmt.Sprintf("gomod:%s/%s:%s", owner, repo.Name, branch)
+
 	// Check cache first
 	if b.cache != nil {
-		cacheKey := fmt.Sprintf("gomod:%s/%s:%s", owner, repo, branch)
 		if data, ok := b.cache.Get(ctx, cacheKey); ok {
 			return data, nil
 		}
 	}
 
-	content, _, resp, err := b.client.Repositories.GetContents(
-		ctx, owner, repo, "go.mod",
-		&github.RepositoryContentGetOptions{Ref: branch},
-	)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("go.mod not found")
-	}
+	var data []byte
+	if source != nil {
+		var err error
+		data, err = source.GetFile(ctx, model.RepoRef{Owner: repo.Owner, Name: repo.Name}, "go.mod", branch)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		content, _, resp, err := b.client.Repositories.GetContents(
+			ctx, repo.Owner, repo.Name, "go.mod",
+			&github.RepositoryContentGetOptions{Ref: branch},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("go.mod not found")
+		}
 
-	// Decode content using the built-in method
-	decodedContent, err := content.GetContent()
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode content: %w", err)
+		// Decode content using the built-in method
+		decodedContent, err := content.GetContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode content: %w", err)
+		}
+		data = []byte(decodedContent)
 	}
 
-	data := []byte(decodedContent)
-
 	// Store in cache
 	if b.cache != nil {
-		cacheKey := fmt.Sprintf("gomod:%s/%s:%s", owner, repo, branch)
 		_ = b.cache.Set(ctx, cacheKey, data)
 	}
 
@@ -197,19 +284,17 @@ func (b *Builder) fetchGoMod(ctx context.Context, owner, repo, branch string) ([
 }
 
 // createModule creates a Module from repo and go.mod info.
-func (b *Builder) createModule(org string, repo *github.Repository, modInfo *GoModInfo, managedOrgs map[string]bool) Module {
+func (b *Builder) createModule(org string, repo model.Repo, modInfo *GoModInfo, managedOrgs map[string]bool) Module {
 	moduleName := modInfo.Module
 	moduleID := NewModuleID(LanguageGo, moduleName)
 
 	// Determine if this module is managed
-	moduleOrg := ExtractOrg(LanguageGo, moduleName)
-	isManaged := managedOrgs["github.com/"+extractOwner(moduleOrg)]
+	isManaged := managedOrgs[ExtractOrg(LanguageGo, moduleName)]
 
 	// Build dependencies
 	var deps []ModuleRef
 	for _, req := range modInfo.DirectDependencies() {
-		depOrg := ExtractOrg(LanguageGo, req.Path)
-		depManaged := managedOrgs["github.com/"+extractOwner(depOrg)]
+		depManaged := managedOrgs[ExtractOrg(LanguageGo, req.Path)]
 
 		deps = append(deps, ModuleRef{
 			ID:        NewModuleID(LanguageGo, req.Path),
@@ -219,34 +304,48 @@ func (b *Builder) createModule(org string, repo *github.Repository, modInfo *GoM
 	}
 
 	return Module{
-		ID:       moduleID,
-		Language: LanguageGo,
-		Name:     moduleName,
-		Org:      org,
-		Version:  getLatestVersion(repo),
-		Repo: &model.Repo{
-			Owner:         repo.GetOwner().GetLogin(),
-			Name:          repo.GetName(),
-			FullName:      repo.GetFullName(),
-			Description:   repo.GetDescription(),
-			DefaultBranch: repo.GetDefaultBranch(),
-			Private:       repo.GetPrivate(),
-			Archived:      repo.GetArchived(),
-			Language:      repo.GetLanguage(),
-			HTMLURL:       repo.GetHTMLURL(),
-		},
+		ID:           moduleID,
+		Language:     LanguageGo,
+		Name:         moduleName,
+		Org:          org,
+		Version:      getLatestVersion(repo),
+		Repo:         &repo,
 		IsManaged:    isManaged,
 		Dependencies: deps,
 	}
 }
 
+// convertRepo converts a GitHub repository to our model.
+func convertRepo(repo *github.Repository) model.Repo {
+	return model.Repo{
+		Owner:         repo.GetOwner().GetLogin(),
+		Name:          repo.GetName(),
+		FullName:      repo.GetFullName(),
+		Description:   repo.GetDescription(),
+		DefaultBranch: repo.GetDefaultBranch(),
+		Private:       repo.GetPrivate(),
+		Archived:      repo.GetArchived(),
+		Language:      repo.GetLanguage(),
+		HTMLURL:       repo.GetHTMLURL(),
+	}
+}
+
+// extractHost extracts the host from an org string.
+// "github.com/grokify" -> "github.com"
+// "grokify" -> ""
+func extractHost(org string) string {
+	host, _, ok := strings.Cut(org, "/")
+	if !ok {
+		return ""
+	}
+	return host
+}
+
 // extractOwner extracts the owner from an org string.
 // "github.com/grokify" -> "grokify"
+// "ghe.example.com/team" -> "team"
 // "grokify" -> "grokify"
 func extractOwner(org string) string {
-	if strings.HasPrefix(org, "github.com/") {
-		return strings.TrimPrefix(org, "github.com/")
-	}
 	parts := strings.Split(org, "/")
 	if len(parts) >= 2 {
 		return parts[1]
@@ -256,9 +355,9 @@ func extractOwner(org string) string {
 
 // getLatestVersion gets the latest version tag from a repo.
 // For now, just returns the default branch name. TODO: fetch actual tags.
-func getLatestVersion(repo *github.Repository) string {
+func getLatestVersion(repo model.Repo) string {
 	// TODO: Fetch actual tags and find latest semver
-	return repo.GetDefaultBranch()
+	return repo.DefaultBranch
 }
 
 // containsLanguage checks if a language is in the list.
@@ -282,3 +381,18 @@ func BuildFromSnapshot(snapshot *GraphSnapshot) *DependencyGraph {
 
 	return graph
 }
+
+// LoadSnapshot reconstructs a graph from a snapshot file, as written by
+// "graph build --format json".
+func LoadSnapshot(path string) (*DependencyGraph, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read graph snapshot: %w", err)
+	}
+
+	var snapshot GraphSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse graph snapshot: %w", err)
+	}
+	return BuildFromSnapshot(&snapshot), nil
+}
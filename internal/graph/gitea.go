@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/plexusone/versionconductor/internal/gitea"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// GiteaSource is a Source for a Gitea or Forgejo instance.
+type GiteaSource struct {
+	client *gitea.Client
+}
+
+// NewGiteaSource creates a source reading repositories with client.
+func NewGiteaSource(client *gitea.Client) *GiteaSource {
+	return &GiteaSource{client: client}
+}
+
+// ListRepos returns the repositories of an organization or user, without
+// forks and mirrors.
+func (s *GiteaSource) ListRepos(ctx context.Context, owner string) ([]model.Repo, error) {
+	repos, err := s.client.ListOrgRepos(ctx, owner)
+	if gitea.IsNotFound(err) {
+		repos, err = s.client.ListUserRepos(ctx, owner)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result []model.Repo
+	for _, r := range repos {
+		if r.Fork || r.Mirror {
+			continue
+		}
+		result = append(result, model.Repo{
+			Owner:         r.Owner.Login,
+			Name:          r.Name,
+			FullName:      r.FullName,
+			Description:   r.Description,
+			DefaultBranch: r.DefaultBranch,
+			Private:       r.Private || r.Internal,
+			Archived:      r.Archived,
+			Language:      r.Language,
+			HTMLURL:       r.HTMLURL,
+		})
+	}
+
+	return result, nil
+}
+
+// GetFile returns the content of a file at ref.
+func (s *GiteaSource) GetFile(ctx context.Context, repo model.RepoRef, path, ref string) ([]byte, error) {
+	return s.client.GetRawFile(ctx, repo.Owner, repo.Name, path, ref)
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v84/github"
+
+	"github.com/plexusone/versionconductor/internal/apitest"
+)
+
+func TestBuilder_Build_Gitea(t *testing.T) {
+	ghServer := mockGitHubServer(t, map[string]http.HandlerFunc{
+		"/users/testorg/repos": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			mustEncode(w, []*github.Repository{makeRepoResponse("testorg", "mogo")})
+		},
+		"/repos/testorg/mogo/contents/go.mod": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			mustEncode(w, &github.RepositoryContent{
+				Content:  github.Ptr(makeGoModContent("github.com/testorg/mogo", nil)),
+				Encoding: github.Ptr("base64"),
+			})
+		},
+	})
+	defer ghServer.Close()
+
+	giteaServer := apitest.NewGiteaServer(t, map[string]any{
+		"GET /orgs/tools/repos": []map[string]any{
+			{"name": "deploy", "full_name": "tools/deploy", "owner": map[string]any{"login": "tools"}, "default_branch": "main"},
+			{"name": "docs", "full_name": "tools/docs", "owner": map[string]any{"login": "tools"}, "default_branch": "main"},
+			{"name": "old", "full_name": "tools/old", "owner": map[string]any{"login": "tools"}, "archived": true},
+		},
+		"GET /repos/tools/deploy/raw/go.mod": []byte("module git.example.com/tools/deploy\n\ngo 1.23\n\n" +
+			"require (\n\tgithub.com/testorg/mogo v0.70.0\n\tgolang.org/x/net v0.30.0\n)\n"),
+	})
+
+	builder := newBuilderWithMockServer(ghServer)
+	builder.sources = map[string]Source{"git.example.com": NewGiteaSource(giteaServer.GiteaClient(t))}
+
+	graph, err := builder.Build(context.Background(), Portfolio{
+		Name:      "test",
+		Orgs:      []string{"github.com/testorg", "git.example.com/tools"},
+		Languages: []string{"go"},
+	})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if len(graph.modules) != 2 {
+		t.Fatalf("expected 2 modules, got %d", len(graph.modules))
+	}
+	deploy, ok := graph.modules["go:git.example.com/tools/deploy"]
+	if !ok {
+		t.Fatal("deploy module not found")
+	}
+	if !deploy.IsManaged || deploy.Org != "git.example.com/tools" || deploy.Repo.FullName != "tools/deploy" {
+		t.Errorf("unexpected module: %+v", deploy)
+	}
+
+	managed := make(map[string]bool)
+	for _, dep := range deploy.Dependencies {
+		managed[dep.ID] = dep.IsManaged
+	}
+	if !managed["go:github.com/testorg/mogo"] || managed["go:golang.org/x/net"] {
+		t.Errorf("expected only the GitHub portfolio dependency to be managed, got %v", managed)
+	}
+
+	// The go.mod of the docs repo was requested and not found.
+	if _, ok := giteaServer.Find(http.MethodGet, "/repos/tools/docs/raw/go.mod"); !ok {
+		t.Error("expected go.mod of docs repo to be requested")
+	}
+	if _, ok := giteaServer.Find(http.MethodGet, "/repos/tools/old/raw/go.mod"); ok {
+		t.Error("expected archived repo to be skipped")
+	}
+}
+
+func TestBuilder_Build_UnknownHost(t *testing.T) {
+	builder := &Builder{client: github.NewClient(nil)}
+
+	_, err := builder.Build(context.Background(), Portfolio{
+		Name: "test",
+		Orgs: []string{"gitlab.com/platform"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "no repository source for gitlab.com/platform") {
+		t.Errorf("expected missing source error, got %v", err)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
+
+	"github.com/plexusone/versionconductor/pkg/model"
 )
 
 // Graph is the interface for dependency graph operations.
@@ -101,6 +104,23 @@ func (g *DependencyGraph) Dependents(moduleID string) []Module {
 	return result
 }
 
+// ManagedDependents returns the number of managed modules that depend on
+// a module of the given repository. Each dependent is counted once.
+func (g *DependencyGraph) ManagedDependents(repo model.RepoRef) int {
+	seen := make(map[string]bool)
+	for id, m := range g.modules {
+		if m.Repo == nil || !strings.EqualFold(m.Repo.Owner, repo.Owner) || !strings.EqualFold(m.Repo.Name, repo.Name) {
+			continue
+		}
+		for _, dependent := range g.Dependents(id) {
+			if dependent.IsManaged && dependent.ID != id {
+				seen[dependent.ID] = true
+			}
+		}
+	}
+	return len(seen)
+}
+
 // Dependencies returns all modules that the given module depends on.
 func (g *DependencyGraph) Dependencies(moduleID string) []Module {
 	depIDs := g.edges[moduleID]
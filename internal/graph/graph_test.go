@@ -1,7 +1,12 @@
 package graph
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/plexusone/versionconductor/pkg/model"
 )
 
 func TestDependencyGraph_AddModule(t *testing.T) {
@@ -437,6 +442,98 @@ func TestDependencyGraph_Snapshot(t *testing.T) {
 	}
 }
 
+func TestDependencyGraph_ManagedDependents(t *testing.T) {
+	g := NewGraph()
+
+	g.AddModule(Module{
+		ID:        "go:github.com/grokify/mogo",
+		Name:      "github.com/grokify/mogo",
+		Repo:      &model.Repo{Owner: "grokify", Name: "mogo"},
+		IsManaged: true,
+	})
+	g.AddModule(Module{
+		ID:        "go:github.com/grokify/gogithub",
+		Name:      "github.com/grokify/gogithub",
+		Repo:      &model.Repo{Owner: "grokify", Name: "gogithub"},
+		IsManaged: true,
+		Dependencies: []ModuleRef{
+			{ID: "go:github.com/grokify/mogo", IsManaged: true},
+		},
+	})
+	g.AddModule(Module{
+		ID:        "go:github.com/grokify/goauth",
+		Name:      "github.com/grokify/goauth",
+		Repo:      &model.Repo{Owner: "grokify", Name: "goauth"},
+		IsManaged: true,
+		Dependencies: []ModuleRef{
+			{ID: "go:github.com/grokify/mogo", IsManaged: true},
+			{ID: "go:github.com/grokify/gogithub", IsManaged: true},
+		},
+	})
+	g.AddModule(Module{
+		ID:   "go:github.com/other/tool",
+		Name: "github.com/other/tool",
+		Dependencies: []ModuleRef{
+			{ID: "go:github.com/grokify/mogo", IsManaged: true},
+		},
+	})
+
+	tests := []struct {
+		repo model.RepoRef
+		want int
+	}{
+		{model.RepoRef{Owner: "grokify", Name: "mogo"}, 2},
+		{model.RepoRef{Owner: "Grokify", Name: "GoGitHub"}, 1},
+		{model.RepoRef{Owner: "grokify", Name: "goauth"}, 0},
+		{model.RepoRef{Owner: "grokify", Name: "unknown"}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := g.ManagedDependents(tt.repo); got != tt.want {
+			t.Errorf("ManagedDependents(%s) = %d, want %d", tt.repo.FullName(), got, tt.want)
+		}
+	}
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	g := NewGraph()
+	g.AddModule(Module{
+		ID:        "go:github.com/grokify/mogo",
+		Name:      "github.com/grokify/mogo",
+		Repo:      &model.Repo{Owner: "grokify", Name: "mogo"},
+		IsManaged: true,
+	})
+	g.AddModule(Module{
+		ID:        "go:github.com/grokify/gogithub",
+		Name:      "github.com/grokify/gogithub",
+		IsManaged: true,
+		Dependencies: []ModuleRef{
+			{ID: "go:github.com/grokify/mogo", IsManaged: true},
+		},
+	})
+
+	data, err := json.Marshal(g.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "graph.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := loaded.ManagedDependents(model.RepoRef{Owner: "grokify", Name: "mogo"}); got != 1 {
+		t.Errorf("expected 1 managed dependent after loading, got %d", got)
+	}
+
+	if _, err := LoadSnapshot(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing snapshot")
+	}
+}
+
 func TestDependencyGraph_StaleModules(t *testing.T) {
 	g := NewGraph()
 
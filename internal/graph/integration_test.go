@@ -496,3 +496,60 @@ func TestBuilder_Build_LanguageFilter(t *testing.T) {
 		t.Errorf("expected 1 module when filtering for Go, got %d", len(graph.modules))
 	}
 }
+
+func TestBuilder_Build_Enterprise(t *testing.T) {
+	handlers := map[string]http.HandlerFunc{
+		"/api/v3/users/team/repos": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			mustEncode(w, []*github.Repository{makeRepoResponse("team", "app")})
+		},
+		"/api/v3/repos/team/app/contents/go.mod": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			mustEncode(w, &github.RepositoryContent{
+				Content: github.Ptr(makeGoModContent("ghe.corp.example/team/app", []string{
+					"ghe.corp.example/team/lib v1.2.0",
+					"github.com/team/lib v1.0.0",
+				})),
+				Encoding: github.Ptr("base64"),
+			})
+		},
+	}
+
+	server := mockGitHubServer(t, handlers)
+	defer server.Close()
+
+	builder, err := NewBuilderWithConfig(BuilderConfig{BaseURL: server.URL + "/api/v3/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The module host differs from the test server's host.
+	builder.host = "ghe.corp.example"
+
+	graph, err := builder.Build(context.Background(), Portfolio{
+		Name:      "test",
+		Orgs:      []string{"team"},
+		Languages: []string{"go"},
+	})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	app, ok := graph.modules["go:ghe.corp.example/team/app"]
+	if !ok {
+		t.Fatal("app module not found")
+	}
+	if !app.IsManaged || app.Org != "ghe.corp.example/team" {
+		t.Errorf("expected managed module in ghe.corp.example/team, got managed=%v org=%q", app.IsManaged, app.Org)
+	}
+
+	managed := make(map[string]bool)
+	for _, dep := range app.Dependencies {
+		managed[dep.ID] = dep.IsManaged
+	}
+	if !managed["go:ghe.corp.example/team/lib"] {
+		t.Error("expected enterprise dependency to be managed")
+	}
+	if managed["go:github.com/team/lib"] {
+		t.Error("expected github.com dependency with the same owner to be external")
+	}
+}
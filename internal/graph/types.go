@@ -54,7 +54,8 @@ type Module struct {
 	// Name is the module name (e.g., "github.com/grokify/mogo")
 	Name string `json:"name"`
 
-	// Org is the GitHub org (e.g., "github.com/grokify")
+	// Org is the GitHub org (e.g., "github.com/grokify" or
+	// "ghe.example.com/team" on GitHub Enterprise Server)
 	Org string `json:"org"`
 
 	// Version is the current version tag
@@ -95,7 +96,8 @@ func ParseModuleID(id string) (Language, string) {
 }
 
 // ExtractOrg extracts the org from a module name.
-// For Go modules: "github.com/grokify/mogo" -> "github.com/grokify"
+// For Go modules: "github.com/grokify/mogo" -> "github.com/grokify",
+// "ghe.example.com/team/lib" -> "ghe.example.com/team"
 // For npm: "@agentplexus/core" -> "@agentplexus"
 func ExtractOrg(lang Language, name string) string {
 	switch lang {
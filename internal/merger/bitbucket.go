@@ -0,0 +1,82 @@
+package merger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/plexusone/versionconductor/internal/bitbucket"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// BitbucketMerger implements Merger for Bitbucket Cloud and Data Center
+// pull requests.
+type BitbucketMerger struct {
+	client bitbucket.API
+}
+
+// NewBitbucketMerger creates a new Bitbucket merger using the given client.
+func NewBitbucketMerger(client bitbucket.API) *BitbucketMerger {
+	return &BitbucketMerger{
+		client: client,
+	}
+}
+
+// MergePR merges a pull request using the specified strategy, mapped to
+// the Bitbucket merge strategy (merge_commit, squash or
+// rebase_fast_forward on Cloud; no-ff, squash or rebase-ff-only on Data
+// Center). An empty strategy uses the repository's default.
+func (m *BitbucketMerger) MergePR(ctx context.Context, repoRef model.RepoRef, prNumber int, strategy MergeStrategy, commitMessage string) (*MergeInfo, error) {
+	pr, err := m.client.MergePullRequest(ctx, repoRef.Owner, repoRef.Name, prNumber, bitbucket.MergeOptions{
+		Strategy: string(strategy),
+		Message:  commitMessage,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge PR: %w", err)
+	}
+
+	return &MergeInfo{
+		SHA:     pr.MergeCommit,
+		Message: "Pull request merged",
+		Merged:  pr.State == "MERGED",
+	}, nil
+}
+
+// ApprovePR approves a pull request, adding body as a comment since
+// Bitbucket approvals have no message.
+func (m *BitbucketMerger) ApprovePR(ctx context.Context, repoRef model.RepoRef, prNumber int, body string) error {
+	if err := m.client.ApprovePullRequest(ctx, repoRef.Owner, repoRef.Name, prNumber); err != nil {
+		return fmt.Errorf("failed to approve PR: %w", err)
+	}
+	if body != "" {
+		if err := m.client.CommentPullRequest(ctx, repoRef.Owner, repoRef.Name, prNumber, body); err != nil {
+			return fmt.Errorf("failed to comment on PR: %w", err)
+		}
+	}
+	return nil
+}
+
+// IsMergeable checks if a PR can be merged, giving the merge check vetoes
+// as the reason if not.
+func (m *BitbucketMerger) IsMergeable(ctx context.Context, repoRef model.RepoRef, prNumber int) (bool, string, error) {
+	status, err := m.client.GetMergeStatus(ctx, repoRef.Owner, repoRef.Name, prNumber)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to check mergeable: %w", err)
+	}
+
+	switch {
+	case status.Conflicted:
+		return false, "has conflicts", nil
+	case !status.CanMerge && len(status.Vetoes) > 0:
+		return false, strings.Join(status.Vetoes, "; "), nil
+	case !status.CanMerge:
+		return false, "blocked", nil
+	default:
+		return true, "clean", nil
+	}
+}
+
+// DeleteBranch deletes the PR's source branch after merge.
+func (m *BitbucketMerger) DeleteBranch(ctx context.Context, repoRef model.RepoRef, branch string) error {
+	return m.client.DeleteBranch(ctx, repoRef.Owner, repoRef.Name, branch)
+}
@@ -0,0 +1,67 @@
+package merger
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/plexusone/versionconductor/internal/apitest"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestBitbucketMerger_MergePR(t *testing.T) {
+	server := apitest.NewBitbucketCloudServer(t, map[string]any{
+		"POST /repositories/team/tools/pullrequests/12/merge": map[string]any{
+			"id": 12, "state": "MERGED", "merge_commit": map[string]any{"hash": "def456"},
+		},
+	})
+	m := NewBitbucketMerger(server.BitbucketClient(t))
+
+	info, err := m.MergePR(context.Background(), model.RepoRef{Owner: "team", Name: "tools"}, 12, MergeStrategyRebase, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.Merged || info.SHA != "def456" {
+		t.Errorf("expected merged PR with merge commit, got %+v", info)
+	}
+
+	req, _ := server.Find(http.MethodPost, "/repositories/team/tools/pullrequests/12/merge")
+	if req.Body["merge_strategy"] != "rebase_fast_forward" || req.Body["close_source_branch"] != false {
+		t.Errorf("unexpected merge request: %v", req.Body)
+	}
+}
+
+func TestBitbucketMerger_ApprovePR(t *testing.T) {
+	server := apitest.NewBitbucketCloudServer(t, map[string]any{
+		"POST /repositories/team/tools/pullrequests/12/approve":  map[string]any{"approved": true},
+		"POST /repositories/team/tools/pullrequests/12/comments": map[string]any{"id": 1},
+	})
+	m := NewBitbucketMerger(server.BitbucketClient(t))
+
+	if err := m.ApprovePR(context.Background(), model.RepoRef{Owner: "team", Name: "tools"}, 12, "LGTM"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req, ok := server.Find(http.MethodPost, "/repositories/team/tools/pullrequests/12/comments")
+	if content, _ := req.Body["content"].(map[string]any); !ok || content["raw"] != "LGTM" {
+		t.Errorf("expected approval comment, got %v", req.Body)
+	}
+}
+
+func TestBitbucketMerger_IsMergeable(t *testing.T) {
+	server := apitest.NewBitbucketDataCenterServer(t, map[string]any{
+		"GET /rest/api/1.0/projects/PLAT/repos/tools/pull-requests/7/merge": map[string]any{
+			"canMerge": false, "conflicted": false,
+			"vetoes": []map[string]any{{"summaryMessage": "Requires 2 approvals"}, {"summaryMessage": "Not all required builds are successful yet"}},
+		},
+	})
+	m := NewBitbucketMerger(server.BitbucketClient(t))
+
+	ok, reason, err := m.IsMergeable(context.Background(), model.RepoRef{Owner: "PLAT", Name: "tools"}, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok || reason != "Requires 2 approvals; Not all required builds are successful yet" {
+		t.Errorf("expected vetoes as reason, got %v, %q", ok, reason)
+	}
+}
@@ -0,0 +1,85 @@
+package merger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/plexusone/versionconductor/internal/gitea"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// GiteaMerger implements Merger for Gitea and Forgejo pull requests.
+type GiteaMerger struct {
+	client *gitea.Client
+}
+
+// NewGiteaMerger creates a new Gitea merger using the given client.
+func NewGiteaMerger(client *gitea.Client) *GiteaMerger {
+	return &GiteaMerger{
+		client: client,
+	}
+}
+
+// MergePR merges a pull request using the specified strategy, which is
+// also the name of the Gitea merge style.
+func (m *GiteaMerger) MergePR(ctx context.Context, repoRef model.RepoRef, prNumber int, strategy MergeStrategy, commitMessage string) (*MergeInfo, error) {
+	if strategy == "" {
+		strategy = MergeStrategyMerge
+	}
+
+	err := m.client.MergePullRequest(ctx, repoRef.Owner, repoRef.Name, prNumber, gitea.MergePullRequestOptions{
+		Do:              string(strategy),
+		MergeTitleField: commitMessage,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge PR: %w", err)
+	}
+
+	// The merge response is empty; the merge commit is on the PR.
+	pr, err := m.client.GetPullRequest(ctx, repoRef.Owner, repoRef.Name, prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merged PR: %w", err)
+	}
+
+	return &MergeInfo{
+		SHA:     pr.MergeCommitSHA,
+		Message: "Pull request merged",
+		Merged:  pr.Merged,
+	}, nil
+}
+
+// ApprovePR adds an approval review to a pull request.
+func (m *GiteaMerger) ApprovePR(ctx context.Context, repoRef model.RepoRef, prNumber int, body string) error {
+	err := m.client.CreateReview(ctx, repoRef.Owner, repoRef.Name, prNumber, gitea.CreateReviewOptions{
+		Event: "APPROVED",
+		Body:  body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to approve PR: %w", err)
+	}
+	return nil
+}
+
+// IsMergeable checks if a PR can be merged.
+func (m *GiteaMerger) IsMergeable(ctx context.Context, repoRef model.RepoRef, prNumber int) (bool, string, error) {
+	pr, err := m.client.GetPullRequest(ctx, repoRef.Owner, repoRef.Name, prNumber)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to check mergeable: %w", err)
+	}
+
+	switch {
+	case pr.Merged:
+		return false, "already merged", nil
+	case pr.State != "open":
+		return false, "closed", nil
+	case !pr.Mergeable:
+		return false, "has conflicts", nil
+	default:
+		return true, "clean", nil
+	}
+}
+
+// DeleteBranch deletes the PR's head branch after merge.
+func (m *GiteaMerger) DeleteBranch(ctx context.Context, repoRef model.RepoRef, branch string) error {
+	return m.client.DeleteBranch(ctx, repoRef.Owner, repoRef.Name, branch)
+}
@@ -0,0 +1,49 @@
+package merger
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/plexusone/versionconductor/internal/apitest"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestGiteaMerger_MergePR(t *testing.T) {
+	server := apitest.NewGiteaServer(t, map[string]any{
+		"POST /repos/team/tools/pulls/12/merge": nil,
+		"GET /repos/team/tools/pulls/12": map[string]any{
+			"number": 12, "state": "closed", "merged": true, "merge_commit_sha": "def456",
+		},
+	})
+	m := NewGiteaMerger(server.GiteaClient(t))
+
+	info, err := m.MergePR(context.Background(), model.RepoRef{Owner: "team", Name: "tools"}, 12, MergeStrategyRebase, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.Merged || info.SHA != "def456" {
+		t.Errorf("expected merged PR with merge commit, got %+v", info)
+	}
+
+	req, _ := server.Find(http.MethodPost, "/repos/team/tools/pulls/12/merge")
+	if req.Body["Do"] != "rebase" {
+		t.Errorf("expected rebase merge style, got %v", req.Body)
+	}
+}
+
+func TestGiteaMerger_ApprovePR(t *testing.T) {
+	server := apitest.NewGiteaServer(t, map[string]any{
+		"POST /repos/team/tools/pulls/12/reviews": map[string]any{"id": 1, "state": "APPROVED"},
+	})
+	m := NewGiteaMerger(server.GiteaClient(t))
+
+	if err := m.ApprovePR(context.Background(), model.RepoRef{Owner: "team", Name: "tools"}, 12, "LGTM"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req, _ := server.Find(http.MethodPost, "/repos/team/tools/pulls/12/reviews")
+	if req.Body["event"] != "APPROVED" || req.Body["body"] != "LGTM" {
+		t.Errorf("unexpected review: %v", req.Body)
+	}
+}
@@ -5,10 +5,10 @@ import (
 	"fmt"
 
 	"github.com/google/go-github/v84/github"
-	"github.com/grokify/gogithub/auth"
 	"github.com/grokify/gogithub/pr"
 	"github.com/grokify/gogithub/repo"
 
+	"github.com/plexusone/versionconductor/internal/auth"
 	"github.com/plexusone/versionconductor/pkg/model"
 )
 
@@ -19,8 +19,13 @@ type GitHubMerger struct {
 
 // NewGitHubMerger creates a new GitHub merger.
 func NewGitHubMerger(token string) *GitHubMerger {
-	ctx := context.Background()
-	client := auth.NewGitHubClient(ctx, token)
+	return NewGitHubMergerWithClient(auth.NewClient(auth.NewTokenProvider(token)))
+}
+
+// NewGitHubMergerWithClient creates a new GitHub merger using the given
+// client. With a client authenticated as a GitHub App, approvals and
+// merges are made by the app's bot account.
+func NewGitHubMergerWithClient(client *github.Client) *GitHubMerger {
 	return &GitHubMerger{
 		client: client,
 	}
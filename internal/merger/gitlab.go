@@ -0,0 +1,88 @@
+package merger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/plexusone/versionconductor/internal/gitlab"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// GitLabMerger implements Merger for GitLab merge requests.
+type GitLabMerger struct {
+	client *gitlab.Client
+}
+
+// NewGitLabMerger creates a new GitLab merger using the given client.
+func NewGitLabMerger(client *gitlab.Client) *GitLabMerger {
+	return &GitLabMerger{
+		client: client,
+	}
+}
+
+// MergePR merges a merge request. GitLab rebases according to the
+// project's merge method, so only the merge and squash strategies are
+// supported.
+func (m *GitLabMerger) MergePR(ctx context.Context, repoRef model.RepoRef, prNumber int, strategy MergeStrategy, commitMessage string) (*MergeInfo, error) {
+	opts := gitlab.AcceptMergeRequestOptions{}
+
+	switch strategy {
+	case MergeStrategyMerge, "":
+		opts.MergeCommitMessage = commitMessage
+	case MergeStrategySquash:
+		opts.Squash = true
+		opts.SquashCommitMessage = commitMessage
+	default:
+		return nil, fmt.Errorf("failed to merge PR: merge strategy %q is not supported by GitLab", strategy)
+	}
+
+	mr, err := m.client.AcceptMergeRequest(ctx, repoRef.FullName(), prNumber, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge PR: %w", err)
+	}
+
+	sha := mr.MergeCommitSHA
+	if sha == "" {
+		sha = mr.SquashCommitSHA
+	}
+	if sha == "" {
+		sha = mr.SHA
+	}
+
+	return &MergeInfo{
+		SHA:     sha,
+		Message: "Merge request merged",
+		Merged:  mr.State == "merged",
+	}, nil
+}
+
+// ApprovePR approves a merge request. GitLab approvals have no message,
+// so a non-empty body is added as a comment.
+func (m *GitLabMerger) ApprovePR(ctx context.Context, repoRef model.RepoRef, prNumber int, body string) error {
+	if err := m.client.ApproveMergeRequest(ctx, repoRef.FullName(), prNumber); err != nil {
+		return fmt.Errorf("failed to approve PR: %w", err)
+	}
+
+	if body != "" {
+		if err := m.client.CreateMergeRequestNote(ctx, repoRef.FullName(), prNumber, body); err != nil {
+			return fmt.Errorf("failed to comment on PR: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// IsMergeable checks if a merge request can be merged.
+func (m *GitLabMerger) IsMergeable(ctx context.Context, repoRef model.RepoRef, prNumber int) (bool, string, error) {
+	mr, err := m.client.GetMergeRequest(ctx, repoRef.FullName(), prNumber)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to check mergeable: %w", err)
+	}
+
+	return mr.Mergeable(), mr.MergeStatusDetail(), nil
+}
+
+// DeleteBranch deletes the merge request's source branch after merge.
+func (m *GitLabMerger) DeleteBranch(ctx context.Context, repoRef model.RepoRef, branch string) error {
+	return m.client.DeleteBranch(ctx, repoRef.FullName(), branch)
+}
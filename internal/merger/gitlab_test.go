@@ -0,0 +1,69 @@
+package merger
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/plexusone/versionconductor/internal/apitest"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestGitLabMerger_MergePR(t *testing.T) {
+	server := apitest.NewGitLabServer(t, map[string]any{
+		"PUT /projects/group%2Fsub%2Fapp/merge_requests/4/merge": map[string]any{
+			"iid": 4, "state": "merged", "sha": "head", "squash_commit_sha": "squashed",
+		},
+	})
+	m := NewGitLabMerger(server.GitLabClient(t))
+	repo := model.RepoRef{Owner: "group/sub", Name: "app"}
+
+	info, err := m.MergePR(context.Background(), repo, 4, MergeStrategySquash, "chore(deps): update lodash")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.Merged || info.SHA != "squashed" {
+		t.Errorf("expected merged squash commit, got %+v", info)
+	}
+
+	req, _ := server.Find(http.MethodPut, "/projects/group%2Fsub%2Fapp/merge_requests/4/merge")
+	if req.Body["squash"] != true || req.Body["squash_commit_message"] != "chore(deps): update lodash" {
+		t.Errorf("unexpected merge request body: %v", req.Body)
+	}
+
+	_, err = m.MergePR(context.Background(), repo, 4, MergeStrategyRebase, "")
+	if err == nil || !strings.Contains(err.Error(), "not supported by GitLab") {
+		t.Errorf("expected unsupported strategy error, got %v", err)
+	}
+}
+
+func TestGitLabMerger_ApprovePR(t *testing.T) {
+	server := apitest.NewGitLabServer(t, map[string]any{
+		"POST /projects/group%2Fapp/merge_requests/4/approve": map[string]any{},
+		"POST /projects/group%2Fapp/merge_requests/4/notes":   map[string]any{},
+	})
+	m := NewGitLabMerger(server.GitLabClient(t))
+	repo := model.RepoRef{Owner: "group", Name: "app"}
+
+	if err := m.ApprovePR(context.Background(), repo, 4, "Approved by VersionConductor"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req, ok := server.Find(http.MethodPost, "/projects/group%2Fapp/merge_requests/4/notes")
+	if !ok || req.Body["body"] != "Approved by VersionConductor" {
+		t.Errorf("expected approval comment, got %+v", req)
+	}
+
+	// An approval without a message adds no comment.
+	server = apitest.NewGitLabServer(t, map[string]any{
+		"POST /projects/group%2Fapp/merge_requests/4/approve": map[string]any{},
+	})
+	m = NewGitLabMerger(server.GitLabClient(t))
+	if err := m.ApprovePR(context.Background(), repo, 4, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(server.Requests()); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+}
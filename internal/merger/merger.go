@@ -3,6 +3,8 @@ package merger
 import (
 	"context"
 
+	"github.com/google/go-github/v84/github"
+
 	"github.com/plexusone/versionconductor/pkg/model"
 )
 
@@ -60,3 +62,8 @@ func DefaultOptions() Options {
 func NewGitHub(token string) Merger {
 	return NewGitHubMerger(token)
 }
+
+// NewGitHubWithClient creates a new GitHub merger using the given client.
+func NewGitHubWithClient(client *github.Client) Merger {
+	return NewGitHubMergerWithClient(client)
+}
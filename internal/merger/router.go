@@ -0,0 +1,39 @@
+package merger
+
+import (
+	"context"
+
+	"github.com/plexusone/versionconductor/internal/provider"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// Router implements Merger by sending each request to the merger serving
+// the repository's owner.
+type Router struct {
+	routes *provider.Routes[Merger]
+}
+
+// NewRouter creates a merger routing requests by owner.
+func NewRouter(routes *provider.Routes[Merger]) *Router {
+	return &Router{routes: routes}
+}
+
+// MergePR merges a pull request using the specified strategy.
+func (r *Router) MergePR(ctx context.Context, repo model.RepoRef, prNumber int, strategy MergeStrategy, commitMessage string) (*MergeInfo, error) {
+	return r.routes.For(repo.Owner).MergePR(ctx, repo, prNumber, strategy, commitMessage)
+}
+
+// ApprovePR adds an approval review to a pull request.
+func (r *Router) ApprovePR(ctx context.Context, repo model.RepoRef, prNumber int, body string) error {
+	return r.routes.For(repo.Owner).ApprovePR(ctx, repo, prNumber, body)
+}
+
+// IsMergeable checks if a PR can be merged.
+func (r *Router) IsMergeable(ctx context.Context, repo model.RepoRef, prNumber int) (bool, string, error) {
+	return r.routes.For(repo.Owner).IsMergeable(ctx, repo, prNumber)
+}
+
+// DeleteBranch deletes the PR's head branch after merge.
+func (r *Router) DeleteBranch(ctx context.Context, repo model.RepoRef, branch string) error {
+	return r.routes.For(repo.Owner).DeleteBranch(ctx, repo, branch)
+}
@@ -0,0 +1,361 @@
+// Package osv matches dependency updates against an offline copy of the
+// OSV vulnerability database, such as the all.zip archives osv.dev
+// publishes for each ecosystem.
+package osv
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// maxRecordSize limits the size of a single OSV record read from a zip
+// archive.
+const maxRecordSize = 16 << 20
+
+// ecosystems maps our ecosystems to OSV ecosystem names. Ecosystems
+// without an entry, such as docker, have no advisories.
+var ecosystems = map[string]string{
+	"go":             "Go",
+	"npm":            "npm",
+	"pip":            "PyPI",
+	"cargo":          "crates.io",
+	"maven":          "Maven",
+	"rubygems":       "RubyGems",
+	"composer":       "Packagist",
+	"nuget":          "NuGet",
+	"github-actions": "GitHub Actions",
+}
+
+// Advisory is a vulnerability advisory.
+type Advisory struct {
+	ID       string         `json:"id"`
+	Aliases  []string       `json:"aliases,omitempty"`
+	Summary  string         `json:"summary,omitempty"`
+	Severity model.Severity `json:"severity"`
+}
+
+// record is an OSV record, as far as it is used.
+type record struct {
+	ID               string         `json:"id"`
+	Aliases          []string       `json:"aliases"`
+	Summary          string         `json:"summary"`
+	Withdrawn        string         `json:"withdrawn"`
+	Affected         []affected     `json:"affected"`
+	DatabaseSpecific map[string]any `json:"database_specific"`
+}
+
+// affected lists the affected versions of one package.
+type affected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges            []versionRange `json:"ranges"`
+	Versions          []string       `json:"versions"`
+	DatabaseSpecific  map[string]any `json:"database_specific"`
+	EcosystemSpecific map[string]any `json:"ecosystem_specific"`
+}
+
+// versionRange is a range of affected versions, given as events.
+type versionRange struct {
+	Type   string  `json:"type"`
+	Events []event `json:"events"`
+}
+
+// event starts or ends a range of affected versions. Only one field is
+// set.
+type event struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+	Limit        string `json:"limit"`
+}
+
+// entry is the affected versions of a package in an advisory.
+type entry struct {
+	advisory *Advisory
+	affected affected
+}
+
+// Database is an OSV database held in memory, indexed by package.
+type Database struct {
+	packages map[string][]entry
+	count    int
+}
+
+// Load reads the OSV database at path: a zip archive of OSV JSON records,
+// or a directory of records and such archives, searched recursively.
+// Withdrawn advisories are skipped.
+func Load(path string) (*Database, error) {
+	db := &Database{packages: make(map[string][]entry)}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OSV database: %w", err)
+	}
+	if !info.IsDir() {
+		if err := db.loadZip(path); err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case d.IsDir():
+			return nil
+		case strings.HasSuffix(p, ".zip"):
+			return db.loadZip(p)
+		case strings.HasSuffix(p, ".json"):
+			data, err := os.ReadFile(p) // #nosec G304
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", p, err)
+			}
+			return db.add(p, data)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OSV database: %w", err)
+	}
+	return db, nil
+}
+
+// loadZip adds the records in a zip archive.
+func (db *Database) loadZip(path string) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if !strings.HasSuffix(f.Name, ".json") {
+			continue
+		}
+		data, err := readZipFile(f)
+		if err != nil {
+			return fmt.Errorf("failed to read %s in %s: %w", f.Name, path, err)
+		}
+		if err := db.add(f.Name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readZipFile returns the contents of a file in a zip archive.
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxRecordSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxRecordSize {
+		return nil, fmt.Errorf("record larger than %d bytes", maxRecordSize)
+	}
+	return data, nil
+}
+
+// add indexes an OSV record.
+func (db *Database) add(name string, data []byte) error {
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	if rec.ID == "" || rec.Withdrawn != "" {
+		return nil
+	}
+
+	adv := &Advisory{
+		ID:       rec.ID,
+		Aliases:  rec.Aliases,
+		Summary:  rec.Summary,
+		Severity: severityOf(rec.DatabaseSpecific),
+	}
+	for _, a := range rec.Affected {
+		adv.Severity = model.HighestSeverity(adv.Severity, severityOf(a.DatabaseSpecific), severityOf(a.EcosystemSpecific))
+	}
+	if adv.Severity == model.SeverityNone {
+		adv.Severity = model.SeverityUnknown
+	}
+
+	for _, a := range rec.Affected {
+		ecosystem, _, _ := strings.Cut(a.Package.Ecosystem, ":")
+		k := key(ecosystem, a.Package.Name)
+		db.packages[k] = append(db.packages[k], entry{advisory: adv, affected: a})
+	}
+	db.count++
+	return nil
+}
+
+// Len returns the number of advisories in the database.
+func (db *Database) Len() int {
+	if db == nil {
+		return 0
+	}
+	return db.count
+}
+
+// severityOf reads the severity label GitHub advisories and some other
+// databases put in their database_specific or ecosystem_specific field.
+func severityOf(fields map[string]any) model.Severity {
+	label, _ := fields["severity"].(string)
+	switch strings.ToLower(label) {
+	case "low":
+		return model.SeverityLow
+	case "moderate", "medium":
+		return model.SeverityMedium
+	case "high", "important":
+		return model.SeverityHigh
+	case "critical":
+		return model.SeverityCritical
+	default:
+		return model.SeverityNone
+	}
+}
+
+var pypiSeparators = regexp.MustCompile(`[-_.]+`)
+
+// key returns the index key of a package. Names are normalized where the
+// ecosystem treats them case-insensitively.
+func key(ecosystem, name string) string {
+	switch ecosystem {
+	case "PyPI":
+		name = pypiSeparators.ReplaceAllString(strings.ToLower(name), "-")
+	case "NuGet", "Packagist":
+		name = strings.ToLower(name)
+	}
+	return ecosystem + "/" + name
+}
+
+// Fixed returns the advisories affecting fromVersion of a package but not
+// toVersion. The ecosystem is one of ours, such as "go" or "npm".
+func (db *Database) Fixed(ecosystem, name, fromVersion, toVersion string) []*Advisory {
+	osvEcosystem, ok := ecosystems[ecosystem]
+	if !ok || name == "" || fromVersion == "" || toVersion == "" {
+		return nil
+	}
+
+	var fixed []*Advisory
+	for _, e := range db.packages[key(osvEcosystem, name)] {
+		if e.affected.affects(fromVersion) && !e.affected.affects(toVersion) && !slices.Contains(fixed, e.advisory) {
+			fixed = append(fixed, e.advisory)
+		}
+	}
+	return fixed
+}
+
+// affects reports whether version is affected.
+func (a *affected) affects(version string) bool {
+	for _, v := range a.Versions {
+		if compareVersions(v, version) == 0 {
+			return true
+		}
+	}
+	for _, r := range a.Ranges {
+		if (r.Type == "SEMVER" || r.Type == "ECOSYSTEM") && r.affects(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// affects reports whether version is in the range. As in the OSV spec,
+// the events are applied in version order up to the version: introduced
+// starts an affected range, the others end it.
+func (r versionRange) affects(version string) bool {
+	events := slices.Clone(r.Events)
+	slices.SortStableFunc(events, func(a, b event) int {
+		return compareVersions(a.version(), b.version())
+	})
+
+	affected := false
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			if e.Introduced == "0" || compareVersions(version, e.Introduced) >= 0 {
+				affected = true
+			}
+		case e.Fixed != "":
+			if compareVersions(version, e.Fixed) >= 0 {
+				affected = false
+			}
+		case e.LastAffected != "":
+			if compareVersions(version, e.LastAffected) > 0 {
+				affected = false
+			}
+		case e.Limit != "":
+			if compareVersions(version, e.Limit) >= 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// version returns the version of the event.
+func (e event) version() string {
+	switch {
+	case e.Introduced != "":
+		return e.Introduced
+	case e.Fixed != "":
+		return e.Fixed
+	case e.LastAffected != "":
+		return e.LastAffected
+	default:
+		return e.Limit
+	}
+}
+
+// SetVulnerabilities records the advisories fixed by a PR and, for each
+// dependency it updates, the highest severity among those the update
+// fixes. An advisory that is an alias of one already recorded is not
+// listed again. A grouped PR's own severity is the highest of the group.
+func (db *Database) SetVulnerabilities(pr *model.PullRequest) {
+	pr.Vulnerabilities = nil
+	seen := make(map[string]bool)
+	record := func(dep *model.Dependency) {
+		dep.Severity = model.SeverityNone
+		for _, adv := range db.Fixed(dep.Ecosystem, dep.Name, dep.FromVersion, dep.ToVersion) {
+			dep.Severity = model.HighestSeverity(dep.Severity, adv.Severity)
+			if seen[adv.ID] || slices.ContainsFunc(adv.Aliases, func(id string) bool { return seen[id] }) {
+				continue
+			}
+			pr.Vulnerabilities = append(pr.Vulnerabilities, adv.ID)
+			seen[adv.ID] = true
+			for _, id := range adv.Aliases {
+				seen[id] = true
+			}
+		}
+	}
+
+	if !pr.IsGroup() {
+		record(&pr.Dependency)
+		return
+	}
+
+	pr.Dependency.Severity = model.SeverityNone
+	for i := range pr.Dependencies {
+		dep := &pr.Dependencies[i]
+		record(dep)
+		pr.Dependency.Severity = model.HighestSeverity(pr.Dependency.Severity, dep.Severity)
+	}
+}
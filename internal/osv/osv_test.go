@@ -0,0 +1,191 @@
+package osv
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// testRecords are OSV records in the formats used by the Go vulnerability
+// database and GitHub advisories.
+var testRecords = map[string]string{
+	"GO-2024-2687.json": `{
+		"id": "GO-2024-2687",
+		"aliases": ["CVE-2023-45288", "GHSA-4v7x-pqxf-cx7m"],
+		"summary": "HTTP/2 CONTINUATION flood in net/http",
+		"affected": [{
+			"package": {"ecosystem": "Go", "name": "golang.org/x/net"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.23.0"}]}]
+		}]
+	}`,
+	"GHSA-4v7x-pqxf-cx7m.json": `{
+		"id": "GHSA-4v7x-pqxf-cx7m",
+		"aliases": ["CVE-2023-45288"],
+		"affected": [{
+			"package": {"ecosystem": "Go", "name": "golang.org/x/net"},
+			"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "0.23.0"}]}]
+		}],
+		"database_specific": {"severity": "MODERATE"}
+	}`,
+	"GHSA-jfh8-c2jp-5v3q.json": `{
+		"id": "GHSA-jfh8-c2jp-5v3q",
+		"affected": [{
+			"package": {"ecosystem": "npm", "name": "lodash"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "4.0.0"}, {"last_affected": "4.17.20"}]}]
+		}],
+		"database_specific": {"severity": "HIGH"}
+	}`,
+	"PYSEC-2023-1.json": `{
+		"id": "PYSEC-2023-1",
+		"affected": [{
+			"package": {"ecosystem": "PyPI", "name": "Django_Utils"},
+			"versions": ["1.0", "1.1"]
+		}],
+		"database_specific": {"severity": "critical"}
+	}`,
+	"GHSA-withdrawn.json": `{
+		"id": "GHSA-withdrawn",
+		"withdrawn": "2024-01-01T00:00:00Z",
+		"affected": [{
+			"package": {"ecosystem": "npm", "name": "lodash"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+		}]
+	}`,
+}
+
+// writeZip writes the records to a zip archive at path.
+func writeZip(t *testing.T, path string, records map[string]string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, data := range records {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, "all.zip")
+	writeZip(t, zipPath, testRecords)
+
+	db, err := Load(zipPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db.Len() != 4 {
+		t.Errorf("expected 4 advisories from zip, got %d", db.Len())
+	}
+
+	// A directory of records and archives is searched recursively.
+	tree := filepath.Join(dir, "tree")
+	if err := os.MkdirAll(filepath.Join(tree, "Go"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeZip(t, filepath.Join(tree, "Go", "all.zip"), map[string]string{"GO-2024-2687.json": testRecords["GO-2024-2687.json"]})
+	if err := os.WriteFile(filepath.Join(tree, "PYSEC-2023-1.json"), []byte(testRecords["PYSEC-2023-1.json"]), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	db, err = Load(tree)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db.Len() != 2 {
+		t.Errorf("expected 2 advisories from directory, got %d", db.Len())
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for missing database")
+	}
+	if err := os.WriteFile(filepath.Join(tree, "broken.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(tree); err == nil {
+		t.Error("expected error for invalid record")
+	}
+}
+
+func TestDatabase_Fixed(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "all.zip")
+	writeZip(t, zipPath, testRecords)
+	db, err := Load(zipPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		ecosystem, name, from, to string
+		want                      []string
+	}{
+		{"go", "golang.org/x/net", "v0.22.0", "v0.23.0", []string{"GHSA-4v7x-pqxf-cx7m", "GO-2024-2687"}},
+		{"go", "golang.org/x/net", "v0.23.0", "v0.24.0", nil},
+		{"go", "golang.org/x/net", "v0.21.0", "v0.22.0", nil},
+		{"npm", "lodash", "4.17.20", "4.17.21", []string{"GHSA-jfh8-c2jp-5v3q"}},
+		{"npm", "lodash", "3.10.1", "4.17.21", nil},
+		{"pip", "django-utils", "1.1", "1.2", []string{"PYSEC-2023-1"}},
+		{"docker", "lodash", "4.17.20", "4.17.21", nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, adv := range db.Fixed(tt.ecosystem, tt.name, tt.from, tt.to) {
+			got = append(got, adv.ID)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Fixed(%s, %s, %s, %s) = %v, want %v", tt.ecosystem, tt.name, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestDatabase_SetVulnerabilities(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "all.zip")
+	writeZip(t, zipPath, testRecords)
+	db, err := Load(zipPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pr := &model.PullRequest{
+		Dependency: model.Dependency{Name: "golang.org/x/net", Ecosystem: "go", FromVersion: "v0.22.0", ToVersion: "v0.23.0"},
+	}
+	db.SetVulnerabilities(pr)
+	if len(pr.Vulnerabilities) != 1 {
+		t.Errorf("expected aliased advisories to be listed once, got %v", pr.Vulnerabilities)
+	}
+	if pr.Dependency.Severity != model.SeverityMedium {
+		t.Errorf("expected medium severity, got %q", pr.Dependency.Severity)
+	}
+
+	group := &model.PullRequest{
+		Dependencies: []model.Dependency{
+			{Name: "lodash", Ecosystem: "npm", FromVersion: "4.17.20", ToVersion: "4.17.21"},
+			{Name: "express", Ecosystem: "npm", FromVersion: "4.18.0", ToVersion: "4.19.0"},
+		},
+	}
+	db.SetVulnerabilities(group)
+	if !slices.Equal(group.Vulnerabilities, []string{"GHSA-jfh8-c2jp-5v3q"}) {
+		t.Errorf("expected lodash advisory, got %v", group.Vulnerabilities)
+	}
+	if group.Dependency.Severity != model.SeverityHigh || group.Dependencies[1].FixesVulnerabilities() {
+		t.Errorf("expected group to fix a high severity vulnerability in lodash only, got %+v", group.Dependencies)
+	}
+}
@@ -0,0 +1,101 @@
+package osv
+
+import "strings"
+
+// compareVersions compares two versions of a package, returning -1, 0 or
+// +1. Versions are split into runs of digits, compared as numbers, and
+// runs of letters, compared alphabetically, so semantic versions and most
+// other schemes (1.2.3-rc.1, 2.0.post1, 1.0a2) order as expected. Numbers
+// sort after letters, and a version ending where the other goes on with
+// letters is the later one (1.0 > 1.0-rc1), unless they mark a post
+// release. A "v" prefix and build metadata after "+" are ignored.
+func compareVersions(a, b string) int {
+	ta, tb := versionTokens(a), versionTokens(b)
+	for i := 0; i < len(ta) || i < len(tb); i++ {
+		var c int
+		switch {
+		case i >= len(ta):
+			c = -compareMissing(tb[i])
+		case i >= len(tb):
+			c = compareMissing(ta[i])
+		default:
+			c = compareTokens(ta[i], tb[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareMissing compares a token to the end of a shorter version.
+// Missing numbers count as 0.
+func compareMissing(tok string) int {
+	switch {
+	case isNumber(tok):
+		return compareTokens(tok, "0")
+	case tok == "post" || tok == "p" || tok == "pl" || tok == "patch":
+		return 1
+	default:
+		return -1
+	}
+}
+
+// compareTokens compares two tokens of a version.
+func compareTokens(a, b string) int {
+	an, bn := isNumber(a), isNumber(b)
+	switch {
+	case an && bn:
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	case an:
+		return 1
+	case bn:
+		return -1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// versionTokens splits a version into lower case runs of digits and of
+// letters, dropping separators.
+func versionTokens(v string) []string {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v, _, _ = strings.Cut(v, "+")
+	v = strings.ToLower(v)
+
+	var tokens []string
+	start := -1
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if start >= 0 && (!isAlnum(c) || isDigit(c) != isDigit(v[start])) {
+			tokens = append(tokens, v[start:i])
+			start = -1
+		}
+		if start < 0 && isAlnum(c) {
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, v[start:])
+	}
+	return tokens
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isAlnum(c byte) bool {
+	return isDigit(c) || ('a' <= c && c <= 'z')
+}
+
+func isNumber(tok string) bool {
+	return tok != "" && isDigit(tok[0])
+}
@@ -0,0 +1,113 @@
+// Package pipeline runs per-repository work concurrently with a bounded
+// number of workers, returning results in a deterministic order.
+package pipeline
+
+import (
+	"context"
+	"sync"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// DefaultConcurrency is the number of repositories processed at once when
+// no concurrency is configured.
+const DefaultConcurrency = 4
+
+// DefaultMaxRetries is the number of times a repository is retried after
+// hitting a rate limit.
+const DefaultMaxRetries = 3
+
+// Result is the outcome of processing a single repository.
+type Result[T any] struct {
+	Repo  model.Repo
+	Value T
+	Err   error
+}
+
+// Options configures a pipeline run.
+type Options struct {
+	// Concurrency is the maximum number of repositories processed at
+	// once. Values below 1 use DefaultConcurrency.
+	Concurrency int
+
+	// MaxRetries is the number of retries after a rate limit error.
+	// Negative values disable retries; zero uses DefaultMaxRetries.
+	MaxRetries int
+
+	// Limiter pauses all workers when one hits a rate limit. If nil, a
+	// new limiter is used for the run.
+	Limiter *Limiter
+}
+
+// Run calls fn for each repository using a bounded pool of workers and
+// returns the results in the same order as repos, regardless of the order
+// in which they complete.
+//
+// When fn returns a rate limit error, all workers wait until the limit
+// resets and the repository is processed again, so fn must only perform
+// reads. Mutating steps should run afterwards over the ordered results.
+func Run[T any](ctx context.Context, repos []model.Repo, opts Options, fn func(ctx context.Context, repo model.Repo) (T, error)) []Result[T] {
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+	concurrency = min(concurrency, len(repos))
+
+	maxRetries := opts.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = DefaultMaxRetries
+	}
+
+	limiter := opts.Limiter
+	if limiter == nil {
+		limiter = NewLimiter()
+	}
+
+	results := make([]Result[T], len(repos))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = runOne(ctx, repos[i], maxRetries, limiter, fn)
+			}
+		}()
+	}
+
+	for i := range repos {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// runOne processes a single repository, retrying after rate limits.
+func runOne[T any](ctx context.Context, repo model.Repo, maxRetries int, limiter *Limiter, fn func(ctx context.Context, repo model.Repo) (T, error)) Result[T] {
+	result := Result[T]{Repo: repo}
+
+	for attempt := 0; ; attempt++ {
+		if err := limiter.Wait(ctx); err != nil {
+			result.Err = err
+			return result
+		}
+
+		value, err := fn(ctx, repo)
+		if err == nil {
+			result.Value = value
+			return result
+		}
+
+		wait, limited := RetryAfter(err)
+		if !limited || attempt >= maxRetries {
+			result.Value = value
+			result.Err = err
+			return result
+		}
+		limiter.Pause(wait)
+	}
+}
@@ -0,0 +1,140 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v84/github"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func testRepos(n int) []model.Repo {
+	repos := make([]model.Repo, n)
+	for i := range repos {
+		name := fmt.Sprintf("repo-%02d", i)
+		repos[i] = model.Repo{Owner: "example", Name: name, FullName: "example/" + name}
+	}
+	return repos
+}
+
+func TestRun_PreservesOrder(t *testing.T) {
+	repos := testRepos(20)
+
+	results := Run(context.Background(), repos, Options{Concurrency: 5}, func(_ context.Context, repo model.Repo) (string, error) {
+		// Finish later repos first.
+		var n int
+		_, _ = fmt.Sscanf(repo.Name, "repo-%d", &n)
+		time.Sleep(time.Duration(20-n) * time.Millisecond)
+		return repo.FullName, nil
+	})
+
+	if len(results) != len(repos) {
+		t.Fatalf("expected %d results, got %d", len(repos), len(results))
+	}
+	for i, r := range results {
+		if r.Repo.FullName != repos[i].FullName || r.Value != repos[i].FullName {
+			t.Errorf("result %d: expected %s, got repo %s value %s", i, repos[i].FullName, r.Repo.FullName, r.Value)
+		}
+	}
+}
+
+func TestRun_BoundsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+
+	Run(context.Background(), testRepos(30), Options{Concurrency: 3}, func(_ context.Context, _ model.Repo) (struct{}, error) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		running.Add(-1)
+		return struct{}{}, nil
+	})
+
+	if got := peak.Load(); got > 3 {
+		t.Errorf("expected at most 3 concurrent calls, got %d", got)
+	}
+}
+
+func TestRun_RetriesRateLimit(t *testing.T) {
+	retryAfter := 5 * time.Millisecond
+	var calls atomic.Int32
+
+	results := Run(context.Background(), testRepos(1), Options{}, func(_ context.Context, _ model.Repo) (int, error) {
+		if calls.Add(1) == 1 {
+			return 0, fmt.Errorf("failed to list PRs: %w", &github.AbuseRateLimitError{RetryAfter: &retryAfter})
+		}
+		return 42, nil
+	})
+
+	if results[0].Err != nil || results[0].Value != 42 {
+		t.Errorf("expected retry to succeed, got %+v", results[0])
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected 2 calls, got %d", got)
+	}
+}
+
+func TestRun_DoesNotRetryOtherErrors(t *testing.T) {
+	var calls atomic.Int32
+	errBoom := errors.New("boom")
+
+	results := Run(context.Background(), testRepos(1), Options{}, func(_ context.Context, _ model.Repo) (int, error) {
+		calls.Add(1)
+		return 0, errBoom
+	})
+
+	if !errors.Is(results[0].Err, errBoom) {
+		t.Errorf("expected error to be returned, got %v", results[0].Err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 call, got %d", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	reset := time.Date(2026, 1, 1, 12, 0, 30, 0, time.UTC)
+	now = func() time.Time { return time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	tests := []struct {
+		name    string
+		err     error
+		want    time.Duration
+		limited bool
+	}{
+		{"primary", &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: reset}}}, 30 * time.Second, true},
+		{"secondary without retry-after", &github.AbuseRateLimitError{}, defaultSecondaryWait, true},
+		{"wrapped", fmt.Errorf("failed: %w", &github.AbuseRateLimitError{}), defaultSecondaryWait, true},
+		{"other", errors.New("not found"), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, limited := RetryAfter(tt.err)
+			if got != tt.want || limited != tt.limited {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tt.want, tt.limited, got, limited)
+			}
+		})
+	}
+}
+
+func TestLimiter_WaitCanceled(t *testing.T) {
+	l := NewLimiter()
+	l.Pause(time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v84/github"
+)
+
+// defaultSecondaryWait is how long to wait after a secondary rate limit
+// that doesn't say when to retry.
+const defaultSecondaryWait = time.Minute
+
+// maxWait caps how long a single rate limit pauses the pipeline.
+const maxWait = time.Hour
+
+// now is the current time, replaceable in tests.
+var now = time.Now
+
+// Limiter coordinates rate limit pauses across workers. When one worker
+// hits a rate limit, the others stop starting new work until it resets.
+type Limiter struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// NewLimiter creates a new limiter.
+func NewLimiter() *Limiter {
+	return &Limiter{}
+}
+
+// Pause stops new work for the given duration, or until a later pause
+// already in effect ends.
+func (l *Limiter) Pause(d time.Duration) {
+	d = min(d, maxWait)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := now().Add(d); until.After(l.until) {
+		l.until = until
+	}
+}
+
+// Wait blocks until any pause has ended or ctx is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		wait := l.until.Sub(now())
+		l.mu.Unlock()
+
+		if wait <= 0 {
+			return ctx.Err()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// RetryAfter reports whether err is a GitHub rate limit error and how long
+// to wait before retrying.
+func RetryAfter(err error) (time.Duration, bool) {
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		return max(rateErr.Rate.Reset.Sub(now()), time.Second), true
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		if abuseErr.RetryAfter != nil {
+			return *abuseErr.RetryAfter, true
+		}
+		return defaultSecondaryWait, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,39 @@
+package policy
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// approvalConditions checks that a PR has at least the profile's required
+// number of approving reviews. There is no condition if none are required.
+func approvalConditions(profile *model.MergeProfile, pr *model.PullRequest) []model.PolicyCondition {
+	if profile.RequiredApprovals <= 0 {
+		return nil
+	}
+
+	approvals := len(pr.Approvers)
+	return []model.PolicyCondition{{
+		Name:      ConditionApprovals,
+		Value:     strconv.Itoa(approvals),
+		Threshold: fmt.Sprintf(">= %d", profile.RequiredApprovals),
+		Passed:    approvals >= profile.RequiredApprovals,
+		Code:      model.ReasonApprovalsMissing,
+		Message:   fmt.Sprintf("has %d of %d required approvals", approvals, profile.RequiredApprovals),
+	}}
+}
+
+// checkApprovals returns a denied decision if the PR lacks required
+// approvals, or nil if it has them.
+func checkApprovals(action model.PolicyAction, profile *model.MergeProfile, pr *model.PullRequest) *model.PolicyDecision {
+	trace := &model.PolicyTrace{
+		Action:     string(action),
+		Conditions: approvalConditions(profile, pr),
+	}
+	if len(trace.FailedConditions()) == 0 {
+		return nil
+	}
+	return trace.Decision()
+}
@@ -0,0 +1,69 @@
+package policy
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestEngine_RequiredApprovals(t *testing.T) {
+	profile := ProfileBalanced
+	profile.RequiredApprovals = 1
+
+	pr := newTestPR(model.UpdateTypePatch, 48)
+
+	cedarEngine, err := NewEngineWithConfig(EngineConfig{Profile: &profile, PolicyPaths: []string{"../../policies/examples"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	engines := map[string]*Engine{
+		"profile": NewEngineWithProfile(&profile),
+		"cedar":   cedarEngine,
+	}
+
+	for name, engine := range engines {
+		pr.Approvers = nil
+		decision, err := engine.CanMerge(context.Background(), pr, passingChecks())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if decision.Allowed {
+			t.Errorf("%s: expected PR without approvals to be denied", name)
+		}
+		if want := []model.ReasonCode{model.ReasonApprovalsMissing}; !slices.Equal(decision.Codes(), want) {
+			t.Errorf("%s: expected codes %v, got %v (%v)", name, want, decision.Codes(), decision.Reasons)
+		}
+		if len(decision.Reasons) == 0 || decision.Reasons[0] != "has 0 of 1 required approvals" {
+			t.Errorf("%s: expected missing approvals reason, got %v", name, decision.Reasons)
+		}
+
+		trace, err := engine.Explain(context.Background(), model.PolicyActionMerge, pr, passingChecks())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		failed := trace.FailedConditions()
+		if trace.Allowed || len(failed) != 1 || failed[0].Name != ConditionApprovals || failed[0].Threshold != ">= 1" {
+			t.Errorf("%s: expected only the approvals condition to fail, got %v", name, failed)
+		}
+
+		// Approvals are only required to merge.
+		if decision, err := engine.CanReview(context.Background(), pr, passingChecks()); err != nil || !decision.Allowed {
+			t.Errorf("%s: expected review to be allowed without approvals, got %v, %v", name, decision, err)
+		}
+
+		pr.Approvers = []string{"alice"}
+		decision, err = engine.CanMerge(context.Background(), pr, passingChecks())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !decision.Allowed {
+			t.Errorf("%s: expected approved PR to be allowed, got %v", name, decision.Reasons)
+		}
+	}
+
+	if got := NewContextBuilder().Build(pr, nil, passingChecks()).PR.Approvals; got != 1 {
+		t.Errorf("expected 1 approval in the policy context, got %d", got)
+	}
+}
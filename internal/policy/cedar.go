@@ -0,0 +1,341 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cedar-policy/cedar-go"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// CedarFileExt is the file extension for Cedar policy files.
+const CedarFileExt = ".cedar"
+
+// CedarReasonAnnotation is the annotation giving the reason code a forbid
+// policy denies with, e.g. @reason("CI_FAILED"). Forbid policies without
+// it deny with POLICY_FORBIDDEN.
+const CedarReasonAnnotation = "reason"
+
+// Cedar entity types used in authorization requests.
+const (
+	cedarTypeAction     = "Action"
+	cedarTypeBot        = "Bot"
+	cedarTypePR         = "PullRequest"
+	cedarTypeRepository = "Repository"
+)
+
+// CedarPolicies holds a set of Cedar policies loaded from one or more files.
+type CedarPolicies struct {
+	set      *cedar.PolicySet
+	ids      []string
+	policies []*cedar.Policy
+	codes    map[string]model.ReasonCode
+}
+
+// LoadCedarPolicies loads Cedar policies from the given paths.
+// Each path may be a .cedar file or a directory containing .cedar files.
+// Policy IDs are derived from the file name and the policy's position in
+// the file, e.g. "auto-merge-patch.0".
+func LoadCedarPolicies(paths ...string) (*CedarPolicies, error) {
+	var files []string
+	for _, p := range paths {
+		found, err := findCedarFiles(p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, found...)
+	}
+
+	cp := &CedarPolicies{set: cedar.NewPolicySet(), codes: map[string]model.ReasonCode{}}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file) // #nosec G304
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy file: %w", err)
+		}
+
+		list, err := cedar.NewPolicyListFromBytes(file, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse policy file %s: %w", file, err)
+		}
+
+		base := strings.TrimSuffix(filepath.Base(file), CedarFileExt)
+		for i, p := range list {
+			id := fmt.Sprintf("%s.%d", base, i)
+			if !cp.set.Add(cedar.PolicyID(id), p) {
+				return nil, fmt.Errorf("duplicate policy id: %s", id)
+			}
+			cp.ids = append(cp.ids, id)
+			cp.policies = append(cp.policies, p)
+			if reason, ok := p.Annotations()[CedarReasonAnnotation]; ok {
+				cp.codes[id] = model.ReasonCode(reason)
+			}
+		}
+	}
+
+	return cp, nil
+}
+
+// findCedarFiles returns the .cedar files at path, sorted by name.
+func findCedarFiles(path string) ([]string, error) {
+	cleanPath := filepath.Clean(path)
+	info, err := os.Stat(cleanPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy path: %w", err)
+	}
+
+	if !info.IsDir() {
+		return []string{cleanPath}, nil
+	}
+
+	entries, err := os.ReadDir(cleanPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy directory: %w", err)
+	}
+
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != CedarFileExt {
+			continue
+		}
+		files = append(files, filepath.Join(cleanPath, e.Name()))
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// Len returns the number of loaded policies.
+func (cp *CedarPolicies) Len() int {
+	if cp == nil {
+		return 0
+	}
+	return len(cp.ids)
+}
+
+// IDs returns the IDs of all loaded policies.
+func (cp *CedarPolicies) IDs() []string {
+	if cp == nil {
+		return nil
+	}
+	return cp.ids
+}
+
+// Evaluate evaluates the policies for the given action and context.
+// The decision's Policies field lists the permit or forbid policies that
+// determined the outcome.
+func (cp *CedarPolicies) Evaluate(action model.PolicyAction, pctx *model.PolicyContext) (*model.PolicyDecision, error) {
+	req, err := newCedarRequest(action, pctx)
+	if err != nil {
+		return nil, err
+	}
+
+	decision, diag := cedar.Authorize(cp.set, cedar.EntityMap{}, req)
+
+	result := &model.PolicyDecision{
+		Allowed: decision == cedar.Allow,
+		Action:  string(action),
+	}
+
+	for _, r := range diag.Reasons {
+		result.Policies = append(result.Policies, string(r.PolicyID))
+	}
+	// Cedar reports policies in no particular order; list them in the
+	// order they were loaded so reasons are stable between runs.
+	slices.SortFunc(result.Policies, func(a, b string) int {
+		return slices.Index(cp.ids, a) - slices.Index(cp.ids, b)
+	})
+
+	if !result.Allowed {
+		if len(result.Policies) > 0 {
+			for _, id := range result.Policies {
+				addReason(result, model.Reason{
+					Code:      cp.forbidCode(id),
+					Message:   "forbidden by policy: " + id,
+					Condition: ConditionPolicyPrefix + id,
+				})
+			}
+		} else {
+			addReason(result, model.Reason{
+				Code:    model.ReasonPolicyNotPermitted,
+				Message: fmt.Sprintf("no policy permits %s", action),
+			})
+		}
+	}
+
+	for _, e := range diag.Errors {
+		addReason(result, model.Reason{
+			Code:      model.ReasonPolicyError,
+			Message:   fmt.Sprintf("policy %s error: %s", e.PolicyID, e.Message),
+			Condition: ConditionPolicyPrefix + string(e.PolicyID),
+		})
+	}
+
+	return result, nil
+}
+
+// Explain evaluates each policy on its own and returns a trace with one
+// condition per policy. A permit policy passes when it matches; a forbid
+// policy passes when it does not.
+func (cp *CedarPolicies) Explain(action model.PolicyAction, pctx *model.PolicyContext) (*model.PolicyTrace, error) {
+	decision, err := cp.Evaluate(action, pctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := newCedarRequest(action, pctx)
+	if err != nil {
+		return nil, err
+	}
+
+	trace := &model.PolicyTrace{
+		Action:   string(action),
+		Allowed:  decision.Allowed,
+		Policies: decision.Policies,
+	}
+
+	for i, p := range cp.policies {
+		single := cedar.NewPolicySet()
+		single.Add(cedar.PolicyID(cp.ids[i]), p)
+
+		result, diag := cedar.Authorize(single, cedar.EntityMap{}, req)
+
+		cond := model.PolicyCondition{
+			Name:  ConditionPolicyPrefix + cp.ids[i],
+			Value: "not matched",
+		}
+
+		switch {
+		case len(diag.Errors) > 0:
+			cond.Value = "error"
+			cond.Code = model.ReasonPolicyError
+			cond.Message = fmt.Sprintf("policy %s error: %s", cp.ids[i], diag.Errors[0].Message)
+		case len(diag.Reasons) > 0 && result == cedar.Allow:
+			cond.Value = "matched"
+			cond.Threshold = "permit"
+			cond.Passed = true
+		case len(diag.Reasons) > 0:
+			cond.Value = "matched"
+			cond.Threshold = "forbid"
+			cond.Code = cp.forbidCode(cp.ids[i])
+			cond.Message = "forbidden by policy: " + cp.ids[i]
+		default:
+			// An unmatched policy never blocks on its own; the overall
+			// decision reports when no permit policy matched.
+			cond.Passed = true
+		}
+
+		trace.Conditions = append(trace.Conditions, cond)
+	}
+
+	if !decision.Allowed && len(decision.Policies) == 0 {
+		trace.Conditions = append(trace.Conditions, model.PolicyCondition{
+			Name:      ConditionPolicyPrefix + "*",
+			Value:     "no permit matched",
+			Threshold: "permit",
+			Code:      model.ReasonPolicyNotPermitted,
+			Message:   fmt.Sprintf("no policy permits %s", action),
+		})
+	}
+
+	return trace, nil
+}
+
+// forbidCode returns the reason code of a forbid policy: that of its
+// @reason annotation, or POLICY_FORBIDDEN.
+func (cp *CedarPolicies) forbidCode(id string) model.ReasonCode {
+	if code, ok := cp.codes[id]; ok {
+		return code
+	}
+	return model.ReasonPolicyForbidden
+}
+
+// newCedarRequest creates a Cedar authorization request for a policy context.
+func newCedarRequest(action model.PolicyAction, pctx *model.PolicyContext) (cedar.Request, error) {
+	record, err := contextToRecord(pctx)
+	if err != nil {
+		return cedar.Request{}, err
+	}
+
+	return cedar.Request{
+		Principal: cedar.NewEntityUID(cedarTypeBot, cedar.String(principalID(pctx))),
+		Action:    cedar.NewEntityUID(cedarTypeAction, cedar.String(action)),
+		Resource:  resourceUID(action, pctx),
+		Context:   record,
+	}, nil
+}
+
+// principalID returns the Cedar principal ID for a policy context.
+func principalID(pctx *model.PolicyContext) string {
+	if pctx.PR.DependBot != "" {
+		return pctx.PR.DependBot
+	}
+	return "unknown"
+}
+
+// resourceUID returns the Cedar resource for an action.
+// Release decisions apply to a repository, everything else to a PR.
+func resourceUID(action model.PolicyAction, pctx *model.PolicyContext) cedar.EntityUID {
+	if action == model.PolicyActionRelease {
+		return cedar.NewEntityUID(cedarTypeRepository, cedar.String(pctx.Repo.FullName))
+	}
+	return cedar.NewEntityUID(cedarTypePR, cedar.String(fmt.Sprintf("%s#%d", pctx.Repo.FullName, pctx.PR.Number)))
+}
+
+// contextToRecord converts a PolicyContext to a Cedar record using its
+// JSON representation, so policy attribute names match the JSON tags.
+func contextToRecord(pctx *model.PolicyContext) (cedar.Record, error) {
+	data, err := json.Marshal(pctx)
+	if err != nil {
+		return cedar.Record{}, fmt.Errorf("failed to marshal policy context: %w", err)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return cedar.Record{}, fmt.Errorf("failed to unmarshal policy context: %w", err)
+	}
+
+	return toCedarRecord(m), nil
+}
+
+// toCedarRecord converts a JSON object to a Cedar record.
+// Null values are omitted since Cedar has no null type.
+func toCedarRecord(m map[string]any) cedar.Record {
+	rm := cedar.RecordMap{}
+	for k, v := range m {
+		if cv, ok := toCedarValue(v); ok {
+			rm[cedar.String(k)] = cv
+		}
+	}
+	return cedar.NewRecord(rm)
+}
+
+// toCedarValue converts a decoded JSON value to a Cedar value.
+func toCedarValue(v any) (cedar.Value, bool) {
+	switch val := v.(type) {
+	case string:
+		return cedar.String(val), true
+	case bool:
+		return cedar.Boolean(val), true
+	case float64:
+		return cedar.Long(int64(val)), true
+	case []any:
+		var items []cedar.Value
+		for _, item := range val {
+			if cv, ok := toCedarValue(item); ok {
+				items = append(items, cv)
+			}
+		}
+		return cedar.NewSet(items...), true
+	case map[string]any:
+		return toCedarRecord(val), true
+	default:
+		return nil, false
+	}
+}
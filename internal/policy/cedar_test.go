@@ -0,0 +1,60 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestCedarPolicies_ReasonAnnotation(t *testing.T) {
+	dir := t.TempDir()
+	policies := `permit(principal, action, resource);
+
+@reason("CI_FAILED")
+forbid(principal, action == Action::"merge", resource)
+when { context.ci.allPassed == false };
+
+forbid(principal, action == Action::"merge", resource)
+when { context.pr.draft == true };
+`
+	if err := os.WriteFile(filepath.Join(dir, "merge.cedar"), []byte(policies), 0o600); err != nil {
+		t.Fatalf("failed to write policies: %v", err)
+	}
+
+	cp, err := LoadCedarPolicies(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pr := newTestPR(model.UpdateTypePatch, 48)
+	pr.Draft = true
+	failing := []model.CheckRun{{Name: "build", Status: "completed", Conclusion: "failure"}}
+	pctx := NewContextBuilder().Build(pr, nil, failing)
+
+	decision, err := cp.Evaluate(model.PolicyActionMerge, pctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.Allowed {
+		t.Fatal("expected merge to be denied")
+	}
+	want := []model.ReasonCode{model.ReasonCIFailed, model.ReasonPolicyForbidden}
+	if !slices.Equal(decision.Codes(), want) {
+		t.Errorf("expected codes %v, got %v (%v)", want, decision.Codes(), decision.Reasons)
+	}
+
+	trace, err := cp.Explain(model.PolicyActionMerge, pctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var codes []model.ReasonCode
+	for _, c := range trace.FailedConditions() {
+		codes = append(codes, c.Code)
+	}
+	if !slices.Equal(codes, want) {
+		t.Errorf("expected failed condition codes %v, got %v", want, codes)
+	}
+}
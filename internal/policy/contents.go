@@ -0,0 +1,167 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/plexusone/versionconductor/internal/glob"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// packageFiles are the manifests and lockfiles of the supported package
+// managers. Patterns without "/" match the file name in any directory;
+// patterns with "/" match the full path.
+var packageFiles = []string{
+	// Go
+	"go.mod", "go.sum", "go.work", "go.work.sum",
+	// JavaScript
+	"package.json", "package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml", "bun.lockb",
+	// Python
+	"pyproject.toml", "poetry.lock", "uv.lock", "Pipfile", "Pipfile.lock", "requirements*.txt",
+	// Rust, Ruby, PHP, Java, .NET
+	"Cargo.toml", "Cargo.lock", "Gemfile", "Gemfile.lock", "composer.json", "composer.lock",
+	"pom.xml", "gradle.lockfile", "libs.versions.toml", "packages.lock.json", "Directory.Packages.props",
+	// Terraform
+	".terraform.lock.hcl",
+}
+
+// DefaultAllowedFiles are the files a dependency PR may change when the
+// profile sets no AllowedFiles: the manifests and lockfiles, and the
+// container, CI and tooling files dependency bots also update.
+var DefaultAllowedFiles = append(slices.Clone(packageFiles),
+	"Dockerfile*", "*.dockerfile", "docker-compose*.yml", "docker-compose*.yaml", "compose.yml", "compose.yaml",
+	".github/workflows/*.yml", ".github/workflows/*.yaml",
+	".pre-commit-config.yaml",
+)
+
+// DisallowedFiles returns the files matching none of the allowed patterns.
+// Empty patterns mean DefaultAllowedFiles.
+func DisallowedFiles(files, patterns []string) []string {
+	if len(patterns) == 0 {
+		patterns = DefaultAllowedFiles
+	}
+
+	var disallowed []string
+	for _, f := range files {
+		if !allowedFile(f, patterns) {
+			disallowed = append(disallowed, f)
+		}
+	}
+	return disallowed
+}
+
+// allowedFile reports whether a file matches one of the patterns.
+func allowedFile(file string, patterns []string) bool {
+	for _, pattern := range patterns {
+		name := path.Base(file)
+		if strings.Contains(pattern, "/") {
+			name = file
+		}
+		if glob.Match(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// OtherCommitAuthors returns the commit authors of a PR that match
+// neither the author patterns of its bot nor one of the allowed patterns.
+// Patterns are case-insensitive globs. Commits that aren't linked to an
+// account are returned unless an allowed pattern names them explicitly,
+// e.g. "unlinked:Renovate Bot", since their author name can be set by
+// anyone.
+func OtherCommitAuthors(pr *model.PullRequest, allowed []string) []string {
+	patterns := lowerAll(append(slices.Clone(pr.BotAuthors), allowed...))
+
+	var unlinked []string
+	for _, p := range lowerAll(allowed) {
+		if strings.HasPrefix(p, model.UnlinkedAuthorPrefix) {
+			unlinked = append(unlinked, p)
+		}
+	}
+
+	var others []string
+	for _, a := range pr.CommitAuthors {
+		candidates := patterns
+		if strings.HasPrefix(a, model.UnlinkedAuthorPrefix) {
+			candidates = unlinked
+		}
+		if _, ok := MatchDependency(strings.ToLower(a), candidates); ok {
+			continue
+		}
+		others = append(others, a)
+	}
+	return others
+}
+
+// lowerAll returns the strings in lower case.
+func lowerAll(ss []string) []string {
+	lower := make([]string, len(ss))
+	for i, s := range ss {
+		lower[i] = strings.ToLower(s)
+	}
+	return lower
+}
+
+// contentConditions checks that a PR changes only allowed files and that
+// all its commits are by its bot or an allowed author. Each check is
+// skipped if the files or commit authors weren't listed.
+func contentConditions(profile *model.MergeProfile, pr *model.PullRequest) []model.PolicyCondition {
+	var conds []model.PolicyCondition
+
+	if pr.Files != nil {
+		cond := model.PolicyCondition{
+			Name:      ConditionFiles,
+			Value:     fmt.Sprintf("%d files", len(pr.Files)),
+			Threshold: "manifests and lockfiles",
+			Passed:    true,
+		}
+		if len(profile.AllowedFiles) > 0 {
+			cond.Threshold = strings.Join(profile.AllowedFiles, ", ")
+		}
+		if disallowed := DisallowedFiles(pr.Files, profile.AllowedFiles); len(disallowed) > 0 {
+			cond.Value = strings.Join(disallowed, ", ")
+			cond.Passed = false
+			cond.Code = model.ReasonFilesNotAllowed
+			cond.Message = "changes files other than manifests and lockfiles: " + cond.Value
+		}
+		conds = append(conds, cond)
+	}
+
+	if pr.CommitAuthors != nil {
+		authors := append(slices.Clone(pr.BotAuthors), profile.AllowedCommitAuthors...)
+		cond := model.PolicyCondition{
+			Name:      ConditionCommitAuthor,
+			Value:     strings.Join(pr.CommitAuthors, ", "),
+			Threshold: "linked to " + strings.Join(authors, ", "),
+			Passed:    true,
+		}
+		if len(authors) == 0 {
+			cond.Threshold = "no authors allowed"
+		}
+		if others := OtherCommitAuthors(pr, profile.AllowedCommitAuthors); len(others) > 0 {
+			cond.Value = strings.Join(others, ", ")
+			cond.Passed = false
+			cond.Code = model.ReasonCommitAuthorNotAllowed
+			cond.Message = "has commits by authors other than its bot: " + cond.Value
+		}
+		conds = append(conds, cond)
+	}
+
+	return conds
+}
+
+// checkContents returns a denied decision if the PR changes files or has
+// commit authors the profile doesn't allow, or nil if it doesn't.
+func checkContents(action model.PolicyAction, profile *model.MergeProfile, pr *model.PullRequest) *model.PolicyDecision {
+	trace := &model.PolicyTrace{
+		Action:     string(action),
+		Conditions: contentConditions(profile, pr),
+	}
+	if len(trace.FailedConditions()) == 0 {
+		return nil
+	}
+	return trace.Decision()
+}
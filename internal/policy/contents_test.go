@@ -0,0 +1,118 @@
+package policy
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestDisallowedFiles(t *testing.T) {
+	files := []string{
+		"go.mod",
+		"services/api/go.sum",
+		"web/package-lock.json",
+		"docker/Dockerfile.dev",
+		".github/workflows/ci.yml",
+		"main.go",
+		"scripts/.github/workflows/ci.yml",
+		"tools/requirements-dev.txt",
+	}
+
+	got := DisallowedFiles(files, nil)
+	if want := []string{"main.go", "scripts/.github/workflows/ci.yml"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	got = DisallowedFiles(files, []string{"go.*", "*.go"})
+	if want := []string{"web/package-lock.json", "docker/Dockerfile.dev", ".github/workflows/ci.yml", "scripts/.github/workflows/ci.yml", "tools/requirements-dev.txt"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v with custom patterns, got %v", want, got)
+	}
+}
+
+func TestOtherCommitAuthors(t *testing.T) {
+	tests := []struct {
+		bot     []string
+		commits []string
+		allowed []string
+		want    []string
+	}{
+		{[]string{"*renovate*"}, []string{"renovate[bot]"}, nil, nil},
+		{[]string{"*renovate*"}, []string{"Renovate-Bot"}, nil, nil},
+		{[]string{"*dependabot*"}, []string{"dependabot[bot]", "alice"}, nil, []string{"alice"}},
+		{[]string{"*renovate*"}, []string{"renovate[bot]", "github-actions[bot]"}, []string{"GitHub-Actions*"}, nil},
+		{[]string{"*renovate*"}, []string{"unlinked:Renovate Bot"}, nil, []string{"unlinked:Renovate Bot"}},
+		{[]string{"*renovate*"}, []string{"unlinked:ci"}, []string{"*"}, []string{"unlinked:ci"}},
+		{[]string{"*renovate*"}, []string{"unlinked:Renovate Bot", "unlinked:ci"}, []string{"unlinked:renovate*"}, []string{"unlinked:ci"}},
+		{[]string{"unlinked:*"}, []string{"unlinked:ci"}, nil, []string{"unlinked:ci"}},
+		{nil, []string{"snyk-bot"}, nil, []string{"snyk-bot"}},
+		{nil, []string{"snyk-bot"}, []string{"snyk-*"}, nil},
+	}
+
+	for _, tt := range tests {
+		pr := &model.PullRequest{Author: "renovate[bot]", BotAuthors: tt.bot, CommitAuthors: tt.commits}
+		if got := OtherCommitAuthors(pr, tt.allowed); !slices.Equal(got, tt.want) {
+			t.Errorf("OtherCommitAuthors(%v, %v, %v) = %v, want %v", tt.bot, tt.commits, tt.allowed, got, tt.want)
+		}
+	}
+}
+
+func TestEngine_DeniesUnexpectedContents(t *testing.T) {
+	pr := newTestPR(model.UpdateTypePatch, 48)
+	pr.Author = "renovate[bot]"
+	pr.BotAuthors = []string{"*renovate*"}
+	pr.Files = []string{"go.mod", "go.sum", "internal/evil.go"}
+	pr.CommitAuthors = []string{"renovate[bot]", "mallory"}
+
+	wantReasons := []string{
+		"changes files other than manifests and lockfiles: internal/evil.go",
+		"has commits by authors other than its bot: mallory",
+	}
+
+	cedarEngine, err := NewEngineWithConfig(EngineConfig{ProfileName: "balanced", PolicyPaths: []string{"../../policies/examples"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	engines := map[string]*Engine{
+		"profile": NewEngineWithProfile(&ProfileBalanced),
+		"cedar":   cedarEngine,
+	}
+
+	for name, engine := range engines {
+		for _, action := range []model.PolicyAction{model.PolicyActionMerge, model.PolicyActionReview} {
+			decision, err := engine.Evaluate(context.Background(), action, pr, passingChecks())
+			if err != nil {
+				t.Fatalf("%s %s: unexpected error: %v", name, action, err)
+			}
+			if decision.Allowed {
+				t.Errorf("%s %s: expected PR with unexpected contents to be denied", name, action)
+			}
+			if len(decision.Reasons) == 0 || decision.Reasons[0] != wantReasons[0] {
+				t.Errorf("%s %s: expected reason %q, got %v", name, action, wantReasons[0], decision.Reasons)
+			}
+		}
+
+		trace, err := engine.Explain(context.Background(), model.PolicyActionMerge, pr, passingChecks())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		var reasons []string
+		for _, c := range trace.FailedConditions() {
+			reasons = append(reasons, c.Message)
+		}
+		if trace.Allowed || !slices.Equal(reasons, wantReasons) {
+			t.Errorf("%s: expected trace to fail with %v, got %v", name, wantReasons, reasons)
+		}
+	}
+
+	pr.Files = pr.Files[:2]
+	pr.CommitAuthors = pr.CommitAuthors[:1]
+	decision, err := cedarEngine.CanMerge(context.Background(), pr, passingChecks())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decision.Allowed {
+		t.Errorf("expected PR changing only manifests to be allowed, got %v", decision.Reasons)
+	}
+}
@@ -5,25 +5,65 @@ import (
 )
 
 // ContextBuilder builds PolicyContext from PR and check information.
-type ContextBuilder struct{}
+type ContextBuilder struct {
+	scorer RiskScorer
+}
 
-// NewContextBuilder creates a new context builder.
+// NewContextBuilder creates a new context builder that scores risk with
+// the default risk scorer.
 func NewContextBuilder() *ContextBuilder {
-	return &ContextBuilder{}
+	return NewContextBuilderWithScorer(NewRiskScorer(nil))
+}
+
+// NewContextBuilderWithScorer creates a new context builder that scores
+// risk with the given scorer.
+func NewContextBuilderWithScorer(scorer RiskScorer) *ContextBuilder {
+	return &ContextBuilder{scorer: scorer}
+}
+
+// Risk scores the risk of merging a PR.
+func (b *ContextBuilder) Risk(pr *model.PullRequest, checks []model.CheckRun) model.RiskScore {
+	return b.scorer.Score(pr, checks)
 }
 
 // Build creates a PolicyContext from a PR and its checks.
 func (b *ContextBuilder) Build(pr *model.PullRequest, repo *model.Repo, checks []model.CheckRun) *model.PolicyContext {
+	return b.BuildWithRequiredChecks(pr, repo, checks, nil)
+}
+
+// BuildWithRequiredChecks creates a PolicyContext from a PR and its checks,
+// computing RequiredPassed from the given required checks. Without
+// required checks, RequiredPassed is true only if all checks passed.
+func (b *ContextBuilder) BuildWithRequiredChecks(pr *model.PullRequest, repo *model.Repo, checks []model.CheckRun, required []string) *model.PolicyContext {
 	ctx := &model.PolicyContext{
 		Repo:       b.buildRepoContext(repo),
 		PR:         b.buildPRContext(pr),
 		Dependency: b.buildDependencyContext(&pr.Dependency),
-		CI:         b.buildCIContext(checks),
+		CI:         b.buildCIContext(checks, required),
+		Risk:       b.Risk(pr, checks),
 	}
 
 	return ctx
 }
 
+// BuildPerDependency creates a PolicyContext for each dependency of a PR,
+// so grouped PRs can be evaluated one member at a time. The contexts differ
+// only in their dependency; a PR that is not grouped yields one context.
+func (b *ContextBuilder) BuildPerDependency(pr *model.PullRequest, repo *model.Repo, checks []model.CheckRun, required []string) []*model.PolicyContext {
+	base := b.BuildWithRequiredChecks(pr, repo, checks, required)
+	if !pr.IsGroup() {
+		return []*model.PolicyContext{base}
+	}
+
+	contexts := make([]*model.PolicyContext, 0, len(pr.Dependencies))
+	for _, dep := range pr.Dependencies {
+		pctx := *base
+		pctx.Dependency = b.buildDependencyContext(&dep)
+		contexts = append(contexts, &pctx)
+	}
+	return contexts
+}
+
 // buildRepoContext builds the repository context.
 func (b *ContextBuilder) buildRepoContext(repo *model.Repo) model.RepoContext {
 	if repo == nil {
@@ -48,6 +88,10 @@ func (b *ContextBuilder) buildPRContext(pr *model.PullRequest) model.PRContext {
 	}
 
 	ageHours := pr.AgeHours()
+	depCount := len(pr.Dependencies)
+	if depCount == 0 && pr.IsDependency {
+		depCount = 1
+	}
 
 	return model.PRContext{
 		Number:       pr.Number,
@@ -61,6 +105,9 @@ func (b *ContextBuilder) buildPRContext(pr *model.PullRequest) model.PRContext {
 		Draft:        pr.Draft,
 		Labels:       pr.Labels,
 		HasConflicts: pr.MergeableStr == "dirty",
+
+		DependencyCount: depCount,
+		Approvals:       len(pr.Approvers),
 	}
 }
 
@@ -70,24 +117,52 @@ func (b *ContextBuilder) buildDependencyContext(dep *model.Dependency) model.Dep
 		return model.DependencyContext{}
 	}
 
+	severity := string(dep.Severity)
+	if severity == "" {
+		severity = "none"
+	}
+
 	return model.DependencyContext{
 		Name:        dep.Name,
 		Ecosystem:   dep.Ecosystem,
 		FromVersion: dep.FromVersion,
 		ToVersion:   dep.ToVersion,
 		UpdateType:  string(dep.UpdateType),
+		DepType:     string(dep.DepType),
+		Manager:     dep.Manager,
 		IsMajor:     dep.UpdateType == model.UpdateTypeMajor,
 		IsMinor:     dep.UpdateType == model.UpdateTypeMinor,
 		IsPatch:     dep.UpdateType == model.UpdateTypePatch,
+
+		ReleaseAgeHours: dep.ReleaseAgeHours(),
+
+		FixesVulnerabilities: dep.FixesVulnerabilities(),
+		Severity:             severity,
 	}
 }
 
 // buildCIContext builds the CI/check context.
-func (b *ContextBuilder) buildCIContext(checks []model.CheckRun) model.CIContext {
+func (b *ContextBuilder) buildCIContext(checks []model.CheckRun, required []string) model.CIContext {
 	ctx := model.CIContext{
-		PassedChecks:  []string{},
-		FailedChecks:  []string{},
-		PendingChecks: []string{},
+		PassedChecks:   []string{},
+		FailedChecks:   []string{},
+		PendingChecks:  []string{},
+		RequiredChecks: []string{},
+		MissingChecks:  []string{},
+	}
+	ctx.RequiredChecks = append(ctx.RequiredChecks, required...)
+
+	if len(required) > 0 {
+		ctx.RequiredPassed = true
+		for _, name := range required {
+			c, ok := findCheck(checks, name)
+			if !ok {
+				ctx.MissingChecks = append(ctx.MissingChecks, name)
+			}
+			if !ok || !c.IsSuccess() {
+				ctx.RequiredPassed = false
+			}
+		}
 	}
 
 	if len(checks) == 0 {
@@ -116,7 +191,30 @@ func (b *ContextBuilder) buildCIContext(checks []model.CheckRun) model.CIContext
 	ctx.AllPassed = allPassed
 	ctx.AnyFailed = anyFailed
 	ctx.AnyPending = anyPending
-	ctx.RequiredPassed = allPassed // Simplified; could check specific required checks
+	if len(required) == 0 {
+		ctx.RequiredPassed = allPassed
+	}
 
 	return ctx
 }
+
+// findCheck returns the run of the named check. If the check ran more
+// than once, a successful run is preferred, then a pending one.
+func findCheck(checks []model.CheckRun, name string) (model.CheckRun, bool) {
+	var found model.CheckRun
+	ok := false
+	for _, c := range checks {
+		if c.Name != name {
+			continue
+		}
+		switch {
+		case !ok:
+			found, ok = c, true
+		case c.IsSuccess():
+			return c, true
+		case c.Status != "completed":
+			found = c
+		}
+	}
+	return found, ok
+}
@@ -0,0 +1,94 @@
+package policy
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestContextBuilder_RequiredChecks(t *testing.T) {
+	checks := []model.CheckRun{
+		{Name: "build", Status: "completed", Conclusion: "success"},
+		{Name: "lint", Status: "completed", Conclusion: "failure"},
+		{Name: "e2e", Status: "in_progress"},
+	}
+
+	tests := []struct {
+		name         string
+		required     []string
+		wantPassed   bool
+		wantMissing  []string
+		wantRequired []string
+	}{
+		{"no required checks uses all checks", nil, false, []string{}, []string{}},
+		{"required checks passed", []string{"build"}, true, []string{}, []string{"build"}},
+		{"required check failed", []string{"build", "lint"}, false, []string{}, []string{"build", "lint"}},
+		{"required check pending", []string{"e2e"}, false, []string{}, []string{"e2e"}},
+		{"required check missing", []string{"build", "test"}, false, []string{"test"}, []string{"build", "test"}},
+	}
+
+	builder := NewContextBuilder()
+	pr := newTestPR(model.UpdateTypePatch, 48)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ci := builder.BuildWithRequiredChecks(pr, repoFromRef(pr.Repo), checks, tt.required).CI
+
+			if ci.RequiredPassed != tt.wantPassed {
+				t.Errorf("expected requiredPassed=%v, got %v", tt.wantPassed, ci.RequiredPassed)
+			}
+			if !slices.Equal(ci.MissingChecks, tt.wantMissing) {
+				t.Errorf("expected missing checks %v, got %v", tt.wantMissing, ci.MissingChecks)
+			}
+			if !slices.Equal(ci.RequiredChecks, tt.wantRequired) {
+				t.Errorf("expected required checks %v, got %v", tt.wantRequired, ci.RequiredChecks)
+			}
+			if slices.Contains(ci.PendingChecks, "test") {
+				t.Error("expected missing check not to be reported as pending")
+			}
+		})
+	}
+}
+
+func TestContextBuilder_RequiredChecksRerun(t *testing.T) {
+	checks := []model.CheckRun{
+		{Name: "build", Status: "completed", Conclusion: "failure"},
+		{Name: "build", Status: "completed", Conclusion: "success"},
+	}
+
+	pr := newTestPR(model.UpdateTypePatch, 48)
+	ci := NewContextBuilder().BuildWithRequiredChecks(pr, repoFromRef(pr.Repo), checks, []string{"build"}).CI
+
+	if !ci.RequiredPassed {
+		t.Error("expected a successful rerun to satisfy the required check")
+	}
+}
+
+func TestContextBuilder_CommitStatuses(t *testing.T) {
+	checks := []model.CheckRun{
+		{Name: "build", Status: "completed", Conclusion: "success", Source: model.CheckSourceCheckRun},
+		model.NewStatusCheck("ci/jenkins", "success"),
+		model.NewStatusCheck("buildkite/deploy", "pending"),
+		model.NewStatusCheck("ci/circleci", "error"),
+	}
+
+	pr := newTestPR(model.UpdateTypePatch, 48)
+	ci := NewContextBuilder().Build(pr, repoFromRef(pr.Repo), checks).CI
+
+	if want := []string{"build", "ci/jenkins"}; !slices.Equal(ci.PassedChecks, want) {
+		t.Errorf("expected passed checks %v, got %v", want, ci.PassedChecks)
+	}
+	if want := []string{"buildkite/deploy"}; !slices.Equal(ci.PendingChecks, want) {
+		t.Errorf("expected pending checks %v, got %v", want, ci.PendingChecks)
+	}
+	if want := []string{"ci/circleci"}; !slices.Equal(ci.FailedChecks, want) {
+		t.Errorf("expected failed checks %v, got %v", want, ci.FailedChecks)
+	}
+
+	// A passing commit status satisfies a required check of the same name.
+	ci = NewContextBuilder().BuildWithRequiredChecks(pr, repoFromRef(pr.Repo), checks, []string{"ci/jenkins"}).CI
+	if !ci.RequiredPassed {
+		t.Error("expected commit status to satisfy the required check")
+	}
+}
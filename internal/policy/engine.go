@@ -2,63 +2,216 @@ package policy
 
 import (
 	"context"
+	"slices"
 
 	"github.com/plexusone/versionconductor/pkg/model"
 )
 
-// Engine evaluates policies for PR merge decisions.
-// This is a simplified implementation that uses merge profiles.
-// Cedar policy support can be added in a future version.
+// Engine evaluates policies for PR review, merge and release decisions.
+// When Cedar policies are configured they decide every action; otherwise
+// the merge profile is used as a fallback.
 type Engine struct {
-	profile *model.MergeProfile
+	profile  *model.MergeProfile
+	policies *CedarPolicies
+	builder  *ContextBuilder
+}
+
+// EngineConfig configures the policy engine.
+type EngineConfig struct {
+	// ProfileName is the name of a built-in merge profile.
+	// Ignored if Profile is set. Default is "balanced".
+	ProfileName string
+
+	// Profile is the merge profile used when no Cedar policies are configured.
+	Profile *model.MergeProfile
+
+	// PolicyPaths are .cedar files or directories containing .cedar files.
+	PolicyPaths []string
 }
 
 // NewEngine creates a new policy engine with the given profile name.
 func NewEngine(profileName string) (*Engine, error) {
-	profile := GetProfile(profileName)
+	return NewEngineWithConfig(EngineConfig{ProfileName: profileName})
+}
+
+// NewEngineWithProfile creates a new policy engine with the given profile.
+func NewEngineWithProfile(profile *model.MergeProfile) *Engine {
+	return &Engine{
+		profile: profile,
+		builder: NewContextBuilder(),
+	}
+}
+
+// NewEngineWithConfig creates a new policy engine with configuration.
+func NewEngineWithConfig(cfg EngineConfig) (*Engine, error) {
+	profile := cfg.Profile
+	if profile == nil {
+		profile = GetProfile(cfg.ProfileName)
+	}
 	if profile == nil {
 		profile = &ProfileBalanced
 	}
-	return &Engine{profile: profile}, nil
+
+	profile, err := WithFreezeCalendar(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	e := NewEngineWithProfile(profile)
+
+	if len(cfg.PolicyPaths) > 0 {
+		policies, err := LoadCedarPolicies(cfg.PolicyPaths...)
+		if err != nil {
+			return nil, err
+		}
+		if policies.Len() > 0 {
+			e.policies = policies
+		}
+	}
+
+	return e, nil
 }
 
-// NewEngineWithProfile creates a new policy engine with the given profile.
-func NewEngineWithProfile(profile *model.MergeProfile) *Engine {
-	return &Engine{profile: profile}
+// Profile returns the merge profile used by the engine.
+func (e *Engine) Profile() *model.MergeProfile {
+	return e.profile
+}
+
+// WithRiskScorer returns an engine that scores the risk of PRs with the
+// given scorer instead of the default one.
+func (e *Engine) WithRiskScorer(scorer RiskScorer) *Engine {
+	return &Engine{
+		profile:  e.profile,
+		policies: e.policies,
+		builder:  NewContextBuilderWithScorer(scorer),
+	}
+}
+
+// Risk scores the risk of merging a PR.
+func (e *Engine) Risk(pr *model.PullRequest, checks []model.CheckRun) model.RiskScore {
+	return e.builder.Risk(pr, checks)
+}
+
+// WithProfile returns an engine that shares this engine's Cedar policies
+// but falls back to a different merge profile.
+func (e *Engine) WithProfile(profile *model.MergeProfile) *Engine {
+	return &Engine{
+		profile:  profile,
+		policies: e.policies,
+		builder:  e.builder,
+	}
+}
+
+// HasCedarPolicies returns true if Cedar policies are configured.
+func (e *Engine) HasCedarPolicies() bool {
+	return e.policies.Len() > 0
 }
 
 // Evaluate evaluates the policy for the given action and context.
 func (e *Engine) Evaluate(ctx context.Context, action model.PolicyAction, pr *model.PullRequest, checks []model.CheckRun) (*model.PolicyDecision, error) {
+	if e.HasCedarPolicies() {
+		if d := e.preCheck(action, pr); d != nil {
+			return d, nil
+		}
+		contexts := e.builder.BuildPerDependency(pr, repoFromRef(pr.Repo), checks, e.profile.RequiredChecks)
+		if len(contexts) == 1 {
+			return e.EvaluateContext(ctx, action, contexts[0])
+		}
+		return e.evaluateGroup(ctx, action, pr, contexts)
+	}
+
 	result := &model.PolicyDecision{
 		Action: string(action),
 	}
 
 	switch action {
 	case model.PolicyActionMerge:
-		allowed, reason := EvaluateProfile(e.profile, pr, checks)
-		result.Allowed = allowed
-		if !allowed {
-			result.Reasons = []string{reason}
-		}
+		trace := explainProfile(e.profile, pr, checks, e.Risk(pr, checks))
+		result = firstFailureDecision(trace)
 	case model.PolicyActionReview:
-		// For review, we're more permissive - just need passing tests
-		if pr.TestsPassed && !pr.Draft {
-			result.Allowed = true
-		} else {
-			result.Allowed = false
-			if !pr.TestsPassed {
-				result.Reasons = append(result.Reasons, "CI checks not passed")
-			}
-			if pr.Draft {
-				result.Reasons = append(result.Reasons, "PR is a draft")
-			}
-		}
+		result = firstFailureDecision(ExplainReviewProfile(e.profile, pr, checks))
 	case model.PolicyActionRelease:
 		// Release is allowed if there are merged PRs
 		result.Allowed = true
 	default:
 		result.Allowed = false
-		result.Reasons = []string{"unknown action"}
+		addReason(result, model.Reason{Code: model.ReasonUnknown, Message: "unknown action"})
+	}
+
+	return result, nil
+}
+
+// preCheck runs the profile checks that apply before Cedar policies: the
+// schedule, contents and approvals for merges, and the contents for
+// reviews. It returns the denied decision of the first failing check, or
+// nil if all pass.
+func (e *Engine) preCheck(action model.PolicyAction, pr *model.PullRequest) *model.PolicyDecision {
+	if action == model.PolicyActionMerge {
+		if d := checkSchedule(action, e.profile); d != nil {
+			return d
+		}
+	}
+	if action == model.PolicyActionMerge || action == model.PolicyActionReview {
+		if d := checkContents(action, e.profile, pr); d != nil {
+			return d
+		}
+	}
+	if action == model.PolicyActionMerge {
+		if d := checkApprovals(action, e.profile, pr); d != nil {
+			return d
+		}
+	}
+	return nil
+}
+
+// EvaluateContext evaluates Cedar policies for the given action against a
+// prebuilt policy context. Without Cedar policies the action is allowed
+// only for releases, since profiles need the PR itself.
+func (e *Engine) EvaluateContext(ctx context.Context, action model.PolicyAction, pctx *model.PolicyContext) (*model.PolicyDecision, error) {
+	if e.HasCedarPolicies() {
+		return e.policies.Evaluate(action, pctx)
+	}
+
+	if action == model.PolicyActionRelease {
+		return &model.PolicyDecision{
+			Allowed: true,
+			Action:  string(action),
+		}, nil
+	}
+
+	result := &model.PolicyDecision{
+		Allowed: false,
+		Action:  string(action),
+	}
+	addReason(result, model.Reason{Code: model.ReasonPolicyNotPermitted, Message: "no Cedar policies configured"})
+	return result, nil
+}
+
+// evaluateGroup evaluates Cedar policies against the context of each
+// dependency of a grouped PR. The action is allowed only if it is allowed
+// for every dependency; reasons are prefixed with the dependency name.
+func (e *Engine) evaluateGroup(ctx context.Context, action model.PolicyAction, pr *model.PullRequest, contexts []*model.PolicyContext) (*model.PolicyDecision, error) {
+	result := &model.PolicyDecision{
+		Allowed: true,
+		Action:  string(action),
+	}
+
+	for i, pctx := range contexts {
+		d, err := e.EvaluateContext(ctx, action, pctx)
+		if err != nil {
+			return nil, err
+		}
+		result.Allowed = result.Allowed && d.Allowed
+		for _, reason := range d.Details {
+			reason.Message = pr.Dependencies[i].Name + ": " + reason.Message
+			reason.Dependency = pr.Dependencies[i].Name
+			addReason(result, reason)
+		}
+		for _, id := range d.Policies {
+			if !slices.Contains(result.Policies, id) {
+				result.Policies = append(result.Policies, id)
+			}
+		}
 	}
 
 	return result, nil
@@ -74,10 +227,30 @@ func (e *Engine) CanReview(ctx context.Context, pr *model.PullRequest, checks []
 	return e.Evaluate(ctx, model.PolicyActionReview, pr, checks)
 }
 
-// CanRelease evaluates whether a release can be created.
-func (e *Engine) CanRelease(ctx context.Context) (*model.PolicyDecision, error) {
-	return &model.PolicyDecision{
-		Allowed: true,
-		Action:  string(model.PolicyActionRelease),
-	}, nil
+// CanRelease evaluates whether a release can be created for a repository.
+// Releases follow the profile's merge windows and freezes.
+func (e *Engine) CanRelease(ctx context.Context, repo *model.Repo) (*model.PolicyDecision, error) {
+	if d := checkSchedule(model.PolicyActionRelease, e.profile); d != nil {
+		return d, nil
+	}
+
+	pctx := &model.PolicyContext{
+		Repo: e.builder.buildRepoContext(repo),
+	}
+	return e.EvaluateContext(ctx, model.PolicyActionRelease, pctx)
+}
+
+// addReason adds a reason to a decision, both as text and structured.
+func addReason(d *model.PolicyDecision, reason model.Reason) {
+	d.Reasons = append(d.Reasons, reason.Message)
+	d.Details = append(d.Details, reason)
+}
+
+// repoFromRef creates a minimal Repo from a RepoRef.
+func repoFromRef(ref model.RepoRef) *model.Repo {
+	return &model.Repo{
+		Owner:    ref.Owner,
+		Name:     ref.Name,
+		FullName: ref.FullName(),
+	}
 }
@@ -0,0 +1,189 @@
+package policy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func newTestPR(updateType model.UpdateType, ageHours int) *model.PullRequest {
+	return &model.PullRequest{
+		Number:       1,
+		Title:        "chore(deps): update example",
+		IsDependency: true,
+		DependBot:    model.DependBotRenovate,
+		Dependency:   model.Dependency{Name: "github.com/example/pkg", UpdateType: updateType},
+		TestsPassed:  true,
+		Mergeable:    true,
+		CreatedAt:    time.Now().Add(-time.Duration(ageHours) * time.Hour),
+		Repo:         model.RepoRef{Owner: "example", Name: "repo"},
+	}
+}
+
+func passingChecks() []model.CheckRun {
+	return []model.CheckRun{{Name: "build", Status: "completed", Conclusion: "success"}}
+}
+
+func TestEngine_ProfileFallback(t *testing.T) {
+	engine, err := NewEngine("balanced")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if engine.HasCedarPolicies() {
+		t.Fatal("expected no Cedar policies")
+	}
+
+	tests := []struct {
+		name       string
+		action     model.PolicyAction
+		pr         *model.PullRequest
+		wantAllow  bool
+		wantReason string
+	}{
+		{"merge patch", model.PolicyActionMerge, newTestPR(model.UpdateTypePatch, 48), true, ""},
+		{"merge too young", model.PolicyActionMerge, newTestPR(model.UpdateTypePatch, 1), false, "PR is too young"},
+		{"merge major", model.PolicyActionMerge, newTestPR(model.UpdateTypeMajor, 48), false, "major updates require manual review"},
+		{"review minor", model.PolicyActionReview, newTestPR(model.UpdateTypeMinor, 1), true, ""},
+		{"review major", model.PolicyActionReview, newTestPR(model.UpdateTypeMajor, 1), false, "major updates require manual review"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := engine.Evaluate(context.Background(), tt.action, tt.pr, passingChecks())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decision.Allowed != tt.wantAllow {
+				t.Errorf("expected allowed=%v, got %v (reasons: %v)", tt.wantAllow, decision.Allowed, decision.Reasons)
+			}
+			if tt.wantReason != "" && (len(decision.Reasons) == 0 || decision.Reasons[0] != tt.wantReason) {
+				t.Errorf("expected reason %q, got %v", tt.wantReason, decision.Reasons)
+			}
+		})
+	}
+}
+
+func TestEngine_GroupEvaluatesEachDependency(t *testing.T) {
+	engine := NewEngineWithProfile(&ProfileBalanced)
+
+	pr := newTestPR("", 48)
+	pr.Dependencies = []model.Dependency{
+		{Name: "lodash", UpdateType: model.UpdateTypePatch},
+		{Name: "eslint", UpdateType: model.UpdateTypeMajor},
+	}
+	pr.Dependency = model.GroupDependency(pr.Dependencies)
+
+	decision, err := engine.CanMerge(context.Background(), pr, passingChecks())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.Allowed {
+		t.Fatal("expected group with a major update to be denied")
+	}
+	if want := "eslint: major updates require manual review"; len(decision.Reasons) == 0 || decision.Reasons[0] != want {
+		t.Errorf("expected reason %q, got %v", want, decision.Reasons)
+	}
+	if len(decision.Details) == 0 || decision.Details[0].Code != model.ReasonUpdateTypeNotAllowed || decision.Details[0].Dependency != "eslint" {
+		t.Errorf("expected %s for eslint, got %+v", model.ReasonUpdateTypeNotAllowed, decision.Details)
+	}
+
+	pr.Dependencies[1].UpdateType = model.UpdateTypeMinor
+	pr.Dependency = model.GroupDependency(pr.Dependencies)
+	decision, err = engine.CanMerge(context.Background(), pr, passingChecks())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decision.Allowed {
+		t.Errorf("expected group of patch and minor updates to be allowed, got %v", decision.Reasons)
+	}
+}
+
+func TestEngine_ReasonCodes(t *testing.T) {
+	engine := NewEngineWithProfile(&ProfileBalanced)
+
+	tests := []struct {
+		name     string
+		action   model.PolicyAction
+		pr       func() *model.PullRequest
+		checks   []model.CheckRun
+		wantCode model.ReasonCode
+	}{
+		{"too young", model.PolicyActionMerge, func() *model.PullRequest { return newTestPR(model.UpdateTypePatch, 1) }, passingChecks(), model.ReasonAgeTooYoung},
+		{"major", model.PolicyActionMerge, func() *model.PullRequest { return newTestPR(model.UpdateTypeMajor, 48) }, passingChecks(), model.ReasonUpdateTypeNotAllowed},
+		{"failed check", model.PolicyActionMerge, func() *model.PullRequest { return newTestPR(model.UpdateTypePatch, 48) },
+			[]model.CheckRun{{Name: "build", Status: "completed", Conclusion: "failure"}}, model.ReasonCIFailed},
+		{"pending check", model.PolicyActionMerge, func() *model.PullRequest { return newTestPR(model.UpdateTypePatch, 48) },
+			[]model.CheckRun{{Name: "build", Status: "in_progress"}}, model.ReasonCIPending},
+		{"not mergeable", model.PolicyActionMerge, func() *model.PullRequest {
+			pr := newTestPR(model.UpdateTypePatch, 48)
+			pr.Mergeable = false
+			return pr
+		}, passingChecks(), model.ReasonNotMergeable},
+		{"draft", model.PolicyActionMerge, func() *model.PullRequest {
+			pr := newTestPR(model.UpdateTypePatch, 48)
+			pr.Draft = true
+			return pr
+		}, passingChecks(), model.ReasonDraft},
+		{"review pending checks", model.PolicyActionReview, func() *model.PullRequest {
+			pr := newTestPR(model.UpdateTypePatch, 48)
+			pr.TestsPassed = false
+			return pr
+		}, []model.CheckRun{{Name: "build", Status: "queued"}}, model.ReasonCIPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := engine.Evaluate(context.Background(), tt.action, tt.pr(), tt.checks)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decision.Allowed {
+				t.Fatal("expected decision to be denied")
+			}
+			if len(decision.Details) != len(decision.Reasons) {
+				t.Fatalf("expected a detail for each reason, got %v and %+v", decision.Reasons, decision.Details)
+			}
+			if codes := decision.Codes(); len(codes) != 1 || codes[0] != tt.wantCode {
+				t.Errorf("expected code %s, got %v", tt.wantCode, codes)
+			}
+			if decision.Details[0].Message != decision.Reasons[0] || decision.Details[0].Condition == "" {
+				t.Errorf("expected detail to match reason %q with its condition, got %+v", decision.Reasons[0], decision.Details[0])
+			}
+		})
+	}
+}
+
+func TestEngine_CanReleaseWithoutPolicies(t *testing.T) {
+	engine := NewEngineWithProfile(&ProfileBalanced)
+
+	decision, err := engine.CanRelease(context.Background(), &model.Repo{Owner: "example", Name: "repo", FullName: "example/repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decision.Allowed {
+		t.Errorf("expected release to be allowed, got reasons: %v", decision.Reasons)
+	}
+}
+
+func TestNewEngineWithConfig_MissingPolicyDir(t *testing.T) {
+	_, err := NewEngineWithConfig(EngineConfig{PolicyPaths: []string{"testdata/does-not-exist"}})
+	if err == nil {
+		t.Fatal("expected error for missing policy directory")
+	}
+}
+
+func TestNewEngineWithConfig_EmptyPolicyDir(t *testing.T) {
+	engine, err := NewEngineWithConfig(EngineConfig{ProfileName: "conservative", PolicyPaths: []string{t.TempDir()}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if engine.HasCedarPolicies() {
+		t.Error("expected empty policy directory to fall back to profile")
+	}
+	if engine.Profile().Name != "conservative" {
+		t.Errorf("expected conservative profile, got %s", engine.Profile().Name)
+	}
+}
@@ -0,0 +1,516 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// Condition names used in policy traces.
+const (
+	ConditionMinAge       = "min-age"
+	ConditionMaxAge       = "max-age"
+	ConditionReleaseAge   = "min-release-age"
+	ConditionUpdateType   = "update-type"
+	ConditionDependency   = "dependency"
+	ConditionFiles        = "files"
+	ConditionCommitAuthor = "commit-authors"
+	ConditionRulePrefix   = "rule:"
+	ConditionTestsPassed  = "tests-passed"
+	ConditionCheckPrefix  = "check:"
+	ConditionApprovals    = "approvals"
+	ConditionMergeable    = "mergeable"
+	ConditionDraft        = "draft"
+	ConditionRisk         = "max-risk"
+	ConditionMergeWindow  = "merge-window"
+	ConditionFreeze       = "freeze"
+	ConditionPolicyPrefix = "policy:"
+)
+
+// Explain evaluates the policy for the given action and returns a full
+// trace of every condition checked.
+func (e *Engine) Explain(ctx context.Context, action model.PolicyAction, pr *model.PullRequest, checks []model.CheckRun) (*model.PolicyTrace, error) {
+	if e.HasCedarPolicies() {
+		contexts := e.builder.BuildPerDependency(pr, repoFromRef(pr.Repo), checks, e.profile.RequiredChecks)
+		trace, err := e.explainContexts(action, pr, contexts)
+		if err != nil {
+			return nil, err
+		}
+		trace.PR = *pr
+		if action == model.PolicyActionMerge && !applySchedule(trace, e.profile) {
+			trace.Allowed = false
+		}
+		if action == model.PolicyActionMerge || action == model.PolicyActionReview {
+			conds := contentConditions(e.profile, pr)
+			if action == model.PolicyActionMerge {
+				conds = append(conds, approvalConditions(e.profile, pr)...)
+			}
+			trace.Conditions = append(trace.Conditions, conds...)
+			for _, c := range conds {
+				trace.Allowed = trace.Allowed && c.Passed
+			}
+		}
+		return trace, nil
+	}
+
+	var trace *model.PolicyTrace
+	switch action {
+	case model.PolicyActionMerge:
+		trace = explainProfile(e.profile, pr, checks, e.Risk(pr, checks))
+	case model.PolicyActionReview:
+		trace = ExplainReviewProfile(e.profile, pr, checks)
+	default:
+		return nil, fmt.Errorf("cannot explain action: %s", action)
+	}
+
+	return trace, nil
+}
+
+// explainContexts explains the Cedar policies against each context. For
+// grouped PRs, with one context per dependency, the traces are combined
+// and each condition is prefixed with its dependency name.
+func (e *Engine) explainContexts(action model.PolicyAction, pr *model.PullRequest, contexts []*model.PolicyContext) (*model.PolicyTrace, error) {
+	if len(contexts) == 1 {
+		return e.policies.Explain(action, contexts[0])
+	}
+
+	combined := &model.PolicyTrace{
+		Action:  string(action),
+		Allowed: true,
+	}
+	for i, pctx := range contexts {
+		trace, err := e.policies.Explain(action, pctx)
+		if err != nil {
+			return nil, err
+		}
+		name := pr.Dependencies[i].Name
+		combined.Allowed = combined.Allowed && trace.Allowed
+		for _, id := range trace.Policies {
+			if !slices.Contains(combined.Policies, id) {
+				combined.Policies = append(combined.Policies, id)
+			}
+		}
+		for _, cond := range trace.Conditions {
+			cond.Dependency = name
+			cond.Value = name + ": " + cond.Value
+			if cond.Message != "" {
+				cond.Message = name + ": " + cond.Message
+			}
+			combined.Conditions = append(combined.Conditions, cond)
+		}
+	}
+
+	return combined, nil
+}
+
+// ExplainProfile evaluates every merge condition of a profile against a PR,
+// scoring its risk with the default risk scorer.
+func ExplainProfile(profile *model.MergeProfile, pr *model.PullRequest, checks []model.CheckRun) *model.PolicyTrace {
+	return explainProfile(profile, pr, checks, NewRiskScorer(nil).Score(pr, checks))
+}
+
+// explainProfile evaluates every merge condition of a profile against a PR
+// with the given risk score.
+func explainProfile(profile *model.MergeProfile, pr *model.PullRequest, checks []model.CheckRun, risk model.RiskScore) *model.PolicyTrace {
+	trace := newProfileTrace(model.PolicyActionMerge, profile, pr)
+
+	// Check age requirements. Security fixes may skip the minimum age.
+	ageHours := pr.AgeHours()
+	if profile.MinAgeHours > 0 {
+		cond := model.PolicyCondition{
+			Name:      ConditionMinAge,
+			Value:     fmt.Sprintf("%dh", ageHours),
+			Threshold: fmt.Sprintf(">= %dh", profile.MinAgeHours),
+			Passed:    ageHours >= profile.MinAgeHours,
+			Code:      model.ReasonAgeTooYoung,
+			Message:   "PR is too young",
+		}
+		if skipsAge(profile, &pr.Dependency) {
+			cond.Value += " (security fix)"
+			cond.Passed = true
+		}
+		trace.Conditions = append(trace.Conditions, cond)
+	}
+	if profile.MaxAgeHours > 0 {
+		trace.Conditions = append(trace.Conditions, model.PolicyCondition{
+			Name:      ConditionMaxAge,
+			Value:     fmt.Sprintf("%dh", ageHours),
+			Threshold: fmt.Sprintf("<= %dh", profile.MaxAgeHours),
+			Passed:    ageHours <= profile.MaxAgeHours,
+			Code:      model.ReasonAgeTooOld,
+			Message:   "PR is too old",
+		})
+	}
+
+	// Check how long ago the new versions were published
+	trace.Conditions = append(trace.Conditions, releaseAgeConditions(profile, pr)...)
+
+	// Check update type
+	trace.Conditions = append(trace.Conditions, dependencyConditions(profile, pr, false)...)
+
+	// Check changed files and commit authors
+	trace.Conditions = append(trace.Conditions, contentConditions(profile, pr)...)
+
+	// Check CI status
+	switch {
+	case len(profile.RequiredChecks) > 0:
+		for _, name := range profile.RequiredChecks {
+			trace.Conditions = append(trace.Conditions, requiredCheckCondition(checks, name, profile.AllowPendingChecks))
+		}
+	case profile.RequireAllChecks:
+		for _, c := range checks {
+			trace.Conditions = append(trace.Conditions, checkCondition(c, profile.AllowPendingChecks))
+		}
+	}
+
+	// Check approving reviews
+	trace.Conditions = append(trace.Conditions, approvalConditions(profile, pr)...)
+
+	// Check mergeable status
+	mergeable := strconv.FormatBool(pr.Mergeable)
+	if pr.MergeableStr != "" {
+		mergeable += " (" + pr.MergeableStr + ")"
+	}
+	trace.Conditions = append(trace.Conditions, model.PolicyCondition{
+		Name:      ConditionMergeable,
+		Value:     mergeable,
+		Threshold: "true",
+		Passed:    pr.Mergeable,
+		Code:      model.ReasonNotMergeable,
+		Message:   "PR is not mergeable",
+	})
+
+	trace.Conditions = append(trace.Conditions, draftCondition(pr))
+
+	if profile.MaxRiskScore > 0 {
+		trace.Conditions = append(trace.Conditions, riskCondition(profile, risk))
+	}
+
+	// Merge windows and freezes are checked first so they are reported
+	// as the reason even when other conditions also fail.
+	applySchedule(trace, profile)
+
+	trace.Allowed = len(trace.FailedConditions()) == 0
+	return trace
+}
+
+// ExplainReviewProfile evaluates every review condition of a profile against a PR.
+func ExplainReviewProfile(profile *model.MergeProfile, pr *model.PullRequest, checks []model.CheckRun) *model.PolicyTrace {
+	trace := newProfileTrace(model.PolicyActionReview, profile, pr)
+
+	// Check if tests pass
+	switch {
+	case len(profile.RequiredChecks) > 0:
+		for _, name := range profile.RequiredChecks {
+			cond := requiredCheckCondition(checks, name, false)
+			if !cond.Passed && cond.Value != checkMissing {
+				cond.Message = "not all CI checks passed: " + name
+			}
+			trace.Conditions = append(trace.Conditions, cond)
+		}
+	case profile.RequireAllChecks:
+		trace.Conditions = append(trace.Conditions, model.PolicyCondition{
+			Name:      ConditionTestsPassed,
+			Value:     strconv.FormatBool(pr.TestsPassed),
+			Threshold: "true",
+			Passed:    pr.TestsPassed,
+			Code:      checksCode(checks),
+			Message:   "CI checks not passed",
+		})
+		for _, c := range checks {
+			cond := checkCondition(c, false)
+			cond.Message = "not all CI checks passed: " + c.Name
+			trace.Conditions = append(trace.Conditions, cond)
+		}
+	}
+
+	// Check update type eligibility
+	trace.Conditions = append(trace.Conditions, dependencyConditions(profile, pr, true)...)
+
+	// Check changed files and commit authors
+	trace.Conditions = append(trace.Conditions, contentConditions(profile, pr)...)
+
+	// Check if PR is in a reviewable state
+	trace.Conditions = append(trace.Conditions, draftCondition(pr))
+
+	trace.Allowed = len(trace.FailedConditions()) == 0
+	return trace
+}
+
+// newProfileTrace creates an empty trace for a profile evaluation.
+func newProfileTrace(action model.PolicyAction, profile *model.MergeProfile, pr *model.PullRequest) *model.PolicyTrace {
+	return &model.PolicyTrace{
+		Action:  string(action),
+		PR:      *pr,
+		Profile: profile.Name,
+	}
+}
+
+// dependencyConditions checks the update type and name of each dependency
+// of a PR. For grouped PRs every member is checked on its own, and its
+// name is added to messages that do not already mention it.
+func dependencyConditions(profile *model.MergeProfile, pr *model.PullRequest, allowUnknown bool) []model.PolicyCondition {
+	var conds []model.PolicyCondition
+	for _, dep := range pr.AllDependencies() {
+		cond := updateTypeOrRuleCondition(profile, dep, allowUnknown)
+		if pr.IsGroup() {
+			cond.Dependency = dep.Name
+			if cond.Name == ConditionUpdateType {
+				cond.Value = dep.Name + ": " + cond.Value
+			}
+			if !strings.Contains(cond.Message, dep.Name) {
+				cond.Message = dep.Name + ": " + cond.Message
+			}
+		}
+		conds = append(conds, cond)
+		if cond, ok := dependencyCondition(profile, dep.Name); ok {
+			if pr.IsGroup() {
+				cond.Dependency = dep.Name
+			}
+			conds = append(conds, cond)
+		}
+	}
+	return conds
+}
+
+// releaseAgeConditions checks that the new version of each dependency
+// was published at least MinReleaseAgeHours ago. Versions with an unknown
+// publish time fail, since they may be just as new; security fixes pass
+// if the profile lets them skip the age.
+func releaseAgeConditions(profile *model.MergeProfile, pr *model.PullRequest) []model.PolicyCondition {
+	if profile.MinReleaseAgeHours <= 0 {
+		return nil
+	}
+
+	var conds []model.PolicyCondition
+	for _, dep := range pr.AllDependencies() {
+		cond := model.PolicyCondition{
+			Name:      ConditionReleaseAge,
+			Value:     "unknown",
+			Threshold: fmt.Sprintf(">= %dh", profile.MinReleaseAgeHours),
+			Code:      model.ReasonReleaseAgeUnknown,
+			Message:   fmt.Sprintf("publish time of %s %s is unknown", dep.Name, dep.ToVersion),
+		}
+		if age := dep.ReleaseAgeHours(); age >= 0 {
+			cond.Code = model.ReasonReleaseTooRecent
+			cond.Value = fmt.Sprintf("%dh", age)
+			cond.Passed = age >= profile.MinReleaseAgeHours
+			cond.Message = fmt.Sprintf("%s %s was released too recently", dep.Name, dep.ToVersion)
+		}
+		if skipsAge(profile, &dep) {
+			cond.Value += " (security fix)"
+			cond.Passed = true
+		}
+		if pr.IsGroup() {
+			cond.Dependency = dep.Name
+			cond.Value = dep.Name + ": " + cond.Value
+		}
+		conds = append(conds, cond)
+	}
+	return conds
+}
+
+// riskCondition checks the risk score of a PR against the profile's
+// maximum, naming the factors that contributed to it.
+func riskCondition(profile *model.MergeProfile, risk model.RiskScore) model.PolicyCondition {
+	factors := make([]string, 0, len(risk.Factors))
+	for _, f := range risk.Factors {
+		factors = append(factors, fmt.Sprintf("%s +%d", f.Detail, f.Points))
+	}
+	return model.PolicyCondition{
+		Name:      ConditionRisk,
+		Value:     strconv.Itoa(risk.Score),
+		Threshold: fmt.Sprintf("<= %d", profile.MaxRiskScore),
+		Passed:    risk.Score <= profile.MaxRiskScore,
+		Code:      model.ReasonRiskTooHigh,
+		Message:   fmt.Sprintf("risk score %d is above %d: %s", risk.Score, profile.MaxRiskScore, strings.Join(factors, ", ")),
+	}
+}
+
+// skipsAge reports whether the profile exempts the dependency update from
+// the minimum ages because it fixes a known vulnerability.
+func skipsAge(profile *model.MergeProfile, dep *model.Dependency) bool {
+	return profile.SecurityFixesSkipAge && dep.FixesVulnerabilities()
+}
+
+// updateTypeOrRuleCondition checks the dependency against the first
+// matching dependency rule, falling back to the profile's update type
+// settings when no rule matches.
+func updateTypeOrRuleCondition(profile *model.MergeProfile, dep model.Dependency, allowUnknown bool) model.PolicyCondition {
+	if cond, ok := ruleCondition(profile, dep); ok {
+		return cond
+	}
+	return updateTypeCondition(profile, dep.UpdateType, allowUnknown)
+}
+
+// updateTypeCondition checks the update type against the profile.
+// Unknown update types pass only if allowUnknown is set.
+func updateTypeCondition(profile *model.MergeProfile, updateType model.UpdateType, allowUnknown bool) model.PolicyCondition {
+	var allowed []string
+	if profile.AutoMergePatch {
+		allowed = append(allowed, string(model.UpdateTypePatch))
+	}
+	if profile.AutoMergeMinor {
+		allowed = append(allowed, string(model.UpdateTypeMinor))
+	}
+	if profile.AutoMergeMajor {
+		allowed = append(allowed, string(model.UpdateTypeMajor))
+	}
+
+	value := string(updateType)
+	if value == "" {
+		value = string(model.UpdateTypeUnknown)
+	}
+
+	cond := model.PolicyCondition{
+		Name:      ConditionUpdateType,
+		Value:     value,
+		Threshold: strings.Join(allowed, ", "),
+		Code:      model.ReasonUpdateTypeNotAllowed,
+	}
+
+	switch updateType {
+	case model.UpdateTypeMajor:
+		cond.Passed = profile.AutoMergeMajor
+		cond.Message = "major updates require manual review"
+	case model.UpdateTypeMinor:
+		cond.Passed = profile.AutoMergeMinor
+		cond.Message = "minor updates require manual review"
+	case model.UpdateTypePatch:
+		cond.Passed = profile.AutoMergePatch
+		cond.Message = "patch updates require manual review"
+	default:
+		cond.Passed = allowUnknown
+		cond.Message = "unknown update type"
+	}
+
+	return cond
+}
+
+// dependencyCondition checks the dependency name against the profile's
+// allow and deny lists. It returns false if the profile has no lists.
+func dependencyCondition(profile *model.MergeProfile, name string) (model.PolicyCondition, bool) {
+	if len(profile.AllowDependencies) == 0 && len(profile.DenyDependencies) == 0 {
+		return model.PolicyCondition{}, false
+	}
+
+	cond := model.PolicyCondition{
+		Name:  ConditionDependency,
+		Value: name,
+		Code:  model.ReasonDependencyDenied,
+	}
+	if name == "" {
+		cond.Value = "unknown"
+	}
+
+	if pattern, ok := MatchDependency(name, profile.DenyDependencies); ok {
+		cond.Threshold = "not " + pattern
+		cond.Message = fmt.Sprintf("dependency %s is denied by %s", cond.Value, pattern)
+		return cond, true
+	}
+
+	if len(profile.AllowDependencies) > 0 {
+		pattern, ok := MatchDependency(name, profile.AllowDependencies)
+		if !ok {
+			cond.Threshold = strings.Join(profile.AllowDependencies, ", ")
+			cond.Message = fmt.Sprintf("dependency %s is not in the allow list", cond.Value)
+			return cond, true
+		}
+		cond.Threshold = pattern
+	}
+
+	cond.Passed = true
+	return cond, true
+}
+
+// checkMissing is the condition value for a required check with no run.
+const checkMissing = "missing"
+
+// requiredCheckCondition checks that a required check ran and passed.
+func requiredCheckCondition(checks []model.CheckRun, name string, allowPending bool) model.PolicyCondition {
+	c, ok := findCheck(checks, name)
+	if !ok {
+		return model.PolicyCondition{
+			Name:      ConditionCheckPrefix + name,
+			Value:     checkMissing,
+			Threshold: "completed/success (required)",
+			Code:      model.ReasonCIMissing,
+			Message:   "required check missing: " + name,
+		}
+	}
+
+	cond := checkCondition(c, allowPending)
+	cond.Threshold += " (required)"
+	return cond
+}
+
+// checkCondition checks a single CI check run.
+func checkCondition(c model.CheckRun, allowPending bool) model.PolicyCondition {
+	value := c.Status
+	if c.Conclusion != "" {
+		value += "/" + c.Conclusion
+	}
+
+	cond := model.PolicyCondition{
+		Name:      ConditionCheckPrefix + c.Name,
+		Value:     value,
+		Threshold: "completed/success",
+	}
+	if allowPending {
+		cond.Threshold += " or pending"
+	}
+
+	switch {
+	case c.IsSuccess():
+		cond.Passed = true
+	case c.Status != "completed":
+		cond.Passed = allowPending
+		cond.Code = model.ReasonCIPending
+		cond.Message = "CI checks still pending"
+	default:
+		cond.Code = model.ReasonCIFailed
+		cond.Message = "CI checks failed"
+	}
+
+	return cond
+}
+
+// draftCondition checks that the PR is not a draft.
+func draftCondition(pr *model.PullRequest) model.PolicyCondition {
+	return model.PolicyCondition{
+		Name:      ConditionDraft,
+		Value:     strconv.FormatBool(pr.Draft),
+		Threshold: "false",
+		Passed:    !pr.Draft,
+		Code:      model.ReasonDraft,
+		Message:   "PR is a draft",
+	}
+}
+
+// checksCode returns the reason code for checks that have not all passed:
+// CI_MISSING if there are none, CI_FAILED if any failed, CI_PENDING if the
+// rest are still running.
+func checksCode(checks []model.CheckRun) model.ReasonCode {
+	if len(checks) == 0 {
+		return model.ReasonCIMissing
+	}
+
+	pending := false
+	for _, c := range checks {
+		switch {
+		case c.IsSuccess():
+		case c.Status != "completed":
+			pending = true
+		default:
+			return model.ReasonCIFailed
+		}
+	}
+	if pending {
+		return model.ReasonCIPending
+	}
+	return model.ReasonCIFailed
+}
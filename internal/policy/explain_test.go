@@ -0,0 +1,249 @@
+package policy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestExplainProfile_ReportsAllFailures(t *testing.T) {
+	pr := newTestPR(model.UpdateTypeMajor, 1)
+	pr.Mergeable = false
+	pr.Draft = true
+
+	checks := []model.CheckRun{
+		{Name: "build", Status: "completed", Conclusion: "success"},
+		{Name: "lint", Status: "completed", Conclusion: "failure"},
+		{Name: "e2e", Status: "in_progress"},
+	}
+
+	trace := ExplainProfile(&ProfileBalanced, pr, checks)
+
+	if trace.Allowed {
+		t.Fatal("expected trace to be denied")
+	}
+
+	failed := make(map[string]bool)
+	for _, c := range trace.FailedConditions() {
+		failed[c.Name] = true
+	}
+
+	for _, name := range []string{
+		ConditionMinAge,
+		ConditionUpdateType,
+		ConditionCheckPrefix + "lint",
+		ConditionCheckPrefix + "e2e",
+		ConditionMergeable,
+		ConditionDraft,
+	} {
+		if !failed[name] {
+			t.Errorf("expected condition %s to fail", name)
+		}
+	}
+
+	if failed[ConditionCheckPrefix+"build"] {
+		t.Error("expected build check to pass")
+	}
+
+	// Every condition, passing or not, is recorded.
+	if got, want := len(trace.Conditions), 7; got != want {
+		t.Errorf("expected %d conditions, got %d", want, got)
+	}
+}
+
+func TestExplainProfile_MatchesEvaluateProfile(t *testing.T) {
+	pr := newTestPR(model.UpdateTypePatch, 48)
+	pr.Mergeable = false
+
+	allowed, reason := EvaluateProfile(&ProfileBalanced, pr, passingChecks())
+	trace := ExplainProfile(&ProfileBalanced, pr, passingChecks())
+
+	if allowed != trace.Allowed {
+		t.Fatalf("expected allowed=%v to match trace %v", allowed, trace.Allowed)
+	}
+
+	decision := trace.Decision()
+	if len(decision.Reasons) != 1 || decision.Reasons[0] != reason {
+		t.Errorf("expected reasons [%s], got %v", reason, decision.Reasons)
+	}
+}
+
+func TestExplainProfile_MinReleaseAge(t *testing.T) {
+	profile := ProfileBalanced
+	profile.MinReleaseAgeHours = 72
+
+	released := func(hours int) *time.Time {
+		t := time.Now().Add(-time.Duration(hours) * time.Hour)
+		return &t
+	}
+
+	tests := []struct {
+		name       string
+		released   *time.Time
+		wantReason string
+	}{
+		{"old release", released(100), ""},
+		{"recent release", released(10), "github.com/example/pkg v1.2.3 was released too recently"},
+		{"unknown release", nil, "publish time of github.com/example/pkg v1.2.3 is unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := newTestPR(model.UpdateTypePatch, 48)
+			pr.Dependency.ToVersion = "v1.2.3"
+			pr.Dependency.ReleasedAt = tt.released
+
+			trace := ExplainProfile(&profile, pr, passingChecks())
+			if wantPass := tt.wantReason == ""; trace.Allowed != wantPass {
+				t.Errorf("expected allowed=%v, got %v (failed: %v)", wantPass, trace.Allowed, trace.FailedConditions())
+			}
+			if tt.wantReason != "" {
+				if got := trace.Decision().Reasons[0]; got != tt.wantReason {
+					t.Errorf("expected reason %q, got %q", tt.wantReason, got)
+				}
+			}
+
+			pctx := NewContextBuilder().Build(pr, nil, passingChecks())
+			if tt.released == nil && pctx.Dependency.ReleaseAgeHours != -1 {
+				t.Errorf("expected unknown release age to be -1, got %d", pctx.Dependency.ReleaseAgeHours)
+			}
+		})
+	}
+
+	pr := newTestPR(model.UpdateTypePatch, 48)
+	pr.Dependencies = []model.Dependency{
+		{Name: "golang.org/x/net", ToVersion: "v0.30.0", UpdateType: model.UpdateTypePatch, ReleasedAt: released(100)},
+		{Name: "golang.org/x/sys", ToVersion: "v0.26.0", UpdateType: model.UpdateTypePatch, ReleasedAt: released(1)},
+	}
+	failed := ExplainProfile(&profile, pr, passingChecks()).FailedConditions()
+	if len(failed) != 1 || failed[0].Message != "golang.org/x/sys v0.26.0 was released too recently" {
+		t.Errorf("expected only the recent group member to fail, got %v", failed)
+	}
+}
+
+func TestExplainProfile_SecurityFixesSkipAge(t *testing.T) {
+	profile := ProfileBalanced
+	profile.MinReleaseAgeHours = 72
+	released := time.Now().Add(-time.Hour)
+
+	pr := newTestPR(model.UpdateTypePatch, 1)
+	pr.Dependency.ReleasedAt = &released
+	pr.Dependency.Severity = model.SeverityHigh
+
+	failed := ExplainProfile(&profile, pr, passingChecks()).FailedConditions()
+	if len(failed) != 2 || failed[0].Name != ConditionMinAge || failed[1].Name != ConditionReleaseAge {
+		t.Errorf("expected young security fix to wait without SecurityFixesSkipAge, got %v", failed)
+	}
+
+	profile.SecurityFixesSkipAge = true
+	if trace := ExplainProfile(&profile, pr, passingChecks()); !trace.Allowed {
+		t.Errorf("expected security fix to skip the minimum ages, got %v", trace.FailedConditions())
+	}
+
+	pctx := NewContextBuilder().Build(pr, nil, passingChecks())
+	if !pctx.Dependency.FixesVulnerabilities || pctx.Dependency.Severity != "high" {
+		t.Errorf("expected context to show a high severity fix, got %+v", pctx.Dependency)
+	}
+
+	pr.Dependency.Severity = model.SeverityNone
+	if trace := ExplainProfile(&profile, pr, passingChecks()); trace.Allowed {
+		t.Error("expected update fixing no vulnerability to wait")
+	}
+	if pctx := NewContextBuilder().Build(pr, nil, passingChecks()); pctx.Dependency.FixesVulnerabilities || pctx.Dependency.Severity != "none" {
+		t.Errorf("expected context to show no fix, got %+v", pctx.Dependency)
+	}
+}
+
+func TestExplainProfile_AllowPendingChecks(t *testing.T) {
+	profile := ProfileAggressive
+	profile.AllowPendingChecks = true
+
+	pr := newTestPR(model.UpdateTypeMinor, 0)
+	checks := []model.CheckRun{{Name: "e2e", Status: "queued"}}
+
+	trace := ExplainProfile(&profile, pr, checks)
+	if !trace.Allowed {
+		t.Errorf("expected pending checks to be allowed, failed: %v", trace.FailedConditions())
+	}
+}
+
+func TestExplainProfile_RequiredChecks(t *testing.T) {
+	profile := ProfileBalanced
+	profile.RequiredChecks = []string{"build", "test"}
+
+	pr := newTestPR(model.UpdateTypePatch, 48)
+	checks := []model.CheckRun{
+		{Name: "build", Status: "completed", Conclusion: "success"},
+		{Name: "lint", Status: "completed", Conclusion: "failure"},
+	}
+
+	trace := ExplainProfile(&profile, pr, checks)
+	if trace.Allowed {
+		t.Fatal("expected missing required check to deny the merge")
+	}
+
+	failed := trace.FailedConditions()
+	if len(failed) != 1 {
+		t.Fatalf("expected only the missing check to fail, got %v", failed)
+	}
+	if failed[0].Name != ConditionCheckPrefix+"test" || failed[0].Value != checkMissing {
+		t.Errorf("expected test to be reported missing, got %+v", failed[0])
+	}
+	if got, want := failed[0].Message, "required check missing: test"; got != want {
+		t.Errorf("expected message %q, got %q", want, got)
+	}
+
+	// Checks outside the required set are not evaluated.
+	checks = append(checks, model.CheckRun{Name: "test", Status: "completed", Conclusion: "success"})
+	if trace := ExplainProfile(&profile, pr, checks); !trace.Allowed {
+		t.Errorf("expected merge to be allowed, failed: %v", trace.FailedConditions())
+	}
+}
+
+func TestExplainProfile_RequiredCheckPending(t *testing.T) {
+	profile := ProfileBalanced
+	profile.RequiredChecks = []string{"build"}
+
+	pr := newTestPR(model.UpdateTypePatch, 48)
+	checks := []model.CheckRun{{Name: "build", Status: "in_progress"}}
+
+	trace := ExplainProfile(&profile, pr, checks)
+	failed := trace.FailedConditions()
+	if len(failed) != 1 || failed[0].Value == checkMissing {
+		t.Fatalf("expected build to be reported pending, got %v", failed)
+	}
+}
+
+func TestEngine_ExplainReview(t *testing.T) {
+	engine := NewEngineWithProfile(&ProfileConservative)
+
+	pr := newTestPR(model.UpdateTypeMinor, 100)
+	pr.TestsPassed = false
+
+	trace, err := engine.Explain(context.Background(), model.PolicyActionReview, pr, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if trace.Profile != "conservative" {
+		t.Errorf("expected profile conservative, got %s", trace.Profile)
+	}
+
+	var names []string
+	for _, c := range trace.FailedConditions() {
+		names = append(names, c.Name)
+	}
+	if len(names) != 2 || names[0] != ConditionTestsPassed || names[1] != ConditionUpdateType {
+		t.Errorf("expected tests-passed and update-type to fail, got %v", names)
+	}
+}
+
+func TestEngine_ExplainUnsupportedAction(t *testing.T) {
+	engine := NewEngineWithProfile(&ProfileBalanced)
+
+	if _, err := engine.Explain(context.Background(), model.PolicyActionRelease, newTestPR(model.UpdateTypePatch, 1), nil); err == nil {
+		t.Error("expected error explaining release without Cedar policies")
+	}
+}
@@ -0,0 +1,286 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// Fixture is a policy test case: an input PR or policy context and the
+// decision the policy engine is expected to return for it.
+//
+// Fixtures are written in YAML or JSON using the same field names as the
+// JSON output of the model types. Either PR (with optional Checks) or
+// Context must be set.
+type Fixture struct {
+	Name    string               `json:"name"`
+	Action  model.PolicyAction   `json:"action"`
+	PR      *model.PullRequest   `json:"pr,omitempty"`
+	Checks  []model.CheckRun     `json:"checks,omitempty"`
+	Context *model.PolicyContext `json:"context,omitempty"`
+
+	// AgeHours sets the PR's creation time relative to now, so fixtures
+	// don't depend on when they are run.
+	AgeHours *int `json:"ageHours,omitempty"`
+
+	// Expect is the expected decision. Allowed is always compared;
+	// Reasons, the codes of Details, and Policies are compared only when
+	// set.
+	Expect model.PolicyDecision `json:"expect"`
+
+	// File is the fixture file the test case was loaded from.
+	File string `json:"-"`
+}
+
+// fixtureExts are the file extensions recognized as fixture files.
+var fixtureExts = []string{".yaml", ".yml", ".json"}
+
+// LoadFixtures loads policy test fixtures from a file or directory.
+// Directories are searched recursively. YAML files may hold several
+// fixtures separated by "---".
+func LoadFixtures(path string) ([]Fixture, error) {
+	cleanPath := filepath.Clean(path)
+	info, err := os.Stat(cleanPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture path: %w", err)
+	}
+
+	var files []string
+	if info.IsDir() {
+		err := filepath.WalkDir(cleanPath, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && slices.Contains(fixtureExts, strings.ToLower(filepath.Ext(p))) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture directory: %w", err)
+		}
+		sort.Strings(files)
+	} else {
+		files = []string{cleanPath}
+	}
+
+	var fixtures []Fixture
+	for _, file := range files {
+		loaded, err := loadFixtureFile(file)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, loaded...)
+	}
+
+	return fixtures, nil
+}
+
+// loadFixtureFile loads all fixtures from a single file.
+func loadFixtureFile(file string) ([]Fixture, error) {
+	data, err := os.ReadFile(file) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture file: %w", err)
+	}
+
+	var fixtures []Fixture
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+
+	for i := 0; ; i++ {
+		var doc any
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse fixture file %s: %w", file, err)
+		}
+		if doc == nil {
+			continue
+		}
+
+		// Round-trip through JSON so fixtures use the model's JSON field names.
+		jsonData, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse fixture file %s: %w", file, err)
+		}
+
+		var f Fixture
+		if err := json.Unmarshal(jsonData, &f); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture file %s: %w", file, err)
+		}
+
+		f.File = file
+		if f.Name == "" {
+			f.Name = fmt.Sprintf("%s[%d]", filepath.Base(file), i)
+		}
+		if f.Action == "" {
+			f.Action = model.PolicyActionMerge
+		}
+
+		fixtures = append(fixtures, f)
+	}
+
+	return fixtures, nil
+}
+
+// RunFixtures evaluates each fixture with the engine and compares the
+// result with the expected decision.
+func (e *Engine) RunFixtures(ctx context.Context, fixtures []Fixture) *model.PolicyTestReport {
+	report := &model.PolicyTestReport{
+		Timestamp: time.Now(),
+		Profile:   e.profile.Name,
+		Policies:  e.policies.IDs(),
+	}
+
+	for _, f := range fixtures {
+		result := model.PolicyTestResult{
+			Name:     f.Name,
+			File:     f.File,
+			Action:   string(f.Action),
+			Expected: f.Expect,
+		}
+
+		actual, err := e.evaluateFixture(ctx, f)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Actual = actual
+			result.Mismatches = compareDecisions(&f.Expect, actual)
+			result.Passed = len(result.Mismatches) == 0
+		}
+
+		if result.Passed {
+			report.PassedCount++
+		} else {
+			report.FailedCount++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+// evaluateFixture evaluates a single fixture.
+func (e *Engine) evaluateFixture(ctx context.Context, f Fixture) (*model.PolicyDecision, error) {
+	switch {
+	case f.PR != nil:
+		pr := *f.PR
+		if f.AgeHours != nil {
+			pr.CreatedAt = time.Now().Add(-time.Duration(*f.AgeHours) * time.Hour)
+		}
+		if len(f.Checks) > 0 {
+			pr.TestsPassed = allChecksPassed(f.Checks)
+		}
+		return e.Evaluate(ctx, f.Action, &pr, f.Checks)
+	case f.Context != nil:
+		pr, checks := prFromContext(f.Context)
+		if e.HasCedarPolicies() {
+			// Run the same profile checks merge and review run before
+			// the policies, then evaluate the context as given.
+			if d := e.preCheck(f.Action, pr); d != nil {
+				return d, nil
+			}
+			return e.EvaluateContext(ctx, f.Action, f.Context)
+		}
+		return e.Evaluate(ctx, f.Action, pr, checks)
+	default:
+		return nil, fmt.Errorf("fixture must set pr or context")
+	}
+}
+
+// compareDecisions returns a description of each difference between the
+// expected and actual decisions.
+func compareDecisions(expected, actual *model.PolicyDecision) []string {
+	var mismatches []string
+
+	if expected.Allowed != actual.Allowed {
+		mismatches = append(mismatches, fmt.Sprintf("allowed: expected %t, got %t", expected.Allowed, actual.Allowed))
+	}
+	if len(expected.Reasons) > 0 && !slices.Equal(expected.Reasons, actual.Reasons) {
+		mismatches = append(mismatches, fmt.Sprintf("reasons: expected %q, got %q", expected.Reasons, actual.Reasons))
+	}
+	if len(expected.Details) > 0 && !slices.Equal(expected.Codes(), actual.Codes()) {
+		mismatches = append(mismatches, fmt.Sprintf("codes: expected %q, got %q", expected.Codes(), actual.Codes()))
+	}
+	if len(expected.Policies) > 0 && !slices.Equal(expected.Policies, actual.Policies) {
+		mismatches = append(mismatches, fmt.Sprintf("policies: expected %q, got %q", expected.Policies, actual.Policies))
+	}
+
+	return mismatches
+}
+
+// prFromContext reconstructs a PR and its checks from a policy context so
+// context-only fixtures can be evaluated against merge profiles. The
+// context doesn't list changed files or commit authors, so the contents
+// check is skipped for it.
+func prFromContext(pctx *model.PolicyContext) (*model.PullRequest, []model.CheckRun) {
+	pr := &model.PullRequest{
+		Number:       pctx.PR.Number,
+		Title:        pctx.PR.Title,
+		Author:       pctx.PR.Author,
+		IsDependency: pctx.PR.IsDependency,
+		DependBot:    model.DependBot(pctx.PR.DependBot),
+		Dependency: model.Dependency{
+			Name:        pctx.Dependency.Name,
+			Ecosystem:   pctx.Dependency.Ecosystem,
+			FromVersion: pctx.Dependency.FromVersion,
+			ToVersion:   pctx.Dependency.ToVersion,
+			UpdateType:  model.UpdateType(pctx.Dependency.UpdateType),
+			DepType:     model.DependencyType(pctx.Dependency.DepType),
+			Manager:     pctx.Dependency.Manager,
+		},
+		TestsPassed: pctx.CI.AllPassed,
+		Mergeable:   pctx.PR.Mergeable,
+		Draft:       pctx.PR.Draft,
+		Labels:      pctx.PR.Labels,
+		CreatedAt:   time.Now().Add(-time.Duration(pctx.PR.AgeHours) * time.Hour),
+		Repo:        model.RepoRef{Owner: pctx.Repo.Owner, Name: pctx.Repo.Name},
+	}
+	if pctx.PR.HasConflicts {
+		pr.MergeableStr = "dirty"
+	}
+	// The context only counts approvals, which is all the approvals
+	// check needs.
+	for i := range pctx.PR.Approvals {
+		pr.Approvers = append(pr.Approvers, fmt.Sprintf("approver-%d", i+1))
+	}
+
+	var checks []model.CheckRun
+	for _, name := range pctx.CI.PassedChecks {
+		checks = append(checks, model.CheckRun{Name: name, Status: "completed", Conclusion: "success"})
+	}
+	for _, name := range pctx.CI.FailedChecks {
+		checks = append(checks, model.CheckRun{Name: name, Status: "completed", Conclusion: "failure"})
+	}
+	for _, name := range pctx.CI.PendingChecks {
+		checks = append(checks, model.CheckRun{Name: name, Status: "in_progress"})
+	}
+
+	return pr, checks
+}
+
+// allChecksPassed returns true if there is at least one check and all passed.
+func allChecksPassed(checks []model.CheckRun) bool {
+	if len(checks) == 0 {
+		return false
+	}
+	for _, c := range checks {
+		if !c.IsSuccess() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,145 @@
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestLoadFixtures(t *testing.T) {
+	fixtures, err := LoadFixtures("testdata/fixtures")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fixtures) != 4 {
+		t.Fatalf("expected 4 fixtures, got %d", len(fixtures))
+	}
+
+	f := fixtures[0]
+	if f.Name != "patch update merges after a day" {
+		t.Errorf("unexpected name: %s", f.Name)
+	}
+	if f.PR == nil || f.PR.Dependency.UpdateType != model.UpdateTypePatch {
+		t.Errorf("expected patch PR, got %+v", f.PR)
+	}
+	if f.AgeHours == nil || *f.AgeHours != 48 {
+		t.Errorf("expected ageHours 48, got %v", f.AgeHours)
+	}
+	if f.File != filepath.Join("testdata", "fixtures", "balanced.yaml") {
+		t.Errorf("unexpected file: %s", f.File)
+	}
+
+	if fixtures[2].Context == nil || fixtures[2].Action != model.PolicyActionReview {
+		t.Errorf("expected review context fixture, got %+v", fixtures[2])
+	}
+}
+
+func TestLoadFixtures_Defaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "default.json")
+	if err := os.WriteFile(path, []byte(`{"pr": {"number": 1}, "expect": {"allowed": false}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures, err := LoadFixtures(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fixtures) != 1 {
+		t.Fatalf("expected 1 fixture, got %d", len(fixtures))
+	}
+	if fixtures[0].Name != "default.json[0]" {
+		t.Errorf("expected generated name, got %s", fixtures[0].Name)
+	}
+	if fixtures[0].Action != model.PolicyActionMerge {
+		t.Errorf("expected default action merge, got %s", fixtures[0].Action)
+	}
+}
+
+func TestEngine_RunFixtures(t *testing.T) {
+	fixtures, err := LoadFixtures("testdata/fixtures")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report := NewEngineWithProfile(&ProfileBalanced).RunFixtures(context.Background(), fixtures)
+
+	if report.FailedCount != 0 {
+		for _, r := range report.Results {
+			if !r.Passed {
+				t.Errorf("fixture %q failed: %v %s", r.Name, r.Mismatches, r.Error)
+			}
+		}
+	}
+	if report.PassedCount != 4 {
+		t.Errorf("expected 4 passed, got %d", report.PassedCount)
+	}
+}
+
+func TestEngine_RunFixturesMismatch(t *testing.T) {
+	fixtures := []Fixture{
+		{
+			Name:   "wrong expectation",
+			Action: model.PolicyActionMerge,
+			PR:     newTestPR(model.UpdateTypeMajor, 48),
+			Checks: passingChecks(),
+			Expect: model.PolicyDecision{Allowed: true},
+		},
+		{
+			Name:   "no input",
+			Action: model.PolicyActionMerge,
+		},
+	}
+
+	report := NewEngineWithProfile(&ProfileBalanced).RunFixtures(context.Background(), fixtures)
+
+	if report.FailedCount != 2 {
+		t.Fatalf("expected 2 failures, got %d", report.FailedCount)
+	}
+	if len(report.Results[0].Mismatches) != 1 {
+		t.Errorf("expected 1 mismatch, got %v", report.Results[0].Mismatches)
+	}
+	if report.Results[1].Error == "" {
+		t.Error("expected error for fixture without pr or context")
+	}
+}
+
+func TestEngine_RunFixturesContextPreChecks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "allow.cedar"), []byte("permit(principal, action, resource);\n"), 0o600); err != nil {
+		t.Fatalf("failed to write policy: %v", err)
+	}
+
+	profile := ProfileBalanced
+	profile.RequiredApprovals = 1
+	engine, err := NewEngineWithConfig(EngineConfig{Profile: &profile, PolicyPaths: []string{dir}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fixtures := []Fixture{
+		{
+			Name:    "unapproved",
+			Action:  model.PolicyActionMerge,
+			Context: &model.PolicyContext{PR: model.PRContext{Number: 1}},
+			Expect:  model.PolicyDecision{Allowed: false, Details: []model.Reason{{Code: model.ReasonApprovalsMissing}}},
+		},
+		{
+			Name:    "approved",
+			Action:  model.PolicyActionMerge,
+			Context: &model.PolicyContext{PR: model.PRContext{Number: 1, Approvals: 1}},
+			Expect:  model.PolicyDecision{Allowed: true},
+		},
+	}
+
+	report := engine.RunFixtures(context.Background(), fixtures)
+	for _, r := range report.Results {
+		if !r.Passed {
+			t.Errorf("fixture %q failed: %v %s", r.Name, r.Mismatches, r.Error)
+		}
+	}
+}
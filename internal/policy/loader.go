@@ -1,7 +1,10 @@
 package policy
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -35,6 +38,53 @@ func LoadProfileFromBytes(data []byte) (*model.MergeProfile, error) {
 	return &profile, nil
 }
 
+// ResolveProfile returns a copy of the built-in profile with the given
+// name or, if name is the path of a .yaml or .yml file, the profile in
+// that file. A profile file starts from the built-in profile named by its
+// extends field, balanced by default, so it only sets what it changes.
+// Unknown fields are rejected so that typos don't silently loosen policy.
+func ResolveProfile(name string) (*model.MergeProfile, error) {
+	if ext := filepath.Ext(name); ext != ".yaml" && ext != ".yml" {
+		profile := GetProfile(name)
+		if profile == nil {
+			return nil, fmt.Errorf("unknown profile: %s", name)
+		}
+		p := *profile
+		return &p, nil
+	}
+
+	data, err := os.ReadFile(filepath.Clean(name)) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile file: %w", err)
+	}
+
+	var header struct {
+		Extends string `yaml:"extends"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse profile file: %w", err)
+	}
+	if header.Extends == "" {
+		header.Extends = "balanced"
+	}
+	base := GetProfile(header.Extends)
+	if base == nil {
+		return nil, fmt.Errorf("profile file %s extends unknown profile: %s", name, header.Extends)
+	}
+
+	file := struct {
+		Extends            string `yaml:"extends"`
+		model.MergeProfile `yaml:",inline"`
+	}{MergeProfile: *base}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse profile file: %w", err)
+	}
+
+	return &file.MergeProfile, nil
+}
+
 // SaveProfileToFile saves a merge profile to a YAML file.
 func SaveProfileToFile(profile *model.MergeProfile, path string) error {
 	data, err := yaml.Marshal(profile)
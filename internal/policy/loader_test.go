@@ -0,0 +1,75 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestResolveProfile(t *testing.T) {
+	profile, err := ResolveProfile("conservative")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	profile.MaxPRsPerRun = 100
+	if ProfileConservative.MaxPRsPerRun == 100 {
+		t.Error("expected a copy of the built-in profile")
+	}
+
+	if _, err := ResolveProfile("reckless"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
+
+func TestResolveProfile_File(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	profile, err := ResolveProfile(write("rules.yaml", `
+extends: conservative
+minAgeHours: 12
+dependencyRules:
+  - match: golang.org/x/*
+    action: allow
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.MinAgeHours != 12 {
+		t.Errorf("expected minAgeHours 12, got %d", profile.MinAgeHours)
+	}
+	if !profile.RequireApproval || profile.AutoMergeMinor {
+		t.Errorf("expected unset fields from the conservative profile, got %+v", profile)
+	}
+	if len(profile.DependencyRules) != 1 || profile.DependencyRules[0].Action != model.RuleActionAllow {
+		t.Errorf("expected one allow rule, got %+v", profile.DependencyRules)
+	}
+
+	profile, err = ResolveProfile(write("empty.yml", ""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.MinAgeHours != ProfileBalanced.MinAgeHours || !profile.RequireAllChecks {
+		t.Errorf("expected the balanced profile, got %+v", profile)
+	}
+
+	for name, data := range map[string]string{
+		"typo.yaml":    "autoMergeMinr: true\n",
+		"extends.yaml": "extends: reckless\n",
+	} {
+		if _, err := ResolveProfile(write(name, data)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if _, err := ResolveProfile(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
@@ -94,65 +94,39 @@ func ListProfiles() []string {
 
 // EvaluateProfile evaluates a PR against a merge profile.
 // Returns true if the PR should be merged according to the profile.
+// The reason is the message of the first failing condition; use
+// ExplainProfile to see every condition.
 func EvaluateProfile(profile *model.MergeProfile, pr *model.PullRequest, checks []model.CheckRun) (bool, string) {
-	// Check age requirements
-	ageHours := pr.AgeHours()
-	if profile.MinAgeHours > 0 && ageHours < profile.MinAgeHours {
-		return false, "PR is too young"
-	}
-	if profile.MaxAgeHours > 0 && ageHours > profile.MaxAgeHours {
-		return false, "PR is too old"
-	}
+	return firstFailure(ExplainProfile(profile, pr, checks))
+}
 
-	// Check update type
-	switch pr.Dependency.UpdateType {
-	case model.UpdateTypeMajor:
-		if !profile.AutoMergeMajor {
-			return false, "major updates require manual review"
-		}
-	case model.UpdateTypeMinor:
-		if !profile.AutoMergeMinor {
-			return false, "minor updates require manual review"
-		}
-	case model.UpdateTypePatch:
-		if !profile.AutoMergePatch {
-			return false, "patch updates require manual review"
-		}
-	default:
-		return false, "unknown update type"
-	}
+// EvaluateReviewProfile evaluates whether a PR should receive an approval
+// review according to a merge profile.
+func EvaluateReviewProfile(profile *model.MergeProfile, pr *model.PullRequest, checks []model.CheckRun) (bool, string) {
+	return firstFailure(ExplainReviewProfile(profile, pr, checks))
+}
 
-	// Check CI status
-	if profile.RequireAllChecks {
-		allPassed := true
-		anyPending := false
-
-		for _, c := range checks {
-			if c.Status != "completed" {
-				anyPending = true
-				allPassed = false
-			} else if !c.IsSuccess() {
-				return false, "CI checks failed"
-			}
-		}
-
-		if anyPending && !profile.AllowPendingChecks {
-			return false, "CI checks still pending"
-		}
-
-		if !allPassed && !anyPending {
-			return false, "CI checks failed"
-		}
+// firstFailureDecision summarizes a trace as a decision reporting only
+// the first failed condition.
+func firstFailureDecision(trace *model.PolicyTrace) *model.PolicyDecision {
+	result := &model.PolicyDecision{
+		Allowed: true,
+		Action:  trace.Action,
 	}
-
-	// Check mergeable status
-	if !pr.Mergeable {
-		return false, "PR is not mergeable"
+	failed := trace.FailedConditions()
+	if len(failed) > 0 {
+		result.Allowed = false
+		result.NextWindow = trace.NextWindow
+		addReason(result, failed[0].Reason())
 	}
+	return result
+}
 
-	if pr.Draft {
-		return false, "PR is a draft"
+// firstFailure returns whether a trace passed and the first failure message.
+func firstFailure(trace *model.PolicyTrace) (bool, string) {
+	failed := trace.FailedConditions()
+	if len(failed) == 0 {
+		return true, ""
 	}
-
-	return true, ""
+	return false, failed[0].Message
 }
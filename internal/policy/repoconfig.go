@@ -0,0 +1,98 @@
+package policy
+
+import (
+	"slices"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// ApplyRepoConfig returns a copy of profile with the repository config's
+// overrides, dependency lists and rules applied. The base profile is not modified.
+func ApplyRepoConfig(profile *model.MergeProfile, cfg *model.RepoConfig) *model.MergeProfile {
+	p := *profile
+	p.RequiredChecks = slices.Clone(profile.RequiredChecks)
+	p.AllowDependencies = slices.Clone(profile.AllowDependencies)
+	p.DenyDependencies = slices.Clone(profile.DenyDependencies)
+	p.DependencyRules = slices.Clone(profile.DependencyRules)
+	p.MergeWindows = slices.Clone(profile.MergeWindows)
+	p.Freezes = slices.Clone(profile.Freezes)
+
+	if cfg == nil {
+		return &p
+	}
+
+	o := cfg.Overrides
+	if o.MinAgeHours != nil {
+		p.MinAgeHours = *o.MinAgeHours
+	}
+	if o.MaxAgeHours != nil {
+		p.MaxAgeHours = *o.MaxAgeHours
+	}
+	if o.MinReleaseAgeHours != nil {
+		p.MinReleaseAgeHours = *o.MinReleaseAgeHours
+	}
+	if o.SecurityFixesSkipAge != nil {
+		p.SecurityFixesSkipAge = *o.SecurityFixesSkipAge
+	}
+	if o.AutoMergePatch != nil {
+		p.AutoMergePatch = *o.AutoMergePatch
+	}
+	if o.AutoMergeMinor != nil {
+		p.AutoMergeMinor = *o.AutoMergeMinor
+	}
+	if o.AutoMergeMajor != nil {
+		p.AutoMergeMajor = *o.AutoMergeMajor
+	}
+	if o.RequireAllChecks != nil {
+		p.RequireAllChecks = *o.RequireAllChecks
+	}
+	if o.RequiredChecks != nil {
+		p.RequiredChecks = slices.Clone(o.RequiredChecks)
+	}
+	if o.AllowPendingChecks != nil {
+		p.AllowPendingChecks = *o.AllowPendingChecks
+	}
+	if o.MergeStrategy != nil {
+		p.MergeStrategy = *o.MergeStrategy
+	}
+	if o.DeleteBranch != nil {
+		p.DeleteBranch = *o.DeleteBranch
+	}
+	if o.RequireApproval != nil {
+		p.RequireApproval = *o.RequireApproval
+	}
+	if o.MaxPRsPerRun != nil {
+		p.MaxPRsPerRun = *o.MaxPRsPerRun
+	}
+	if o.MaxRiskScore != nil {
+		p.MaxRiskScore = *o.MaxRiskScore
+	}
+	if o.MergeWindows != nil {
+		p.MergeWindows = slices.Clone(o.MergeWindows)
+	}
+	p.Freezes = append(p.Freezes, o.Freezes...)
+
+	p.AllowDependencies = append(p.AllowDependencies, cfg.Dependencies.Allow...)
+	p.DenyDependencies = append(p.DenyDependencies, cfg.Dependencies.Deny...)
+
+	// Repository rules are more specific, so they are checked first.
+	p.DependencyRules = append(slices.Clone(cfg.Dependencies.Rules), p.DependencyRules...)
+
+	return &p
+}
+
+// ApplyBranchProtection adds the checks required by branch protection to
+// the profile's required checks and raises its required approvals to the
+// protection's, so merges honour the same rules GitHub enforces. The
+// profile is modified in place.
+func ApplyBranchProtection(profile *model.MergeProfile, protection *model.BranchProtection) {
+	if protection == nil {
+		return
+	}
+	for _, name := range protection.RequiredChecks {
+		if !slices.Contains(profile.RequiredChecks, name) {
+			profile.RequiredChecks = append(profile.RequiredChecks, name)
+		}
+	}
+	profile.RequiredApprovals = max(profile.RequiredApprovals, protection.RequiredApprovals)
+}
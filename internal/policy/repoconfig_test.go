@@ -0,0 +1,98 @@
+package policy
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestApplyRepoConfig(t *testing.T) {
+	minAge := 72
+	noMinor := false
+	cfg := &model.RepoConfig{
+		Overrides: model.ProfileOverrides{
+			MinAgeHours:    &minAge,
+			AutoMergeMinor: &noMinor,
+		},
+		Dependencies: model.DependencyLists{
+			Deny: []string{"github.com/aws/*"},
+		},
+	}
+
+	p := ApplyRepoConfig(&ProfileBalanced, cfg)
+
+	if p.MinAgeHours != 72 || p.AutoMergeMinor {
+		t.Errorf("expected overrides applied, got minAge=%d autoMergeMinor=%v", p.MinAgeHours, p.AutoMergeMinor)
+	}
+	if !p.AutoMergePatch || p.MergeStrategy != "squash" {
+		t.Error("expected unset fields to keep base profile values")
+	}
+	if len(p.DenyDependencies) != 1 {
+		t.Errorf("expected deny list applied, got %v", p.DenyDependencies)
+	}
+
+	if ProfileBalanced.MinAgeHours != 24 || len(ProfileBalanced.DenyDependencies) != 0 {
+		t.Error("expected base profile to be unchanged")
+	}
+}
+
+func TestExplainProfile_DependencyLists(t *testing.T) {
+	tests := []struct {
+		name       string
+		allow      []string
+		deny       []string
+		dependency string
+		wantPass   bool
+	}{
+		{"denied by glob", nil, []string{"github.com/aws/*"}, "github.com/aws/smithy-go", false},
+		{"glob crosses slash", nil, []string{"github.com/aws/*"}, "github.com/aws/aws-sdk-go-v2/config", false},
+		{"glob prefix only", nil, []string{"github.com/aws/*"}, "github.com/awslabs/smithy", true},
+		{"allowed", []string{"golang.org/x/*"}, nil, "golang.org/x/net", true},
+		{"not in allow list", []string{"golang.org/x/*"}, nil, "github.com/example/pkg", false},
+		{"deny wins over allow", []string{"golang.org/x/*"}, []string{"golang.org/x/crypto"}, "golang.org/x/crypto", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := ProfileBalanced
+			profile.AllowDependencies = tt.allow
+			profile.DenyDependencies = tt.deny
+
+			pr := newTestPR(model.UpdateTypePatch, 48)
+			pr.Dependency.Name = tt.dependency
+
+			trace := ExplainProfile(&profile, pr, passingChecks())
+			if trace.Allowed != tt.wantPass {
+				t.Errorf("expected allowed=%v, got %v (failed: %v)", tt.wantPass, trace.Allowed, trace.FailedConditions())
+			}
+		})
+	}
+}
+
+func TestApplyBranchProtection(t *testing.T) {
+	profile := ApplyRepoConfig(&ProfileBalanced, &model.RepoConfig{
+		Overrides: model.ProfileOverrides{RequiredChecks: []string{"build"}},
+	})
+
+	ApplyBranchProtection(profile, &model.BranchProtection{
+		Branch:            "main",
+		RequiredChecks:    []string{"build", "test"},
+		RequiredApprovals: 2,
+	})
+
+	if want := []string{"build", "test"}; !slices.Equal(profile.RequiredChecks, want) {
+		t.Errorf("expected required checks %v, got %v", want, profile.RequiredChecks)
+	}
+	if profile.RequiredApprovals != 2 {
+		t.Errorf("expected 2 required approvals, got %d", profile.RequiredApprovals)
+	}
+	if len(ProfileBalanced.RequiredChecks) != 0 {
+		t.Error("expected base profile to be unchanged")
+	}
+
+	ApplyBranchProtection(profile, nil)
+	if len(profile.RequiredChecks) != 2 {
+		t.Errorf("expected nil protection to be a no-op, got %v", profile.RequiredChecks)
+	}
+}
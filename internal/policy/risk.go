@@ -0,0 +1,132 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// Risk factor names.
+const (
+	RiskUpdateType   = "update-type"
+	RiskDependents   = "dependents"
+	RiskCICoverage   = "ci-coverage"
+	RiskReleaseAge   = "release-age"
+	RiskUnstable     = "unstable-version"
+	RiskNonLockfiles = "non-lockfiles"
+)
+
+// RiskScorer rates the risk of merging a dependency PR.
+type RiskScorer interface {
+	Score(pr *model.PullRequest, checks []model.CheckRun) model.RiskScore
+}
+
+// DefaultRiskScorer adds up points for each risk factor of a PR, for a
+// score of at most 100:
+//
+//   - update type: 10 for minor, 25 for major or unknown updates
+//   - dependents: 5 per managed module depending on the repository, up to 20
+//   - CI coverage: 20 without checks, 10 if none has passed
+//   - release age: 15 if the new version is less than a day old, 10 if
+//     less than three days, 5 if less than a week
+//   - unstable version: 10 if the new version is a v0 version
+//   - non-lockfiles: 10 if the PR changes files other than manifests and
+//     lockfiles, such as Dockerfiles or workflows
+type DefaultRiskScorer struct {
+	// Dependents returns the number of managed modules depending on a
+	// repository. If nil, dependents are not scored.
+	Dependents func(repo model.RepoRef) int
+}
+
+// NewRiskScorer creates the default risk scorer, counting dependents with
+// the given function if not nil.
+func NewRiskScorer(dependents func(repo model.RepoRef) int) *DefaultRiskScorer {
+	return &DefaultRiskScorer{Dependents: dependents}
+}
+
+// Score implements RiskScorer.
+func (s *DefaultRiskScorer) Score(pr *model.PullRequest, checks []model.CheckRun) model.RiskScore {
+	risk := model.RiskScore{Factors: []model.RiskFactor{}}
+	add := func(name string, points int, detail string) {
+		if points <= 0 {
+			return
+		}
+		risk.Score += points
+		risk.Factors = append(risk.Factors, model.RiskFactor{Name: name, Points: points, Detail: detail})
+	}
+
+	switch updateType := model.HighestUpdateType(pr.Dependency.UpdateType); updateType {
+	case model.UpdateTypeMinor:
+		add(RiskUpdateType, 10, "minor update")
+	case model.UpdateTypeMajor, model.UpdateTypeUnknown:
+		add(RiskUpdateType, 25, string(updateType)+" update")
+	}
+
+	if s.Dependents != nil {
+		if n := s.Dependents(pr.Repo); n > 0 {
+			add(RiskDependents, min(5*n, 20), fmt.Sprintf("%d managed dependents", n))
+		}
+	}
+
+	switch {
+	case len(checks) == 0:
+		add(RiskCICoverage, 20, "no CI checks")
+	case !anyCheckPassed(checks):
+		add(RiskCICoverage, 10, "no CI check has passed")
+	}
+
+	if age := youngestRelease(pr); age >= 0 {
+		switch {
+		case age < 24:
+			add(RiskReleaseAge, 15, fmt.Sprintf("released %dh ago", age))
+		case age < 72:
+			add(RiskReleaseAge, 10, fmt.Sprintf("released %dh ago", age))
+		case age < 168:
+			add(RiskReleaseAge, 5, fmt.Sprintf("released %dh ago", age))
+		}
+	}
+
+	for _, dep := range pr.AllDependencies() {
+		if isUnstableVersion(dep.ToVersion) {
+			add(RiskUnstable, 10, fmt.Sprintf("%s %s is a v0 version", dep.Name, dep.ToVersion))
+			break
+		}
+	}
+
+	if other := DisallowedFiles(pr.Files, packageFiles); len(other) > 0 {
+		add(RiskNonLockfiles, 10, "changes "+strings.Join(other, ", "))
+	}
+
+	risk.Score = min(risk.Score, 100)
+	return risk
+}
+
+// anyCheckPassed reports whether any check has passed.
+func anyCheckPassed(checks []model.CheckRun) bool {
+	for _, c := range checks {
+		if c.IsSuccess() {
+			return true
+		}
+	}
+	return false
+}
+
+// youngestRelease returns the release age in hours of the most recently
+// published new version of a PR, or -1 if none is known.
+func youngestRelease(pr *model.PullRequest) int {
+	youngest := -1
+	for _, dep := range pr.AllDependencies() {
+		if age := dep.ReleaseAgeHours(); age >= 0 && (youngest < 0 || age < youngest) {
+			youngest = age
+		}
+	}
+	return youngest
+}
+
+// isUnstableVersion reports whether a version is a v0 version, which
+// promises no compatibility between releases.
+func isUnstableVersion(version string) bool {
+	major, _, ok := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	return ok && major == "0"
+}
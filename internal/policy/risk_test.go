@@ -0,0 +1,228 @@
+package policy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestDefaultRiskScorer(t *testing.T) {
+	released := func(hours int) *time.Time {
+		t := time.Now().Add(-time.Duration(hours) * time.Hour)
+		return &t
+	}
+
+	tests := []struct {
+		name        string
+		pr          func() *model.PullRequest
+		checks      []model.CheckRun
+		dependents  int
+		wantScore   int
+		wantFactors []string
+	}{
+		{
+			name:      "patch with passing checks",
+			pr:        func() *model.PullRequest { return newTestPR(model.UpdateTypePatch, 48) },
+			checks:    passingChecks(),
+			wantScore: 0,
+		},
+		{
+			name:        "major without checks",
+			pr:          func() *model.PullRequest { return newTestPR(model.UpdateTypeMajor, 48) },
+			wantScore:   45,
+			wantFactors: []string{RiskUpdateType, RiskCICoverage},
+		},
+		{
+			name: "minor with failing checks",
+			pr:   func() *model.PullRequest { return newTestPR(model.UpdateTypeMinor, 48) },
+			checks: []model.CheckRun{
+				{Name: "build", Status: "completed", Conclusion: "failure"},
+				{Name: "lint", Status: "in_progress"},
+			},
+			wantScore:   20,
+			wantFactors: []string{RiskUpdateType, RiskCICoverage},
+		},
+		{
+			name: "fresh v0 release",
+			pr: func() *model.PullRequest {
+				pr := newTestPR(model.UpdateTypePatch, 48)
+				pr.Dependency.ToVersion = "v0.4.1"
+				pr.Dependency.ReleasedAt = released(2)
+				return pr
+			},
+			checks:      passingChecks(),
+			wantScore:   25,
+			wantFactors: []string{RiskReleaseAge, RiskUnstable},
+		},
+		{
+			name: "release a few days old",
+			pr: func() *model.PullRequest {
+				pr := newTestPR(model.UpdateTypePatch, 48)
+				pr.Dependency.ToVersion = "v1.4.1"
+				pr.Dependency.ReleasedAt = released(100)
+				return pr
+			},
+			checks:      passingChecks(),
+			wantScore:   5,
+			wantFactors: []string{RiskReleaseAge},
+		},
+		{
+			name:        "managed dependents",
+			pr:          func() *model.PullRequest { return newTestPR(model.UpdateTypePatch, 48) },
+			checks:      passingChecks(),
+			dependents:  3,
+			wantScore:   15,
+			wantFactors: []string{RiskDependents},
+		},
+		{
+			name: "manifest and lockfile changes",
+			pr: func() *model.PullRequest {
+				pr := newTestPR(model.UpdateTypePatch, 48)
+				pr.Files = []string{"go.mod", "go.sum", "web/package.json", "web/package-lock.json"}
+				return pr
+			},
+			checks:    passingChecks(),
+			wantScore: 0,
+		},
+		{
+			name: "non-lockfile changes",
+			pr: func() *model.PullRequest {
+				pr := newTestPR(model.UpdateTypePatch, 48)
+				pr.Files = []string{"go.mod", "go.sum", "Dockerfile"}
+				return pr
+			},
+			checks:      passingChecks(),
+			wantScore:   10,
+			wantFactors: []string{RiskNonLockfiles},
+		},
+		{
+			name: "everything at once is capped",
+			pr: func() *model.PullRequest {
+				pr := newTestPR(model.UpdateTypeMajor, 48)
+				pr.Dependency.ToVersion = "v0.1.0"
+				pr.Dependency.ReleasedAt = released(1)
+				pr.Files = []string{".github/workflows/ci.yml"}
+				return pr
+			},
+			dependents:  10,
+			wantScore:   100,
+			wantFactors: []string{RiskUpdateType, RiskDependents, RiskCICoverage, RiskReleaseAge, RiskUnstable, RiskNonLockfiles},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scorer := NewRiskScorer(func(repo model.RepoRef) int {
+				if repo.FullName() != "example/repo" {
+					t.Errorf("unexpected repository %s", repo.FullName())
+				}
+				return tt.dependents
+			})
+
+			risk := scorer.Score(tt.pr(), tt.checks)
+			if risk.Score != tt.wantScore {
+				t.Errorf("expected score %d, got %d (factors: %+v)", tt.wantScore, risk.Score, risk.Factors)
+			}
+			if risk.Factors == nil {
+				t.Fatal("expected non-nil factors")
+			}
+
+			var names []string
+			for _, f := range risk.Factors {
+				names = append(names, f.Name)
+			}
+			if len(names) != len(tt.wantFactors) {
+				t.Fatalf("expected factors %v, got %v", tt.wantFactors, names)
+			}
+			for i := range names {
+				if names[i] != tt.wantFactors[i] {
+					t.Errorf("expected factors %v, got %v", tt.wantFactors, names)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestContextBuilder_Risk(t *testing.T) {
+	pr := newTestPR(model.UpdateTypeMajor, 48)
+
+	pctx := NewContextBuilder().Build(pr, nil, passingChecks())
+	if pctx.Risk.Score != 25 {
+		t.Errorf("expected risk score 25, got %d", pctx.Risk.Score)
+	}
+	if pctx.Risk.Factors == nil {
+		t.Error("expected non-nil risk factors")
+	}
+}
+
+func TestExplainProfile_MaxRiskScore(t *testing.T) {
+	profile := ProfileAggressive
+	profile.MaxRiskScore = 20
+
+	pr := newTestPR(model.UpdateTypeMinor, 48)
+	trace := ExplainProfile(&profile, pr, passingChecks())
+	if !trace.Allowed {
+		t.Errorf("expected risk score 10 to be allowed, failed: %v", trace.FailedConditions())
+	}
+
+	pr.Dependency.ToVersion = "v0.9.0"
+	pr.Dependency.ReleasedAt = func() *time.Time { t := time.Now().Add(-time.Hour); return &t }()
+	trace = ExplainProfile(&profile, pr, passingChecks())
+	if trace.Allowed {
+		t.Fatal("expected risk score 35 to be denied")
+	}
+	failed := trace.FailedConditions()
+	if len(failed) != 1 || failed[0].Name != ConditionRisk {
+		t.Fatalf("expected only %s to fail, got %v", ConditionRisk, failed)
+	}
+	if failed[0].Value != "35" || failed[0].Threshold != "<= 20" {
+		t.Errorf("expected value 35 and threshold <= 20, got %q and %q", failed[0].Value, failed[0].Threshold)
+	}
+}
+
+// fixedRiskScorer scores every PR the same.
+type fixedRiskScorer int
+
+func (s fixedRiskScorer) Score(*model.PullRequest, []model.CheckRun) model.RiskScore {
+	return model.RiskScore{Score: int(s), Factors: []model.RiskFactor{{Name: "fixed", Points: int(s), Detail: "fixed score"}}}
+}
+
+func TestEngine_WithRiskScorer(t *testing.T) {
+	profile := ProfileBalanced
+	profile.MaxRiskScore = 50
+
+	engine, err := NewEngineWithConfig(EngineConfig{Profile: &profile})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	pr := newTestPR(model.UpdateTypePatch, 48)
+	decision, err := engine.CanMerge(ctx, pr, passingChecks())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decision.Allowed {
+		t.Fatalf("expected default scorer to allow merge, got %v", decision.Reasons)
+	}
+
+	scored := engine.WithRiskScorer(fixedRiskScorer(80))
+	if got := scored.Risk(pr, passingChecks()).Score; got != 80 {
+		t.Errorf("expected custom risk score 80, got %d", got)
+	}
+	decision, err = scored.CanMerge(ctx, pr, passingChecks())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.Allowed {
+		t.Error("expected custom scorer to deny merge")
+	}
+
+	// Switching profiles keeps the scorer.
+	if got := scored.WithProfile(&profile).Risk(pr, passingChecks()).Score; got != 80 {
+		t.Errorf("expected WithProfile to keep the risk scorer, got score %d", got)
+	}
+}
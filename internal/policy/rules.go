@@ -0,0 +1,103 @@
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/plexusone/versionconductor/internal/glob"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// MatchDependency returns the first of the given names or glob patterns
+// that matches a dependency name.
+func MatchDependency(name string, patterns []string) (string, bool) {
+	for _, pattern := range patterns {
+		if glob.Match(pattern, name) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// MatchRule returns the index of the first rule matching a dependency,
+// or -1 if none match.
+func MatchRule(rules []model.DependencyRule, dep model.Dependency) int {
+	for i, r := range rules {
+		if r.Match != "" && !glob.Match(r.Match, dep.Name) {
+			continue
+		}
+		if r.Ecosystem != "" && !strings.EqualFold(r.Ecosystem, dep.Ecosystem) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// ruleName returns the name used for a rule in decision reasons.
+func ruleName(r model.DependencyRule, index int) string {
+	switch {
+	case r.Name != "":
+		return r.Name
+	case r.Match != "":
+		return r.Match
+	default:
+		return fmt.Sprintf("#%d", index+1)
+	}
+}
+
+// ruleCondition checks a dependency against the first matching rule.
+// It returns false if no rule matches, in which case the profile's update
+// type settings apply.
+func ruleCondition(profile *model.MergeProfile, dep model.Dependency) (model.PolicyCondition, bool) {
+	i := MatchRule(profile.DependencyRules, dep)
+	if i < 0 {
+		return model.PolicyCondition{}, false
+	}
+
+	rule := profile.DependencyRules[i]
+	name := ruleName(rule, i)
+
+	depName := dep.Name
+	if depName == "" {
+		depName = "unknown dependency"
+	}
+	updateType := string(dep.UpdateType)
+	if updateType == "" {
+		updateType = string(model.UpdateTypeUnknown)
+	}
+
+	cond := model.PolicyCondition{
+		Name:      ConditionRulePrefix + name,
+		Value:     fmt.Sprintf("%s (%s)", depName, updateType),
+		Threshold: string(rule.Action),
+	}
+
+	switch rule.Action {
+	case model.RuleActionAllow:
+		if len(rule.UpdateTypes) == 0 {
+			cond.Passed = true
+			break
+		}
+		var allowed []string
+		for _, t := range rule.UpdateTypes {
+			allowed = append(allowed, string(t))
+		}
+		cond.Threshold += " " + strings.Join(allowed, ", ")
+		cond.Passed = slices.Contains(rule.UpdateTypes, dep.UpdateType)
+		cond.Code = model.ReasonUpdateTypeNotAllowed
+		cond.Message = fmt.Sprintf("dependency rule %q: %s updates are not allowed", name, updateType)
+	case model.RuleActionDeny:
+		cond.Code = model.ReasonDependencyDenied
+		cond.Message = fmt.Sprintf("dependency rule %q: %s is denied", name, depName)
+	case model.RuleActionRequireApproval:
+		cond.Code = model.ReasonApprovalRequired
+		cond.Message = fmt.Sprintf("dependency rule %q: %s requires manual approval", name, depName)
+	default:
+		cond.Code = model.ReasonPolicyError
+		cond.Message = fmt.Sprintf("dependency rule %q: unknown action %q", name, rule.Action)
+	}
+
+	return cond, true
+}
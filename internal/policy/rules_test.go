@@ -0,0 +1,85 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestEvaluateProfile_DependencyRules(t *testing.T) {
+	profile := ProfileConservative
+	profile.DependencyRules = []model.DependencyRule{
+		{Name: "aws-sdk", Match: "github.com/aws/aws-sdk-go-v2/*", Action: model.RuleActionRequireApproval},
+		{Name: "x-packages", Match: "golang.org/x/*", Ecosystem: "go", UpdateTypes: []model.UpdateType{model.UpdateTypePatch, model.UpdateTypeMinor}, Action: model.RuleActionAllow},
+		{Match: "github.com/blocked/*", Action: model.RuleActionDeny},
+	}
+
+	tests := []struct {
+		name       string
+		dep        model.Dependency
+		wantAllow  bool
+		wantReason string
+	}{
+		{
+			name:      "allow rule permits minor",
+			dep:       model.Dependency{Name: "golang.org/x/net", Ecosystem: "go", UpdateType: model.UpdateTypeMinor},
+			wantAllow: true,
+		},
+		{
+			name:       "allow rule limits update types",
+			dep:        model.Dependency{Name: "golang.org/x/net", Ecosystem: "go", UpdateType: model.UpdateTypeMajor},
+			wantReason: `dependency rule "x-packages": major updates are not allowed`,
+		},
+		{
+			name:       "ecosystem mismatch falls back to profile",
+			dep:        model.Dependency{Name: "golang.org/x/net", Ecosystem: "npm", UpdateType: model.UpdateTypeMinor},
+			wantReason: "minor updates require manual review",
+		},
+		{
+			name:       "require approval",
+			dep:        model.Dependency{Name: "github.com/aws/aws-sdk-go-v2/service/s3", UpdateType: model.UpdateTypePatch},
+			wantReason: `dependency rule "aws-sdk": github.com/aws/aws-sdk-go-v2/service/s3 requires manual approval`,
+		},
+		{
+			name:       "deny named by pattern",
+			dep:        model.Dependency{Name: "github.com/blocked/pkg", UpdateType: model.UpdateTypePatch},
+			wantReason: `dependency rule "github.com/blocked/*": github.com/blocked/pkg is denied`,
+		},
+		{
+			name:      "no rule uses profile",
+			dep:       model.Dependency{Name: "github.com/example/pkg", UpdateType: model.UpdateTypePatch},
+			wantAllow: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := newTestPR(tt.dep.UpdateType, 72)
+			pr.Dependency = tt.dep
+
+			allowed, reason := EvaluateProfile(&profile, pr, passingChecks())
+			if allowed != tt.wantAllow {
+				t.Errorf("expected allowed=%v, got %v (reason: %s)", tt.wantAllow, allowed, reason)
+			}
+			if tt.wantReason != "" && reason != tt.wantReason {
+				t.Errorf("expected reason %q, got %q", tt.wantReason, reason)
+			}
+		})
+	}
+}
+
+func TestApplyRepoConfig_RulesFirst(t *testing.T) {
+	profile := ProfileBalanced
+	profile.DependencyRules = []model.DependencyRule{{Name: "base", Action: model.RuleActionAllow}}
+
+	cfg := &model.RepoConfig{
+		Dependencies: model.DependencyLists{
+			Rules: []model.DependencyRule{{Name: "repo", Action: model.RuleActionDeny}},
+		},
+	}
+
+	p := ApplyRepoConfig(&profile, cfg)
+	if len(p.DependencyRules) != 2 || p.DependencyRules[0].Name != "repo" {
+		t.Errorf("expected repository rule first, got %v", p.DependencyRules)
+	}
+}
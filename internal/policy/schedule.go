@@ -0,0 +1,589 @@
+package policy
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// now returns the current time. Tests replace it to get a fixed clock.
+var now = time.Now
+
+// Schedule condition messages.
+const (
+	msgOutsideMergeWindow = "outside merge window"
+	msgChangeFreeze       = "change freeze: "
+)
+
+// scheduleHorizon bounds the search for the next allowed window.
+const scheduleHorizon = 366 * 24 * time.Hour
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// window is a parsed merge window.
+type window struct {
+	days       []time.Weekday
+	start, end time.Duration
+	loc        *time.Location
+}
+
+// parseWindow validates and parses a merge window.
+func parseWindow(w model.MergeWindow) (window, error) {
+	pw := window{loc: time.UTC}
+
+	for _, d := range w.Days {
+		days, err := parseDays(d)
+		if err != nil {
+			return pw, err
+		}
+		pw.days = append(pw.days, days...)
+	}
+
+	var err error
+	if pw.start, err = parseTimeOfDay(w.Start); err != nil {
+		return pw, err
+	}
+	if pw.end, err = parseTimeOfDay(w.End); err != nil {
+		return pw, err
+	}
+	if pw.end <= pw.start {
+		return pw, fmt.Errorf("merge window end %s must be after start %s", w.End, w.Start)
+	}
+
+	if w.Timezone != "" {
+		if pw.loc, err = time.LoadLocation(w.Timezone); err != nil {
+			return pw, fmt.Errorf("invalid timezone %q: %w", w.Timezone, err)
+		}
+	}
+
+	return pw, nil
+}
+
+// parseDays parses a weekday name or range such as "mon-fri".
+func parseDays(s string) ([]time.Weekday, error) {
+	from, to, isRange := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "-")
+
+	first, ok := weekdays[from]
+	if !ok {
+		return nil, fmt.Errorf("invalid weekday: %s", s)
+	}
+	if !isRange {
+		return []time.Weekday{first}, nil
+	}
+
+	last, ok := weekdays[to]
+	if !ok {
+		return nil, fmt.Errorf("invalid weekday: %s", s)
+	}
+
+	var days []time.Weekday
+	for d := first; ; d = (d + 1) % 7 {
+		days = append(days, d)
+		if d == last {
+			break
+		}
+	}
+	return days, nil
+}
+
+// parseTimeOfDay parses an "HH:MM" time of day. "24:00" is allowed as
+// the end of a day.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time of day %q, use HH:MM", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// contains reports whether t falls inside the window. The time of day is
+// read from the wall clock, so windows keep their hours on days when
+// daylight saving time starts or ends.
+func (w window) contains(t time.Time) bool {
+	lt := t.In(w.loc)
+	if len(w.days) > 0 && !slices.Contains(w.days, lt.Weekday()) {
+		return false
+	}
+	offset := time.Duration(lt.Hour())*time.Hour + time.Duration(lt.Minute())*time.Minute +
+		time.Duration(lt.Second())*time.Second + time.Duration(lt.Nanosecond())
+	return offset >= w.start && offset < w.end
+}
+
+// nextStart returns the first start of the window after t.
+func (w window) nextStart(t time.Time) time.Time {
+	lt := t.In(w.loc)
+	hour, minute := int(w.start/time.Hour), int(w.start%time.Hour/time.Minute)
+	for d := 0; d <= 7; d++ {
+		start := time.Date(lt.Year(), lt.Month(), lt.Day()+d, hour, minute, 0, 0, w.loc)
+		if len(w.days) > 0 && !slices.Contains(w.days, start.Weekday()) {
+			continue
+		}
+		if start.After(t) {
+			return start
+		}
+	}
+	return time.Time{}
+}
+
+// formatWindow formats a merge window like "mon,tue 09:00-17:00 Europe/Berlin".
+func formatWindow(w model.MergeWindow) string {
+	var parts []string
+	if len(w.Days) > 0 {
+		parts = append(parts, strings.Join(w.Days, ","))
+	}
+	parts = append(parts, w.Start+"-"+w.End)
+	if w.Timezone != "" {
+		parts = append(parts, w.Timezone)
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseMergeWindow parses a merge window from its compact form:
+// "[days] HH:MM-HH:MM [timezone]", e.g. "mon-fri 09:00-17:00 Europe/Berlin".
+func ParseMergeWindow(spec string) (model.MergeWindow, error) {
+	var w model.MergeWindow
+
+	fields := strings.Fields(spec)
+	if len(fields) > 0 && !strings.Contains(fields[0], ":") {
+		w.Days = strings.Split(fields[0], ",")
+		fields = fields[1:]
+	}
+	if len(fields) == 0 || len(fields) > 2 {
+		return w, fmt.Errorf("invalid merge window %q, use \"[days] HH:MM-HH:MM [timezone]\"", spec)
+	}
+
+	start, end, ok := strings.Cut(fields[0], "-")
+	if !ok {
+		return w, fmt.Errorf("invalid merge window %q, use \"[days] HH:MM-HH:MM [timezone]\"", spec)
+	}
+	w.Start, w.End = start, end
+
+	if len(fields) == 2 {
+		w.Timezone = fields[1]
+	}
+
+	if _, err := parseWindow(w); err != nil {
+		return w, err
+	}
+	return w, nil
+}
+
+// activeFreeze returns the first freeze containing t, or nil.
+func activeFreeze(freezes []model.Freeze, t time.Time) *model.Freeze {
+	for i, f := range freezes {
+		if !t.Before(f.Start) && t.Before(f.End) {
+			return &freezes[i]
+		}
+	}
+	return nil
+}
+
+// NextAllowedTime returns the first time at or after t that is inside a
+// merge window and outside every freeze. It returns false if there is no
+// such time within a year or the windows are invalid.
+func NextAllowedTime(profile *model.MergeProfile, t time.Time) (time.Time, bool) {
+	windows := make([]window, 0, len(profile.MergeWindows))
+	for _, mw := range profile.MergeWindows {
+		w, err := parseWindow(mw)
+		if err != nil {
+			return time.Time{}, false
+		}
+		windows = append(windows, w)
+	}
+
+	limit := t.Add(scheduleHorizon)
+	for t.Before(limit) {
+		if f := activeFreeze(profile.Freezes, t); f != nil {
+			t = f.End
+			continue
+		}
+		if len(windows) == 0 || slices.ContainsFunc(windows, func(w window) bool { return w.contains(t) }) {
+			return t, true
+		}
+
+		var next time.Time
+		for _, w := range windows {
+			if s := w.nextStart(t); !s.IsZero() && (next.IsZero() || s.Before(next)) {
+				next = s
+			}
+		}
+		if next.IsZero() {
+			return time.Time{}, false
+		}
+		t = next
+	}
+
+	return time.Time{}, false
+}
+
+// scheduleConditions checks the profile's merge windows and freezes at t.
+// It returns no conditions if the profile has neither.
+func scheduleConditions(profile *model.MergeProfile, t time.Time) []model.PolicyCondition {
+	var conds []model.PolicyCondition
+
+	if len(profile.MergeWindows) > 0 {
+		cond := model.PolicyCondition{
+			Name:    ConditionMergeWindow,
+			Value:   t.UTC().Format(time.RFC3339),
+			Code:    model.ReasonOutsideMergeWindow,
+			Message: msgOutsideMergeWindow,
+		}
+
+		var specs []string
+		for _, mw := range profile.MergeWindows {
+			specs = append(specs, formatWindow(mw))
+
+			w, err := parseWindow(mw)
+			if err != nil {
+				cond.Passed = false
+				cond.Code = model.ReasonPolicyError
+				cond.Message = fmt.Sprintf("invalid merge window: %v", err)
+				break
+			}
+			if w.contains(t) {
+				cond.Passed = true
+				cond.Value = t.In(w.loc).Format("Mon 15:04 MST")
+			}
+		}
+		cond.Threshold = strings.Join(specs, "; ")
+
+		conds = append(conds, cond)
+	}
+
+	if len(profile.Freezes) > 0 {
+		cond := model.PolicyCondition{
+			Name:      ConditionFreeze,
+			Value:     "none",
+			Threshold: "no active freeze",
+			Passed:    true,
+		}
+		if f := activeFreeze(profile.Freezes, t); f != nil {
+			cond.Value = fmt.Sprintf("%s (until %s)", f.Name, f.End.UTC().Format(time.RFC3339))
+			cond.Passed = false
+			cond.Code = model.ReasonChangeFreeze
+			cond.Message = msgChangeFreeze + f.Name
+		}
+		conds = append(conds, cond)
+	}
+
+	return conds
+}
+
+// applySchedule prepends the profile's schedule conditions to a trace and
+// records the next allowed time if they fail. It returns false if they fail.
+// The caller is responsible for updating trace.Allowed.
+func applySchedule(trace *model.PolicyTrace, profile *model.MergeProfile) bool {
+	t := now()
+	conds := scheduleConditions(profile, t)
+
+	trace.Conditions = append(conds, trace.Conditions...)
+
+	for _, c := range conds {
+		if !c.Passed {
+			if next, ok := NextAllowedTime(profile, t); ok {
+				trace.NextWindow = &next
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// checkSchedule returns a denied decision if the profile's merge windows
+// or freezes block the action now, or nil if they allow it.
+func checkSchedule(action model.PolicyAction, profile *model.MergeProfile) *model.PolicyDecision {
+	trace := &model.PolicyTrace{Action: string(action)}
+	if applySchedule(trace, profile) {
+		return nil
+	}
+	return trace.Decision()
+}
+
+// LoadFreezeCalendar loads freezes from an iCal (.ics) or YAML file.
+// YAML files hold a list of freezes, either at the top level or under a
+// "freezes" key. Each iCal VEVENT becomes a freeze named by its SUMMARY.
+// All-day and floating iCal times, which carry no timezone, are read in
+// the calendar's X-WR-TIMEZONE, or in loc if it has none.
+func LoadFreezeCalendar(path string, loc *time.Location) ([]model.Freeze, error) {
+	cleanPath := filepath.Clean(path)
+	data, err := os.ReadFile(cleanPath) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read freeze calendar: %w", err)
+	}
+
+	var freezes []model.Freeze
+	switch strings.ToLower(filepath.Ext(cleanPath)) {
+	case ".ics", ".ical":
+		freezes, err = parseICalFreezes(data, loc)
+	default:
+		freezes, err = parseYAMLFreezes(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse freeze calendar %s: %w", cleanPath, err)
+	}
+
+	for _, f := range freezes {
+		if !f.End.After(f.Start) {
+			return nil, fmt.Errorf("freeze %q in %s must end after it starts", f.Name, cleanPath)
+		}
+	}
+
+	return freezes, nil
+}
+
+// WithFreezeCalendar returns a copy of profile with the freezes from its
+// FreezeCalendar file added. Profiles without a calendar are returned as is.
+// All-day and floating iCal times are read in the timezone of the first
+// merge window that sets one, so a freeze on a date covers that date where
+// merges are scheduled, and in UTC otherwise.
+func WithFreezeCalendar(profile *model.MergeProfile) (*model.MergeProfile, error) {
+	if profile.FreezeCalendar == "" {
+		return profile, nil
+	}
+
+	loc := time.UTC
+	for _, w := range profile.MergeWindows {
+		if w.Timezone == "" {
+			continue
+		}
+		l, err := time.LoadLocation(w.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", w.Timezone, err)
+		}
+		loc = l
+		break
+	}
+
+	freezes, err := LoadFreezeCalendar(profile.FreezeCalendar, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	p := *profile
+	p.Freezes = append(slices.Clone(profile.Freezes), freezes...)
+	return &p, nil
+}
+
+// parseYAMLFreezes parses a YAML freeze list.
+func parseYAMLFreezes(data []byte) ([]model.Freeze, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	if len(node.Content) == 0 {
+		return nil, nil
+	}
+
+	var freezes []model.Freeze
+	if node.Content[0].Kind == yaml.SequenceNode {
+		if err := node.Content[0].Decode(&freezes); err != nil {
+			return nil, err
+		}
+		return freezes, nil
+	}
+
+	var doc struct {
+		Freezes []model.Freeze `yaml:"freezes"`
+	}
+	if err := node.Content[0].Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc.Freezes, nil
+}
+
+// parseICalFreezes parses the VEVENTs of an iCalendar file. As in RFC
+// 5545, an event ends at its DTEND, or after its DURATION, and an all-day
+// event without either lasts one day. Timed events without either, which
+// would last no time, and recurring events are rejected. Times without a
+// TZID or UTC suffix are read in the calendar's X-WR-TIMEZONE or in loc.
+func parseICalFreezes(data []byte, loc *time.Location) ([]model.Freeze, error) {
+	var freezes []model.Freeze
+	var event *model.Freeze
+	var allDay bool
+	var hasEnd bool
+	var duration string
+
+	for _, line := range unfoldICal(data) {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		prop, params, _ := strings.Cut(name, ";")
+
+		switch strings.ToUpper(prop) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				event = &model.Freeze{}
+				allDay, hasEnd, duration = false, false, ""
+			}
+		case "END":
+			if strings.EqualFold(value, "VEVENT") && event != nil {
+				switch {
+				case hasEnd:
+				case duration != "":
+					end, err := addICalDuration(event.Start, duration)
+					if err != nil {
+						return nil, fmt.Errorf("event %q: %w", event.Name, err)
+					}
+					event.End = end
+				case allDay:
+					event.End = event.Start.AddDate(0, 0, 1)
+				default:
+					return nil, fmt.Errorf("event %q has a start time but no DTEND or DURATION", event.Name)
+				}
+				freezes = append(freezes, *event)
+				event = nil
+			}
+		case "X-WR-TIMEZONE":
+			if event == nil {
+				l, err := time.LoadLocation(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid X-WR-TIMEZONE %q: %w", value, err)
+				}
+				loc = l
+			}
+		case "RRULE", "RDATE":
+			if event != nil {
+				return nil, fmt.Errorf("recurring events are not supported, list each occurrence as its own event (%s)", strings.ToUpper(prop))
+			}
+		case "DURATION":
+			if event != nil {
+				duration = value
+			}
+		case "SUMMARY":
+			if event != nil {
+				event.Name = unescapeICal(value)
+			}
+		case "DTSTART":
+			if event != nil {
+				t, date, err := parseICalTime(value, params, loc)
+				if err != nil {
+					return nil, err
+				}
+				event.Start, allDay = t, date
+			}
+		case "DTEND":
+			if event != nil {
+				t, _, err := parseICalTime(value, params, loc)
+				if err != nil {
+					return nil, err
+				}
+				event.End, hasEnd = t, true
+			}
+		}
+	}
+
+	return freezes, nil
+}
+
+// unfoldICal splits iCalendar data into logical lines, joining folded
+// continuation lines that start with a space or tab.
+func unfoldICal(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// parseICalTime parses a DTSTART or DTEND value. It reports whether the
+// value is a date without a time. Dates and times without a TZID or "Z"
+// suffix are read in loc.
+func parseICalTime(value, params string, loc *time.Location) (time.Time, bool, error) {
+	for _, p := range strings.Split(params, ";") {
+		if k, v, ok := strings.Cut(p, "="); ok && strings.EqualFold(k, "TZID") {
+			l, err := time.LoadLocation(strings.Trim(v, `"`))
+			if err != nil {
+				return time.Time{}, false, fmt.Errorf("invalid TZID %q: %w", v, err)
+			}
+			loc = l
+		}
+	}
+
+	switch {
+	case len(value) == 8:
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	case strings.HasSuffix(value, "Z"):
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	default:
+		t, err := time.ParseInLocation("20060102T150405", value, loc)
+		return t, false, err
+	}
+}
+
+// addICalDuration adds an iCalendar DURATION such as "P1D", "PT4H" or
+// "P1W" to t. Days and weeks are calendar days, as RFC 5545 requires.
+func addICalDuration(t time.Time, value string) (time.Time, error) {
+	invalid := fmt.Errorf("invalid DURATION %q", value)
+
+	s, ok := strings.CutPrefix(strings.TrimPrefix(value, "+"), "P")
+	if !ok || s == "" {
+		return time.Time{}, invalid
+	}
+
+	var days int
+	var d time.Duration
+	inTime := false
+	for s != "" {
+		if s[0] == 'T' {
+			inTime, s = true, s[1:]
+			continue
+		}
+		i := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+		if i <= 0 {
+			return time.Time{}, invalid
+		}
+		var n int
+		if _, err := fmt.Sscanf(s[:i], "%d", &n); err != nil {
+			return time.Time{}, invalid
+		}
+
+		switch unit := s[i]; {
+		case !inTime && unit == 'W':
+			days += 7 * n
+		case !inTime && unit == 'D':
+			days += n
+		case inTime && unit == 'H':
+			d += time.Duration(n) * time.Hour
+		case inTime && unit == 'M':
+			d += time.Duration(n) * time.Minute
+		case inTime && unit == 'S':
+			d += time.Duration(n) * time.Second
+		default:
+			return time.Time{}, invalid
+		}
+		s = s[i+1:]
+	}
+
+	return t.AddDate(0, 0, days).Add(d), nil
+}
+
+// unescapeICal removes iCalendar text escaping.
+func unescapeICal(s string) string {
+	return strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(s)
+}
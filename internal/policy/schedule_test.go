@@ -0,0 +1,334 @@
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// setNow fixes the policy clock for the duration of a test.
+func setNow(t *testing.T, ts time.Time) {
+	t.Helper()
+	orig := now
+	now = func() time.Time { return ts }
+	t.Cleanup(func() { now = orig })
+}
+
+func businessHoursProfile() model.MergeProfile {
+	profile := ProfileAggressive
+	profile.MergeWindows = []model.MergeWindow{
+		{Days: []string{"mon-fri"}, Start: "09:00", End: "17:00", Timezone: "Europe/Berlin"},
+	}
+	profile.Freezes = []model.Freeze{
+		{
+			Name:  "Q4 release",
+			Start: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	return profile
+}
+
+func TestParseMergeWindow(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    model.MergeWindow
+		wantErr bool
+	}{
+		{spec: "09:00-17:00", want: model.MergeWindow{Start: "09:00", End: "17:00"}},
+		{spec: "mon-fri 09:00-17:00 Europe/Berlin", want: model.MergeWindow{Days: []string{"mon-fri"}, Start: "09:00", End: "17:00", Timezone: "Europe/Berlin"}},
+		{spec: "mon,wed 10:00-12:00", want: model.MergeWindow{Days: []string{"mon", "wed"}, Start: "10:00", End: "12:00"}},
+		{spec: "17:00-09:00", wantErr: true},
+		{spec: "funday 09:00-17:00", wantErr: true},
+		{spec: "09:00-17:00 Nowhere/City", wantErr: true},
+		{spec: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseMergeWindow(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if formatWindow(got) != formatWindow(tt.want) {
+				t.Errorf("expected %s, got %s", formatWindow(tt.want), formatWindow(got))
+			}
+		})
+	}
+}
+
+func TestNextAllowedTime(t *testing.T) {
+	profile := businessHoursProfile()
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	tests := []struct {
+		name string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "inside window",
+			from: time.Date(2026, 10, 16, 10, 0, 0, 0, berlin),
+			want: time.Date(2026, 10, 16, 10, 0, 0, 0, berlin),
+		},
+		{
+			name: "friday evening skips weekend and freeze",
+			from: time.Date(2026, 10, 16, 18, 0, 0, 0, berlin),
+			want: time.Date(2026, 10, 21, 9, 0, 0, 0, berlin),
+		},
+		{
+			name: "early morning",
+			from: time.Date(2026, 10, 22, 7, 30, 0, 0, berlin),
+			want: time.Date(2026, 10, 22, 9, 0, 0, 0, berlin),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NextAllowedTime(&profile, tt.from)
+			if !ok {
+				t.Fatal("expected an allowed time")
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("expected %s, got %s", tt.want, got.In(berlin))
+			}
+		})
+	}
+}
+
+func TestMergeWindow_DaylightSavingTime(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	profile := ProfileAggressive
+	profile.MergeWindows = []model.MergeWindow{{Start: "09:00", End: "17:00", Timezone: "Europe/Berlin"}}
+
+	// Clocks go back an hour on 2026-10-25 and forward on 2026-03-29.
+	for _, day := range []int{25, 26} {
+		for _, tt := range []struct {
+			hour, minute int
+			want         bool
+		}{{8, 59, false}, {9, 0, true}, {16, 30, true}, {17, 0, false}} {
+			ts := time.Date(2026, 10, day, tt.hour, tt.minute, 0, 0, berlin)
+			if got := scheduleConditions(&profile, ts)[0].Passed; got != tt.want {
+				t.Errorf("%s: expected in window %v, got %v", ts, tt.want, got)
+			}
+		}
+	}
+
+	for _, from := range []time.Time{
+		time.Date(2026, 10, 25, 7, 0, 0, 0, berlin),
+		time.Date(2026, 3, 29, 7, 0, 0, 0, berlin),
+	} {
+		got, ok := NextAllowedTime(&profile, from)
+		want := time.Date(from.Year(), from.Month(), from.Day(), 9, 0, 0, 0, berlin)
+		if !ok || !got.Equal(want) {
+			t.Errorf("from %s: expected next window at %s, got %s", from, want, got.In(berlin))
+		}
+	}
+}
+
+func TestEvaluateProfile_Schedule(t *testing.T) {
+	profile := businessHoursProfile()
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		wantReason string
+	}{
+		{"inside window", time.Date(2026, 10, 16, 10, 0, 0, 0, berlin), ""},
+		{"weekend", time.Date(2026, 10, 17, 10, 0, 0, 0, berlin), "outside merge window"},
+		{"freeze", time.Date(2026, 10, 19, 10, 0, 0, 0, berlin), "change freeze: Q4 release"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setNow(t, tt.now)
+
+			// Schedule failures are reported even if other conditions fail too.
+			pr := newTestPR(model.UpdateTypePatch, 48)
+			if tt.wantReason != "" {
+				pr.Draft = true
+			}
+
+			engine := NewEngineWithProfile(&profile)
+			decision, err := engine.CanMerge(context.Background(), pr, passingChecks())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.wantReason == "" {
+				if !decision.Allowed {
+					t.Errorf("expected merge to be allowed, got %v", decision.Reasons)
+				}
+				return
+			}
+
+			if decision.Allowed || len(decision.Reasons) == 0 || decision.Reasons[0] != tt.wantReason {
+				t.Errorf("expected reason %q, got %v", tt.wantReason, decision.Reasons)
+			}
+			if decision.NextWindow == nil {
+				t.Error("expected next window to be set")
+			}
+		})
+	}
+}
+
+func TestEngine_CanReleaseDuringFreeze(t *testing.T) {
+	profile := businessHoursProfile()
+	setNow(t, time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC))
+
+	decision, err := NewEngineWithProfile(&profile).CanRelease(context.Background(), &model.Repo{Owner: "example", Name: "repo", FullName: "example/repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.Allowed {
+		t.Fatal("expected release to be blocked by freeze")
+	}
+	if decision.Reasons[0] != "change freeze: Q4 release" {
+		t.Errorf("unexpected reason: %v", decision.Reasons)
+	}
+}
+
+func TestLoadFreezeCalendar(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	t.Run("ical", func(t *testing.T) {
+		freezes, err := LoadFreezeCalendar("testdata/calendars/freezes.ics", time.UTC)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(freezes) != 4 {
+			t.Fatalf("expected 4 freezes, got %d", len(freezes))
+		}
+
+		if freezes[0].Name != "Holiday freeze" || !freezes[0].End.Equal(time.Date(2027, 1, 4, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("unexpected all-day freeze: %+v", freezes[0])
+		}
+		if freezes[1].Name != "Release 4.2, final checks" {
+			t.Errorf("expected unfolded, unescaped summary, got %q", freezes[1].Name)
+		}
+		if !freezes[1].Start.Equal(time.Date(2026, 11, 10, 8, 0, 0, 0, berlin)) {
+			t.Errorf("expected TZID to be applied, got %s", freezes[1].Start)
+		}
+		if want := time.Date(2026, 12, 3, 4, 0, 0, 0, time.UTC); !freezes[2].End.Equal(want) {
+			t.Errorf("expected DURATION to end the freeze at %s, got %s", want, freezes[2].End)
+		}
+		if want := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC); !freezes[3].End.Equal(want) {
+			t.Errorf("expected all-day event without DTEND to last a day, got end %s", freezes[3].End)
+		}
+	})
+
+	t.Run("unsupported ical", func(t *testing.T) {
+		for name, event := range map[string]string{
+			"no end":    "SUMMARY:Deploy\r\nDTSTART:20261201T220000Z",
+			"recurring": "SUMMARY:Weekly freeze\r\nDTSTART;VALUE=DATE:20261201\r\nRRULE:FREQ=WEEKLY",
+			"duration":  "SUMMARY:Deploy\r\nDTSTART:20261201T220000Z\r\nDURATION:1 day",
+		} {
+			path := filepath.Join(t.TempDir(), "freezes.ics")
+			data := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n" + event + "\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+			if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadFreezeCalendar(path, time.UTC); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+
+	t.Run("all-day events in local time", func(t *testing.T) {
+		newYork, _ := time.LoadLocation("America/New_York")
+		freezes, err := LoadFreezeCalendar("testdata/calendars/freezes.ics", newYork)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := time.Date(2026, 11, 30, 0, 0, 0, 0, newYork); !freezes[3].Start.Equal(want) {
+			t.Errorf("expected all-day freeze to start at %s, got %s", want, freezes[3].Start)
+		}
+		if want := time.Date(2026, 12, 1, 0, 0, 0, 0, newYork); !freezes[3].End.Equal(want) {
+			t.Errorf("expected all-day freeze to end at %s, got %s", want, freezes[3].End)
+		}
+		if want := time.Date(2026, 11, 10, 8, 0, 0, 0, berlin); !freezes[1].Start.Equal(want) {
+			t.Errorf("expected TZID to take precedence, got %s", freezes[1].Start)
+		}
+		if want := time.Date(2026, 12, 1, 22, 0, 0, 0, time.UTC); !freezes[2].Start.Equal(want) {
+			t.Errorf("expected UTC time to stay in UTC, got %s", freezes[2].Start)
+		}
+	})
+
+	t.Run("calendar timezone", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "freezes.ics")
+		data := "BEGIN:VCALENDAR\r\nX-WR-TIMEZONE:Asia/Tokyo\r\nBEGIN:VEVENT\r\nSUMMARY:Christmas Eve\r\nDTSTART;VALUE=DATE:20261224\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		freezes, err := LoadFreezeCalendar(path, time.UTC)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		tokyo, _ := time.LoadLocation("Asia/Tokyo")
+		if want := time.Date(2026, 12, 24, 0, 0, 0, 0, tokyo); len(freezes) != 1 || !freezes[0].Start.Equal(want) {
+			t.Errorf("expected freeze to start at %s, got %+v", want, freezes)
+		}
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		freezes, err := LoadFreezeCalendar("testdata/calendars/freezes.yaml", time.UTC)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(freezes) != 1 || freezes[0].Name != "Holiday freeze" {
+			t.Errorf("unexpected freezes: %+v", freezes)
+		}
+	})
+}
+
+func TestNewEngineWithConfig_FreezeCalendar(t *testing.T) {
+	profile := ProfileBalanced
+	profile.FreezeCalendar = "testdata/calendars/freezes.yaml"
+
+	engine, err := NewEngineWithConfig(EngineConfig{Profile: &profile})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(engine.Profile().Freezes) != 1 {
+		t.Errorf("expected calendar freezes on profile, got %v", engine.Profile().Freezes)
+	}
+	if len(profile.Freezes) != 0 {
+		t.Error("expected input profile to be unchanged")
+	}
+}
+
+func TestWithFreezeCalendar_MergeWindowTimezone(t *testing.T) {
+	profile := ProfileBalanced
+	profile.MergeWindows = []model.MergeWindow{
+		{Days: []string{"mon-fri"}, Start: "09:00", End: "17:00", Timezone: "America/New_York"},
+	}
+	profile.FreezeCalendar = "testdata/calendars/freezes.ics"
+
+	p, err := WithFreezeCalendar(&profile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The Offsite all-day event on 2026-11-30 covers that whole day in New
+	// York, so a merge late in the evening there, already 1 December in
+	// UTC, is still frozen.
+	newYork, _ := time.LoadLocation("America/New_York")
+	lateEvening := time.Date(2026, 11, 30, 21, 0, 0, 0, newYork)
+	if f := activeFreeze(p.Freezes, lateEvening); f == nil || f.Name != "Offsite" {
+		t.Errorf("expected Offsite freeze at %s, got %+v", lateEvening, f)
+	}
+	if f := activeFreeze(p.Freezes, time.Date(2026, 12, 1, 0, 0, 0, 0, newYork)); f != nil {
+		t.Errorf("expected no freeze after the all-day event, got %+v", f)
+	}
+}
@@ -0,0 +1,100 @@
+// Package provider assigns organizations to the code hosting platform
+// serving them, so one run can cover repositories on several platforms.
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type is a code hosting platform.
+type Type string
+
+const (
+	GitHub    Type = "github"
+	GitLab    Type = "gitlab"
+	Gitea     Type = "gitea"     // also Forgejo
+	Bitbucket Type = "bitbucket" // Cloud, or Data Center with a URL
+)
+
+// Types lists the supported platforms.
+var Types = []Type{GitHub, GitLab, Gitea, Bitbucket}
+
+// Config assigns organizations to a platform instance.
+type Config struct {
+	// Type is the platform.
+	Type Type `mapstructure:"type" yaml:"type" json:"type"`
+
+	// URL is the instance URL, e.g. https://gitlab.example.com. Default is
+	// the platform's public instance; required for Gitea.
+	URL string `mapstructure:"url" yaml:"url" json:"url"`
+
+	// Orgs are the organizations, groups or users hosted there: Bitbucket
+	// workspaces or project keys.
+	Orgs []string `mapstructure:"orgs" yaml:"orgs" json:"orgs"`
+
+	// Token authenticates requests. If empty, the credential configured
+	// for the instance's host is used. The default GitHub token is never
+	// sent to other providers.
+	Token string `mapstructure:"token" yaml:"token" json:"token"`
+
+	// TokenCommand is a shell command printing the token, used instead
+	// of Token.
+	TokenCommand string `mapstructure:"tokenCommand" yaml:"tokenCommand" json:"tokenCommand"`
+}
+
+// Validate checks the config is complete.
+func (c Config) Validate() error {
+	known := false
+	for _, t := range Types {
+		if c.Type == t {
+			known = true
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown provider type %q", c.Type)
+	}
+	if c.Type == Gitea && c.URL == "" {
+		return fmt.Errorf("%s provider: url required", c.Type)
+	}
+	if len(c.Orgs) == 0 {
+		return fmt.Errorf("%s provider %s: orgs required", c.Type, c.URL)
+	}
+	if c.Token != "" && c.TokenCommand != "" {
+		return fmt.Errorf("%s provider %s: token and tokenCommand are mutually exclusive", c.Type, c.URL)
+	}
+	return nil
+}
+
+// Routes maps organizations to a value, such as the collector serving
+// them, with a fallback for all other organizations.
+type Routes[T any] struct {
+	byOrg    map[string]T
+	fallback T
+}
+
+// NewRoutes creates routes using fallback for unassigned organizations.
+func NewRoutes[T any](fallback T) *Routes[T] {
+	return &Routes[T]{byOrg: make(map[string]T), fallback: fallback}
+}
+
+// Add assigns an organization to v.
+func (r *Routes[T]) Add(org string, v T) {
+	r.byOrg[strings.ToLower(org)] = v
+}
+
+// For returns the value for an owner. An owner inside a GitLab group,
+// such as "group/subgroup", uses the route of its closest parent group.
+func (r *Routes[T]) For(owner string) T {
+	key := strings.ToLower(owner)
+	for {
+		if v, ok := r.byOrg[key]; ok {
+			return v
+		}
+		i := strings.LastIndex(key, "/")
+		if i < 0 {
+			return r.fallback
+		}
+		key = key[:i]
+	}
+}
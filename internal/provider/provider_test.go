@@ -0,0 +1,51 @@
+package provider
+
+import "testing"
+
+func TestRoutes_For(t *testing.T) {
+	r := NewRoutes("github")
+	r.Add("Platform", "gitlab")
+	r.Add("platform/legacy", "gitlab-legacy")
+
+	tests := []struct {
+		owner string
+		want  string
+	}{
+		{"platform", "gitlab"},
+		{"PLATFORM", "gitlab"},
+		{"platform/frontend", "gitlab"},
+		{"platform/legacy", "gitlab-legacy"},
+		{"platform/legacy/tools", "gitlab-legacy"},
+		{"platforms", "github"},
+		{"acme", "github"},
+	}
+
+	for _, tt := range tests {
+		if got := r.For(tt.owner); got != tt.want {
+			t.Errorf("For(%q) = %q, want %q", tt.owner, got, tt.want)
+		}
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"valid", Config{Type: GitLab, Orgs: []string{"platform"}, TokenCommand: "pass gitlab"}, false},
+		{"unknown type", Config{Type: "svn", Orgs: []string{"platform"}}, true},
+		{"no orgs", Config{Type: GitLab}, true},
+		{"gitea", Config{Type: Gitea, URL: "https://git.example.com", Orgs: []string{"tools"}}, false},
+		{"gitea without url", Config{Type: Gitea, Orgs: []string{"tools"}}, true},
+		{"bitbucket cloud", Config{Type: Bitbucket, Orgs: []string{"team"}}, false},
+		{"token and command", Config{Type: GitLab, Orgs: []string{"platform"}, Token: "t", TokenCommand: "c"}, true},
+	}
+
+	for _, tt := range tests {
+		err := tt.cfg.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
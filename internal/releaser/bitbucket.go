@@ -0,0 +1,139 @@
+package releaser
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/plexusone/versionconductor/internal/bitbucket"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// commitHashPattern matches a full commit hash.
+var commitHashPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// BitbucketReleaser implements Releaser for Bitbucket Cloud and Data
+// Center repositories. Bitbucket has no releases, so a release is an
+// annotated tag.
+type BitbucketReleaser struct {
+	client bitbucket.API
+}
+
+// NewBitbucketReleaser creates a new Bitbucket releaser using the given
+// client.
+func NewBitbucketReleaser(client bitbucket.API) *BitbucketReleaser {
+	return &BitbucketReleaser{
+		client: client,
+	}
+}
+
+// CreateRelease creates an annotated tag on the target commitish, or the
+// default branch if empty, with the release name and body as its message.
+// Drafts can't be created; the prerelease and generated notes options are
+// ignored, so the release is never a prerelease.
+func (r *BitbucketReleaser) CreateRelease(ctx context.Context, req *model.ReleaseRequest) (*model.Release, error) {
+	if req.Draft {
+		return nil, fmt.Errorf("failed to create release: Bitbucket has no draft releases")
+	}
+
+	commit, err := r.resolveCommit(ctx, req.Repo, req.TargetCommitish)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release: %w", err)
+	}
+
+	name := req.Name
+	if name == "" {
+		name = req.TagName
+	}
+	message := strings.TrimSpace(name + "\n\n" + req.Body)
+
+	t, err := r.client.CreateTag(ctx, req.Repo.Owner, req.Repo.Name, bitbucket.CreateTagOptions{
+		Name:    req.TagName,
+		Commit:  commit,
+		Message: message,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release: %w", err)
+	}
+
+	return &model.Release{
+		TagName: t.Name,
+		Name:    name,
+		Body:    req.Body,
+		Repo:    req.Repo,
+	}, nil
+}
+
+// CreateTag creates a new tag for a repository.
+func (r *BitbucketReleaser) CreateTag(ctx context.Context, repo model.RepoRef, tagName, sha, message string) error {
+	_, err := r.client.CreateTag(ctx, repo.Owner, repo.Name, bitbucket.CreateTagOptions{
+		Name:    tagName,
+		Commit:  sha,
+		Message: message,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+	return nil
+}
+
+// GetLatestTag returns the most recent semver tag.
+func (r *BitbucketReleaser) GetLatestTag(ctx context.Context, repo model.RepoRef) (string, error) {
+	tags, err := r.client.ListTags(ctx, repo.Owner, repo.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	var tagNames []string
+	for _, t := range tags {
+		tagNames = append(tagNames, t.Name)
+	}
+
+	latest := FindLatestVersion(tagNames)
+	if latest == "" {
+		return "", fmt.Errorf("no semver tags found")
+	}
+
+	return latest, nil
+}
+
+// GetTagSHA returns the commit SHA for a given tag.
+func (r *BitbucketReleaser) GetTagSHA(ctx context.Context, repo model.RepoRef, tagName string) (string, error) {
+	t, err := r.client.GetTag(ctx, repo.Owner, repo.Name, tagName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get tag: %w", err)
+	}
+	return t.Commit, nil
+}
+
+// GetDefaultBranchSHA returns the SHA of the default branch HEAD.
+func (r *BitbucketReleaser) GetDefaultBranchSHA(ctx context.Context, repo model.RepoRef, branch string) (string, error) {
+	b, err := r.client.GetBranch(ctx, repo.Owner, repo.Name, branch)
+	if err != nil {
+		return "", fmt.Errorf("failed to get branch ref: %w", err)
+	}
+	return b.Commit, nil
+}
+
+// resolveCommit returns the commit hash of a branch or commit hash, or of
+// the default branch if empty.
+func (r *BitbucketReleaser) resolveCommit(ctx context.Context, repo model.RepoRef, commitish string) (string, error) {
+	if commitHashPattern.MatchString(commitish) {
+		return commitish, nil
+	}
+
+	if commitish == "" {
+		bbRepo, err := r.client.GetRepo(ctx, repo.Owner, repo.Name)
+		if err != nil {
+			return "", err
+		}
+		commitish = bbRepo.DefaultBranch
+	}
+
+	b, err := r.client.GetBranch(ctx, repo.Owner, repo.Name, commitish)
+	if err != nil {
+		return "", err
+	}
+	return b.Commit, nil
+}
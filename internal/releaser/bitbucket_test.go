@@ -0,0 +1,48 @@
+package releaser
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/plexusone/versionconductor/internal/apitest"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestBitbucketReleaser_CreateRelease(t *testing.T) {
+	server := apitest.NewBitbucketDataCenterServer(t, map[string]any{
+		"GET /rest/api/1.0/projects/PLAT/repos/tools":                map[string]any{"slug": "tools", "project": map[string]any{"key": "PLAT"}},
+		"GET /rest/api/1.0/projects/PLAT/repos/tools/default-branch": map[string]any{"displayId": "main"},
+		"GET /rest/api/1.0/projects/PLAT/repos/tools/branches": map[string]any{"isLastPage": true, "values": []map[string]any{
+			{"displayId": "main-old", "latestCommit": "000000"},
+			{"displayId": "main", "latestCommit": "abc123"},
+		}},
+		"POST /rest/api/1.0/projects/PLAT/repos/tools/tags": map[string]any{"displayId": "v1.3.0", "latestCommit": "abc123"},
+	})
+	r := NewBitbucketReleaser(server.BitbucketClient(t))
+	repo := model.RepoRef{Owner: "PLAT", Name: "tools"}
+
+	rel, err := r.CreateRelease(context.Background(), &model.ReleaseRequest{
+		Repo:       repo,
+		TagName:    "v1.3.0",
+		Name:       "v1.3.0",
+		Body:       "- Update golang.org/x/net",
+		Prerelease: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rel.TagName != "v1.3.0" || rel.Prerelease {
+		t.Errorf("unexpected release: %+v", rel)
+	}
+
+	req, _ := server.Find(http.MethodPost, "/rest/api/1.0/projects/PLAT/repos/tools/tags")
+	if req.Body["startPoint"] != "abc123" || req.Body["message"] != "v1.3.0\n\n- Update golang.org/x/net" {
+		t.Errorf("expected annotated tag on main, got %v", req.Body)
+	}
+
+	_, err = r.CreateRelease(context.Background(), &model.ReleaseRequest{Repo: repo, TagName: "v1.4.0", Draft: true})
+	if err == nil {
+		t.Error("expected error for draft release")
+	}
+}
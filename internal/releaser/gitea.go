@@ -0,0 +1,101 @@
+package releaser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/plexusone/versionconductor/internal/gitea"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// GiteaReleaser implements Releaser for Gitea and Forgejo repositories.
+type GiteaReleaser struct {
+	client *gitea.Client
+}
+
+// NewGiteaReleaser creates a new Gitea releaser using the given client.
+func NewGiteaReleaser(client *gitea.Client) *GiteaReleaser {
+	return &GiteaReleaser{
+		client: client,
+	}
+}
+
+// CreateRelease creates a new release, creating its tag from the target
+// commitish if needed. Gitea doesn't generate release notes, so that
+// option is ignored.
+func (r *GiteaReleaser) CreateRelease(ctx context.Context, req *model.ReleaseRequest) (*model.Release, error) {
+	created, err := r.client.CreateRelease(ctx, req.Repo.Owner, req.Repo.Name, gitea.CreateReleaseOptions{
+		TagName:         req.TagName,
+		TargetCommitish: req.TargetCommitish,
+		Name:            req.Name,
+		Body:            req.Body,
+		Draft:           req.Draft,
+		Prerelease:      req.Prerelease,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release: %w", err)
+	}
+
+	return &model.Release{
+		TagName:     created.TagName,
+		Name:        created.Name,
+		Body:        created.Body,
+		Draft:       created.Draft,
+		Prerelease:  created.Prerelease,
+		CreatedAt:   created.CreatedAt,
+		PublishedAt: created.PublishedAt,
+		HTMLURL:     created.HTMLURL,
+		Repo:        req.Repo,
+	}, nil
+}
+
+// CreateTag creates a new tag for a repository.
+func (r *GiteaReleaser) CreateTag(ctx context.Context, repo model.RepoRef, tagName, sha, message string) error {
+	_, err := r.client.CreateTag(ctx, repo.Owner, repo.Name, gitea.CreateTagOptions{
+		TagName: tagName,
+		Target:  sha,
+		Message: message,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+	return nil
+}
+
+// GetLatestTag returns the most recent semver tag.
+func (r *GiteaReleaser) GetLatestTag(ctx context.Context, repo model.RepoRef) (string, error) {
+	tags, err := r.client.ListTags(ctx, repo.Owner, repo.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	var tagNames []string
+	for _, t := range tags {
+		tagNames = append(tagNames, t.Name)
+	}
+
+	latest := FindLatestVersion(tagNames)
+	if latest == "" {
+		return "", fmt.Errorf("no semver tags found")
+	}
+
+	return latest, nil
+}
+
+// GetTagSHA returns the commit SHA for a given tag.
+func (r *GiteaReleaser) GetTagSHA(ctx context.Context, repo model.RepoRef, tagName string) (string, error) {
+	t, err := r.client.GetTag(ctx, repo.Owner, repo.Name, tagName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get tag: %w", err)
+	}
+	return t.Commit.SHA, nil
+}
+
+// GetDefaultBranchSHA returns the SHA of the default branch HEAD.
+func (r *GiteaReleaser) GetDefaultBranchSHA(ctx context.Context, repo model.RepoRef, branch string) (string, error) {
+	b, err := r.client.GetBranch(ctx, repo.Owner, repo.Name, branch)
+	if err != nil {
+		return "", fmt.Errorf("failed to get branch ref: %w", err)
+	}
+	return b.Commit.ID, nil
+}
@@ -0,0 +1,39 @@
+package releaser
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/plexusone/versionconductor/internal/apitest"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestGiteaReleaser_CreateRelease(t *testing.T) {
+	server := apitest.NewGiteaServer(t, map[string]any{
+		"POST /repos/team/tools/releases": map[string]any{
+			"tag_name": "v0.4.1", "name": "v0.4.1", "draft": true,
+			"html_url": "https://git.example.com/team/tools/releases/tag/v0.4.1",
+		},
+	})
+	r := NewGiteaReleaser(server.GiteaClient(t))
+
+	rel, err := r.CreateRelease(context.Background(), &model.ReleaseRequest{
+		Repo:            model.RepoRef{Owner: "team", Name: "tools"},
+		TagName:         "v0.4.1",
+		Name:            "v0.4.1",
+		TargetCommitish: "main",
+		Draft:           true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rel.Draft || rel.HTMLURL != "https://git.example.com/team/tools/releases/tag/v0.4.1" {
+		t.Errorf("unexpected release: %+v", rel)
+	}
+
+	req, _ := server.Find(http.MethodPost, "/repos/team/tools/releases")
+	if req.Body["tag_name"] != "v0.4.1" || req.Body["target_commitish"] != "main" || req.Body["draft"] != true {
+		t.Errorf("unexpected release request body: %v", req.Body)
+	}
+}
@@ -5,10 +5,10 @@ import (
 	"fmt"
 
 	"github.com/google/go-github/v84/github"
-	"github.com/grokify/gogithub/auth"
 	"github.com/grokify/gogithub/release"
 	"github.com/grokify/gogithub/tag"
 
+	"github.com/plexusone/versionconductor/internal/auth"
 	"github.com/plexusone/versionconductor/pkg/model"
 )
 
@@ -19,8 +19,12 @@ type GitHubReleaser struct {
 
 // NewGitHubReleaser creates a new GitHub releaser.
 func NewGitHubReleaser(token string) *GitHubReleaser {
-	ctx := context.Background()
-	client := auth.NewGitHubClient(ctx, token)
+	return NewGitHubReleaserWithClient(auth.NewClient(auth.NewTokenProvider(token)))
+}
+
+// NewGitHubReleaserWithClient creates a new GitHub releaser using the
+// given client.
+func NewGitHubReleaserWithClient(client *github.Client) *GitHubReleaser {
 	return &GitHubReleaser{
 		client: client,
 	}
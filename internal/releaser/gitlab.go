@@ -0,0 +1,102 @@
+package releaser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/plexusone/versionconductor/internal/gitlab"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// GitLabReleaser implements Releaser for GitLab projects.
+type GitLabReleaser struct {
+	client *gitlab.Client
+}
+
+// NewGitLabReleaser creates a new GitLab releaser using the given client.
+func NewGitLabReleaser(client *gitlab.Client) *GitLabReleaser {
+	return &GitLabReleaser{
+		client: client,
+	}
+}
+
+// CreateRelease creates a new release, creating its tag from the target
+// commitish if needed. GitLab has no draft or prerelease releases and
+// doesn't generate release notes, so those options are not supported; the
+// prerelease option is ignored and the release is never a prerelease.
+func (r *GitLabReleaser) CreateRelease(ctx context.Context, req *model.ReleaseRequest) (*model.Release, error) {
+	if req.Draft {
+		return nil, fmt.Errorf("failed to create release: GitLab does not support draft releases")
+	}
+
+	created, err := r.client.CreateRelease(ctx, req.Repo.FullName(), gitlab.CreateReleaseOptions{
+		TagName:     req.TagName,
+		Name:        req.Name,
+		Description: req.Body,
+		Ref:         req.TargetCommitish,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release: %w", err)
+	}
+
+	return &model.Release{
+		TagName:     created.TagName,
+		Name:        created.Name,
+		Body:        created.Description,
+		CreatedAt:   created.CreatedAt,
+		PublishedAt: created.ReleasedAt,
+		HTMLURL:     created.Links.Self,
+		Repo:        req.Repo,
+	}, nil
+}
+
+// CreateTag creates a new tag for a project.
+func (r *GitLabReleaser) CreateTag(ctx context.Context, repo model.RepoRef, tagName, sha, message string) error {
+	_, err := r.client.CreateTag(ctx, repo.FullName(), gitlab.CreateTagOptions{
+		TagName: tagName,
+		Ref:     sha,
+		Message: message,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+	return nil
+}
+
+// GetLatestTag returns the most recent semver tag.
+func (r *GitLabReleaser) GetLatestTag(ctx context.Context, repo model.RepoRef) (string, error) {
+	tags, err := r.client.ListTags(ctx, repo.FullName())
+	if err != nil {
+		return "", fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	var tagNames []string
+	for _, t := range tags {
+		tagNames = append(tagNames, t.Name)
+	}
+
+	latest := FindLatestVersion(tagNames)
+	if latest == "" {
+		return "", fmt.Errorf("no semver tags found")
+	}
+
+	return latest, nil
+}
+
+// GetTagSHA returns the commit SHA for a given tag.
+func (r *GitLabReleaser) GetTagSHA(ctx context.Context, repo model.RepoRef, tagName string) (string, error) {
+	t, err := r.client.GetTag(ctx, repo.FullName(), tagName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get tag: %w", err)
+	}
+	return t.Commit.ID, nil
+}
+
+// GetDefaultBranchSHA returns the SHA of the default branch HEAD.
+func (r *GitLabReleaser) GetDefaultBranchSHA(ctx context.Context, repo model.RepoRef, branch string) (string, error) {
+	b, err := r.client.GetBranch(ctx, repo.FullName(), branch)
+	if err != nil {
+		return "", fmt.Errorf("failed to get branch ref: %w", err)
+	}
+	return b.Commit.ID, nil
+}
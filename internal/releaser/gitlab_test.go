@@ -0,0 +1,62 @@
+package releaser
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/plexusone/versionconductor/internal/apitest"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+func TestGitLabReleaser_CreateRelease(t *testing.T) {
+	server := apitest.NewGitLabServer(t, map[string]any{
+		"POST /projects/group%2Fapp/releases": map[string]any{
+			"tag_name": "v1.2.0", "name": "v1.2.0", "description": "notes",
+			"_links": map[string]any{"self": "https://gitlab.example.com/group/app/-/releases/v1.2.0"},
+		},
+	})
+	r := NewGitLabReleaser(server.GitLabClient(t))
+	repo := model.RepoRef{Owner: "group", Name: "app"}
+
+	rel, err := r.CreateRelease(context.Background(), &model.ReleaseRequest{
+		Repo:            repo,
+		TagName:         "v1.2.0",
+		Name:            "v1.2.0",
+		Body:            "notes",
+		TargetCommitish: "main",
+		Prerelease:      true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rel.TagName != "v1.2.0" || rel.HTMLURL != "https://gitlab.example.com/group/app/-/releases/v1.2.0" || rel.Prerelease {
+		t.Errorf("unexpected release: %+v", rel)
+	}
+
+	req, _ := server.Find(http.MethodPost, "/projects/group%2Fapp/releases")
+	if req.Body["tag_name"] != "v1.2.0" || req.Body["ref"] != "main" || req.Body["description"] != "notes" {
+		t.Errorf("unexpected release request body: %v", req.Body)
+	}
+
+	if _, err := r.CreateRelease(context.Background(), &model.ReleaseRequest{Repo: repo, TagName: "v1.3.0", Draft: true}); err == nil {
+		t.Error("expected error for draft release")
+	}
+}
+
+func TestGitLabReleaser_GetLatestTag(t *testing.T) {
+	server := apitest.NewGitLabServer(t, map[string]any{
+		"GET /projects/group%2Fapp/repository/tags": []map[string]any{
+			{"name": "v1.10.0"}, {"name": "nightly"}, {"name": "v1.9.3"},
+		},
+	})
+	r := NewGitLabReleaser(server.GitLabClient(t))
+
+	tag, err := r.GetLatestTag(context.Background(), model.RepoRef{Owner: "group", Name: "app"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tag != "v1.10.0" {
+		t.Errorf("expected v1.10.0, got %s", tag)
+	}
+}
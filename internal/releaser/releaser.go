@@ -3,6 +3,8 @@ package releaser
 import (
 	"context"
 
+	"github.com/google/go-github/v84/github"
+
 	"github.com/plexusone/versionconductor/pkg/model"
 )
 
@@ -48,3 +50,8 @@ func DefaultOptions() Options {
 func NewGitHub(token string) Releaser {
 	return NewGitHubReleaser(token)
 }
+
+// NewGitHubWithClient creates a new GitHub releaser using the given client.
+func NewGitHubWithClient(client *github.Client) Releaser {
+	return NewGitHubReleaserWithClient(client)
+}
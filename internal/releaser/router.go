@@ -0,0 +1,44 @@
+package releaser
+
+import (
+	"context"
+
+	"github.com/plexusone/versionconductor/internal/provider"
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// Router implements Releaser by sending each request to the releaser
+// serving the repository's owner.
+type Router struct {
+	routes *provider.Routes[Releaser]
+}
+
+// NewRouter creates a releaser routing requests by owner.
+func NewRouter(routes *provider.Routes[Releaser]) *Router {
+	return &Router{routes: routes}
+}
+
+// CreateRelease creates a new release for a repository.
+func (r *Router) CreateRelease(ctx context.Context, req *model.ReleaseRequest) (*model.Release, error) {
+	return r.routes.For(req.Repo.Owner).CreateRelease(ctx, req)
+}
+
+// CreateTag creates a new tag for a repository.
+func (r *Router) CreateTag(ctx context.Context, repo model.RepoRef, tagName, sha, message string) error {
+	return r.routes.For(repo.Owner).CreateTag(ctx, repo, tagName, sha, message)
+}
+
+// GetLatestTag returns the most recent semver tag.
+func (r *Router) GetLatestTag(ctx context.Context, repo model.RepoRef) (string, error) {
+	return r.routes.For(repo.Owner).GetLatestTag(ctx, repo)
+}
+
+// GetTagSHA returns the SHA for a given tag.
+func (r *Router) GetTagSHA(ctx context.Context, repo model.RepoRef, tagName string) (string, error) {
+	return r.routes.For(repo.Owner).GetTagSHA(ctx, repo, tagName)
+}
+
+// GetDefaultBranchSHA returns the SHA of the default branch HEAD.
+func (r *Router) GetDefaultBranchSHA(ctx context.Context, repo model.RepoRef, branch string) (string, error) {
+	return r.routes.For(repo.Owner).GetDefaultBranchSHA(ctx, repo, branch)
+}
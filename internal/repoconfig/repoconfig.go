@@ -0,0 +1,28 @@
+// Package repoconfig parses the per-repository config file collectors read
+// from each repository's default branch.
+package repoconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/plexusone/versionconductor/pkg/model"
+)
+
+// Parse parses a per-repository config file. Unknown fields are rejected
+// so that typos don't silently loosen policy.
+func Parse(data []byte) (*model.RepoConfig, error) {
+	var cfg model.RepoConfig
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse repository config: %w", err)
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,48 @@
+package repoconfig
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	data := []byte(`
+overrides:
+  autoMergeMinor: false
+  minAgeHours: 72
+dependencies:
+  deny:
+    - github.com/aws/aws-sdk-go-v2/*
+`)
+
+	cfg, err := Parse(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Disabled {
+		t.Error("expected config to be enabled")
+	}
+	if cfg.Overrides.AutoMergeMinor == nil || *cfg.Overrides.AutoMergeMinor {
+		t.Errorf("expected autoMergeMinor override false, got %v", cfg.Overrides.AutoMergeMinor)
+	}
+	if cfg.Overrides.AutoMergePatch != nil {
+		t.Error("expected autoMergePatch to be unset")
+	}
+	if len(cfg.Dependencies.Deny) != 1 {
+		t.Errorf("expected 1 deny entry, got %v", cfg.Dependencies.Deny)
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	cfg, err := Parse(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Disabled {
+		t.Error("expected empty config to be enabled")
+	}
+}
+
+func TestParse_UnknownField(t *testing.T) {
+	if _, err := Parse([]byte("overrides:\n  autoMergeMinr: false\n")); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
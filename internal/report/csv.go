@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/csv"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/plexusone/versionconductor/pkg/model"
 )
@@ -22,34 +24,50 @@ func (f *CSVFormatter) FormatScanResult(result *model.ScanResult) (string, error
 	w := csv.NewWriter(&buf)
 
 	// Header
-	header := []string{"Repository", "PR Number", "Title", "Bot", "Dependency", "From", "To", "Update Type", "Age (hours)", "Tests Passed", "URL"}
+	header := []string{"Repository", "PR Number", "Title", "Bot", "Dependency", "From", "To", "Update Type", "Severity", "Vulnerabilities", "Risk Score", "Risk Factors", "Age (hours)", "Tests Passed", "URL"}
 	if err := w.Write(header); err != nil {
 		return "", err
 	}
 
-	// Data rows
+	// Data rows, one per dependency so grouped PRs list each package
 	for _, pr := range result.PRs {
 		testsPassed := "false"
 		if pr.TestsPassed {
 			testsPassed = "true"
 		}
 
-		row := []string{
-			pr.Repo.FullName(),
-			fmt.Sprintf("%d", pr.Number),
-			pr.Title,
-			string(pr.DependBot),
-			pr.Dependency.Name,
-			pr.Dependency.FromVersion,
-			pr.Dependency.ToVersion,
-			string(pr.Dependency.UpdateType),
-			fmt.Sprintf("%d", pr.AgeHours()),
-			testsPassed,
-			pr.HTMLURL,
+		riskScore, riskFactors := "", ""
+		if pr.Risk != nil {
+			riskScore = strconv.Itoa(pr.Risk.Score)
+			factors := make([]string, 0, len(pr.Risk.Factors))
+			for _, f := range pr.Risk.Factors {
+				factors = append(factors, fmt.Sprintf("%s:%d", f.Name, f.Points))
+			}
+			riskFactors = strings.Join(factors, " ")
 		}
 
-		if err := w.Write(row); err != nil {
-			return "", err
+		for _, dep := range pr.AllDependencies() {
+			row := []string{
+				pr.Repo.FullName(),
+				fmt.Sprintf("%d", pr.Number),
+				pr.Title,
+				string(pr.DependBot),
+				dep.Name,
+				dep.FromVersion,
+				dep.ToVersion,
+				string(dep.UpdateType),
+				string(dep.Severity),
+				strings.Join(pr.Vulnerabilities, " "),
+				riskScore,
+				riskFactors,
+				fmt.Sprintf("%d", pr.AgeHours()),
+				testsPassed,
+				pr.HTMLURL,
+			}
+
+			if err := w.Write(row); err != nil {
+				return "", err
+			}
 		}
 	}
 
@@ -63,7 +81,7 @@ func (f *CSVFormatter) FormatMergeResult(result *model.MergeResult) (string, err
 	w := csv.NewWriter(&buf)
 
 	// Header
-	header := []string{"Repository", "PR Number", "Title", "Status", "Details", "URL"}
+	header := []string{"Repository", "PR Number", "Title", "Status", "Details", "Codes", "URL"}
 	if err := w.Write(header); err != nil {
 		return "", err
 	}
@@ -76,6 +94,7 @@ func (f *CSVFormatter) FormatMergeResult(result *model.MergeResult) (string, err
 			m.PR.Title,
 			"merged",
 			m.SHA,
+			"",
 			m.PR.HTMLURL,
 		}
 		if err := w.Write(row); err != nil {
@@ -91,6 +110,7 @@ func (f *CSVFormatter) FormatMergeResult(result *model.MergeResult) (string, err
 			s.PR.Title,
 			"skipped",
 			s.Reason,
+			joinCodes(s.Codes),
 			s.PR.HTMLURL,
 		}
 		if err := w.Write(row); err != nil {
@@ -106,6 +126,7 @@ func (f *CSVFormatter) FormatMergeResult(result *model.MergeResult) (string, err
 			fail.PR.Title,
 			"failed",
 			fail.Error,
+			"",
 			fail.PR.HTMLURL,
 		}
 		if err := w.Write(row); err != nil {
@@ -123,7 +144,7 @@ func (f *CSVFormatter) FormatReviewResult(result *model.ReviewResult) (string, e
 	w := csv.NewWriter(&buf)
 
 	// Header
-	header := []string{"Repository", "PR Number", "Title", "Status", "Reason", "URL"}
+	header := []string{"Repository", "PR Number", "Title", "Status", "Reason", "Codes", "URL"}
 	if err := w.Write(header); err != nil {
 		return "", err
 	}
@@ -136,6 +157,7 @@ func (f *CSVFormatter) FormatReviewResult(result *model.ReviewResult) (string, e
 			pr.Title,
 			"approved",
 			"",
+			"",
 			pr.HTMLURL,
 		}
 		if err := w.Write(row); err != nil {
@@ -151,6 +173,7 @@ func (f *CSVFormatter) FormatReviewResult(result *model.ReviewResult) (string, e
 			d.PR.Title,
 			"denied",
 			d.Reason,
+			joinCodes(d.Codes),
 			d.PR.HTMLURL,
 		}
 		if err := w.Write(row); err != nil {
@@ -224,3 +247,77 @@ func (f *CSVFormatter) FormatReleaseResult(result *model.ReleaseResult) (string,
 	w.Flush()
 	return buf.String(), w.Error()
 }
+
+// FormatPolicyTrace formats a policy evaluation trace as CSV.
+func (f *CSVFormatter) FormatPolicyTrace(trace *model.PolicyTrace) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	// Header
+	header := []string{"Repository", "PR Number", "Action", "Condition", "Value", "Threshold", "Passed", "Message"}
+	if err := w.Write(header); err != nil {
+		return "", err
+	}
+
+	for _, c := range trace.Conditions {
+		row := []string{
+			trace.PR.Repo.FullName(),
+			fmt.Sprintf("%d", trace.PR.Number),
+			trace.Action,
+			c.Name,
+			c.Value,
+			c.Threshold,
+			fmt.Sprintf("%t", c.Passed),
+			c.Message,
+		}
+		if err := w.Write(row); err != nil {
+			return "", err
+		}
+	}
+
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// FormatPolicyTestReport formats policy test results as CSV.
+func (f *CSVFormatter) FormatPolicyTestReport(report *model.PolicyTestReport) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	// Header
+	header := []string{"Fixture", "File", "Action", "Expected", "Actual", "Passed", "Details"}
+	if err := w.Write(header); err != nil {
+		return "", err
+	}
+
+	for _, r := range report.Results {
+		details := strings.Join(r.Mismatches, "; ")
+		if r.Error != "" {
+			details = r.Error
+		}
+		row := []string{
+			r.Name,
+			r.File,
+			r.Action,
+			decisionString(&r.Expected),
+			decisionString(r.Actual),
+			fmt.Sprintf("%t", r.Passed),
+			details,
+		}
+		if err := w.Write(row); err != nil {
+			return "", err
+		}
+	}
+
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// joinCodes joins reason codes with spaces.
+func joinCodes(codes []model.ReasonCode) string {
+	s := make([]string, 0, len(codes))
+	for _, c := range codes {
+		s = append(s, string(c))
+	}
+	return strings.Join(s, " ")
+}
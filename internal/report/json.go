@@ -36,6 +36,16 @@ func (f *JSONFormatter) FormatReleaseResult(result *model.ReleaseResult) (string
 	return f.marshal(result)
 }
 
+// FormatPolicyTrace formats a policy evaluation trace as JSON.
+func (f *JSONFormatter) FormatPolicyTrace(trace *model.PolicyTrace) (string, error) {
+	return f.marshal(trace)
+}
+
+// FormatPolicyTestReport formats policy test results as JSON.
+func (f *JSONFormatter) FormatPolicyTestReport(report *model.PolicyTestReport) (string, error) {
+	return f.marshal(report)
+}
+
 func (f *JSONFormatter) marshal(v any) (string, error) {
 	var data []byte
 	var err error
@@ -28,8 +28,8 @@ func (f *MarkdownFormatter) FormatScanResult(result *model.ScanResult) (string,
 
 	if len(result.PRs) > 0 {
 		sb.WriteString("## Open Dependency PRs\n\n")
-		sb.WriteString("| Repository | PR | Title | Bot | Update | Age | Tests |\n")
-		sb.WriteString("|------------|-----|-------|-----|--------|-----|-------|\n")
+		sb.WriteString("| Repository | PR | Title | Bot | Update | Severity | Risk | Age | Tests |\n")
+		sb.WriteString("|------------|-----|-------|-----|--------|----------|------|-----|-------|\n")
 
 		for _, pr := range result.PRs {
 			tests := "⏳"
@@ -37,25 +37,23 @@ func (f *MarkdownFormatter) FormatScanResult(result *model.ScanResult) (string,
 				tests = "✅"
 			}
 
-			sb.WriteString(fmt.Sprintf("| %s | [#%d](%s) | %s | %s | %s | %dh | %s |\n",
+			sb.WriteString(fmt.Sprintf("| %s | [#%d](%s) | %s | %s | %s | %s | %s | %dh | %s |\n",
 				pr.Repo.FullName(),
 				pr.Number,
 				pr.HTMLURL,
-				truncate(pr.Title, 50),
+				truncate(pr.Title, 50)+groupMembers(&pr),
 				pr.DependBot,
 				pr.Dependency.UpdateType,
+				severityLabel(pr.Dependency.Severity),
+				riskLabel(pr.Risk),
 				pr.AgeHours(),
 				tests,
 			))
 		}
 	}
 
-	if len(result.Errors) > 0 {
-		sb.WriteString("\n## Errors\n\n")
-		for _, e := range result.Errors {
-			sb.WriteString(fmt.Sprintf("- **%s:** %s\n", e.Repo, e.Message))
-		}
-	}
+	writeMarkdownRepoProfiles(&sb, result.Profiles)
+	writeMarkdownScanErrors(&sb, result.Errors)
 
 	return sb.String(), nil
 }
@@ -77,8 +75,8 @@ func (f *MarkdownFormatter) FormatMergeResult(result *model.MergeResult) (string
 	if len(result.Merged) > 0 {
 		sb.WriteString("## Merged PRs\n\n")
 		for _, m := range result.Merged {
-			sb.WriteString(fmt.Sprintf("- [%s#%d](%s): %s\n",
-				m.PR.Repo.FullName(), m.PR.Number, m.PR.HTMLURL, m.PR.Title))
+			sb.WriteString(fmt.Sprintf("- [%s#%d](%s): %s%s\n",
+				m.PR.Repo.FullName(), m.PR.Number, m.PR.HTMLURL, m.PR.Title, groupMembers(&m.PR)))
 		}
 		sb.WriteString("\n")
 	}
@@ -86,23 +84,86 @@ func (f *MarkdownFormatter) FormatMergeResult(result *model.MergeResult) (string
 	if len(result.Skipped) > 0 {
 		sb.WriteString("## Skipped PRs\n\n")
 		for _, s := range result.Skipped {
-			sb.WriteString(fmt.Sprintf("- [%s#%d](%s): %s - *%s*\n",
-				s.PR.Repo.FullName(), s.PR.Number, s.PR.HTMLURL, s.PR.Title, s.Reason))
+			sb.WriteString(fmt.Sprintf("- [%s#%d](%s): %s - *%s*%s\n",
+				s.PR.Repo.FullName(), s.PR.Number, s.PR.HTMLURL, s.PR.Title, s.Reason, groupMembers(&s.PR)))
 		}
 		sb.WriteString("\n")
+		writeMarkdownReasonCounts(&sb, "Skipped by Reason", result.SkippedByReason)
 	}
 
 	if len(result.Failed) > 0 {
 		sb.WriteString("## Failed PRs\n\n")
 		for _, f := range result.Failed {
-			sb.WriteString(fmt.Sprintf("- [%s#%d](%s): %s - **%s**\n",
-				f.PR.Repo.FullName(), f.PR.Number, f.PR.HTMLURL, f.PR.Title, f.Error))
+			sb.WriteString(fmt.Sprintf("- [%s#%d](%s): %s - **%s**%s\n",
+				f.PR.Repo.FullName(), f.PR.Number, f.PR.HTMLURL, f.PR.Title, f.Error, groupMembers(&f.PR)))
 		}
+		sb.WriteString("\n")
 	}
 
+	writeMarkdownRepoProfiles(&sb, result.Profiles)
+	writeMarkdownScanErrors(&sb, result.Errors)
+
 	return sb.String(), nil
 }
 
+// groupMembers lists the dependencies of a grouped PR, each on its own
+// line, for appending to the PR's entry.
+func groupMembers(pr *model.PullRequest) string {
+	if !pr.IsGroup() {
+		return ""
+	}
+	var members string
+	for _, dep := range pr.Dependencies {
+		members += "<br>• " + dependencySummary(dep)
+	}
+	return members
+}
+
+// writeMarkdownReasonCounts writes the number of PRs for each reason code
+// as a table.
+func writeMarkdownReasonCounts(sb *strings.Builder, title string, counts []model.ReasonCount) {
+	if len(counts) == 0 {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf("## %s\n\n", title))
+	sb.WriteString("| Code | PRs |\n")
+	sb.WriteString("|------|-----|\n")
+	for _, c := range counts {
+		sb.WriteString(fmt.Sprintf("| `%s` | %d |\n", c.Code, c.Count))
+	}
+	sb.WriteString("\n")
+}
+
+// writeMarkdownRepoProfiles writes the effective profiles of repositories
+// with an in-repo config.
+func writeMarkdownRepoProfiles(sb *strings.Builder, profiles []model.RepoProfile) {
+	if len(profiles) == 0 {
+		return
+	}
+
+	sb.WriteString("\n## Repository Overrides\n\n")
+	for _, rp := range profiles {
+		if rp.Disabled {
+			sb.WriteString(fmt.Sprintf("- **%s:** disabled (`%s`)\n", rp.Repo.FullName(), rp.ConfigPath))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("- **%s:** %s\n", rp.Repo.FullName(), profileSummary(&rp.Profile)))
+	}
+}
+
+// writeMarkdownScanErrors writes the repositories that couldn't be read.
+func writeMarkdownScanErrors(sb *strings.Builder, scanErrors []model.ScanError) {
+	if len(scanErrors) == 0 {
+		return
+	}
+
+	sb.WriteString("\n## Errors\n\n")
+	for _, e := range scanErrors {
+		sb.WriteString(fmt.Sprintf("- **%s:** %s\n", e.Repo, e.Message))
+	}
+}
+
 // FormatReviewResult formats a review result as Markdown.
 func (f *MarkdownFormatter) FormatReviewResult(result *model.ReviewResult) (string, error) {
 	var sb strings.Builder
@@ -120,8 +181,8 @@ func (f *MarkdownFormatter) FormatReviewResult(result *model.ReviewResult) (stri
 	if len(result.Approved) > 0 {
 		sb.WriteString("## Approved PRs\n\n")
 		for _, pr := range result.Approved {
-			sb.WriteString(fmt.Sprintf("- [%s#%d](%s): %s\n",
-				pr.Repo.FullName(), pr.Number, pr.HTMLURL, pr.Title))
+			sb.WriteString(fmt.Sprintf("- [%s#%d](%s): %s%s\n",
+				pr.Repo.FullName(), pr.Number, pr.HTMLURL, pr.Title, groupMembers(&pr)))
 		}
 		sb.WriteString("\n")
 	}
@@ -129,11 +190,15 @@ func (f *MarkdownFormatter) FormatReviewResult(result *model.ReviewResult) (stri
 	if len(result.Denied) > 0 {
 		sb.WriteString("## Denied PRs\n\n")
 		for _, d := range result.Denied {
-			sb.WriteString(fmt.Sprintf("- [%s#%d](%s): %s - *%s*\n",
-				d.PR.Repo.FullName(), d.PR.Number, d.PR.HTMLURL, d.PR.Title, d.Reason))
+			sb.WriteString(fmt.Sprintf("- [%s#%d](%s): %s - *%s*%s\n",
+				d.PR.Repo.FullName(), d.PR.Number, d.PR.HTMLURL, d.PR.Title, d.Reason, groupMembers(&d.PR)))
 		}
+		sb.WriteString("\n")
+		writeMarkdownReasonCounts(&sb, "Denied by Reason", result.DeniedByReason)
 	}
 
+	writeMarkdownScanErrors(&sb, result.Errors)
+
 	return sb.String(), nil
 }
 
@@ -178,3 +243,79 @@ func (f *MarkdownFormatter) FormatReleaseResult(result *model.ReleaseResult) (st
 
 	return sb.String(), nil
 }
+
+// FormatPolicyTrace formats a policy evaluation trace as Markdown.
+func (f *MarkdownFormatter) FormatPolicyTrace(trace *model.PolicyTrace) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# Policy Trace: %s [%s#%d](%s)\n\n",
+		trace.Action, trace.PR.Repo.FullName(), trace.PR.Number, trace.PR.HTMLURL))
+	sb.WriteString(fmt.Sprintf("**Title:** %s\n\n", trace.PR.Title))
+	if trace.Profile != "" {
+		sb.WriteString(fmt.Sprintf("**Profile:** %s\n\n", trace.Profile))
+	}
+	if len(trace.Policies) > 0 {
+		sb.WriteString(fmt.Sprintf("**Policies:** %s\n\n", strings.Join(trace.Policies, ", ")))
+	}
+	if trace.Allowed {
+		sb.WriteString("**Decision:** ✅ allowed\n\n")
+	} else {
+		sb.WriteString("**Decision:** ❌ denied\n\n")
+	}
+	if trace.NextWindow != nil {
+		sb.WriteString(fmt.Sprintf("**Next window:** %s\n\n", trace.NextWindow.Format(time.RFC3339)))
+	}
+
+	if len(trace.Conditions) > 0 {
+		sb.WriteString("| Condition | Value | Threshold | Result | Message |\n")
+		sb.WriteString("|-----------|-------|-----------|--------|---------|\n")
+
+		for _, c := range trace.Conditions {
+			result := "✅"
+			message := ""
+			if !c.Passed {
+				result = "❌"
+				message = c.Message
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+				c.Name, c.Value, c.Threshold, result, message))
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// FormatPolicyTestReport formats policy test results as Markdown.
+func (f *MarkdownFormatter) FormatPolicyTestReport(report *model.PolicyTestReport) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("# Policy Tests\n\n")
+	sb.WriteString(fmt.Sprintf("**Generated:** %s\n\n", report.Timestamp.Format(time.RFC3339)))
+	if report.Profile != "" {
+		sb.WriteString(fmt.Sprintf("**Profile:** %s\n\n", report.Profile))
+	}
+	if len(report.Policies) > 0 {
+		sb.WriteString(fmt.Sprintf("**Policies:** %s\n\n", strings.Join(report.Policies, ", ")))
+	}
+	sb.WriteString(fmt.Sprintf("**Passed:** %d | **Failed:** %d\n\n", report.PassedCount, report.FailedCount))
+
+	if len(report.Results) > 0 {
+		sb.WriteString("| Fixture | Action | Expected | Actual | Result | Details |\n")
+		sb.WriteString("|---------|--------|----------|--------|--------|---------|\n")
+
+		for _, r := range report.Results {
+			result := "✅"
+			if !r.Passed {
+				result = "❌"
+			}
+			details := strings.Join(r.Mismatches, "; ")
+			if r.Error != "" {
+				details = r.Error
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+				r.Name, r.Action, decisionString(&r.Expected), decisionString(r.Actual), result, details))
+		}
+	}
+
+	return sb.String(), nil
+}
@@ -15,4 +15,10 @@ type Formatter interface {
 
 	// FormatReleaseResult formats a release result.
 	FormatReleaseResult(result *model.ReleaseResult) (string, error)
+
+	// FormatPolicyTrace formats a policy evaluation trace.
+	FormatPolicyTrace(trace *model.PolicyTrace) (string, error)
+
+	// FormatPolicyTestReport formats the results of policy test fixtures.
+	FormatPolicyTestReport(report *model.PolicyTestReport) (string, error)
 }
@@ -2,6 +2,7 @@ package report
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,41 +24,39 @@ func (f *TableFormatter) FormatScanResult(result *model.ScanResult) (string, err
 	sb.WriteString(fmt.Sprintf("Dependency PR Scan Results (%s)\n", result.Timestamp.Format(time.RFC3339)))
 	sb.WriteString(fmt.Sprintf("Organizations: %s\n", strings.Join(result.Orgs, ", ")))
 	sb.WriteString(fmt.Sprintf("Repositories: %d | PRs Found: %d\n", result.ReposScanned, result.PRsFound))
-	sb.WriteString(strings.Repeat("-", 100) + "\n")
+	sb.WriteString(strings.Repeat("-", 114) + "\n")
 
 	if len(result.PRs) == 0 {
 		sb.WriteString("No dependency PRs found.\n")
-		return sb.String(), nil
-	}
-
-	// Table header
-	sb.WriteString(fmt.Sprintf("%-35s %-6s %-35s %-10s %-8s %-6s %-5s\n",
-		"REPOSITORY", "PR", "TITLE", "BOT", "UPDATE", "AGE", "CI"))
-	sb.WriteString(strings.Repeat("-", 100) + "\n")
+	} else {
+		// Table header
+		sb.WriteString(fmt.Sprintf("%-35s %-6s %-35s %-10s %-8s %-8s %4s %-6s %-5s\n",
+			"REPOSITORY", "PR", "TITLE", "BOT", "UPDATE", "SEVERITY", "RISK", "AGE", "CI"))
+		sb.WriteString(strings.Repeat("-", 114) + "\n")
+
+		for _, pr := range result.PRs {
+			ci := "⏳"
+			if pr.TestsPassed {
+				ci = "✅"
+			}
 
-	for _, pr := range result.PRs {
-		ci := "⏳"
-		if pr.TestsPassed {
-			ci = "✅"
+			sb.WriteString(fmt.Sprintf("%-35s #%-5d %-35s %-10s %-8s %-8s %4s %4dh %-5s\n",
+				truncate(pr.Repo.FullName(), 35),
+				pr.Number,
+				truncate(pr.Title, 35),
+				pr.DependBot,
+				pr.Dependency.UpdateType,
+				severityLabel(pr.Dependency.Severity),
+				riskLabel(pr.Risk),
+				pr.AgeHours(),
+				ci,
+			))
+			writeGroupMembers(&sb, &pr, 43)
 		}
-
-		sb.WriteString(fmt.Sprintf("%-35s #%-5d %-35s %-10s %-8s %4dh %-5s\n",
-			truncate(pr.Repo.FullName(), 35),
-			pr.Number,
-			truncate(pr.Title, 35),
-			pr.DependBot,
-			pr.Dependency.UpdateType,
-			pr.AgeHours(),
-			ci,
-		))
 	}
 
-	if len(result.Errors) > 0 {
-		sb.WriteString("\nErrors:\n")
-		for _, e := range result.Errors {
-			sb.WriteString(fmt.Sprintf("  %s: %s\n", e.Repo, e.Message))
-		}
-	}
+	writeRepoProfiles(&sb, result.Profiles)
+	writeScanErrors(&sb, result.Errors)
 
 	return sb.String(), nil
 }
@@ -81,6 +80,7 @@ func (f *TableFormatter) FormatMergeResult(result *model.MergeResult) (string, e
 		for _, m := range result.Merged {
 			sb.WriteString(fmt.Sprintf("  ✅ %s#%d: %s\n",
 				m.PR.Repo.FullName(), m.PR.Number, truncate(m.PR.Title, 50)))
+			writeGroupMembers(&sb, &m.PR, 4)
 		}
 	}
 
@@ -89,7 +89,9 @@ func (f *TableFormatter) FormatMergeResult(result *model.MergeResult) (string, e
 		for _, s := range result.Skipped {
 			sb.WriteString(fmt.Sprintf("  ⏭️  %s#%d: %s (%s)\n",
 				s.PR.Repo.FullName(), s.PR.Number, truncate(s.PR.Title, 40), s.Reason))
+			writeGroupMembers(&sb, &s.PR, 4)
 		}
+		writeReasonCounts(&sb, "Skipped by reason", result.SkippedByReason)
 	}
 
 	if len(result.Failed) > 0 {
@@ -97,9 +99,13 @@ func (f *TableFormatter) FormatMergeResult(result *model.MergeResult) (string, e
 		for _, fail := range result.Failed {
 			sb.WriteString(fmt.Sprintf("  ❌ %s#%d: %s (%s)\n",
 				fail.PR.Repo.FullName(), fail.PR.Number, truncate(fail.PR.Title, 40), fail.Error))
+			writeGroupMembers(&sb, &fail.PR, 4)
 		}
 	}
 
+	writeRepoProfiles(&sb, result.Profiles)
+	writeScanErrors(&sb, result.Errors)
+
 	return sb.String(), nil
 }
 
@@ -122,6 +128,7 @@ func (f *TableFormatter) FormatReviewResult(result *model.ReviewResult) (string,
 		for _, pr := range result.Approved {
 			sb.WriteString(fmt.Sprintf("  ✅ %s#%d: %s\n",
 				pr.Repo.FullName(), pr.Number, truncate(pr.Title, 50)))
+			writeGroupMembers(&sb, &pr, 4)
 		}
 	}
 
@@ -130,9 +137,13 @@ func (f *TableFormatter) FormatReviewResult(result *model.ReviewResult) (string,
 		for _, d := range result.Denied {
 			sb.WriteString(fmt.Sprintf("  ❌ %s#%d: %s (%s)\n",
 				d.PR.Repo.FullName(), d.PR.Number, truncate(d.PR.Title, 40), d.Reason))
+			writeGroupMembers(&sb, &d.PR, 4)
 		}
+		writeReasonCounts(&sb, "Denied by reason", result.DeniedByReason)
 	}
 
+	writeScanErrors(&sb, result.Errors)
+
 	return sb.String(), nil
 }
 
@@ -175,6 +186,251 @@ func (f *TableFormatter) FormatReleaseResult(result *model.ReleaseResult) (strin
 	return sb.String(), nil
 }
 
+// FormatPolicyTrace formats a policy evaluation trace as a text table.
+func (f *TableFormatter) FormatPolicyTrace(trace *model.PolicyTrace) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Policy Trace: %s %s#%d\n", trace.Action, trace.PR.Repo.FullName(), trace.PR.Number))
+	sb.WriteString(fmt.Sprintf("Title: %s\n", trace.PR.Title))
+	if trace.Profile != "" {
+		sb.WriteString(fmt.Sprintf("Profile: %s\n", trace.Profile))
+	}
+	if len(trace.Policies) > 0 {
+		sb.WriteString(fmt.Sprintf("Policies: %s\n", strings.Join(trace.Policies, ", ")))
+	}
+	if trace.Allowed {
+		sb.WriteString("Decision: ✅ allowed\n")
+	} else {
+		sb.WriteString("Decision: ❌ denied\n")
+	}
+	if trace.NextWindow != nil {
+		sb.WriteString(fmt.Sprintf("Next window: %s\n", trace.NextWindow.Format(time.RFC3339)))
+	}
+	sb.WriteString(strings.Repeat("-", 80) + "\n")
+
+	table := Table{
+		Headers: []string{"CONDITION", "VALUE", "THRESHOLD", "RESULT", "MESSAGE"},
+	}
+	for _, c := range trace.Conditions {
+		result := "pass"
+		message := ""
+		if !c.Passed {
+			result = "FAIL"
+			message = c.Message
+		}
+		table.Rows = append(table.Rows, TableRow{
+			Cells: []string{c.Name, c.Value, c.Threshold, result, message},
+		})
+	}
+	sb.WriteString(table.Render())
+
+	return sb.String(), nil
+}
+
+// FormatPolicyTestReport formats policy test results as a text table.
+func (f *TableFormatter) FormatPolicyTestReport(report *model.PolicyTestReport) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Policy Tests (%s)\n", report.Timestamp.Format(time.RFC3339)))
+	if report.Profile != "" {
+		sb.WriteString(fmt.Sprintf("Profile: %s\n", report.Profile))
+	}
+	if len(report.Policies) > 0 {
+		sb.WriteString(fmt.Sprintf("Policies: %d loaded\n", len(report.Policies)))
+	}
+	sb.WriteString(fmt.Sprintf("Passed: %d, Failed: %d\n", report.PassedCount, report.FailedCount))
+	sb.WriteString(strings.Repeat("-", 80) + "\n")
+
+	if len(report.Results) == 0 {
+		sb.WriteString("No fixtures found.\n")
+		return sb.String(), nil
+	}
+
+	table := Table{
+		Headers: []string{"NAME", "ACTION", "EXPECTED", "ACTUAL", "RESULT"},
+	}
+	for _, r := range report.Results {
+		result := "pass"
+		if !r.Passed {
+			result = "FAIL"
+		}
+		table.Rows = append(table.Rows, TableRow{
+			Cells: []string{
+				truncate(r.Name, 40),
+				r.Action,
+				decisionString(&r.Expected),
+				decisionString(r.Actual),
+				result,
+			},
+		})
+	}
+	sb.WriteString(table.Render())
+
+	for _, r := range report.Results {
+		if r.Passed {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\nFAIL %s (%s)\n", r.Name, r.File))
+		if r.Error != "" {
+			sb.WriteString(fmt.Sprintf("  error: %s\n", r.Error))
+		}
+		for _, m := range r.Mismatches {
+			sb.WriteString(fmt.Sprintf("  %s\n", m))
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// decisionString returns a short description of a policy decision.
+func decisionString(d *model.PolicyDecision) string {
+	switch {
+	case d == nil:
+		return "-"
+	case d.Allowed:
+		return "allowed"
+	default:
+		return "denied"
+	}
+}
+
+// writeScanErrors writes the repositories that couldn't be read.
+func writeScanErrors(sb *strings.Builder, scanErrors []model.ScanError) {
+	if len(scanErrors) == 0 {
+		return
+	}
+
+	sb.WriteString("\nErrors:\n")
+	for _, e := range scanErrors {
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", e.Repo, e.Message))
+	}
+}
+
+// writeRepoProfiles writes the effective profiles of repositories with
+// an in-repo config.
+func writeRepoProfiles(sb *strings.Builder, profiles []model.RepoProfile) {
+	if len(profiles) == 0 {
+		return
+	}
+
+	sb.WriteString("\nRepository Overrides:\n")
+	for _, rp := range profiles {
+		if rp.Disabled {
+			sb.WriteString(fmt.Sprintf("  🚫 %s: disabled (%s)\n", rp.Repo.FullName(), rp.ConfigPath))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  ⚙️  %s: %s\n", rp.Repo.FullName(), profileSummary(&rp.Profile)))
+	}
+}
+
+// profileSummary returns a one-line summary of a merge profile.
+func profileSummary(p *model.MergeProfile) string {
+	var updates []string
+	if p.AutoMergePatch {
+		updates = append(updates, string(model.UpdateTypePatch))
+	}
+	if p.AutoMergeMinor {
+		updates = append(updates, string(model.UpdateTypeMinor))
+	}
+	if p.AutoMergeMajor {
+		updates = append(updates, string(model.UpdateTypeMajor))
+	}
+	if len(updates) == 0 {
+		updates = append(updates, "none")
+	}
+
+	parts := []string{
+		p.Name,
+		fmt.Sprintf("min age %dh", p.MinAgeHours),
+	}
+	if p.MinReleaseAgeHours > 0 {
+		parts = append(parts, fmt.Sprintf("min release age %dh", p.MinReleaseAgeHours))
+	}
+	if p.SecurityFixesSkipAge {
+		parts = append(parts, "security fixes skip age")
+	}
+	if p.MaxRiskScore > 0 {
+		parts = append(parts, fmt.Sprintf("max risk %d", p.MaxRiskScore))
+	}
+	if p.RequiredApprovals > 0 {
+		parts = append(parts, fmt.Sprintf("%d approvals", p.RequiredApprovals))
+	}
+	parts = append(parts, "auto-merge "+strings.Join(updates, "/"))
+	if len(p.AllowDependencies) > 0 {
+		parts = append(parts, "allow "+strings.Join(p.AllowDependencies, ", "))
+	}
+	if len(p.DenyDependencies) > 0 {
+		parts = append(parts, "deny "+strings.Join(p.DenyDependencies, ", "))
+	}
+	if len(p.DependencyRules) > 0 {
+		parts = append(parts, fmt.Sprintf("%d dependency rules", len(p.DependencyRules)))
+	}
+	if len(p.RequiredChecks) > 0 {
+		parts = append(parts, "required checks "+strings.Join(p.RequiredChecks, ", "))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// writeGroupMembers lists the dependencies of a grouped PR below its line,
+// indented by indent columns.
+func writeGroupMembers(sb *strings.Builder, pr *model.PullRequest, indent int) {
+	if !pr.IsGroup() {
+		return
+	}
+	for _, dep := range pr.Dependencies {
+		sb.WriteString(fmt.Sprintf("%*s └ %s\n", indent, "", dependencySummary(dep)))
+	}
+}
+
+// dependencySummary returns a one-line description of a dependency update,
+// such as "golang.org/x/net v0.29.0 → v0.30.0 (minor)".
+func dependencySummary(dep model.Dependency) string {
+	s := dep.Name
+	switch {
+	case dep.FromVersion != "" && dep.ToVersion != "":
+		s += fmt.Sprintf(" %s → %s", dep.FromVersion, dep.ToVersion)
+	case dep.ToVersion != "":
+		s += " → " + dep.ToVersion
+	}
+	if dep.UpdateType != "" {
+		s += fmt.Sprintf(" (%s)", dep.UpdateType)
+	}
+	if dep.FixesVulnerabilities() {
+		s += fmt.Sprintf(" [security fix: %s]", dep.Severity)
+	}
+	return s
+}
+
+// severityLabel returns the severity of the vulnerabilities fixed by an
+// update, or "-" if it fixes none.
+func severityLabel(s model.Severity) string {
+	if s == model.SeverityNone {
+		return "-"
+	}
+	return string(s)
+}
+
+// writeReasonCounts writes the number of PRs for each reason code.
+func writeReasonCounts(sb *strings.Builder, title string, counts []model.ReasonCount) {
+	if len(counts) == 0 {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf("\n%s:\n", title))
+	for _, c := range counts {
+		sb.WriteString(fmt.Sprintf("  %-26s %d\n", c.Code, c.Count))
+	}
+}
+
+// riskLabel returns the risk score of a PR, or "-" if it wasn't scored.
+func riskLabel(risk *model.RiskScore) string {
+	if risk == nil {
+		return "-"
+	}
+	return strconv.Itoa(risk.Score)
+}
+
 // truncate shortens a string to maxLen, adding "..." if truncated.
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -0,0 +1,96 @@
+// Package rest sends JSON requests to the REST APIs of code hosting
+// platforms. The platform clients, such as the gitlab and gitea packages,
+// add their endpoints, error format and pagination.
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Client sends requests relative to a base URL.
+type Client struct {
+	baseURL    string // ends with /
+	httpClient *http.Client
+	newError   func(resp *http.Response) error
+}
+
+// NewClient creates a client for the API at baseURL, which must end with
+// a slash. Requests are sent with httpClient, or http.DefaultClient if
+// nil. Responses with a status of 300 or above are turned into an error
+// by newError.
+func NewClient(httpClient *http.Client, baseURL string, newError func(resp *http.Response) error) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{baseURL: baseURL, httpClient: httpClient, newError: newError}
+}
+
+// BaseURL returns the API base URL.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Do sends a request with body, if not nil, encoded as JSON and decodes
+// the JSON response into v, if not nil. If v is a *[]byte, it receives
+// the raw response body instead. The response is returned, with its body
+// closed, so callers can read pagination headers.
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, body, v any) (*http.Response, error) {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		return resp, c.newError(resp)
+	}
+
+	if v == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp, nil
+	}
+	if raw, ok := v.(*[]byte); ok {
+		*raw, err = io.ReadAll(resp.Body)
+		return resp, err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return resp, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ReadError returns the body of an error response, limited to 64 KiB.
+func ReadError(resp *http.Response) []byte {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	return data
+}
@@ -1,5 +1,10 @@
 package model
 
+import (
+	"slices"
+	"time"
+)
+
 // PolicyContext provides context for Cedar policy evaluation.
 // This struct is serialized to JSON for Cedar entity evaluation.
 type PolicyContext struct {
@@ -7,6 +12,21 @@ type PolicyContext struct {
 	PR         PRContext         `json:"pr"`
 	Dependency DependencyContext `json:"dependency"`
 	CI         CIContext         `json:"ci"`
+	Risk       RiskScore         `json:"risk"`
+}
+
+// RiskScore rates how risky merging a PR is, from 0 (no known risk) to
+// 100, with the factors that contributed to it.
+type RiskScore struct {
+	Score   int          `json:"score"`
+	Factors []RiskFactor `json:"factors"`
+}
+
+// RiskFactor is one contribution to a risk score.
+type RiskFactor struct {
+	Name   string `json:"name"`
+	Points int    `json:"points"`
+	Detail string `json:"detail"`
 }
 
 // RepoContext contains repository information for policy evaluation.
@@ -34,6 +54,13 @@ type PRContext struct {
 	Draft        bool     `json:"draft"`
 	Labels       []string `json:"labels"`
 	HasConflicts bool     `json:"hasConflicts"`
+
+	// DependencyCount is the number of dependencies the PR updates;
+	// more than one for grouped PRs.
+	DependencyCount int `json:"dependencyCount"`
+
+	// Approvals is the number of users whose review approves the PR.
+	Approvals int `json:"approvals"`
 }
 
 // DependencyContext contains dependency update information for policy evaluation.
@@ -43,9 +70,20 @@ type DependencyContext struct {
 	FromVersion string `json:"fromVersion"`
 	ToVersion   string `json:"toVersion"`
 	UpdateType  string `json:"updateType"`
+	DepType     string `json:"depType"`
+	Manager     string `json:"manager"`
 	IsMajor     bool   `json:"isMajor"`
 	IsMinor     bool   `json:"isMinor"`
 	IsPatch     bool   `json:"isPatch"`
+
+	// ReleaseAgeHours is the number of hours since ToVersion was
+	// published, or -1 if unknown.
+	ReleaseAgeHours int `json:"releaseAgeHours"`
+
+	// FixesVulnerabilities is true if the update fixes a known
+	// vulnerability; Severity is the highest severity it fixes, or "none".
+	FixesVulnerabilities bool   `json:"fixesVulnerabilities"`
+	Severity             string `json:"severity"`
 }
 
 // CIContext contains CI/test status for policy evaluation.
@@ -57,6 +95,11 @@ type CIContext struct {
 	FailedChecks   []string `json:"failedChecks"`
 	PendingChecks  []string `json:"pendingChecks"`
 	RequiredPassed bool     `json:"requiredPassed"`
+
+	// RequiredChecks are the checks that must pass, from the profile and
+	// branch protection. MissingChecks are required checks with no run.
+	RequiredChecks []string `json:"requiredChecks"`
+	MissingChecks  []string `json:"missingChecks"`
 }
 
 // PolicyAction represents an action that can be evaluated against policies.
@@ -69,11 +112,170 @@ const (
 )
 
 // PolicyDecision represents the result of policy evaluation.
+// Details holds the structured form of each of Reasons, in the same order.
 type PolicyDecision struct {
 	Allowed  bool     `json:"allowed"`
 	Action   string   `json:"action"`
 	Reasons  []string `json:"reasons,omitempty"`
+	Details  []Reason `json:"details,omitempty"`
 	Policies []string `json:"policies,omitempty"`
+
+	// NextWindow is the next time the action is allowed by the merge
+	// windows and freezes, set when they deny it.
+	NextWindow *time.Time `json:"nextWindow,omitempty"`
+}
+
+// Codes returns the distinct reason codes of the decision, in order.
+func (d *PolicyDecision) Codes() []ReasonCode {
+	var codes []ReasonCode
+	for _, r := range d.Details {
+		if !slices.Contains(codes, r.Code) {
+			codes = append(codes, r.Code)
+		}
+	}
+	return codes
+}
+
+// ReasonCode is a stable code for why a policy denied an action or a PR
+// was skipped, for aggregating results across runs.
+type ReasonCode string
+
+const (
+	ReasonAgeTooYoung            ReasonCode = "AGE_TOO_YOUNG"
+	ReasonAgeTooOld              ReasonCode = "AGE_TOO_OLD"
+	ReasonReleaseTooRecent       ReasonCode = "RELEASE_TOO_RECENT"
+	ReasonReleaseAgeUnknown      ReasonCode = "RELEASE_AGE_UNKNOWN"
+	ReasonUpdateTypeNotAllowed   ReasonCode = "UPDATE_TYPE_NOT_ALLOWED"
+	ReasonDependencyDenied       ReasonCode = "DEPENDENCY_DENIED"
+	ReasonApprovalRequired       ReasonCode = "APPROVAL_REQUIRED"
+	ReasonApprovalsMissing       ReasonCode = "APPROVALS_MISSING"
+	ReasonFilesNotAllowed        ReasonCode = "FILES_NOT_ALLOWED"
+	ReasonCommitAuthorNotAllowed ReasonCode = "COMMIT_AUTHOR_NOT_ALLOWED"
+	ReasonCIFailed               ReasonCode = "CI_FAILED"
+	ReasonCIPending              ReasonCode = "CI_PENDING"
+	ReasonCIMissing              ReasonCode = "CI_MISSING"
+	ReasonNotMergeable           ReasonCode = "NOT_MERGEABLE"
+	ReasonDraft                  ReasonCode = "DRAFT"
+	ReasonRiskTooHigh            ReasonCode = "RISK_TOO_HIGH"
+	ReasonOutsideMergeWindow     ReasonCode = "OUTSIDE_MERGE_WINDOW"
+	ReasonChangeFreeze           ReasonCode = "CHANGE_FREEZE"
+	ReasonPolicyForbidden        ReasonCode = "POLICY_FORBIDDEN"
+	ReasonPolicyNotPermitted     ReasonCode = "POLICY_NOT_PERMITTED"
+	ReasonPolicyError            ReasonCode = "POLICY_ERROR"
+	ReasonApprovalFailed         ReasonCode = "APPROVAL_FAILED"
+	ReasonProtectionUnavailable  ReasonCode = "BRANCH_PROTECTION_UNAVAILABLE"
+	ReasonChecksUnavailable      ReasonCode = "CHECKS_UNAVAILABLE"
+	ReasonPRInfoUnavailable      ReasonCode = "PR_INFO_UNAVAILABLE"
+	ReasonNotOpen                ReasonCode = "NOT_OPEN"
+	ReasonUnknown                ReasonCode = "UNKNOWN"
+)
+
+// Reason is a structured reason a policy denied an action. Condition,
+// Value and Threshold are those of the failed policy condition, if any;
+// Dependency names the package of a grouped PR the reason applies to.
+type Reason struct {
+	Code       ReasonCode `json:"code"`
+	Message    string     `json:"message"`
+	Condition  string     `json:"condition,omitempty"`
+	Value      string     `json:"value,omitempty"`
+	Threshold  string     `json:"threshold,omitempty"`
+	Dependency string     `json:"dependency,omitempty"`
+}
+
+// ReasonCount is the number of PRs denied or skipped for a reason code.
+type ReasonCount struct {
+	Code  ReasonCode `json:"code"`
+	Count int        `json:"count"`
+}
+
+// CountReasons counts the reason codes of each decision, counting a code
+// once per decision, most frequent first.
+func CountReasons(codes ...[]ReasonCode) []ReasonCount {
+	var counts []ReasonCount
+	for _, cs := range codes {
+		for _, code := range cs {
+			i := slices.IndexFunc(counts, func(c ReasonCount) bool { return c.Code == code })
+			if i < 0 {
+				counts = append(counts, ReasonCount{Code: code})
+				i = len(counts) - 1
+			}
+			counts[i].Count++
+		}
+	}
+	slices.SortStableFunc(counts, func(a, b ReasonCount) int {
+		return b.Count - a.Count
+	})
+	return counts
+}
+
+// PolicyTrace is a full trace of a policy evaluation for a PR.
+// Unlike PolicyDecision, it records every condition checked rather than
+// stopping at the first failure.
+type PolicyTrace struct {
+	Action     string            `json:"action"`
+	PR         PullRequest       `json:"pr"`
+	Profile    string            `json:"profile,omitempty"`
+	Allowed    bool              `json:"allowed"`
+	Conditions []PolicyCondition `json:"conditions"`
+	Policies   []string          `json:"policies,omitempty"`
+	NextWindow *time.Time        `json:"nextWindow,omitempty"`
+}
+
+// PolicyCondition is a single condition checked during policy evaluation.
+// Code is the reason code reported if the condition fails; Dependency is
+// set for conditions on one package of a grouped PR.
+type PolicyCondition struct {
+	Name       string     `json:"name"`
+	Value      string     `json:"value"`
+	Threshold  string     `json:"threshold,omitempty"`
+	Passed     bool       `json:"passed"`
+	Code       ReasonCode `json:"code,omitempty"`
+	Message    string     `json:"message,omitempty"`
+	Dependency string     `json:"dependency,omitempty"`
+}
+
+// Reason returns the structured reason for a failed condition.
+func (c PolicyCondition) Reason() Reason {
+	code := c.Code
+	if code == "" {
+		code = ReasonUnknown
+	}
+	return Reason{
+		Code:       code,
+		Message:    c.Message,
+		Condition:  c.Name,
+		Value:      c.Value,
+		Threshold:  c.Threshold,
+		Dependency: c.Dependency,
+	}
+}
+
+// FailedConditions returns the conditions that did not pass.
+func (t *PolicyTrace) FailedConditions() []PolicyCondition {
+	var failed []PolicyCondition
+	for _, c := range t.Conditions {
+		if !c.Passed {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// Decision summarizes the trace as a PolicyDecision.
+func (t *PolicyTrace) Decision() *PolicyDecision {
+	d := &PolicyDecision{
+		Allowed:    t.Allowed,
+		Action:     t.Action,
+		Policies:   t.Policies,
+		NextWindow: t.NextWindow,
+	}
+	if !t.Allowed {
+		for _, c := range t.FailedConditions() {
+			d.Reasons = append(d.Reasons, c.Message)
+			d.Details = append(d.Details, c.Reason())
+		}
+	}
+	return d
 }
 
 // MergeProfile defines a set of merge policies and behaviors.
@@ -81,25 +283,115 @@ type MergeProfile struct {
 	Name        string `json:"name" yaml:"name"`
 	Description string `json:"description" yaml:"description"`
 
-	// Timing controls
-	MinAgeHours int `json:"minAgeHours" yaml:"minAgeHours"`
-	MaxAgeHours int `json:"maxAgeHours" yaml:"maxAgeHours"`
+	// Timing controls. MinReleaseAgeHours is the minimum time since the
+	// new version of each dependency was published; versions with an
+	// unknown publish time are held back.
+	// With SecurityFixesSkipAge, PRs fixing a known vulnerability are
+	// exempt from both minimum ages.
+	MinAgeHours          int  `json:"minAgeHours" yaml:"minAgeHours"`
+	MaxAgeHours          int  `json:"maxAgeHours" yaml:"maxAgeHours"`
+	MinReleaseAgeHours   int  `json:"minReleaseAgeHours" yaml:"minReleaseAgeHours"`
+	SecurityFixesSkipAge bool `json:"securityFixesSkipAge" yaml:"securityFixesSkipAge"`
 
 	// Update type controls
 	AutoMergePatch bool `json:"autoMergePatch" yaml:"autoMergePatch"`
 	AutoMergeMinor bool `json:"autoMergeMinor" yaml:"autoMergeMinor"`
 	AutoMergeMajor bool `json:"autoMergeMajor" yaml:"autoMergeMajor"`
 
-	// CI requirements
+	// CI requirements. When RequiredChecks is set (or the repository's
+	// branch protection requires checks), only those checks are evaluated.
 	RequireAllChecks   bool     `json:"requireAllChecks" yaml:"requireAllChecks"`
 	RequiredChecks     []string `json:"requiredChecks,omitempty" yaml:"requiredChecks,omitempty"`
 	AllowPendingChecks bool     `json:"allowPendingChecks" yaml:"allowPendingChecks"`
 
+	// Review requirements. RequiredApprovals is the number of approving
+	// reviews a PR needs to be merged; the repository's branch protection
+	// can raise it.
+	RequiredApprovals int `json:"requiredApprovals,omitempty" yaml:"requiredApprovals,omitempty"`
+
 	// Merge settings
 	MergeStrategy string `json:"mergeStrategy" yaml:"mergeStrategy"` // merge, squash, rebase
 	DeleteBranch  bool   `json:"deleteBranch" yaml:"deleteBranch"`
 
-	// Safety
+	// Safety. PRs with a risk score above MaxRiskScore are not merged;
+	// 0 means no limit.
 	RequireApproval bool `json:"requireApproval" yaml:"requireApproval"`
 	MaxPRsPerRun    int  `json:"maxPRsPerRun" yaml:"maxPRsPerRun"`
+	MaxRiskScore    int  `json:"maxRiskScore" yaml:"maxRiskScore"`
+
+	// Dependency controls. Entries are dependency names or glob patterns
+	// such as "golang.org/x/*". An empty allow list allows everything.
+	AllowDependencies []string `json:"allowDependencies,omitempty" yaml:"allowDependencies,omitempty"`
+	DenyDependencies  []string `json:"denyDependencies,omitempty" yaml:"denyDependencies,omitempty"`
+
+	// DependencyRules are evaluated in order; the first rule matching a
+	// dependency replaces the AutoMerge* update type settings for it.
+	DependencyRules []DependencyRule `json:"dependencyRules,omitempty" yaml:"dependencyRules,omitempty"`
+
+	// Contents. A PR may only change files matching AllowedFiles (the
+	// usual manifests and lockfiles if empty), and its commits must be
+	// linked to an account matching its bot's authors or
+	// AllowedCommitAuthors. Both are glob patterns; unlinked authors only
+	// match AllowedCommitAuthors entries starting with "unlinked:".
+	AllowedFiles         []string `json:"allowedFiles,omitempty" yaml:"allowedFiles,omitempty"`
+	AllowedCommitAuthors []string `json:"allowedCommitAuthors,omitempty" yaml:"allowedCommitAuthors,omitempty"`
+
+	// Scheduling. Merges and releases are allowed only inside a merge
+	// window (at any time if none are set) and outside every freeze.
+	// FreezeCalendar is an iCal or YAML file with additional freezes.
+	MergeWindows   []MergeWindow `json:"mergeWindows,omitempty" yaml:"mergeWindows,omitempty"`
+	Freezes        []Freeze      `json:"freezes,omitempty" yaml:"freezes,omitempty"`
+	FreezeCalendar string        `json:"freezeCalendar,omitempty" yaml:"freezeCalendar,omitempty"`
+}
+
+// MergeWindow is a recurring weekly time range in which merges are allowed.
+type MergeWindow struct {
+	// Days are weekday names or ranges such as "mon-fri".
+	// Empty means every day.
+	Days []string `json:"days,omitempty" yaml:"days,omitempty"`
+
+	// Start and End are times of day in 24h "HH:MM" format.
+	// End is exclusive and must be after Start.
+	Start string `json:"start" yaml:"start"`
+	End   string `json:"end" yaml:"end"`
+
+	// Timezone is an IANA timezone name. Default is UTC.
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+// Freeze is a named period in which merges and releases are not allowed.
+// End is exclusive.
+type Freeze struct {
+	Name  string    `json:"name" yaml:"name"`
+	Start time.Time `json:"start" yaml:"start"`
+	End   time.Time `json:"end" yaml:"end"`
+}
+
+// RuleAction is the action a dependency rule takes for matching dependencies.
+type RuleAction string
+
+const (
+	RuleActionAllow           RuleAction = "allow"
+	RuleActionDeny            RuleAction = "deny"
+	RuleActionRequireApproval RuleAction = "require-approval"
+)
+
+// DependencyRule applies an action to dependencies matching a name glob
+// and ecosystem.
+type DependencyRule struct {
+	// Name identifies the rule in decision reasons. Defaults to Match.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Match is a glob for the dependency name; "*" matches any characters,
+	// including "/". Empty matches every dependency.
+	Match string `json:"match,omitempty" yaml:"match,omitempty"`
+
+	// Ecosystem restricts the rule to one ecosystem, e.g. "go" or "npm".
+	Ecosystem string `json:"ecosystem,omitempty" yaml:"ecosystem,omitempty"`
+
+	// UpdateTypes are the update types allowed by an allow rule.
+	// Empty allows every update type.
+	UpdateTypes []UpdateType `json:"updateTypes,omitempty" yaml:"updateTypes,omitempty"`
+
+	Action RuleAction `json:"action" yaml:"action"`
 }
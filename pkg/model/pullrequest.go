@@ -1,33 +1,80 @@
 package model
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
 
 // PullRequest represents a GitHub pull request, with dependency-specific metadata.
+// A grouped PR updating several packages lists them in Dependencies, and
+// Dependency holds their aggregate (see GroupDependency).
 type PullRequest struct {
-	Number       int        `json:"number"`
-	Title        string     `json:"title"`
-	Body         string     `json:"body,omitempty"`
-	State        string     `json:"state"` // open, closed
-	Author       string     `json:"author"`
-	HTMLURL      string     `json:"htmlUrl"`
-	IsDependency bool       `json:"isDependency"`
-	DependBot    DependBot  `json:"dependBot,omitempty"`
-	Dependency   Dependency `json:"dependency,omitempty"`
-	TestsPassed  bool       `json:"testsPassed"`
-	Mergeable    bool       `json:"mergeable"`
-	MergeableStr string     `json:"mergeableState,omitempty"`
-	Draft        bool       `json:"draft"`
-	Labels       []string   `json:"labels,omitempty"`
-	CreatedAt    time.Time  `json:"createdAt"`
-	UpdatedAt    time.Time  `json:"updatedAt"`
-	MergedAt     *time.Time `json:"mergedAt,omitempty"`
-	Repo         RepoRef    `json:"repo"`
-}
-
-// DependBot identifies the dependency management bot.
+	Number          int          `json:"number"`
+	Title           string       `json:"title"`
+	Body            string       `json:"body,omitempty"`
+	State           string       `json:"state"` // open, closed
+	Author          string       `json:"author"`
+	HTMLURL         string       `json:"htmlUrl"`
+	IsDependency    bool         `json:"isDependency"`
+	DependBot       DependBot    `json:"dependBot,omitempty"`
+	BotAuthors      []string     `json:"botAuthors,omitempty"` // author login patterns of the detected bot
+	Dependency      Dependency   `json:"dependency,omitempty"`
+	Dependencies    []Dependency `json:"dependencies,omitempty"`
+	TestsPassed     bool         `json:"testsPassed"`
+	Mergeable       bool         `json:"mergeable"`
+	MergeableStr    string       `json:"mergeableState,omitempty"`
+	Draft           bool         `json:"draft"`
+	Labels          []string     `json:"labels,omitempty"`
+	HeadBranch      string       `json:"headBranch,omitempty"`
+	Files           []string     `json:"files,omitempty"`           // changed files, if listed
+	CommitAuthors   []string     `json:"commitAuthors,omitempty"`   // distinct commit authors, if listed (see UnlinkedAuthor)
+	Approvers       []string     `json:"approvers,omitempty"`       // users whose review approves it, if listed
+	Vulnerabilities []string     `json:"vulnerabilities,omitempty"` // IDs of the advisories the PR fixes
+	Risk            *RiskScore   `json:"risk,omitempty"`            // risk of merging, if scored
+	CreatedAt       time.Time    `json:"createdAt"`
+	UpdatedAt       time.Time    `json:"updatedAt"`
+	MergedAt        *time.Time   `json:"mergedAt,omitempty"`
+	Repo            RepoRef      `json:"repo"`
+}
+
+// UnlinkedAuthorPrefix marks a commit author that isn't linked to an
+// account, e.g. "unlinked:Renovate Bot". The name of such an author is
+// taken from the commit and can be set by anyone.
+const UnlinkedAuthorPrefix = "unlinked:"
+
+// UnlinkedAuthor returns the commit author for a commit that isn't linked
+// to an account.
+func UnlinkedAuthor(name string) string {
+	if name == "" {
+		name = "unknown"
+	}
+	return UnlinkedAuthorPrefix + name
+}
+
+// ParsePRRef parses a PR reference like "owner/repo#123".
+func ParsePRRef(s string) (RepoRef, int, error) {
+	idx := strings.LastIndex(s, "#")
+	if idx == -1 {
+		return RepoRef{}, 0, fmt.Errorf("invalid PR reference %q, expected owner/repo#number", s)
+	}
+
+	ref := ParseRepoRef(s[:idx])
+	if ref.Owner == "" || ref.Name == "" {
+		return RepoRef{}, 0, fmt.Errorf("invalid PR reference %q, expected owner/repo#number", s)
+	}
+
+	number, err := strconv.Atoi(s[idx+1:])
+	if err != nil || number <= 0 {
+		return RepoRef{}, 0, fmt.Errorf("invalid PR number in %q", s)
+	}
+
+	return ref, number, nil
+}
+
+// DependBot identifies the dependency management bot. Besides the
+// built-in bots, it is the name of a bot configured in the bots config.
 type DependBot string
 
 const (
@@ -36,7 +83,8 @@ const (
 	DependBotDependabot DependBot = "dependabot"
 )
 
-// DetectDependBot determines which dependency bot created a PR based on author.
+// DetectDependBot determines which built-in dependency bot created a PR
+// based on author.
 func DetectDependBot(author string) DependBot {
 	lower := strings.ToLower(author)
 	switch {
@@ -51,13 +99,41 @@ func DetectDependBot(author string) DependBot {
 
 // Dependency represents a dependency update in a PR.
 type Dependency struct {
-	Name        string     `json:"name"`
-	Ecosystem   string     `json:"ecosystem"` // go, npm, pip, maven, etc.
-	FromVersion string     `json:"fromVersion"`
-	ToVersion   string     `json:"toVersion"`
-	UpdateType  UpdateType `json:"updateType"` // major, minor, patch
+	Name        string         `json:"name"`
+	Ecosystem   string         `json:"ecosystem"` // go, npm, pip, maven, etc.
+	FromVersion string         `json:"fromVersion"`
+	ToVersion   string         `json:"toVersion"`
+	UpdateType  UpdateType     `json:"updateType"`           // major, minor, patch
+	DepType     DependencyType `json:"depType,omitempty"`    // direct, indirect, development
+	Manager     string         `json:"manager,omitempty"`    // bot's package manager, e.g. gomod or npm_and_yarn
+	ReleasedAt  *time.Time     `json:"releasedAt,omitempty"` // when ToVersion was published, if known
+	Severity    Severity       `json:"severity,omitempty"`   // highest severity of the vulnerabilities fixed, if any
+}
+
+// FixesVulnerabilities returns true if the update fixes a known
+// vulnerability.
+func (d *Dependency) FixesVulnerabilities() bool {
+	return d.Severity != SeverityNone
 }
 
+// ReleaseAgeHours returns the number of hours since ToVersion was
+// published, or -1 if the publish time is unknown.
+func (d *Dependency) ReleaseAgeHours() int {
+	if d.ReleasedAt == nil {
+		return -1
+	}
+	return int(time.Since(*d.ReleasedAt).Hours())
+}
+
+// DependencyType is how a repository depends on a package.
+type DependencyType string
+
+const (
+	DependencyTypeDirect      DependencyType = "direct"
+	DependencyTypeIndirect    DependencyType = "indirect"
+	DependencyTypeDevelopment DependencyType = "development" // e.g. npm devDependencies
+)
+
 // UpdateType represents the semantic version update type.
 type UpdateType string
 
@@ -68,6 +144,124 @@ const (
 	UpdateTypeUnknown UpdateType = "unknown"
 )
 
+// updateTypeRank orders update types by risk. Unknown update types rank
+// above major, since a breaking change cannot be ruled out.
+var updateTypeRank = map[UpdateType]int{
+	UpdateTypePatch:   1,
+	UpdateTypeMinor:   2,
+	UpdateTypeMajor:   3,
+	UpdateTypeUnknown: 4,
+	"":                4,
+}
+
+// Severity is the severity of a vulnerability.
+type Severity string
+
+const (
+	SeverityNone     Severity = ""
+	SeverityUnknown  Severity = "unknown"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// severityRank orders severities. Advisories without a severity rank
+// above no advisory at all but below every rated one.
+var severityRank = map[Severity]int{
+	SeverityNone:     0,
+	SeverityUnknown:  1,
+	SeverityLow:      2,
+	SeverityMedium:   3,
+	SeverityHigh:     4,
+	SeverityCritical: 5,
+}
+
+// Rank returns the position of the severity in the order none, unknown,
+// low, medium, high, critical, starting at 0.
+func (s Severity) Rank() int {
+	return severityRank[s]
+}
+
+// HighestSeverity returns the most severe of the given severities, or
+// SeverityNone if there are none.
+func HighestSeverity(severities ...Severity) Severity {
+	highest := SeverityNone
+	for _, s := range severities {
+		if s.Rank() > highest.Rank() {
+			highest = s
+		}
+	}
+	return highest
+}
+
+// HighestUpdateType returns the riskiest of the given update types, or
+// UpdateTypeUnknown if there are none.
+func HighestUpdateType(types ...UpdateType) UpdateType {
+	if len(types) == 0 {
+		return UpdateTypeUnknown
+	}
+
+	highest := types[0]
+	for _, t := range types[1:] {
+		if updateTypeRank[t] > updateTypeRank[highest] {
+			highest = t
+		}
+	}
+	if highest == "" {
+		return UpdateTypeUnknown
+	}
+	return highest
+}
+
+// GroupDependency returns the aggregate of a group of dependency updates.
+// Its update type is the highest of the group; ecosystem, dependency type
+// and manager are kept only if every member shares them, and its severity
+// is the highest of the group. Name and versions are left empty since they
+// differ per member.
+func GroupDependency(deps []Dependency) Dependency {
+	if len(deps) == 0 {
+		return Dependency{}
+	}
+
+	group := Dependency{
+		Ecosystem: deps[0].Ecosystem,
+		DepType:   deps[0].DepType,
+		Manager:   deps[0].Manager,
+	}
+	types := make([]UpdateType, 0, len(deps))
+	for _, d := range deps {
+		types = append(types, d.UpdateType)
+		group.Severity = HighestSeverity(group.Severity, d.Severity)
+		if d.Ecosystem != group.Ecosystem {
+			group.Ecosystem = ""
+		}
+		if d.DepType != group.DepType {
+			group.DepType = ""
+		}
+		if d.Manager != group.Manager {
+			group.Manager = ""
+		}
+	}
+	group.UpdateType = HighestUpdateType(types...)
+
+	return group
+}
+
+// IsGroup returns true if the PR updates more than one dependency.
+func (pr *PullRequest) IsGroup() bool {
+	return len(pr.Dependencies) > 1
+}
+
+// AllDependencies returns every dependency updated by the PR: the members
+// of a grouped PR, or else its single dependency.
+func (pr *PullRequest) AllDependencies() []Dependency {
+	if len(pr.Dependencies) > 0 {
+		return pr.Dependencies
+	}
+	return []Dependency{pr.Dependency}
+}
+
 // AgeHours returns the age of the PR in hours.
 func (pr *PullRequest) AgeHours() int {
 	return int(time.Since(pr.CreatedAt).Hours())
@@ -78,11 +272,43 @@ func (pr *PullRequest) IsMerged() bool {
 	return pr.MergedAt != nil
 }
 
-// CheckRun represents a CI check run status.
+// CheckSource identifies the API a CI result was reported through.
+type CheckSource string
+
+const (
+	CheckSourceCheckRun CheckSource = "check-run" // Checks API
+	CheckSourceStatus   CheckSource = "status"    // legacy commit Status API
+)
+
+// CheckRun represents a CI check run status. Commit statuses are converted
+// to check runs with NewStatusCheck so both are evaluated the same way.
 type CheckRun struct {
-	Name       string `json:"name"`
-	Status     string `json:"status"`     // queued, in_progress, completed
-	Conclusion string `json:"conclusion"` // success, failure, neutral, cancelled, skipped, timed_out, action_required
+	Name       string      `json:"name"`
+	Status     string      `json:"status"`     // queued, in_progress, completed
+	Conclusion string      `json:"conclusion"` // success, failure, neutral, cancelled, skipped, timed_out, action_required
+	Source     CheckSource `json:"source,omitempty"`
+}
+
+// NewStatusCheck converts a commit status to a check run. The status
+// context becomes the check name; the state (pending, success, failure
+// or error) maps to the equivalent check run status and conclusion.
+func NewStatusCheck(context, state string) CheckRun {
+	c := CheckRun{
+		Name:   context,
+		Status: "completed",
+		Source: CheckSourceStatus,
+	}
+
+	switch state {
+	case "success":
+		c.Conclusion = "success"
+	case "failure", "error":
+		c.Conclusion = "failure"
+	default:
+		c.Status = "in_progress"
+	}
+
+	return c
 }
 
 // IsSuccess returns true if the check run completed successfully.
@@ -17,6 +17,13 @@ type Repo struct {
 	HTMLURL       string    `json:"htmlUrl"`
 }
 
+// BranchProtection contains the merge requirements of a protected branch.
+type BranchProtection struct {
+	Branch            string   `json:"branch"`
+	RequiredChecks    []string `json:"requiredChecks,omitempty"`
+	RequiredApprovals int      `json:"requiredApprovals,omitempty"`
+}
+
 // RepoFilter defines criteria for filtering repositories.
 type RepoFilter struct {
 	IncludeArchived  bool     `json:"includeArchived"`
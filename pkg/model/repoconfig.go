@@ -0,0 +1,62 @@
+package model
+
+// RepoConfigPath is the path of the optional per-repository config file,
+// read from the repository's default branch.
+const RepoConfigPath = ".github/versionconductor.yaml"
+
+// RepoConfig is a per-repository configuration that adjusts the merge
+// profile selected on the command line.
+type RepoConfig struct {
+	// Disabled opts the repository out of VersionConductor entirely.
+	Disabled bool `json:"disabled" yaml:"disabled"`
+
+	// Overrides replaces individual merge profile fields.
+	Overrides ProfileOverrides `json:"overrides" yaml:"overrides"`
+
+	// Dependencies adds to the profile's dependency allow and deny lists
+	// and rules.
+	Dependencies DependencyLists `json:"dependencies" yaml:"dependencies"`
+}
+
+// ProfileOverrides holds merge profile fields to override.
+// Nil fields keep the value from the base profile.
+type ProfileOverrides struct {
+	MinAgeHours          *int     `json:"minAgeHours,omitempty" yaml:"minAgeHours,omitempty"`
+	MaxAgeHours          *int     `json:"maxAgeHours,omitempty" yaml:"maxAgeHours,omitempty"`
+	MinReleaseAgeHours   *int     `json:"minReleaseAgeHours,omitempty" yaml:"minReleaseAgeHours,omitempty"`
+	SecurityFixesSkipAge *bool    `json:"securityFixesSkipAge,omitempty" yaml:"securityFixesSkipAge,omitempty"`
+	AutoMergePatch       *bool    `json:"autoMergePatch,omitempty" yaml:"autoMergePatch,omitempty"`
+	AutoMergeMinor       *bool    `json:"autoMergeMinor,omitempty" yaml:"autoMergeMinor,omitempty"`
+	AutoMergeMajor       *bool    `json:"autoMergeMajor,omitempty" yaml:"autoMergeMajor,omitempty"`
+	RequireAllChecks     *bool    `json:"requireAllChecks,omitempty" yaml:"requireAllChecks,omitempty"`
+	RequiredChecks       []string `json:"requiredChecks,omitempty" yaml:"requiredChecks,omitempty"`
+	AllowPendingChecks   *bool    `json:"allowPendingChecks,omitempty" yaml:"allowPendingChecks,omitempty"`
+	MergeStrategy        *string  `json:"mergeStrategy,omitempty" yaml:"mergeStrategy,omitempty"`
+	DeleteBranch         *bool    `json:"deleteBranch,omitempty" yaml:"deleteBranch,omitempty"`
+	RequireApproval      *bool    `json:"requireApproval,omitempty" yaml:"requireApproval,omitempty"`
+	MaxPRsPerRun         *int     `json:"maxPRsPerRun,omitempty" yaml:"maxPRsPerRun,omitempty"`
+	MaxRiskScore         *int     `json:"maxRiskScore,omitempty" yaml:"maxRiskScore,omitempty"`
+
+	// MergeWindows replaces the profile's merge windows, e.g. to use the
+	// owning team's timezone. Freezes are added to the profile's freezes.
+	MergeWindows []MergeWindow `json:"mergeWindows,omitempty" yaml:"mergeWindows,omitempty"`
+	Freezes      []Freeze      `json:"freezes,omitempty" yaml:"freezes,omitempty"`
+}
+
+// DependencyLists holds dependency names or glob patterns to allow or deny,
+// and dependency rules evaluated before the profile's own rules.
+type DependencyLists struct {
+	Allow []string         `json:"allow,omitempty" yaml:"allow,omitempty"`
+	Deny  []string         `json:"deny,omitempty" yaml:"deny,omitempty"`
+	Rules []DependencyRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// RepoProfile is the effective merge profile for a repository after its
+// in-repo config has been applied.
+type RepoProfile struct {
+	Repo       RepoRef           `json:"repo"`
+	ConfigPath string            `json:"configPath,omitempty"`
+	Disabled   bool              `json:"disabled"`
+	Protection *BranchProtection `json:"protection,omitempty"`
+	Profile    MergeProfile      `json:"profile"`
+}
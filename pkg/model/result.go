@@ -9,6 +9,7 @@ type ScanResult struct {
 	ReposScanned int           `json:"reposScanned"`
 	PRsFound     int           `json:"prsFound"`
 	PRs          []PullRequest `json:"prs"`
+	Profiles     []RepoProfile `json:"profiles,omitempty"`
 	Errors       []ScanError   `json:"errors,omitempty"`
 }
 
@@ -20,14 +21,19 @@ type ScanError struct {
 
 // MergeResult contains the results of a merge operation.
 type MergeResult struct {
-	Timestamp    time.Time   `json:"timestamp"`
-	DryRun       bool        `json:"dryRun"`
-	Merged       []MergedPR  `json:"merged,omitempty"`
-	Skipped      []SkippedPR `json:"skipped,omitempty"`
-	Failed       []FailedPR  `json:"failed,omitempty"`
-	MergedCount  int         `json:"mergedCount"`
-	SkippedCount int         `json:"skippedCount"`
-	FailedCount  int         `json:"failedCount"`
+	Timestamp    time.Time     `json:"timestamp"`
+	DryRun       bool          `json:"dryRun"`
+	Merged       []MergedPR    `json:"merged,omitempty"`
+	Skipped      []SkippedPR   `json:"skipped,omitempty"`
+	Failed       []FailedPR    `json:"failed,omitempty"`
+	Profiles     []RepoProfile `json:"profiles,omitempty"`
+	Errors       []ScanError   `json:"errors,omitempty"`
+	MergedCount  int           `json:"mergedCount"`
+	SkippedCount int           `json:"skippedCount"`
+	FailedCount  int           `json:"failedCount"`
+
+	// SkippedByReason counts the skipped PRs by reason code.
+	SkippedByReason []ReasonCount `json:"skippedByReason,omitempty"`
 }
 
 // MergedPR represents a successfully merged PR.
@@ -37,10 +43,14 @@ type MergedPR struct {
 	SHA      string      `json:"sha"`
 }
 
-// SkippedPR represents a PR that was skipped during merge.
+// SkippedPR represents a PR that was skipped during merge. Codes and
+// Details are the structured form of Reason.
 type SkippedPR struct {
-	PR     PullRequest `json:"pr"`
-	Reason string      `json:"reason"`
+	PR         PullRequest  `json:"pr"`
+	Reason     string       `json:"reason"`
+	Codes      []ReasonCode `json:"codes,omitempty"`
+	Details    []Reason     `json:"details,omitempty"`
+	NextWindow *time.Time   `json:"nextWindow,omitempty"`
 }
 
 // FailedPR represents a PR that failed to merge.
@@ -55,14 +65,39 @@ type ReviewResult struct {
 	DryRun        bool          `json:"dryRun"`
 	Approved      []PullRequest `json:"approved,omitempty"`
 	Denied        []DeniedPR    `json:"denied,omitempty"`
+	Errors        []ScanError   `json:"errors,omitempty"`
 	ApprovedCount int           `json:"approvedCount"`
 	DeniedCount   int           `json:"deniedCount"`
+
+	// DeniedByReason counts the denied PRs by reason code.
+	DeniedByReason []ReasonCount `json:"deniedByReason,omitempty"`
 }
 
-// DeniedPR represents a PR that was denied review approval.
+// DeniedPR represents a PR that was denied review approval. Codes and
+// Details are the structured form of Reason.
 type DeniedPR struct {
-	PR     PullRequest `json:"pr"`
-	Reason string      `json:"reason"`
+	PR      PullRequest  `json:"pr"`
+	Reason  string       `json:"reason"`
+	Codes   []ReasonCode `json:"codes,omitempty"`
+	Details []Reason     `json:"details,omitempty"`
+}
+
+// CountSkippedReasons counts the skipped PRs by reason code.
+func (r *MergeResult) CountSkippedReasons() {
+	codes := make([][]ReasonCode, 0, len(r.Skipped))
+	for _, s := range r.Skipped {
+		codes = append(codes, s.Codes)
+	}
+	r.SkippedByReason = CountReasons(codes...)
+}
+
+// CountDeniedReasons counts the denied PRs by reason code.
+func (r *ReviewResult) CountDeniedReasons() {
+	codes := make([][]ReasonCode, 0, len(r.Denied))
+	for _, d := range r.Denied {
+		codes = append(codes, d.Codes)
+	}
+	r.DeniedByReason = CountReasons(codes...)
 }
 
 // ReleaseResult contains the results of creating releases.
@@ -88,8 +123,9 @@ type CreatedRelease struct {
 
 // SkippedRelease represents a repository that was skipped for release.
 type SkippedRelease struct {
-	Repo   RepoRef `json:"repo"`
-	Reason string  `json:"reason"`
+	Repo       RepoRef    `json:"repo"`
+	Reason     string     `json:"reason"`
+	NextWindow *time.Time `json:"nextWindow,omitempty"`
 }
 
 // FailedRelease represents a failed release attempt.
@@ -97,3 +133,25 @@ type FailedRelease struct {
 	Repo  RepoRef `json:"repo"`
 	Error string  `json:"error"`
 }
+
+// PolicyTestReport contains the results of running policy test fixtures.
+type PolicyTestReport struct {
+	Timestamp   time.Time          `json:"timestamp"`
+	Profile     string             `json:"profile,omitempty"`
+	Policies    []string           `json:"policies,omitempty"`
+	Results     []PolicyTestResult `json:"results"`
+	PassedCount int                `json:"passedCount"`
+	FailedCount int                `json:"failedCount"`
+}
+
+// PolicyTestResult is the outcome of a single policy test fixture.
+type PolicyTestResult struct {
+	Name       string          `json:"name"`
+	File       string          `json:"file"`
+	Action     string          `json:"action"`
+	Passed     bool            `json:"passed"`
+	Expected   PolicyDecision  `json:"expected"`
+	Actual     *PolicyDecision `json:"actual,omitempty"`
+	Mismatches []string        `json:"mismatches,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}